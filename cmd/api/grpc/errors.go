@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain identifica el origen de los errores en errdetails.ErrorInfo.
+const errorDomain = "user-api"
+
+// statusFromError traduce un error de dominio al status gRPC equivalente al
+// código HTTP de la API REST, con un errdetails.ErrorInfo cuyo Reason
+// identifica el error de forma estable. Los errores inesperados se registran
+// en el log y se responden como Internal sin exponer su detalle.
+func statusFromError(err error) error {
+	var errNotNullable domain.ErrValueNotNullable
+	var validationErrors validator.ValidationErrors
+
+	switch {
+	case errors.Is(err, application.ErrUnauthenticated):
+		return newStatus(codes.Unauthenticated, err, "UNAUTHENTICATED")
+	case errors.Is(err, domain.ErrUserNotFound):
+		return newStatus(codes.NotFound, err, "USER_NOT_FOUND")
+	case errors.Is(err, domain.ErrIdInUse):
+		return newStatus(codes.AlreadyExists, err, "ID_IN_USE")
+	case errors.Is(err, domain.ErrUsernameInUse):
+		return newStatus(codes.AlreadyExists, err, "USERNAME_IN_USE")
+	case errors.Is(err, domain.ErrEmailInUse):
+		return newStatus(codes.AlreadyExists, err, "EMAIL_IN_USE")
+	case errors.Is(err, domain.ErrUserNotDeleted):
+		return newStatus(codes.FailedPrecondition, err, "USER_NOT_DELETED")
+	case errors.Is(err, domain.ErrUnknownField):
+		return newStatus(codes.InvalidArgument, err, "UNKNOWN_FIELD")
+	case errors.Is(err, domain.ErrTenantNotFound):
+		return newStatus(codes.NotFound, err, "TENANT_NOT_FOUND")
+	case errors.Is(err, domain.ErrTenantForbidden):
+		return newStatus(codes.PermissionDenied, err, "TENANT_FORBIDDEN")
+	case errors.Is(err, application.ErrEventStreamLagged):
+		return newStatus(codes.Aborted, err, "STREAM_LAGGED")
+	case errors.As(err, &errNotNullable):
+		return newStatus(codes.InvalidArgument, err, "VALUE_NOT_NULLABLE",
+			&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: errNotNullable.Value, Description: err.Error()},
+			}})
+	case errors.As(err, &validationErrors):
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(validationErrors))
+		for i, fieldError := range validationErrors {
+			violations[i] = &errdetails.BadRequest_FieldViolation{
+				Field:       strings.ToLower(fieldError.Field()),
+				Description: "failed on the '" + fieldError.Tag() + "' rule",
+			}
+		}
+		return newStatus(codes.InvalidArgument, errors.New("validation failed"), "VALIDATION_FAILED",
+			&errdetails.BadRequest{FieldViolations: violations})
+	default:
+		log.Printf("[gRPC] internal error: %v", err)
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
+
+// invalidArgument construye un InvalidArgument para un campo de la petición.
+func invalidArgument(field, description string) error {
+	return newStatus(codes.InvalidArgument, errors.New(field+": "+description), "INVALID_ARGUMENT",
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: description},
+		}})
+}
+
+// permissionDenied construye un PermissionDenied con el mensaje indicado.
+func permissionDenied(message string) error {
+	return newStatus(codes.PermissionDenied, errors.New(message), "PERMISSION_DENIED")
+}
+
+// newStatus construye el status con el mensaje de err, un ErrorInfo con
+// reason y los detalles adicionales indicados.
+func newStatus(code codes.Code, err error, reason string, details ...*errdetails.BadRequest) error {
+	st := status.New(code, err.Error())
+
+	withDetails, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain})
+	if detailErr != nil {
+		return st.Err()
+	}
+	for _, detail := range details {
+		if next, detailErr := withDetails.WithDetails(detail); detailErr == nil {
+			withDetails = next
+		}
+	}
+
+	return withDetails.Err()
+}
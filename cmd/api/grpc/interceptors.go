@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/oklog/ulid/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDMetadata es la clave de metadata que transporta el ID de
+// correlación, equivalente a la cabecera X-Request-ID de la API REST.
+const requestIDMetadata = "x-request-id"
+
+// tenantMetadata es la clave de metadata con la que un operador elige la
+// organización, equivalente a la cabecera X-Tenant-ID de la API REST.
+const tenantMetadata = "x-tenant-id"
+
+// interceptors autentica las llamadas con las mismas credenciales que la
+// API REST: Basic Auth o una clave de API (apiKeys puede ser nil), y
+// resuelve su organización (tenants nil = la organización por defecto).
+type interceptors struct {
+	apiKeys *application.APIKeyService
+	tenants *application.TenantService
+}
+
+// unary autentica la llamada, le asigna un ID de correlación y registra su
+// método, código de estado y duración.
+func (i interceptors) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	var resp any
+	ctx, err := i.authenticate(ctx)
+	if err == nil {
+		resp, err = handler(ctx, req)
+	}
+
+	logCall(info.FullMethod, err, start)
+	return resp, err
+}
+
+// stream es el equivalente de unary para los streams.
+func (i interceptors) stream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	ctx, err := i.authenticate(stream.Context())
+	if err == nil {
+		err = handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+
+	logCall(info.FullMethod, err, start)
+	return err
+}
+
+// authenticate verifica las credenciales de la metadata authorization y
+// propaga en el contexto el principal, su organización y el ID de correlación.
+func (i interceptors) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	// 1. ID de correlación: se reutiliza el recibido o se genera un ULID nuevo
+	requestID := first(md, requestIDMetadata)
+	if requestID == "" || len(requestID) > 128 {
+		t := time.Now()
+		entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
+		requestID = ulid.MustNew(ulid.Timestamp(t), entropy).String()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, requestID))
+	ctx = domain.WithRequestID(ctx, requestID)
+
+	// 2. Autenticación (compartida con la API REST)
+	principal, err := application.AuthenticateRequest(ctx, i.apiKeys, first(md, "authorization"))
+	if err != nil {
+		return ctx, statusFromError(err)
+	}
+	ctx = domain.WithPrincipal(ctx, principal)
+
+	// 3. Organización (ver TenantMiddleware de la API REST)
+	if i.tenants != nil {
+		tenantID, err := i.tenants.Resolve(ctx, principal, first(md, tenantMetadata))
+		if err != nil {
+			return ctx, statusFromError(err)
+		}
+		ctx = domain.WithTenant(ctx, tenantID)
+	}
+
+	return ctx, nil
+}
+
+// first retorna el primer valor de la clave de metadata indicada.
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// logCall registra la llamada con el mismo formato que la API REST.
+func logCall(method string, err error, start time.Time) {
+	log.Printf("[gRPC] %s | Code: %s | Duration: %v", method, status.Code(err), time.Since(start))
+}
+
+// contextStream reemplaza el contexto de un grpc.ServerStream por el que
+// transporta el principal y el ID de correlación.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context retorna el contexto enriquecido por streamInterceptor.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
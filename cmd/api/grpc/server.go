@@ -0,0 +1,32 @@
+// Package grpc expone application.UserService como servicio gRPC
+// (user.v1.UserService, ver proto/user/v1/user.proto) para los servicios
+// internos, con la misma autenticación y semántica de errores que la API REST.
+package grpc
+
+//go:generate protoc -I ../../../proto --go_out=../../.. --go_opt=module=user-api-restful --go-grpc_out=../../.. --go-grpc_opt=module=user-api-restful user/v1/user.proto
+
+import (
+	"user-api-restful/cmd/api/grpc/userv1"
+	"user-api-restful/internal/application"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer crea el servidor gRPC con el UserService registrado, los
+// interceptores de autenticación, ID de correlación y logging, y el servicio
+// de reflexión (para herramientas como grpcurl). apiKeys puede ser nil, en
+// cuyo caso solo se acepta Basic Auth; tenants puede ser nil, en cuyo caso
+// todas las llamadas operan sobre la organización por defecto.
+func NewServer(users application.UserService, feed *application.EventFeed, apiKeys *application.APIKeyService, tenants *application.TenantService) *grpc.Server {
+	interceptors := interceptors{apiKeys: apiKeys, tenants: tenants}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors.unary),
+		grpc.ChainStreamInterceptor(interceptors.stream),
+	)
+
+	userv1.RegisterUserServiceServer(server, NewUserServer(users, feed))
+	reflection.Register(server)
+
+	return server
+}
@@ -0,0 +1,376 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+	"user-api-restful/cmd/api/grpc/userv1"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// defaultPageSize y maxPageSize acotan el tamaño de página de ListUsers.
+	defaultPageSize = 50
+	maxPageSize     = 500
+
+	// watchHeartbeat es el intervalo con el que WatchUsers verifica la
+	// conexión mientras no hay eventos (el keep-alive lo maneja HTTP/2).
+	watchHeartbeat = 15 * time.Second
+)
+
+// updatableFields son los campos que UpdateUser acepta en update_mask.
+var updatableFields = []string{"name", "username", "email"}
+
+// UserServer implementa userv1.UserServiceServer sobre application.UserService.
+type UserServer struct {
+	userv1.UnimplementedUserServiceServer
+
+	userService application.UserService
+	feed        *application.EventFeed
+	validator   *validator.Validate
+}
+
+// NewUserServer crea una nueva instancia de UserServer.
+func NewUserServer(service application.UserService, feed *application.EventFeed) *UserServer {
+	return &UserServer{
+		userService: service,
+		feed:        feed,
+		validator:   validator.New(),
+	}
+}
+
+// CreateUser crea un usuario, con las mismas validaciones que POST /users.
+func (s *UserServer) CreateUser(ctx context.Context, req *userv1.CreateUserRequest) (*userv1.User, error) {
+	// 1. Validación de la estructura
+	request := domain.UserCreateRequest{Name: req.GetName(), Username: req.GetUsername(), Email: req.GetEmail()}
+	if err := s.validator.Struct(request); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	// 2. Llamada al servicio de aplicación
+	user, err := s.userService.Create(ctx, &request)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return toProtoUser(user, nil), nil
+}
+
+// GetUser retorna un usuario por ID, limitado a los campos de read_mask.
+func (s *UserServer) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.User, error) {
+	if req.GetId() == "" {
+		return nil, invalidArgument("id", "is required")
+	}
+
+	fields, err := maskFields(req.GetReadMask())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	user, err := s.userService.FindById(ctx, req.GetId(), fields...)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return toProtoUser(user, fields), nil
+}
+
+// ListUsers retorna una página de usuarios en orden de ID. El token de
+// página es el ID del último usuario retornado (paginación por keyset).
+func (s *UserServer) ListUsers(ctx context.Context, req *userv1.ListUsersRequest) (*userv1.ListUsersResponse, error) {
+	// 1. Construcción del filtro
+	if req.GetIncludeDeleted() && !isAdmin(ctx) {
+		return nil, permissionDenied("admin privileges required to include deleted users")
+	}
+
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize < 0:
+		return nil, invalidArgument("page_size", "must not be negative")
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	afterID, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, invalidArgument("page_token", "is not valid")
+	}
+
+	fields, err := maskFields(req.GetReadMask())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	statuses, err := domain.ParseUserStatuses(strings.Join(req.GetStatuses(), ","))
+	if err != nil {
+		return nil, invalidArgument("statuses", err.Error())
+	}
+
+	filter := domain.UserFilter{
+		IncludeDeleted: req.GetIncludeDeleted(),
+		Statuses:       statuses,
+		CreatedAfter:   optionalTime(req.GetCreatedAfter()),
+		CreatedBefore:  optionalTime(req.GetCreatedBefore()),
+		UpdatedAfter:   optionalTime(req.GetUpdatedAfter()),
+		UpdatedBefore:  optionalTime(req.GetUpdatedBefore()),
+		Fields:         withID(fields),
+		AfterID:        afterID,
+		// Se lee un usuario adicional para saber si hay otra página.
+		Limit: pageSize + 1,
+	}
+
+	// 2. Llamada al servicio
+	users, err := s.userService.FindAll(ctx, filter)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	// 3. Armado de la página
+	page := *users
+	response := &userv1.ListUsersResponse{}
+	if len(page) > pageSize {
+		page = page[:pageSize]
+		response.NextPageToken = encodePageToken(page[len(page)-1].ID)
+	}
+
+	response.Users = make([]*userv1.User, len(page))
+	for i := range page {
+		response.Users[i] = toProtoUser(&page[i], fields)
+	}
+
+	return response, nil
+}
+
+// UpdateUser aplica al usuario los campos indicados en update_mask (vacío =
+// name, username y email) y persiste el resultado como PUT /users.
+func (s *UserServer) UpdateUser(ctx context.Context, req *userv1.UpdateUserRequest) (*userv1.User, error) {
+	// 1. Validación de la máscara
+	changes := req.GetUser()
+	if changes.GetId() == "" {
+		return nil, invalidArgument("user.id", "is required")
+	}
+
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = updatableFields
+	}
+
+	// 2. Lectura del estado actual y aplicación de los cambios
+	user, err := s.userService.FindById(ctx, changes.GetId())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	for _, path := range paths {
+		switch path {
+		case "name":
+			user.Name = changes.GetName()
+		case "username":
+			user.Username = changes.GetUsername()
+		case "email":
+			user.Email = changes.GetEmail()
+		default:
+			return nil, invalidArgument("update_mask", "field "+path+" cannot be updated; updatable fields: "+strings.Join(updatableFields, ","))
+		}
+	}
+
+	request := domain.UserCreateRequest{Name: user.Name, Username: user.Username, Email: user.Email}
+	if err := s.validator.Struct(request); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	// 3. Llamada al servicio
+	updated, err := s.userService.Update(ctx, user)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return toProtoUser(updated, nil), nil
+}
+
+// DeleteUser elimina lógicamente un usuario.
+func (s *UserServer) DeleteUser(ctx context.Context, req *userv1.DeleteUserRequest) (*emptypb.Empty, error) {
+	if req.GetId() == "" {
+		return nil, invalidArgument("id", "is required")
+	}
+
+	if err := s.userService.Delete(ctx, req.GetId()); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// WatchUsers transmite los eventos con published_sequence mayor a after_sequence,
+// primero los retenidos en el registro y luego los publicados en vivo, como
+// GET /users/events. Termina cuando el cliente cancela la llamada.
+func (s *UserServer) WatchUsers(req *userv1.WatchUsersRequest, stream userv1.UserService_WatchUsersServer) error {
+	// 1. Validación de parámetros
+	if req.GetAfterSequence() < 0 {
+		return invalidArgument("after_sequence", "must be a non-negative integer")
+	}
+
+	var types []domain.EventType
+	if len(req.GetTypes()) > 0 {
+		var err error
+		if types, err = application.ParseEventTypes(req.GetTypes()); err != nil {
+			return invalidArgument("types", err.Error())
+		}
+	}
+
+	// 2. Envío de eventos hasta que el cliente se desconecte
+	ctx := stream.Context()
+	onEvent := func(event domain.UserEvent) error {
+		return stream.Send(toProtoEvent(&event))
+	}
+	onHeartbeat := func() error {
+		return ctx.Err()
+	}
+
+	err := s.feed.Follow(ctx, req.GetAfterSequence(), types, watchHeartbeat, onEvent, onHeartbeat)
+	if err != nil && ctx.Err() == nil {
+		return statusFromError(err)
+	}
+
+	return nil
+}
+
+// maskFields convierte una FieldMask en los campos de domain.User a leer
+// (nil = todos).
+func maskFields(mask *fieldmaskpb.FieldMask) ([]string, error) {
+	return domain.ParseUserFields(strings.Join(mask.GetPaths(), ","))
+}
+
+// withID agrega id a los campos leídos, necesario para el token de página.
+func withID(fields []string) []string {
+	if len(fields) == 0 {
+		return nil
+	}
+	for _, field := range fields {
+		if field == "id" {
+			return fields
+		}
+	}
+	return append([]string{"id"}, fields...)
+}
+
+// encodePageToken codifica el ID del último usuario de la página como token opaco.
+func encodePageToken(lastID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(lastID))
+}
+
+// decodePageToken retorna el ID codificado en el token ("" si está vacío).
+func decodePageToken(token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(decoded) == 0 {
+		return "", errors.New("invalid page token")
+	}
+	return string(decoded), nil
+}
+
+// optionalTime convierte un Timestamp opcional en *time.Time (nil si está ausente).
+func optionalTime(timestamp *timestamppb.Timestamp) *time.Time {
+	if timestamp == nil {
+		return nil
+	}
+	t := timestamp.AsTime()
+	return &t
+}
+
+// isAdmin indica si el principal autenticado es administrador.
+func isAdmin(ctx context.Context) bool {
+	principal, ok := domain.PrincipalFromContext(ctx)
+	return ok && principal.IsAdmin()
+}
+
+// toProtoUser convierte un usuario de dominio a su mensaje. Si se indican
+// fields, solo se completan esos campos.
+func toProtoUser(user *domain.User, fields []string) *userv1.User {
+	include := func(string) bool { return true }
+	if len(fields) > 0 {
+		selected := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			selected[field] = true
+		}
+		include = func(field string) bool { return selected[field] }
+	}
+
+	message := &userv1.User{}
+	if include("id") {
+		message.Id = user.ID
+	}
+	if include("name") {
+		message.Name = user.Name
+	}
+	if include("username") {
+		message.Username = user.Username
+	}
+	if include("email") {
+		message.Email = user.Email
+	}
+	if include("created_at") {
+		message.CreatedAt = timestamppb.New(user.CreatedAt)
+	}
+	if include("updated_at") {
+		message.UpdatedAt = timestamppb.New(user.UpdatedAt)
+	}
+	if include("created_by") {
+		message.CreatedBy = user.CreatedBy
+	}
+	if include("updated_by") {
+		message.UpdatedBy = user.UpdatedBy
+	}
+	if include("deleted_at") && user.DeletedAt != nil {
+		message.DeletedAt = timestamppb.New(*user.DeletedAt)
+	}
+	if include("status") {
+		message.Status = string(user.Status)
+	}
+	if include("status_reason") {
+		message.StatusReason = user.StatusReason
+	}
+	if include("status_changed_at") && user.StatusChangedAt != nil {
+		message.StatusChangedAt = timestamppb.New(*user.StatusChangedAt)
+	}
+	if include("email_verified") {
+		message.EmailVerified = user.EmailVerified
+	}
+	if include("email_verified_at") && user.EmailVerifiedAt != nil {
+		message.EmailVerifiedAt = timestamppb.New(*user.EmailVerifiedAt)
+	}
+
+	return message
+}
+
+// toProtoEvent convierte un evento de dominio a su mensaje.
+func toProtoEvent(event *domain.UserEvent) *userv1.UserEvent {
+	message := &userv1.UserEvent{
+		Id:                event.ID,
+		Sequence:          event.Sequence,
+		PublishedSequence: event.PublishedSequence,
+		Type:              string(event.Type),
+		UserId:            event.UserID,
+		OccurredAt:        timestamppb.New(event.OccurredAt),
+		Actor:             event.Actor,
+		RequestId:         event.RequestID,
+		ChangedFields:     event.ChangedFields,
+	}
+	if event.User != nil {
+		message.User = toProtoUser(event.User, nil)
+	}
+	return message
+}
@@ -0,0 +1,393 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+	"user-api-restful/cmd/api/grpc/userv1"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/messaging"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// memoryUsers es un UserService en memoria con las operaciones que expone
+// el servidor gRPC. Registra el contexto de la última llamada.
+type memoryUsers struct {
+	application.UserService
+	users   map[string]domain.User
+	nextID  int
+	lastCtx context.Context
+}
+
+func newMemoryUsers(users ...domain.User) *memoryUsers {
+	service := &memoryUsers{users: make(map[string]domain.User)}
+	for _, user := range users {
+		service.users[user.ID] = user
+	}
+	return service
+}
+
+func (s *memoryUsers) Create(ctx context.Context, request *domain.UserCreateRequest) (*domain.User, error) {
+	s.lastCtx = ctx
+	s.nextID++
+	user := domain.User{ID: "new-" + strconv.Itoa(s.nextID), Name: request.Name, Username: request.Username, Email: request.Email, Status: domain.UserActive}
+	s.users[user.ID] = user
+	return &user, nil
+}
+
+func (s *memoryUsers) FindById(ctx context.Context, id string, _ ...string) (*domain.User, error) {
+	s.lastCtx = ctx
+	user, ok := s.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (s *memoryUsers) FindAll(ctx context.Context, filter domain.UserFilter) (*[]domain.User, error) {
+	s.lastCtx = ctx
+	users := make([]domain.User, 0, len(s.users))
+	for _, user := range s.users {
+		if user.ID > filter.AfterID && (filter.Statuses == nil || slices.Contains(filter.Statuses, user.Status)) {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	if filter.Limit > 0 && len(users) > filter.Limit {
+		users = users[:filter.Limit]
+	}
+	return &users, nil
+}
+
+func (s *memoryUsers) Update(ctx context.Context, user *domain.User) (*domain.User, error) {
+	s.lastCtx = ctx
+	if _, ok := s.users[user.ID]; !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	s.users[user.ID] = *user
+	return user, nil
+}
+
+func (s *memoryUsers) Delete(ctx context.Context, id string) error {
+	s.lastCtx = ctx
+	if _, ok := s.users[id]; !ok {
+		return domain.ErrUserNotFound
+	}
+	delete(s.users, id)
+	return nil
+}
+
+// stubOutbox retorna los eventos publicados fijos. Solo implementa la
+// consulta que usa application.EventFeed.
+type stubOutbox struct {
+	domain.OutboxRepository
+	published []domain.UserEvent
+}
+
+func (s *stubOutbox) FindPublished(_ string, afterPublished int64, _ []domain.EventType, _ int) ([]domain.UserEvent, error) {
+	events := make([]domain.UserEvent, 0)
+	for _, event := range s.published {
+		if event.PublishedSequence > afterPublished {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// dialServer levanta NewServer sobre un listener en memoria y retorna un
+// cliente conectado. Basic Auth queda configurado como admin:secret.
+func dialServer(t *testing.T, users application.UserService, feed *application.EventFeed) userv1.UserServiceClient {
+	t.Helper()
+	t.Setenv("BASIC_AUTH_USER", "admin")
+	t.Setenv("BASIC_AUTH_PASS", "secret")
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(users, feed, nil, nil)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return userv1.NewUserServiceClient(conn)
+}
+
+// authorized retorna un contexto con las credenciales Basic de dialServer.
+func authorized() context.Context {
+	credentials := base64.StdEncoding.EncodeToString([]byte("admin:secret"))
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic "+credentials)
+}
+
+// errorReason retorna el Reason del ErrorInfo del status de err.
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+func TestServerRequiresCredentials(t *testing.T) {
+	client := dialServer(t, newMemoryUsers(), nil)
+
+	_, err := client.GetUser(context.Background(), &userv1.GetUserRequest{Id: "u1"})
+
+	if status.Code(err) != codes.Unauthenticated || errorReason(err) != "UNAUTHENTICATED" {
+		t.Errorf("err = %v (reason %q), want Unauthenticated", err, errorReason(err))
+	}
+}
+
+func TestServerPropagatesRequestIDAndPrincipal(t *testing.T) {
+	users := newMemoryUsers(domain.User{ID: "u1", Name: "Jane"})
+	client := dialServer(t, users, nil)
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(authorized(), requestIDMetadata, "req-42")
+	if _, err := client.GetUser(ctx, &userv1.GetUserRequest{Id: "u1"}, grpc.Header(&header)); err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+
+	if got := header.Get(requestIDMetadata); len(got) != 1 || got[0] != "req-42" {
+		t.Errorf("x-request-id = %v, want req-42", got)
+	}
+	if got := domain.RequestIDFromContext(users.lastCtx); got != "req-42" {
+		t.Errorf("service request ID = %q, want req-42", got)
+	}
+	if principal, ok := domain.PrincipalFromContext(users.lastCtx); !ok || principal.Subject != "admin" {
+		t.Errorf("service principal = %+v, want admin", principal)
+	}
+}
+
+func TestCreateAndGetUser(t *testing.T) {
+	client := dialServer(t, newMemoryUsers(), nil)
+
+	created, err := client.CreateUser(authorized(), &userv1.CreateUserRequest{Name: "Jane", Username: "jane", Email: "jane@example.com"})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	got, err := client.GetUser(authorized(), &userv1.GetUserRequest{Id: created.GetId(), ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"email"}}})
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if got.GetEmail() != "jane@example.com" || got.GetName() != "" || got.GetUsername() != "" {
+		t.Errorf("GetUser = %v, want only the email of the read mask", got)
+	}
+}
+
+func TestErrorsMapToStatusCodesWithDetails(t *testing.T) {
+	client := dialServer(t, newMemoryUsers(), nil)
+
+	_, err := client.CreateUser(authorized(), &userv1.CreateUserRequest{Name: "Jane", Username: "jane", Email: "not-an-email"})
+	if status.Code(err) != codes.InvalidArgument || errorReason(err) != "VALIDATION_FAILED" {
+		t.Fatalf("CreateUser err = %v, want InvalidArgument VALIDATION_FAILED", err)
+	}
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			violations = badRequest.GetFieldViolations()
+		}
+	}
+	if len(violations) != 1 || violations[0].GetField() != "email" {
+		t.Errorf("violations = %v, want the email field", violations)
+	}
+
+	tests := []struct {
+		name   string
+		call   func() error
+		code   codes.Code
+		reason string
+	}{
+		{name: "not found", call: func() error {
+			_, err := client.GetUser(authorized(), &userv1.GetUserRequest{Id: "missing"})
+			return err
+		}, code: codes.NotFound, reason: "USER_NOT_FOUND"},
+		{name: "missing id", call: func() error {
+			_, err := client.DeleteUser(authorized(), &userv1.DeleteUserRequest{})
+			return err
+		}, code: codes.InvalidArgument, reason: "INVALID_ARGUMENT"},
+		{name: "unknown read mask field", call: func() error {
+			_, err := client.GetUser(authorized(), &userv1.GetUserRequest{Id: "u1", ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"password"}}})
+			return err
+		}, code: codes.InvalidArgument, reason: "UNKNOWN_FIELD"},
+		{name: "invalid page token", call: func() error {
+			_, err := client.ListUsers(authorized(), &userv1.ListUsersRequest{PageToken: "%%%"})
+			return err
+		}, code: codes.InvalidArgument, reason: "INVALID_ARGUMENT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if status.Code(err) != tt.code || errorReason(err) != tt.reason {
+				t.Errorf("err = %v (reason %q), want %s %s", err, errorReason(err), tt.code, tt.reason)
+			}
+		})
+	}
+}
+
+func TestListUsersPaginatesByPageToken(t *testing.T) {
+	client := dialServer(t, newMemoryUsers(
+		domain.User{ID: "u1", Name: "Ann"},
+		domain.User{ID: "u2", Name: "Bob"},
+		domain.User{ID: "u3", Name: "Cid"},
+	), nil)
+
+	var ids []string
+	token := ""
+	for pages := 0; pages < 3; pages++ {
+		page, err := client.ListUsers(authorized(), &userv1.ListUsersRequest{PageSize: 2, PageToken: token})
+		if err != nil {
+			t.Fatalf("ListUsers: %v", err)
+		}
+		for _, user := range page.GetUsers() {
+			ids = append(ids, user.GetId())
+		}
+		if token = page.GetNextPageToken(); token == "" {
+			break
+		}
+	}
+
+	if len(ids) != 3 || ids[0] != "u1" || ids[1] != "u2" || ids[2] != "u3" {
+		t.Errorf("ids = %v, want u1, u2, u3 across two pages", ids)
+	}
+}
+
+func TestListUsersFiltersAndExposesTheAccountStatus(t *testing.T) {
+	changedAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	client := dialServer(t, newMemoryUsers(
+		domain.User{ID: "u1", Name: "Ann", Status: domain.UserActive},
+		domain.User{ID: "u2", Name: "Bob", Status: domain.UserSuspended, StatusReason: "chargeback", StatusChangedAt: &changedAt},
+		domain.User{ID: "u3", Name: "Cid", Status: domain.UserLocked, StatusReason: "brute force", StatusChangedAt: &changedAt},
+	), nil)
+
+	page, err := client.ListUsers(authorized(), &userv1.ListUsersRequest{Statuses: []string{"suspended", "locked"}})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if users := page.GetUsers(); len(users) != 2 || users[0].GetId() != "u2" || users[1].GetId() != "u3" {
+		t.Fatalf("users = %v, want u2 and u3", users)
+	}
+	suspended := page.GetUsers()[0]
+	if suspended.GetStatus() != "suspended" || suspended.GetStatusReason() != "chargeback" || !suspended.GetStatusChangedAt().AsTime().Equal(changedAt) {
+		t.Errorf("user = %v, want its status, reason and change time", suspended)
+	}
+
+	masked, err := client.GetUser(authorized(), &userv1.GetUserRequest{Id: "u1", ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}}})
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if masked.GetStatus() != "active" || masked.GetStatusChangedAt() != nil || masked.GetName() != "" {
+		t.Errorf("GetUser = %v, want only the status", masked)
+	}
+
+	_, err = client.ListUsers(authorized(), &userv1.ListUsersRequest{Statuses: []string{"banned"}})
+	if status.Code(err) != codes.InvalidArgument || errorReason(err) != "INVALID_ARGUMENT" {
+		t.Errorf("unknown status err = %v, want InvalidArgument", err)
+	}
+}
+
+func TestGetUserExposesEmailVerification(t *testing.T) {
+	verifiedAt := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	client := dialServer(t, newMemoryUsers(
+		domain.User{ID: "u1", Email: "ann@example.com", EmailVerified: true, EmailVerifiedAt: &verifiedAt},
+		domain.User{ID: "u2", Email: "bob@example.com"},
+	), nil)
+
+	verified, err := client.GetUser(authorized(), &userv1.GetUserRequest{Id: "u1"})
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if !verified.GetEmailVerified() || !verified.GetEmailVerifiedAt().AsTime().Equal(verifiedAt) {
+		t.Errorf("GetUser = %v, want the email verified at %s", verified, verifiedAt)
+	}
+
+	unverified, err := client.GetUser(authorized(), &userv1.GetUserRequest{Id: "u2", ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"email_verified", "email_verified_at"}}})
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if unverified.GetEmailVerified() || unverified.GetEmailVerifiedAt() != nil || unverified.GetEmail() != "" {
+		t.Errorf("GetUser = %v, want an unverified email and only the masked fields", unverified)
+	}
+}
+
+func TestUpdateUserAppliesTheUpdateMask(t *testing.T) {
+	users := newMemoryUsers(domain.User{ID: "u1", Name: "Jane", Username: "jane", Email: "jane@example.com"})
+	client := dialServer(t, users, nil)
+
+	changes := &userv1.User{Id: "u1", Name: "Ignored", Email: "jane@new.example.com"}
+	updated, err := client.UpdateUser(authorized(), &userv1.UpdateUserRequest{User: changes, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"email"}}})
+	if err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if updated.GetEmail() != "jane@new.example.com" || updated.GetName() != "Jane" {
+		t.Errorf("UpdateUser = %v, want only the email changed", updated)
+	}
+
+	_, err = client.UpdateUser(authorized(), &userv1.UpdateUserRequest{User: changes, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"created_at"}}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("update of created_at err = %v, want InvalidArgument", err)
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	users := newMemoryUsers(domain.User{ID: "u1", Name: "Jane"})
+	client := dialServer(t, users, nil)
+
+	if _, err := client.DeleteUser(authorized(), &userv1.DeleteUserRequest{Id: "u1"}); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, ok := users.users["u1"]; ok {
+		t.Error("the user was not deleted")
+	}
+}
+
+func TestWatchUsersReplaysThenStreamsLiveEvents(t *testing.T) {
+	broker := messaging.NewBroker(4)
+	outbox := &stubOutbox{published: []domain.UserEvent{
+		{ID: "a", PublishedSequence: 1, Type: domain.EventUserCreated},
+		{ID: "b", PublishedSequence: 2, Type: domain.EventUserUpdated},
+	}}
+	client := dialServer(t, newMemoryUsers(), application.NewEventFeed(outbox, broker))
+
+	ctx, cancel := context.WithTimeout(authorized(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchUsers(ctx, &userv1.WatchUsersRequest{AfterSequence: 1})
+	if err != nil {
+		t.Fatalf("WatchUsers: %v", err)
+	}
+
+	// El evento retenido llega primero; la suscripción en vivo ya existe.
+	replayed, err := stream.Recv()
+	if err != nil || replayed.GetId() != "b" {
+		t.Fatalf("first event = %v, %v; want the replayed event b", replayed, err)
+	}
+
+	if err := broker.Publish(context.Background(), domain.UserEvent{ID: "c", PublishedSequence: 3, Type: domain.EventUserDeleted}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	live, err := stream.Recv()
+	if err != nil || live.GetId() != "c" || live.GetType() != string(domain.EventUserDeleted) || live.GetPublishedSequence() != 3 {
+		t.Errorf("live event = %v, %v; want c", live, err)
+	}
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AuditHandler maneja las peticiones HTTP de consulta del registro de auditoría.
+// Solo los principales con rol admin o auditor pueden usarlo.
+type AuditHandler struct {
+	auditService application.AuditService
+}
+
+// NewAuditHandler crea una nueva instancia de AuditHandler con el servicio inyectado.
+func NewAuditHandler(service application.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: service}
+}
+
+// History maneja la petición GET /users/{id}/history?limit= que retorna las
+// mutaciones registradas de un usuario.
+func (h *AuditHandler) History(w http.ResponseWriter, r *http.Request) *HTTPError {
+	// 1. Autorización y extracción de parámetros
+	if !canReadAudit(r) {
+		return NewHTTPError(errors.New("audit reader privileges required"), http.StatusForbidden)
+	}
+
+	id := chi.URLParam(r, "id")
+
+	if id == "" {
+		return NewHTTPError(errors.New("user ID is required in the request path or query"), http.StatusBadRequest)
+	}
+
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		return NewHTTPError(err, http.StatusBadRequest)
+	}
+
+	// 2. Llamada al servicio
+	records, err := h.auditService.History(r.Context(), id, limit)
+	if err != nil {
+		return NewHTTPError(errors.New(err.Error()), http.StatusInternalServerError)
+	}
+
+	// 3. Respuesta exitosa (200 OK)
+	return render(w, r, http.StatusOK, records)
+}
+
+// Find maneja la petición GET /audit?actor=&since=&limit= que consulta el
+// registro de auditoría completo.
+func (h *AuditHandler) Find(w http.ResponseWriter, r *http.Request) *HTTPError {
+	// 1. Autorización y construcción del filtro
+	if !canReadAudit(r) {
+		return NewHTTPError(errors.New("audit reader privileges required"), http.StatusForbidden)
+	}
+
+	filter := domain.AuditFilter{Actor: r.URL.Query().Get("actor")}
+
+	var err error
+	if filter.Since, err = queryTime(r, "since"); err != nil {
+		return NewHTTPError(err, http.StatusBadRequest)
+	}
+	if filter.Limit, err = queryInt(r, "limit"); err != nil {
+		return NewHTTPError(err, http.StatusBadRequest)
+	}
+
+	// 2. Llamada al servicio
+	records, err := h.auditService.Find(r.Context(), filter)
+	if err != nil {
+		return NewHTTPError(errors.New(err.Error()), http.StatusInternalServerError)
+	}
+
+	// 3. Respuesta exitosa (200 OK)
+	return render(w, r, http.StatusOK, records)
+}
+
+// canReadAudit indica si el principal autenticado puede leer la auditoría.
+func canReadAudit(r *http.Request) bool {
+	principal, ok := domain.PrincipalFromContext(r.Context())
+	return ok && principal.CanReadAudit()
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-api-restful/internal/domain"
+)
+
+// stubAuditService registra el último filtro consultado.
+type stubAuditService struct {
+	filter domain.AuditFilter
+}
+
+func (s *stubAuditService) History(ctx context.Context, userID string, limit int) ([]domain.AuditRecord, error) {
+	return s.Find(ctx, domain.AuditFilter{UserID: userID, Limit: limit})
+}
+
+func (s *stubAuditService) Find(_ context.Context, filter domain.AuditFilter) ([]domain.AuditRecord, error) {
+	s.filter = filter
+	return []domain.AuditRecord{{ID: "r1", UserID: filter.UserID, Action: domain.AuditActionUpdate}}, nil
+}
+
+// withPrincipal retorna la petición autenticada con los roles indicados.
+func withPrincipal(r *http.Request, roles ...string) *http.Request {
+	return r.WithContext(domain.WithPrincipal(r.Context(), domain.Principal{Subject: "tester", Roles: roles}))
+}
+
+func TestAuditHandlerRequiresAuditReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		roles []string
+		want  int
+	}{
+		{"anonymous", nil, http.StatusForbidden},
+		{"user", []string{"user"}, http.StatusForbidden},
+		{"auditor", []string{domain.RoleAuditor}, http.StatusOK},
+		{"admin", []string{domain.RoleAdmin}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAuditHandler(&stubAuditService{})
+			r := httptest.NewRequest(http.MethodGet, "/audit", nil)
+			if tt.roles != nil {
+				r = withPrincipal(r, tt.roles...)
+			}
+			w := httptest.NewRecorder()
+
+			ErrorHandlerWrapper(handler.Find)(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditHandlerFindParsesFilter(t *testing.T) {
+	service := &stubAuditService{}
+	handler := NewAuditHandler(service)
+
+	r := withPrincipal(httptest.NewRequest(http.MethodGet, "/audit?actor=alice&since=2026-01-02T03:04:05Z&limit=5", nil), domain.RoleAuditor)
+	w := httptest.NewRecorder()
+	ErrorHandlerWrapper(handler.Find)(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body)
+	}
+	if service.filter.Actor != "alice" || service.filter.Limit != 5 || service.filter.Since == nil || service.filter.Since.Year() != 2026 {
+		t.Errorf("filter = %+v", service.filter)
+	}
+
+	var records []domain.AuditRecord
+	if err := json.Unmarshal(w.Body.Bytes(), &records); err != nil || len(records) != 1 {
+		t.Errorf("body = %s (%v)", w.Body, err)
+	}
+
+	r = withPrincipal(httptest.NewRequest(http.MethodGet, "/audit?since=yesterday", nil), domain.RoleAdmin)
+	w = httptest.NewRecorder()
+	ErrorHandlerWrapper(handler.Find)(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid since: status = %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// EmailVerificationHandler maneja las peticiones HTTP de verificación del
+// email de los usuarios.
+type EmailVerificationHandler struct {
+	verificationService *application.EmailVerificationService
+	validator           *validator.Validate
+}
+
+// NewEmailVerificationHandler crea una nueva instancia de EmailVerificationHandler con el servicio inyectado.
+func NewEmailVerificationHandler(service *application.EmailVerificationService) *EmailVerificationHandler {
+	return &EmailVerificationHandler{
+		verificationService: service,
+		validator:           validator.New(),
+	}
+}
+
+// Verify maneja la petición POST /users/{id}/verify-email, que confirma el
+// email del usuario con el token recibido por correo.
+func (h *EmailVerificationHandler) Verify(w http.ResponseWriter, r *http.Request) *HTTPError {
+	// 1. Extracción del parámetro de la URL
+	id := chi.URLParam(r, "id")
+
+	// 2. Deserialización y validación
+	var request domain.EmailVerificationRequest
+	if err := decodeBody(r, &request); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(request); err != nil {
+		return NewHTTPError(errors.New("token is required and must have at most 128 characters"), http.StatusBadRequest)
+	}
+
+	// 3. Llamada al servicio
+	userResponse, err := h.verificationService.Verify(r.Context(), id, request.Token)
+	if err != nil {
+		return mapEmailVerificationError(err)
+	}
+
+	// 4. Respuesta exitosa (200 OK)
+	return render(w, r, http.StatusOK, presentUser(r, userResponse))
+}
+
+// Resend maneja la petición POST /users/{id}:send-verification-email, que
+// emite un nuevo token (los anteriores dejan de ser válidos) y envía el
+// mensaje de verificación.
+func (h *EmailVerificationHandler) Resend(w http.ResponseWriter, r *http.Request) *HTTPError {
+	id := chi.URLParam(r, "id")
+
+	if err := h.verificationService.Resend(r.Context(), id); err != nil {
+		return mapEmailVerificationError(err)
+	}
+
+	// 202 Accepted: el mensaje fue entregado al servidor de correo.
+	w.WriteHeader(http.StatusAccepted)
+
+	return nil
+}
+
+// mapEmailVerificationError traduce los errores de EmailVerificationService a HTTPError.
+func mapEmailVerificationError(err error) *HTTPError {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		return NewHTTPError(errors.New(err.Error()), http.StatusNotFound)
+	case errors.Is(err, domain.ErrEmailAlreadyVerified):
+		return NewHTTPError(errors.New(err.Error()), http.StatusConflict)
+	case errors.Is(err, domain.ErrInvalidVerificationToken):
+		// 422 Unprocessable Entity: el token no es (o ya no es) válido.
+		return NewHTTPError(errors.New(err.Error()), http.StatusUnprocessableEntity)
+	case errors.Is(err, domain.ErrEmailDeliveryFailed):
+		// 503 Service Unavailable: el servidor de correo no aceptó el mensaje.
+		return NewHTTPError(errors.New(err.Error()), http.StatusServiceUnavailable)
+	}
+	return NewHTTPError(errors.New(err.Error()), http.StatusInternalServerError)
+}
@@ -45,23 +45,35 @@ func ErrorHandlerWrapper(handler HandlerFunc) http.HandlerFunc {
 
 		// Si el handler retorna un error, se procesa aquí.
 		if err != nil {
-			statusCode := http.StatusInternalServerError
-			if err.Status != 0 {
-				statusCode = err.Status
-			}
+			writeHTTPError(w, r, err)
+		}
+	}
+}
 
-			// Construye la respuesta de error JSON.
-			response := ErrorResponse{
-				Status:  statusCode,
-				Message: err.Error.Error(), // Usa el mensaje del error envuelto.
-			}
+// writeHTTPError escribe el error como una respuesta ErrorResponse en el
+// formato negociado para la petición (JSON por defecto).
+func writeHTTPError(w http.ResponseWriter, r *http.Request, err *HTTPError) {
+	statusCode := http.StatusInternalServerError
+	if err.Status != 0 {
+		statusCode = err.Status
+	}
 
-			// Establece las cabeceras y escribe el código de estado.
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(statusCode)
+	// Construye la respuesta de error.
+	response := ErrorResponse{
+		Status:  statusCode,
+		Message: err.Error.Error(), // Usa el mensaje del error envuelto.
+	}
 
-			// Codifica y escribe el cuerpo de la respuesta JSON.
-			_ = json.NewEncoder(w).Encode(response)
-		}
+	// Codifica la respuesta en el formato negociado; si falla, recurre a JSON.
+	selected := responseCodec(r)
+	body, encodeErr := encodeBody(selected, response)
+	if encodeErr != nil {
+		selected = codecs[0]
+		body, _ = json.Marshal(response)
 	}
+
+	// Establece las cabeceras y escribe el código de estado.
+	w.Header().Set("Content-Type", selected.mediaType)
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
 }
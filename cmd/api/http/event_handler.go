@@ -0,0 +1,97 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+// sseHeartbeat es el intervalo de los comentarios keep-alive del stream SSE.
+const sseHeartbeat = 15 * time.Second
+
+// EventHandler expone los eventos de usuario como un stream Server-Sent Events.
+type EventHandler struct {
+	feed *application.EventFeed
+}
+
+// NewEventHandler crea una nueva instancia de EventHandler sobre el feed indicado.
+func NewEventHandler(feed *application.EventFeed) *EventHandler {
+	return &EventHandler{feed: feed}
+}
+
+// Stream maneja la petición GET /users/events?types=. Envía cada evento con
+// su PublishedSequence como "id", de modo que el cliente pueda reanudar con la
+// cabecera Last-Event-ID (o el parámetro last_event_id) tras una desconexión.
+func (h *EventHandler) Stream(w http.ResponseWriter, r *http.Request) *HTTPError {
+	// 1. Extracción de parámetros
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return NewHTTPError(errors.New("streaming is not supported"), http.StatusInternalServerError)
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	var afterPublished int64
+	if lastEventID != "" {
+		var err error
+		if afterPublished, err = strconv.ParseInt(lastEventID, 10, 64); err != nil || afterPublished < 0 {
+			return NewHTTPError(errors.New("Last-Event-ID must be a non-negative integer"), http.StatusBadRequest)
+		}
+	}
+
+	var types []domain.EventType
+	if raw := r.URL.Query().Get("types"); raw != "" {
+		var err error
+		if types, err = application.ParseEventTypes(strings.Split(raw, ",")); err != nil {
+			return NewHTTPError(errors.New(err.Error()), http.StatusBadRequest)
+		}
+	}
+
+	// 2. Cabeceras del stream
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	flusher.Flush()
+
+	// 3. Envío de eventos hasta que el cliente se desconecte
+	onEvent := func(event domain.UserEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.PublishedSequence, event.Type, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	onHeartbeat := func() error {
+		if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	err := h.feed.Follow(r.Context(), afterPublished, types, sseHeartbeat, onEvent, onHeartbeat)
+	if err != nil && !errors.Is(err, application.ErrEventStreamLagged) {
+		// La respuesta ya comenzó: solo se registra el error y se cierra el stream.
+		log.Printf("[SSE] stream closed: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/messaging"
+)
+
+// stubOutbox es un registro de eventos publicados fijo. Solo implementa
+// las consultas que usa application.EventFeed.
+type stubOutbox struct {
+	domain.OutboxRepository
+	published []domain.UserEvent
+	tenantID  string
+}
+
+func (s *stubOutbox) FindPublished(tenantID string, afterPublished int64, _ []domain.EventType, _ int) ([]domain.UserEvent, error) {
+	s.tenantID = tenantID
+	events := make([]domain.UserEvent, 0)
+	for _, event := range s.published {
+		if event.PublishedSequence > afterPublished {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func TestEventStreamResumesFromLastEventID(t *testing.T) {
+	outbox := &stubOutbox{published: []domain.UserEvent{
+		{ID: "a", PublishedSequence: 1, Type: domain.EventUserCreated},
+		{ID: "b", PublishedSequence: 2, Type: domain.EventUserUpdated},
+	}}
+	handler := NewEventHandler(application.NewEventFeed(outbox, messaging.NewBroker(1)))
+
+	ctx, cancel := context.WithTimeout(domain.WithTenant(context.Background(), "acme"), 50*time.Millisecond)
+	defer cancel()
+	r := httptest.NewRequest(http.MethodGet, "/users/events", nil).WithContext(ctx)
+	r.Header.Set("Last-Event-ID", "1")
+	w := httptest.NewRecorder()
+
+	ErrorHandlerWrapper(handler.Stream)(w, r)
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status = %d, Content-Type = %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "id: 2\nevent: user.updated\n") || strings.Contains(body, "id: 1\n") {
+		t.Errorf("body = %q, want only the event after Last-Event-ID", body)
+	}
+	if outbox.tenantID != "acme" {
+		t.Errorf("replay queried tenant %q, want acme", outbox.tenantID)
+	}
+}
+
+func TestEventStreamRejectsInvalidParameters(t *testing.T) {
+	handler := NewEventHandler(application.NewEventFeed(&stubOutbox{}, messaging.NewBroker(1)))
+
+	for _, target := range []string{"/users/events?last_event_id=-1", "/users/events?types=user.renamed"} {
+		w := httptest.NewRecorder()
+		ErrorHandlerWrapper(handler.Stream)(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", target, w.Code)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Códigos de error de GraphQL (extensions.code), equivalentes a los códigos
+// HTTP de la API REST.
+const (
+	graphQLBadUserInput     = "BAD_USER_INPUT"
+	graphQLForbidden        = "FORBIDDEN"
+	graphQLNotFound         = "NOT_FOUND"
+	graphQLConflict         = "CONFLICT"
+	graphQLInternal         = "INTERNAL_SERVER_ERROR"
+	graphQLParseFailed      = "GRAPHQL_PARSE_FAILED"
+	graphQLValidationFailed = "GRAPHQL_VALIDATION_FAILED"
+	graphQLQueryTooComplex  = "QUERY_TOO_COMPLEX"
+)
+
+// GraphQLLimits acota el costo de las consultas antes de ejecutarlas.
+type GraphQLLimits struct {
+	// MaxDepth es la máxima profundidad de selecciones anidadas.
+	MaxDepth int
+	// MaxComplexity es el máximo costo estimado: cada campo cuesta 1 y la
+	// selección de users se multiplica por su argumento first.
+	MaxComplexity int
+}
+
+// GraphQLRequest es el cuerpo de POST /graphql.
+type GraphQLRequest struct {
+	Query         string         `json:"query" validate:"required"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// GraphQLResponse es la respuesta de POST /graphql.
+type GraphQLResponse struct {
+	Data   any                        `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// GraphQLHandler expone application.UserService y application.GroupService
+// como endpoint GraphQL.
+type GraphQLHandler struct {
+	userService  application.UserService
+	groupService application.GroupService
+	limits       GraphQLLimits
+	validator    *validator.Validate
+}
+
+// NewGraphQLHandler crea una nueva instancia de GraphQLHandler.
+func NewGraphQLHandler(service application.UserService, groups application.GroupService, limits GraphQLLimits) *GraphQLHandler {
+	return &GraphQLHandler{
+		userService:  service,
+		groupService: groups,
+		limits:       limits,
+		validator:    validator.New(),
+	}
+}
+
+// Execute maneja la petición POST /graphql. Los errores de la consulta
+// (sintaxis, validación, límites y errores de dominio) se responden con
+// 200 OK en la lista errors, cada uno con su código en extensions.code.
+func (h *GraphQLHandler) Execute(w http.ResponseWriter, r *http.Request) *HTTPError {
+	// 1. Deserialización de la petición
+	var request GraphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return NewHTTPError(errors.New("invalid request payload: "+err.Error()), http.StatusBadRequest)
+	}
+	if strings.TrimSpace(request.Query) == "" {
+		return NewHTTPError(errors.New("query is required"), http.StatusBadRequest)
+	}
+
+	// 2. Análisis, validación y control de límites
+	document, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return writeGraphQL(w, GraphQLResponse{Errors: withCode(gqlerrors.FormatErrors(err), graphQLParseFailed)})
+	}
+
+	validation := graphql.ValidateDocument(&graphQLSchema, document, nil)
+	if !validation.IsValid {
+		return writeGraphQL(w, GraphQLResponse{Errors: withCode(validation.Errors, graphQLValidationFailed)})
+	}
+
+	if err := h.checkLimits(document, request.OperationName, request.Variables); err != nil {
+		return writeGraphQL(w, GraphQLResponse{Errors: withCode(gqlerrors.FormatErrors(err), graphQLQueryTooComplex)})
+	}
+
+	// 3. Ejecución con las dependencias de la petición
+	ctx := context.WithValue(r.Context(), graphQLContextKey{}, &graphQLContext{
+		userService:  h.userService,
+		groupService: h.groupService,
+		validator:    h.validator,
+		users:        newUserLoader(r.Context(), h.userService),
+	})
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        graphQLSchema,
+		AST:           document,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       ctx,
+	})
+
+	for i := range result.Errors {
+		if result.Errors[i].Extensions == nil {
+			result.Errors[i].Extensions = graphQLExtensions(result.Errors[i].OriginalError())
+		}
+	}
+
+	return writeGraphQL(w, GraphQLResponse{Data: result.Data, Errors: withCode(result.Errors, graphQLInternal)})
+}
+
+// graphQLExtensions busca las extensions de un error de resolver. El
+// ejecutor envuelve los errores de los thunks (ver userLoader) en errores
+// con formato, que no conservan las extensions del error original.
+func graphQLExtensions(err error) map[string]any {
+	for err != nil {
+		switch wrapped := err.(type) {
+		case gqlerrors.ExtendedError:
+			return wrapped.Extensions()
+		case gqlerrors.FormattedError:
+			err = wrapped.OriginalError()
+		case *gqlerrors.Error:
+			err = wrapped.OriginalError
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// writeGraphQL escribe la respuesta GraphQL como JSON.
+func writeGraphQL(w http.ResponseWriter, response GraphQLResponse) *HTTPError {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("[GraphQL] failed to write response: %v", err)
+	}
+	return nil
+}
+
+// withCode asigna extensions.code a los errores que no lo tienen.
+func withCode(errs []gqlerrors.FormattedError, code string) []gqlerrors.FormattedError {
+	for i := range errs {
+		if errs[i].Extensions == nil {
+			errs[i].Extensions = map[string]any{"code": code}
+		}
+	}
+	return errs
+}
+
+// checkLimits calcula la profundidad y la complejidad de la operación a
+// ejecutar y retorna un error si exceden los límites. Los campos de
+// introspección (__schema, __type) no se cuentan, para no bloquear a las
+// herramientas que consultan el esquema.
+func (h *GraphQLHandler) checkLimits(document *ast.Document, operationName string, variables map[string]any) error {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	var operation *ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	if operation == nil {
+		// La ejecución reporta la operación inexistente.
+		return nil
+	}
+
+	cost := queryCost{fragments: fragments, variables: variables}
+	depth, complexity := cost.selectionSet(operation.SelectionSet)
+
+	if h.limits.MaxDepth > 0 && depth > h.limits.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the maximum of %d", depth, h.limits.MaxDepth)
+	}
+	if h.limits.MaxComplexity > 0 && complexity > h.limits.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the maximum of %d", complexity, h.limits.MaxComplexity)
+	}
+	return nil
+}
+
+// queryCost recorre las selecciones de una operación, resolviendo los fragmentos.
+type queryCost struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+}
+
+// selectionSet retorna la profundidad y la complejidad de un conjunto de selecciones.
+func (c queryCost) selectionSet(set *ast.SelectionSet) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var selectionDepth, selectionComplexity int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			childDepth, childComplexity := c.selectionSet(selection.SelectionSet)
+			selectionDepth = childDepth + 1
+			selectionComplexity = 1 + childComplexity*c.multiplier(selection)
+		case *ast.InlineFragment:
+			selectionDepth, selectionComplexity = c.selectionSet(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := c.fragments[selection.Name.Value]; ok {
+				selectionDepth, selectionComplexity = c.selectionSet(fragment.SelectionSet)
+			}
+		}
+
+		depth = max(depth, selectionDepth)
+		complexity += selectionComplexity
+	}
+
+	return depth, complexity
+}
+
+// multiplier retorna cuántas veces se resuelve la selección de un campo:
+// el argumento first de las conexiones (users) y 1 en los demás campos.
+func (c queryCost) multiplier(field *ast.Field) int {
+	if field.Name.Value != "users" {
+		return 1
+	}
+
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "first" {
+			continue
+		}
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			var first int
+			if _, err := fmt.Sscan(value.Value, &first); err == nil {
+				return min(max(first, 1), graphQLMaxPageSize)
+			}
+		case *ast.Variable:
+			if first, ok := c.variables[value.Name.Value].(float64); ok {
+				return min(max(int(first), 1), graphQLMaxPageSize)
+			}
+		}
+	}
+	return graphQLDefaultPageSize
+}
+
+// graphQLError es un error de un resolver con su código en extensions.code.
+type graphQLError struct {
+	err        error
+	extensions map[string]any
+}
+
+// newGraphQLError crea un graphQLError con el código indicado.
+func newGraphQLError(err error, code string) *graphQLError {
+	return &graphQLError{err: err, extensions: map[string]any{"code": code}}
+}
+
+// Error implementa la interface error.
+func (e *graphQLError) Error() string {
+	return e.err.Error()
+}
+
+// Extensions implementa gqlerrors.ExtendedError.
+func (e *graphQLError) Extensions() map[string]any {
+	return e.extensions
+}
+
+// graphQLErrorFrom traduce un error de dominio al error GraphQL equivalente
+// al código HTTP de la API REST. Los errores de validación incluyen los
+// campos inválidos en extensions.fields; los inesperados se registran en el
+// log y se responden sin su detalle.
+func graphQLErrorFrom(err error) error {
+	var errNotNullable domain.ErrValueNotNullable
+	var validationErrors validator.ValidationErrors
+
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrGroupNotFound):
+		return newGraphQLError(err, graphQLNotFound)
+	case errors.Is(err, domain.ErrIdInUse), errors.Is(err, domain.ErrEmailInUse), errors.Is(err, domain.ErrUsernameInUse),
+		errors.Is(err, domain.ErrGroupNameInUse), errors.Is(err, domain.ErrGroupCycle),
+		errors.Is(err, domain.ErrInvalidStatusTransition):
+		return newGraphQLError(err, graphQLConflict)
+	case errors.Is(err, domain.ErrMemberNotFound), errors.Is(err, domain.ErrInvalidMemberType),
+		errors.Is(err, domain.ErrStatusReasonRequired):
+		return newGraphQLError(err, graphQLBadUserInput)
+	case errors.As(err, &errNotNullable):
+		return newGraphQLError(err, graphQLBadUserInput)
+	case errors.As(err, &validationErrors):
+		fields := make(map[string]any, len(validationErrors))
+		for _, fieldError := range validationErrors {
+			fields[strings.ToLower(fieldError.Field())] = "failed on the '" + fieldError.Tag() + "' rule"
+		}
+		validationErr := newGraphQLError(errors.New("validation failed"), graphQLBadUserInput)
+		validationErr.extensions["fields"] = fields
+		return validationErr
+	default:
+		log.Printf("[GraphQL] internal error: %v", err)
+		return newGraphQLError(errors.New("internal server error"), graphQLInternal)
+	}
+}
@@ -0,0 +1,275 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+// stubGraphQLUsers es un UserService en memoria que registra los filtros
+// de FindAll, para verificar el agrupamiento de las búsquedas por ID.
+type stubGraphQLUsers struct {
+	application.UserService
+	users    map[string]domain.User
+	filters  []domain.UserFilter
+	createFn func(*domain.UserCreateRequest) (*domain.User, error)
+}
+
+func newStubGraphQLUsers(users ...domain.User) *stubGraphQLUsers {
+	service := &stubGraphQLUsers{users: make(map[string]domain.User)}
+	for _, user := range users {
+		service.users[user.ID] = user
+	}
+	return service
+}
+
+func (s *stubGraphQLUsers) FindAll(_ context.Context, filter domain.UserFilter) (*[]domain.User, error) {
+	s.filters = append(s.filters, filter)
+
+	users := make([]domain.User, 0)
+	for _, user := range s.users {
+		if filter.IDs != nil && !containsString(filter.IDs, user.ID) {
+			continue
+		}
+		if user.ID > filter.AfterID {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	if filter.Limit > 0 && len(users) > filter.Limit {
+		users = users[:filter.Limit]
+	}
+	return &users, nil
+}
+
+func (s *stubGraphQLUsers) FindById(_ context.Context, id string, _ ...string) (*domain.User, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (s *stubGraphQLUsers) Create(_ context.Context, request *domain.UserCreateRequest) (*domain.User, error) {
+	if s.createFn != nil {
+		return s.createFn(request)
+	}
+	user := domain.User{ID: "new", Name: request.Name, Username: request.Username, Email: request.Email, Status: domain.UserActive}
+	s.users[user.ID] = user
+	return &user, nil
+}
+
+func (s *stubGraphQLUsers) Update(_ context.Context, user *domain.User) (*domain.User, error) {
+	s.users[user.ID] = *user
+	return user, nil
+}
+
+func (s *stubGraphQLUsers) Delete(_ context.Context, id string) error {
+	if _, ok := s.users[id]; !ok {
+		return domain.ErrUserNotFound
+	}
+	delete(s.users, id)
+	return nil
+}
+
+// containsString indica si values contiene value.
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// graphQLResult es la respuesta de /graphql decodificada para las pruebas.
+type graphQLResult struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// codes retorna los extensions.code de los errores de la respuesta.
+func (r graphQLResult) codes() []string {
+	codes := make([]string, len(r.Errors))
+	for i, err := range r.Errors {
+		codes[i], _ = err.Extensions["code"].(string)
+	}
+	return codes
+}
+
+// executeGraphQL ejecuta la consulta sobre el handler con el principal de roles indicados.
+func executeGraphQL(t *testing.T, handler *GraphQLHandler, query string, variables map[string]any, roles ...string) graphQLResult {
+	t.Helper()
+
+	body, _ := json.Marshal(GraphQLRequest{Query: query, Variables: variables})
+	r := withPrincipal(httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))), roles...)
+	w := httptest.NewRecorder()
+	ErrorHandlerWrapper(handler.Execute)(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d %s", w.Code, w.Body)
+	}
+	var result graphQLResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode %s: %v", w.Body, err)
+	}
+	return result
+}
+
+func TestGraphQLBatchesUserLookups(t *testing.T) {
+	users := newStubGraphQLUsers(domain.User{ID: "u1", Name: "Ann"}, domain.User{ID: "u2", Name: "Bob"})
+	handler := NewGraphQLHandler(users, nil, GraphQLLimits{})
+
+	result := executeGraphQL(t, handler, `{ a: user(id: "u1") { name } b: user(id: "u2") { name } c: user(id: "missing") { name } }`, nil)
+
+	if len(users.filters) != 1 || len(users.filters[0].IDs) != 3 {
+		t.Fatalf("FindAll calls = %+v, want one lookup of the three IDs", users.filters)
+	}
+	if string(result.Data["a"]) != `{"name":"Ann"}` || string(result.Data["b"]) != `{"name":"Bob"}` || string(result.Data["c"]) != "null" {
+		t.Errorf("data = %s %s %s", result.Data["a"], result.Data["b"], result.Data["c"])
+	}
+	if codes := result.codes(); len(codes) != 1 || codes[0] != graphQLNotFound {
+		t.Errorf("error codes = %v, want NOT_FOUND for the missing user", codes)
+	}
+}
+
+func TestGraphQLUsersConnectionPaginates(t *testing.T) {
+	users := newStubGraphQLUsers(domain.User{ID: "u1"}, domain.User{ID: "u2"}, domain.User{ID: "u3"})
+	handler := NewGraphQLHandler(users, nil, GraphQLLimits{})
+	const query = `query($after: String) { users(first: 2, after: $after) { nodes { id } pageInfo { hasNextPage endCursor } } }`
+
+	var connection struct {
+		Nodes    []struct{ ID string }
+		PageInfo struct {
+			HasNextPage bool
+			EndCursor   string
+		}
+	}
+	first := executeGraphQL(t, handler, query, nil)
+	if err := json.Unmarshal(first.Data["users"], &connection); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(connection.Nodes) != 2 || !connection.PageInfo.HasNextPage || connection.PageInfo.EndCursor == "" {
+		t.Fatalf("first page = %+v, want two users and a next page", connection)
+	}
+
+	second := executeGraphQL(t, handler, query, map[string]any{"after": connection.PageInfo.EndCursor})
+	if err := json.Unmarshal(second.Data["users"], &connection); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(connection.Nodes) != 1 || connection.Nodes[0].ID != "u3" || connection.PageInfo.HasNextPage {
+		t.Errorf("second page = %+v, want only u3", connection)
+	}
+
+	invalid := executeGraphQL(t, handler, `{ users(after: "%%%") { nodes { id } } }`, nil)
+	if codes := invalid.codes(); len(codes) != 1 || codes[0] != graphQLBadUserInput {
+		t.Errorf("invalid cursor codes = %v, want BAD_USER_INPUT", codes)
+	}
+}
+
+func TestGraphQLIncludeDeletedRequiresAdmin(t *testing.T) {
+	handler := NewGraphQLHandler(newStubGraphQLUsers(), nil, GraphQLLimits{})
+	const query = `{ users(filter: {includeDeleted: true}) { nodes { id } } }`
+
+	if codes := executeGraphQL(t, handler, query, nil).codes(); len(codes) != 1 || codes[0] != graphQLForbidden {
+		t.Errorf("codes = %v, want FORBIDDEN without the admin role", codes)
+	}
+	if codes := executeGraphQL(t, handler, query, nil, domain.RoleAdmin).codes(); len(codes) != 0 {
+		t.Errorf("codes = %v, want no errors for an admin", codes)
+	}
+}
+
+func TestGraphQLMutations(t *testing.T) {
+	users := newStubGraphQLUsers(domain.User{ID: "u1", Name: "Ann", Username: "ann", Email: "ann@example.com"})
+	handler := NewGraphQLHandler(users, nil, GraphQLLimits{})
+
+	created := executeGraphQL(t, handler, `mutation { createUser(input: {name: "Bob", username: "bob", email: "bob@example.com"}) { id email } }`, nil)
+	if len(created.Errors) != 0 || string(created.Data["createUser"]) != `{"email":"bob@example.com","id":"new"}` {
+		t.Errorf("createUser = %s %v", created.Data["createUser"], created.Errors)
+	}
+
+	updated := executeGraphQL(t, handler, `mutation { updateUser(id: "u1", input: {email: "ann@new.example.com"}) { name email } }`, nil)
+	if string(updated.Data["updateUser"]) != `{"email":"ann@new.example.com","name":"Ann"}` {
+		t.Errorf("updateUser = %s %v, want only the email changed", updated.Data["updateUser"], updated.Errors)
+	}
+
+	deleted := executeGraphQL(t, handler, `mutation { deleteUser(id: "u1") }`, nil)
+	if string(deleted.Data["deleteUser"]) != `"u1"` {
+		t.Errorf("deleteUser = %s %v", deleted.Data["deleteUser"], deleted.Errors)
+	}
+	if codes := executeGraphQL(t, handler, `mutation { deleteUser(id: "u1") }`, nil).codes(); len(codes) != 1 || codes[0] != graphQLNotFound {
+		t.Errorf("second deleteUser codes = %v, want NOT_FOUND", codes)
+	}
+}
+
+func TestGraphQLMapsDomainErrors(t *testing.T) {
+	users := newStubGraphQLUsers()
+	handler := NewGraphQLHandler(users, nil, GraphQLLimits{})
+
+	invalid := executeGraphQL(t, handler, `mutation { createUser(input: {name: "Bob", username: "bob", email: "not-an-email"}) { id } }`, nil)
+	if codes := invalid.codes(); len(codes) != 1 || codes[0] != graphQLBadUserInput {
+		t.Fatalf("codes = %v, want BAD_USER_INPUT", codes)
+	}
+	if fields, _ := invalid.Errors[0].Extensions["fields"].(map[string]any); fields["email"] == nil {
+		t.Errorf("extensions = %v, want the invalid email field", invalid.Errors[0].Extensions)
+	}
+
+	users.createFn = func(*domain.UserCreateRequest) (*domain.User, error) { return nil, domain.ErrEmailInUse }
+	conflict := executeGraphQL(t, handler, `mutation { createUser(input: {name: "Bob", username: "bob", email: "bob@example.com"}) { id } }`, nil)
+	if codes := conflict.codes(); len(codes) != 1 || codes[0] != graphQLConflict {
+		t.Errorf("codes = %v, want CONFLICT", codes)
+	}
+}
+
+func TestGraphQLRejectsInvalidAndExpensiveQueries(t *testing.T) {
+	handler := NewGraphQLHandler(newStubGraphQLUsers(), nil, GraphQLLimits{MaxDepth: 3, MaxComplexity: 50})
+
+	tests := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{name: "syntax error", query: `{ user(id: "u1") { name }`, code: graphQLParseFailed},
+		{name: "unknown field", query: `{ user(id: "u1") { password } }`, code: graphQLValidationFailed},
+		{name: "too deep", query: `{ users { edges { node { groups { name } } } } }`, code: graphQLQueryTooComplex},
+		{name: "too complex", query: `{ users(first: 100) { nodes { id name } } }`, code: graphQLQueryTooComplex},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executeGraphQL(t, handler, tt.query, nil)
+			if codes := result.codes(); len(codes) == 0 || codes[0] != tt.code || result.Data != nil {
+				t.Errorf("codes = %v, data = %v, want %s without data", codes, result.Data, tt.code)
+			}
+		})
+	}
+
+	// La introspección no cuenta para los límites.
+	if result := executeGraphQL(t, handler, `{ __schema { types { name fields { name type { name } } } } }`, nil); len(result.Errors) != 0 {
+		t.Errorf("introspection errors = %v", result.Errors)
+	}
+}
+
+func TestQueryCostCountsFragmentsAndFirst(t *testing.T) {
+	handler := NewGraphQLHandler(newStubGraphQLUsers(), nil, GraphQLLimits{MaxComplexity: 21})
+
+	// users (1) + 10 × (nodes (1) + id (1)) = 21
+	within := executeGraphQL(t, handler, `query { users(first: 10) { ...page } } fragment page on UserConnection { nodes { id } }`, nil)
+	if len(within.Errors) != 0 {
+		t.Errorf("complexity 21 rejected: %v", within.Errors)
+	}
+
+	beyond := executeGraphQL(t, handler, `query($first: Int) { users(first: $first) { ...page } } fragment page on UserConnection { nodes { id } }`, map[string]any{"first": 11})
+	if codes := beyond.codes(); len(codes) != 1 || codes[0] != graphQLQueryTooComplex {
+		t.Errorf("complexity 23 codes = %v, want QUERY_TOO_COMPLEX", codes)
+	}
+}
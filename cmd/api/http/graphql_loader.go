@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"sync"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+// userLoader agrupa las búsquedas de usuarios por ID de una petición GraphQL
+// (patrón DataLoader). load registra el ID y retorna un thunk: el ejecutor
+// resuelve primero todos los campos de un nivel y luego los thunks, de modo
+// que el primero que se evalúa busca en una sola consulta todos los IDs
+// pendientes. Los resultados se conservan durante la petición.
+type userLoader struct {
+	ctx     context.Context
+	service application.UserService
+
+	mu      sync.Mutex
+	pending []string
+	loaded  map[string]*domain.User
+	err     error
+}
+
+// newUserLoader crea un userLoader para la petición con el contexto indicado.
+func newUserLoader(ctx context.Context, service application.UserService) *userLoader {
+	return &userLoader{ctx: ctx, service: service, loaded: make(map[string]*domain.User)}
+}
+
+// load registra la búsqueda del usuario id y retorna el thunk que la resuelve.
+func (l *userLoader) load(id string) func() (any, error) {
+	l.mu.Lock()
+	if _, ok := l.loaded[id]; !ok {
+		l.pending = append(l.pending, id)
+	}
+	l.mu.Unlock()
+
+	return func() (any, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.flush()
+		if l.err != nil {
+			return nil, graphQLErrorFrom(l.err)
+		}
+
+		user := l.loaded[id]
+		if user == nil {
+			return nil, graphQLErrorFrom(domain.ErrUserNotFound)
+		}
+		return user, nil
+	}
+}
+
+// prime agrega al loader un usuario ya leído (e.g., por users), para que
+// las búsquedas posteriores del mismo ID no consulten el servicio.
+func (l *userLoader) prime(user *domain.User) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.loaded[user.ID] = user
+}
+
+// flush busca en una sola consulta los IDs pendientes. Los IDs inexistentes
+// se registran como nil. Debe llamarse con mu tomado.
+func (l *userLoader) flush() {
+	if len(l.pending) == 0 {
+		return
+	}
+
+	ids := l.pending
+	l.pending = nil
+
+	users, err := l.service.FindAll(l.ctx, domain.UserFilter{IDs: ids})
+	if err != nil {
+		l.err = err
+		return
+	}
+
+	for _, id := range ids {
+		l.loaded[id] = nil
+	}
+	for i := range *users {
+		user := &(*users)[i]
+		l.loaded[user.ID] = user
+	}
+}
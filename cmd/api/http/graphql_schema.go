@@ -0,0 +1,646 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/graphql-go/graphql"
+)
+
+// graphQLDefaultPageSize y graphQLMaxPageSize acotan el argumento first de users.
+const (
+	graphQLDefaultPageSize = 50
+	graphQLMaxPageSize     = 500
+)
+
+// graphQLContextKey es la clave privada del graphQLContext en el context.Context.
+type graphQLContextKey struct{}
+
+// graphQLContext son las dependencias de los resolvers durante una petición:
+// los servicios y el loader que agrupa las búsquedas por ID de la petición.
+type graphQLContext struct {
+	userService  application.UserService
+	groupService application.GroupService
+	validator    *validator.Validate
+	users        *userLoader
+}
+
+// resolverContext retorna el graphQLContext de la petición en ejecución.
+func resolverContext(p graphql.ResolveParams) *graphQLContext {
+	return p.Context.Value(graphQLContextKey{}).(*graphQLContext)
+}
+
+// graphQLUserStatus y graphQLUserStatusAction son los estados y las
+// acciones del ciclo de vida de la cuenta, con los valores de la API REST.
+var (
+	graphQLUserStatus       = graphQLEnum("UserStatus", "Account status of a user.", domain.UserStatuses)
+	graphQLUserStatusAction = graphQLEnum("UserStatusAction", "Account lifecycle action.", domain.UserStatusActions)
+)
+
+// graphQLEnum define un enum cuyos valores son los de values, con el mismo
+// nombre en el esquema y en el dominio. Los argumentos se reciben como string.
+func graphQLEnum[T ~string](name, description string, values []T) *graphql.Enum {
+	enumValues := make(graphql.EnumValueConfigMap, len(values))
+	for _, value := range values {
+		enumValues[string(value)] = &graphql.EnumValueConfig{Value: string(value)}
+	}
+	return graphql.NewEnum(graphql.EnumConfig{Name: name, Description: description, Values: enumValues})
+}
+
+// graphQLUserType es el tipo User del esquema GraphQL.
+var graphQLUserType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "User",
+	Description: "A user of the system.",
+	Fields: graphql.Fields{
+		"id":        userField(graphql.NewNonNull(graphql.ID), func(u *domain.User) any { return u.ID }),
+		"name":      userField(graphql.NewNonNull(graphql.String), func(u *domain.User) any { return u.Name }),
+		"username":  userField(graphql.NewNonNull(graphql.String), func(u *domain.User) any { return u.Username }),
+		"email":     userField(graphql.NewNonNull(graphql.String), func(u *domain.User) any { return u.Email }),
+		"createdAt": userField(graphql.NewNonNull(graphql.DateTime), func(u *domain.User) any { return u.CreatedAt }),
+		"updatedAt": userField(graphql.NewNonNull(graphql.DateTime), func(u *domain.User) any { return u.UpdatedAt }),
+		"createdBy": userField(graphql.NewNonNull(graphql.String), func(u *domain.User) any { return u.CreatedBy }),
+		"updatedBy": userField(graphql.NewNonNull(graphql.String), func(u *domain.User) any { return u.UpdatedBy }),
+		"deletedAt": userField(graphql.DateTime, func(u *domain.User) any {
+			if u.DeletedAt == nil {
+				return nil
+			}
+			return *u.DeletedAt
+		}),
+		"status": userField(graphql.NewNonNull(graphQLUserStatus), func(u *domain.User) any { return string(u.Status) }),
+		"statusReason": userField(graphql.String, func(u *domain.User) any {
+			if u.StatusReason == "" {
+				return nil
+			}
+			return u.StatusReason
+		}),
+		"statusChangedAt": userField(graphql.DateTime, func(u *domain.User) any {
+			if u.StatusChangedAt == nil {
+				return nil
+			}
+			return *u.StatusChangedAt
+		}),
+		"emailVerified": userField(graphql.NewNonNull(graphql.Boolean), func(u *domain.User) any { return u.EmailVerified }),
+		"emailVerifiedAt": userField(graphql.DateTime, func(u *domain.User) any {
+			if u.EmailVerifiedAt == nil {
+				return nil
+			}
+			return *u.EmailVerifiedAt
+		}),
+		"groups": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLGroupType))),
+			Description: "Groups the user belongs to; with transitive, also through nested groups.",
+			Args: graphql.FieldConfigArgument{
+				"transitive": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+			},
+			Resolve: resolveUserGroups,
+		},
+	},
+})
+
+// graphQLGroupMemberType es un miembro de un grupo: un usuario o un grupo anidado.
+var graphQLGroupMemberType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "GroupMember",
+	Fields: graphql.Fields{
+		"type":    &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "user or group."},
+		"id":      &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"display": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "Username of a user or name of a group."},
+	},
+})
+
+// graphQLGroupType es el tipo Group del esquema GraphQL.
+var graphQLGroupType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Group",
+	Description: "A group of users and nested groups.",
+	Fields: graphql.Fields{
+		"id":          groupField(graphql.NewNonNull(graphql.ID), func(g *domain.Group) any { return g.ID }),
+		"name":        groupField(graphql.NewNonNull(graphql.String), func(g *domain.Group) any { return g.Name }),
+		"description": groupField(graphql.NewNonNull(graphql.String), func(g *domain.Group) any { return g.Description }),
+		"createdAt":   groupField(graphql.NewNonNull(graphql.DateTime), func(g *domain.Group) any { return g.CreatedAt }),
+		"updatedAt":   groupField(graphql.NewNonNull(graphql.DateTime), func(g *domain.Group) any { return g.UpdatedAt }),
+		"members": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLGroupMemberType))),
+			Description: "Direct members or, with transitive, every user member through nested groups.",
+			Args: graphql.FieldConfigArgument{
+				"transitive": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+			},
+			Resolve: resolveGroupMembers,
+		},
+	},
+})
+
+// groupField define un campo de Group que lee el valor indicado del grupo de dominio.
+func groupField(fieldType graphql.Output, value func(*domain.Group) any) *graphql.Field {
+	return &graphql.Field{
+		Type: fieldType,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return value(p.Source.(*domain.Group)), nil
+		},
+	}
+}
+
+// userField define un campo de User que lee el valor indicado del usuario de dominio.
+func userField(fieldType graphql.Output, value func(*domain.User) any) *graphql.Field {
+	return &graphql.Field{
+		Type: fieldType,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return value(p.Source.(*domain.User)), nil
+		},
+	}
+}
+
+// graphQLUserEdge, graphQLPageInfo y graphQLUserConnection implementan la
+// paginación por cursores (Relay) de users. El cursor es el ID codificado.
+var (
+	graphQLUserEdge = graphql.NewObject(graphql.ObjectConfig{
+		Name: "UserEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: graphql.NewNonNull(graphQLUserType)},
+		},
+	})
+
+	graphQLPageInfo = graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"endCursor":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	graphQLUserConnection = graphql.NewObject(graphql.ObjectConfig{
+		Name: "UserConnection",
+		Fields: graphql.Fields{
+			"edges":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLUserEdge)))},
+			"nodes":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLUserType)))},
+			"pageInfo": &graphql.Field{Type: graphql.NewNonNull(graphQLPageInfo)},
+		},
+	})
+)
+
+// graphQLUserFilter es el filtro de users, equivalente a los parámetros de GET /users.
+var graphQLUserFilter = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "UserFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"includeDeleted": &graphql.InputObjectFieldConfig{Type: graphql.Boolean, Description: "Include soft-deleted users (admin only)."},
+		"createdAfter":   &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+		"createdBefore":  &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+		"updatedAfter":   &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+		"updatedBefore":  &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+		"status":         &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphQLUserStatus)), Description: "Only users in any of these statuses."},
+	},
+})
+
+// graphQLCreateUserInput y graphQLUpdateUserInput son los datos de las
+// mutaciones; en la actualización, los campos omitidos no se modifican.
+var (
+	graphQLCreateUserInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateUserInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"username": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"email":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"status":   &graphql.InputObjectFieldConfig{Type: graphQLUserStatus, Description: "pending or active (default)."},
+		},
+	})
+
+	graphQLUpdateUserInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UpdateUserInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"username": &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"email":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+)
+
+// graphQLCreateGroupInput y graphQLMemberInput son los datos de las
+// mutaciones de grupos.
+var (
+	graphQLCreateGroupInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateGroupInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"description": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	graphQLMemberInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "MemberInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"type": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String), Description: "user or group."},
+			"id":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.ID)},
+		},
+	})
+)
+
+// graphQLSchema es el esquema de /graphql sobre application.UserService y
+// application.GroupService.
+var graphQLSchema = func() graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"user": &graphql.Field{
+					Type:        graphQLUserType,
+					Description: "Retrieve a user by ID.",
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					},
+					Resolve: resolveUser,
+				},
+				"users": &graphql.Field{
+					Type:        graphql.NewNonNull(graphQLUserConnection),
+					Description: "Page through users in ID order.",
+					Args: graphql.FieldConfigArgument{
+						"filter": &graphql.ArgumentConfig{Type: graphQLUserFilter},
+						"first":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: graphQLDefaultPageSize},
+						"after":  &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: resolveUsers,
+				},
+				"group": &graphql.Field{
+					Type:        graphQLGroupType,
+					Description: "Retrieve a group by ID.",
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					},
+					Resolve: resolveGroup,
+				},
+				"groups": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLGroupType))),
+					Description: "List groups in name order.",
+					Resolve:     resolveGroups,
+				},
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"createUser": &graphql.Field{
+					Type: graphql.NewNonNull(graphQLUserType),
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphQLCreateUserInput)},
+					},
+					Resolve: resolveCreateUser,
+				},
+				"updateUser": &graphql.Field{
+					Type: graphql.NewNonNull(graphQLUserType),
+					Args: graphql.FieldConfigArgument{
+						"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphQLUpdateUserInput)},
+					},
+					Resolve: resolveUpdateUser,
+				},
+				"deleteUser": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.ID),
+					Description: "Soft-delete a user; returns its ID.",
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					},
+					Resolve: resolveDeleteUser,
+				},
+				"changeUserStatus": &graphql.Field{
+					Type:        graphql.NewNonNull(graphQLUserType),
+					Description: "Apply an account lifecycle action (admin only); suspend, lock and deactivate require a reason.",
+					Args: graphql.FieldConfigArgument{
+						"id":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+						"action": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphQLUserStatusAction)},
+						"reason": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: resolveChangeUserStatus,
+				},
+				"createGroup": &graphql.Field{
+					Type:        graphql.NewNonNull(graphQLGroupType),
+					Description: "Create a group (admin only).",
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphQLCreateGroupInput)},
+					},
+					Resolve: resolveCreateGroup,
+				},
+				"changeGroupMembers": &graphql.Field{
+					Type:        graphql.NewNonNull(graphQLGroupType),
+					Description: "Add and remove members in a single transaction (admin only).",
+					Args: graphql.FieldConfigArgument{
+						"id":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+						"add":    &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphQLMemberInput))},
+						"remove": &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphQLMemberInput))},
+					},
+					Resolve: resolveChangeGroupMembers,
+				},
+			},
+		}),
+	})
+	if err != nil {
+		panic(fmt.Sprintf("graphql: invalid schema: %v", err))
+	}
+	return schema
+}()
+
+// resolveUser resuelve user(id) con el loader, que agrupa en una sola
+// consulta todas las búsquedas por ID del mismo nivel de la petición.
+func resolveUser(p graphql.ResolveParams) (any, error) {
+	id, _ := p.Args["id"].(string)
+	return resolverContext(p).users.load(id), nil
+}
+
+// resolveUsers resuelve users(filter, first, after).
+func resolveUsers(p graphql.ResolveParams) (any, error) {
+	rc := resolverContext(p)
+
+	// 1. Construcción del filtro
+	first, _ := p.Args["first"].(int)
+	if first < 0 {
+		return nil, newGraphQLError(errors.New("first must not be negative"), graphQLBadUserInput)
+	}
+	first = min(first, graphQLMaxPageSize)
+
+	var filter domain.UserFilter
+	if raw, ok := p.Args["filter"].(map[string]any); ok {
+		filter.IncludeDeleted, _ = raw["includeDeleted"].(bool)
+		filter.CreatedAfter = inputTime(raw["createdAfter"])
+		filter.CreatedBefore = inputTime(raw["createdBefore"])
+		filter.UpdatedAfter = inputTime(raw["updatedAfter"])
+		filter.UpdatedBefore = inputTime(raw["updatedBefore"])
+		statuses, _ := raw["status"].([]any)
+		for _, status := range statuses {
+			if status, ok := status.(string); ok {
+				filter.Statuses = append(filter.Statuses, domain.UserStatus(status))
+			}
+		}
+	}
+	if filter.IncludeDeleted && !principalIsAdmin(p.Context) {
+		return nil, newGraphQLError(errors.New("admin privileges required to include deleted users"), graphQLForbidden)
+	}
+
+	if after, _ := p.Args["after"].(string); after != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(after)
+		if err != nil || len(decoded) == 0 {
+			return nil, newGraphQLError(errors.New("after is not a valid cursor"), graphQLBadUserInput)
+		}
+		filter.AfterID = string(decoded)
+	}
+
+	// Se lee un usuario adicional para saber si hay otra página.
+	filter.Limit = first + 1
+
+	// 2. Llamada al servicio
+	users, err := rc.userService.FindAll(p.Context, filter)
+	if err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	// 3. Armado de la conexión
+	page := *users
+	hasNextPage := len(page) > first
+	if hasNextPage {
+		page = page[:first]
+	}
+
+	edges := make([]map[string]any, len(page))
+	nodes := make([]*domain.User, len(page))
+	var endCursor any
+	for i := range page {
+		user := &page[i]
+		rc.users.prime(user)
+
+		cursor := base64.RawURLEncoding.EncodeToString([]byte(user.ID))
+		edges[i] = map[string]any{"cursor": cursor, "node": user}
+		nodes[i] = user
+		endCursor = cursor
+	}
+
+	return map[string]any{
+		"edges":    edges,
+		"nodes":    nodes,
+		"pageInfo": map[string]any{"hasNextPage": hasNextPage, "endCursor": endCursor},
+	}, nil
+}
+
+// resolveCreateUser crea un usuario, con las mismas validaciones que POST /users.
+func resolveCreateUser(p graphql.ResolveParams) (any, error) {
+	rc := resolverContext(p)
+
+	input, _ := p.Args["input"].(map[string]any)
+	request := domain.UserCreateRequest{}
+	request.Name, _ = input["name"].(string)
+	request.Username, _ = input["username"].(string)
+	request.Email, _ = input["email"].(string)
+	if status, ok := input["status"].(string); ok {
+		request.Status = domain.UserStatus(status)
+	}
+
+	if err := rc.validator.Struct(request); err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	user, err := rc.userService.Create(p.Context, &request)
+	if err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	return user, nil
+}
+
+// resolveUpdateUser aplica los campos presentes en input al usuario y lo
+// persiste como PUT /users.
+func resolveUpdateUser(p graphql.ResolveParams) (any, error) {
+	rc := resolverContext(p)
+
+	// 1. Lectura del estado actual y aplicación de los cambios
+	id, _ := p.Args["id"].(string)
+	user, err := rc.userService.FindById(p.Context, id)
+	if err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	input, _ := p.Args["input"].(map[string]any)
+	for field, target := range map[string]*string{"name": &user.Name, "username": &user.Username, "email": &user.Email} {
+		if value, ok := input[field].(string); ok {
+			*target = value
+		}
+	}
+
+	// 2. Validación del resultado
+	request := domain.UserCreateRequest{Name: user.Name, Username: user.Username, Email: user.Email}
+	if err := rc.validator.Struct(request); err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	// 3. Llamada al servicio
+	updated, err := rc.userService.Update(p.Context, user)
+	if err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	return updated, nil
+}
+
+// resolveDeleteUser elimina lógicamente un usuario.
+func resolveDeleteUser(p graphql.ResolveParams) (any, error) {
+	id, _ := p.Args["id"].(string)
+	if err := resolverContext(p).userService.Delete(p.Context, id); err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+	return id, nil
+}
+
+// resolveChangeUserStatus aplica una acción del ciclo de vida de la cuenta,
+// como POST /users/{id}:<acción>.
+func resolveChangeUserStatus(p graphql.ResolveParams) (any, error) {
+	rc := resolverContext(p)
+	if !principalIsAdmin(p.Context) {
+		return nil, newGraphQLError(errStatusAdminRequired, graphQLForbidden)
+	}
+
+	id, _ := p.Args["id"].(string)
+	action, _ := p.Args["action"].(string)
+	request := domain.UserStatusChangeRequest{}
+	request.Reason, _ = p.Args["reason"].(string)
+	if err := rc.validator.Struct(request); err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	user, err := rc.userService.ChangeStatus(p.Context, id, domain.UserStatusAction(action), request.Reason)
+	if err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	return user, nil
+}
+
+// resolveGroup resuelve group(id).
+func resolveGroup(p graphql.ResolveParams) (any, error) {
+	id, _ := p.Args["id"].(string)
+	group, err := resolverContext(p).groupService.FindById(p.Context, id)
+	if errors.Is(err, domain.ErrGroupNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+	return group, nil
+}
+
+// resolveGroups resuelve groups.
+func resolveGroups(p graphql.ResolveParams) (any, error) {
+	groups, err := resolverContext(p).groupService.FindAll(p.Context)
+	if err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+	return groupPointers(groups), nil
+}
+
+// resolveGroupMembers resuelve Group.members(transitive).
+func resolveGroupMembers(p graphql.ResolveParams) (any, error) {
+	transitive, _ := p.Args["transitive"].(bool)
+	members, err := resolverContext(p).groupService.Members(p.Context, p.Source.(*domain.Group).ID, transitive)
+	if err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+	return members, nil
+}
+
+// resolveUserGroups resuelve User.groups(transitive).
+func resolveUserGroups(p graphql.ResolveParams) (any, error) {
+	transitive, _ := p.Args["transitive"].(bool)
+	groups, err := resolverContext(p).groupService.GroupsOfUser(p.Context, p.Source.(*domain.User).ID, transitive)
+	if err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+	return groupPointers(groups), nil
+}
+
+// resolveCreateGroup crea un grupo, con las mismas validaciones que POST /groups.
+func resolveCreateGroup(p graphql.ResolveParams) (any, error) {
+	rc := resolverContext(p)
+	if !principalIsAdmin(p.Context) {
+		return nil, newGraphQLError(errGroupAdminRequired, graphQLForbidden)
+	}
+
+	input, _ := p.Args["input"].(map[string]any)
+	request := domain.GroupCreateRequest{}
+	request.Name, _ = input["name"].(string)
+	request.Description, _ = input["description"].(string)
+
+	if err := rc.validator.Struct(request); err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	group, err := rc.groupService.Create(p.Context, &request)
+	if err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	return group, nil
+}
+
+// resolveChangeGroupMembers aplica los cambios de membresía como
+// PATCH /groups/{id}/members y retorna el grupo.
+func resolveChangeGroupMembers(p graphql.ResolveParams) (any, error) {
+	rc := resolverContext(p)
+	if !principalIsAdmin(p.Context) {
+		return nil, newGraphQLError(errGroupAdminRequired, graphQLForbidden)
+	}
+
+	id, _ := p.Args["id"].(string)
+	change := domain.GroupMembershipChange{Add: inputMembers(p.Args["add"]), Remove: inputMembers(p.Args["remove"])}
+	if err := rc.validator.Struct(change); err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	if _, err := rc.groupService.ChangeMembers(p.Context, id, &change); err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	group, err := rc.groupService.FindById(p.Context, id)
+	if err != nil {
+		return nil, graphQLErrorFrom(err)
+	}
+
+	return group, nil
+}
+
+// inputMembers convierte una lista opcional de MemberInput en referencias de dominio.
+func inputMembers(value any) []domain.MemberRef {
+	list, _ := value.([]any)
+	refs := make([]domain.MemberRef, 0, len(list))
+	for _, item := range list {
+		member, _ := item.(map[string]any)
+		ref := domain.MemberRef{}
+		if memberType, ok := member["type"].(string); ok {
+			ref.Type = domain.MemberType(memberType)
+		}
+		ref.ID, _ = member["id"].(string)
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// groupPointers retorna punteros a los grupos, como espera el tipo Group.
+func groupPointers(groups []domain.Group) []*domain.Group {
+	pointers := make([]*domain.Group, len(groups))
+	for i := range groups {
+		pointers[i] = &groups[i]
+	}
+	return pointers
+}
+
+// inputTime convierte un DateTime opcional de un input en *time.Time.
+func inputTime(value any) *time.Time {
+	if t, ok := value.(time.Time); ok {
+		return &t
+	}
+	return nil
+}
+
+// principalIsAdmin indica si el principal autenticado del contexto es administrador.
+func principalIsAdmin(ctx context.Context) bool {
+	principal, ok := domain.PrincipalFromContext(ctx)
+	return ok && principal.IsAdmin()
+}
@@ -0,0 +1,193 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// errGroupAdminRequired es el error retornado cuando un principal sin
+// privilegios de administrador intenta modificar grupos.
+var errGroupAdminRequired = errors.New("admin privileges required to manage groups")
+
+// GroupHandler maneja las peticiones HTTP de grupos y membresías. Las
+// consultas están abiertas a todo principal autenticado; las modificaciones
+// requieren privilegios de administrador.
+type GroupHandler struct {
+	groupService application.GroupService
+	validator    *validator.Validate
+}
+
+// NewGroupHandler crea una nueva instancia de GroupHandler con el servicio inyectado.
+func NewGroupHandler(service application.GroupService) *GroupHandler {
+	return &GroupHandler{
+		groupService: service,
+		validator:    validator.New(),
+	}
+}
+
+// Create maneja la petición POST /groups.
+func (h *GroupHandler) Create(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errGroupAdminRequired, http.StatusForbidden)
+	}
+
+	// 1. Deserialización y validación
+	var request domain.GroupCreateRequest
+	if err := decodeBody(r, &request); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(request); err != nil {
+		return NewHTTPError(errors.New("name is required (max 255 characters) and description has at most 1024 characters"), http.StatusBadRequest)
+	}
+
+	// 2. Llamada al servicio
+	group, err := h.groupService.Create(r.Context(), &request)
+	if err != nil {
+		return mapGroupError(err)
+	}
+
+	// 3. Respuesta exitosa (201 Created)
+	w.Header().Set("Location", "/groups/"+group.ID)
+	return render(w, r, http.StatusCreated, group)
+}
+
+// FindAll maneja la petición GET /groups.
+func (h *GroupHandler) FindAll(w http.ResponseWriter, r *http.Request) *HTTPError {
+	groups, err := h.groupService.FindAll(r.Context())
+	if err != nil {
+		return mapGroupError(err)
+	}
+
+	return render(w, r, http.StatusOK, groups)
+}
+
+// FindById maneja la petición GET /groups/{id}.
+func (h *GroupHandler) FindById(w http.ResponseWriter, r *http.Request) *HTTPError {
+	group, err := h.groupService.FindById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return mapGroupError(err)
+	}
+
+	return render(w, r, http.StatusOK, group)
+}
+
+// Update maneja la petición PATCH /groups/{id}.
+func (h *GroupHandler) Update(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errGroupAdminRequired, http.StatusForbidden)
+	}
+
+	// 1. Deserialización y validación
+	var request domain.GroupUpdateRequest
+	if err := decodeBody(r, &request); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(request); err != nil {
+		return NewHTTPError(errors.New("name must have 1 to 255 characters and description at most 1024"), http.StatusBadRequest)
+	}
+
+	// 2. Llamada al servicio
+	group, err := h.groupService.Update(r.Context(), chi.URLParam(r, "id"), &request)
+	if err != nil {
+		return mapGroupError(err)
+	}
+
+	// 3. Respuesta exitosa (200 OK)
+	return render(w, r, http.StatusOK, group)
+}
+
+// Delete maneja la petición DELETE /groups/{id}. Los miembros no se
+// eliminan; solo pierden la membresía.
+func (h *GroupHandler) Delete(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errGroupAdminRequired, http.StatusForbidden)
+	}
+
+	if err := h.groupService.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		return mapGroupError(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+// Members maneja la petición GET /groups/{id}/members?transitive=. Con
+// transitive=true retorna los usuarios miembros a cualquier profundidad.
+func (h *GroupHandler) Members(w http.ResponseWriter, r *http.Request) *HTTPError {
+	transitive, err := queryBool(r, "transitive")
+	if err != nil {
+		return NewHTTPError(err, http.StatusBadRequest)
+	}
+
+	members, err := h.groupService.Members(r.Context(), chi.URLParam(r, "id"), transitive)
+	if err != nil {
+		return mapGroupError(err)
+	}
+
+	return render(w, r, http.StatusOK, members)
+}
+
+// ChangeMembers maneja la petición PATCH /groups/{id}/members, que agrega y
+// quita miembros de forma atómica y retorna los miembros directos resultantes.
+func (h *GroupHandler) ChangeMembers(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errGroupAdminRequired, http.StatusForbidden)
+	}
+
+	// 1. Deserialización y validación
+	var request domain.GroupMembershipChange
+	if err := decodeBody(r, &request); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(request); err != nil {
+		return NewHTTPError(errors.New("each member requires a type (user or group) and an id"), http.StatusBadRequest)
+	}
+
+	// 2. Llamada al servicio
+	members, err := h.groupService.ChangeMembers(r.Context(), chi.URLParam(r, "id"), &request)
+	if err != nil {
+		return mapGroupError(err)
+	}
+
+	// 3. Respuesta exitosa (200 OK)
+	return render(w, r, http.StatusOK, members)
+}
+
+// UserGroups maneja la petición GET /users/{id}/groups?transitive=. Con
+// transitive=true incluye los grupos que contienen al usuario a través de
+// grupos anidados.
+func (h *GroupHandler) UserGroups(w http.ResponseWriter, r *http.Request) *HTTPError {
+	transitive, err := queryBool(r, "transitive")
+	if err != nil {
+		return NewHTTPError(err, http.StatusBadRequest)
+	}
+
+	groups, err := h.groupService.GroupsOfUser(r.Context(), chi.URLParam(r, "id"), transitive)
+	if err != nil {
+		return mapGroupError(err)
+	}
+
+	return render(w, r, http.StatusOK, groups)
+}
+
+// mapGroupError traduce los errores de dominio de grupos a HTTP.
+func mapGroupError(err error) *HTTPError {
+	switch {
+	case errors.Is(err, domain.ErrGroupNotFound), errors.Is(err, domain.ErrUserNotFound):
+		return NewHTTPError(errors.New(err.Error()), http.StatusNotFound)
+	case errors.Is(err, domain.ErrGroupNameInUse), errors.Is(err, domain.ErrGroupCycle):
+		return NewHTTPError(errors.New(err.Error()), http.StatusConflict)
+	case errors.Is(err, domain.ErrMemberNotFound):
+		return NewHTTPError(errors.New(err.Error()), http.StatusUnprocessableEntity)
+	case errors.Is(err, domain.ErrInvalidMemberType), errors.As(err, new(domain.ErrValueNotNullable)):
+		return NewHTTPError(errors.New(err.Error()), http.StatusBadRequest)
+	default:
+		return NewHTTPError(errors.New(err.Error()), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,178 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+// stubGroupService registra el último cambio de membresía y la bandera
+// transitive, y retorna err en cada operación si está definido.
+type stubGroupService struct {
+	application.GroupService
+	err        error
+	change     *domain.GroupMembershipChange
+	transitive bool
+}
+
+func (s *stubGroupService) Create(_ context.Context, request *domain.GroupCreateRequest) (*domain.Group, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &domain.Group{ID: "g1", Name: request.Name}, nil
+}
+
+func (s *stubGroupService) Delete(context.Context, string) error {
+	return s.err
+}
+
+func (s *stubGroupService) Members(_ context.Context, _ string, transitive bool) ([]domain.GroupMember, error) {
+	s.transitive = transitive
+	return []domain.GroupMember{{Type: domain.MemberUser, ID: "u1", Display: "jane"}}, s.err
+}
+
+func (s *stubGroupService) ChangeMembers(_ context.Context, _ string, change *domain.GroupMembershipChange) ([]domain.GroupMember, error) {
+	s.change = change
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []domain.GroupMember{{Type: domain.MemberUser, ID: "u1", Display: "jane"}}, nil
+}
+
+func (s *stubGroupService) GroupsOfUser(_ context.Context, _ string, transitive bool) ([]domain.Group, error) {
+	s.transitive = transitive
+	return []domain.Group{{ID: "g1", Name: "Staff"}}, s.err
+}
+
+func TestGroupHandlerRequiresAdminToModify(t *testing.T) {
+	handler := NewGroupHandler(&stubGroupService{})
+	tests := []struct {
+		name    string
+		method  string
+		body    string
+		handle  func(http.ResponseWriter, *http.Request) *HTTPError
+		success int
+	}{
+		{"create", http.MethodPost, `{"name":"Staff"}`, handler.Create, http.StatusCreated},
+		{"delete", http.MethodDelete, "", handler.Delete, http.StatusNoContent},
+		{"change members", http.MethodPatch, `{"add":[{"type":"user","id":"u1"}]}`, handler.ChangeMembers, http.StatusOK},
+	}
+	for _, tt := range tests {
+		for _, roles := range [][]string{{"user"}, {domain.RoleAdmin}} {
+			t.Run(tt.name+" as "+roles[0], func(t *testing.T) {
+				r := withPrincipal(httptest.NewRequest(tt.method, "/groups/g1", strings.NewReader(tt.body)), roles...)
+				r.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				ErrorHandlerWrapper(tt.handle)(w, r)
+
+				want := http.StatusForbidden
+				if roles[0] == domain.RoleAdmin {
+					want = tt.success
+				}
+				if w.Code != want {
+					t.Errorf("status = %d, want %d: %s", w.Code, want, w.Body)
+				}
+			})
+		}
+	}
+}
+
+func TestGroupHandlerMapsErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{domain.ErrGroupNotFound, http.StatusNotFound},
+		{domain.ErrUserNotFound, http.StatusNotFound},
+		{domain.ErrGroupNameInUse, http.StatusConflict},
+		{fmt.Errorf("%w: group g2", domain.ErrGroupCycle), http.StatusConflict},
+		{fmt.Errorf("%w: user u9", domain.ErrMemberNotFound), http.StatusUnprocessableEntity},
+		{domain.ErrInvalidMemberType, http.StatusBadRequest},
+		{domain.ErrValueNotNullable{Value: "name"}, http.StatusBadRequest},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			handler := NewGroupHandler(&stubGroupService{err: tt.err})
+			r := withPrincipal(httptest.NewRequest(http.MethodPatch, "/groups/g1/members", strings.NewReader(`{"add":[{"type":"group","id":"g2"}]}`)), domain.RoleAdmin)
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			ErrorHandlerWrapper(handler.ChangeMembers)(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupHandlerChangeMembersValidatesBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"member without id", `{"add":[{"type":"user"}]}`, http.StatusBadRequest},
+		{"unknown member type", `{"remove":[{"type":"robot","id":"r2"}]}`, http.StatusBadRequest},
+		{"add and remove", `{"add":[{"type":"group","id":"g2"}],"remove":[{"type":"user","id":"u1"}]}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubGroupService{}
+			handler := NewGroupHandler(service)
+			r := withPrincipal(httptest.NewRequest(http.MethodPatch, "/groups/g1/members", strings.NewReader(tt.body)), domain.RoleAdmin)
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			ErrorHandlerWrapper(handler.ChangeMembers)(w, r)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+			if tt.want == http.StatusBadRequest && service.change != nil {
+				t.Errorf("invalid change reached the service: %+v", service.change)
+			}
+			if tt.want == http.StatusOK && (len(service.change.Add) != 1 || len(service.change.Remove) != 1) {
+				t.Errorf("change = %+v, want one addition and one removal", service.change)
+			}
+		})
+	}
+}
+
+func TestGroupHandlerParsesTransitive(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int
+		flag  bool
+	}{
+		{"", http.StatusOK, false},
+		{"?transitive=true", http.StatusOK, true},
+		{"?transitive=maybe", http.StatusBadRequest, false},
+	}
+	for _, tt := range tests {
+		t.Run("members"+tt.query, func(t *testing.T) {
+			service := &stubGroupService{}
+			w := httptest.NewRecorder()
+			ErrorHandlerWrapper(NewGroupHandler(service).Members)(w, withPrincipal(httptest.NewRequest(http.MethodGet, "/groups/g1/members"+tt.query, nil), "user"))
+			if w.Code != tt.want || service.transitive != tt.flag {
+				t.Errorf("status = %d, transitive = %t; want %d, %t", w.Code, service.transitive, tt.want, tt.flag)
+			}
+		})
+		t.Run("user groups"+tt.query, func(t *testing.T) {
+			service := &stubGroupService{}
+			w := httptest.NewRecorder()
+			ErrorHandlerWrapper(NewGroupHandler(service).UserGroups)(w, withPrincipal(httptest.NewRequest(http.MethodGet, "/users/u1/groups"+tt.query, nil), "user"))
+			if w.Code != tt.want || service.transitive != tt.flag {
+				t.Errorf("status = %d, transitive = %t; want %d, %t", w.Code, service.transitive, tt.want, tt.flag)
+			}
+		})
+	}
+}
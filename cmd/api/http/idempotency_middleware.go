@@ -0,0 +1,134 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+const (
+	// IdempotencyKeyHeader es la cabecera con la clave de idempotencia del cliente.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marca las respuestas reproducidas desde una petición anterior.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength es la longitud máxima aceptada de la clave.
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize es el tamaño máximo del cuerpo de una petición idempotente.
+	maxIdempotentBodySize = 1 << 20
+)
+
+// IdempotencyMiddleware hace idempotentes las peticiones que incluyen la
+// cabecera Idempotency-Key: la primera petición con una clave se procesa y su
+// respuesta se guarda; los reintentos con la misma clave y el mismo cuerpo
+// reciben la respuesta original (con Idempotent-Replayed: true), y los que
+// reutilizan la clave con otro cuerpo reciben 422. Las respuestas 5xx no se
+// guardan, para que el cliente pueda reintentar. Sin la cabecera, la petición
+// se procesa normalmente.
+func IdempotencyMiddleware(service *application.IdempotencyService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				writeHTTPError(w, r, NewHTTPError(errors.New("Idempotency-Key must be at most 255 characters"), http.StatusBadRequest))
+				return
+			}
+
+			// 1. Huella de la petición (método, ruta y cuerpo)
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+			if err != nil || len(body) > maxIdempotentBodySize {
+				writeHTTPError(w, r, NewHTTPError(errors.New("invalid request body format"), http.StatusBadRequest))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			hash := sha256.New()
+			hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+			hash.Write(body)
+			fingerprint := hex.EncodeToString(hash.Sum(nil))
+
+			// 2. Reserva de la clave o reproducción de la respuesta original
+			record, err := service.Begin(r.Context(), key, fingerprint)
+			if err != nil {
+				switch {
+				case errors.Is(err, domain.ErrIdempotencyKeyReused):
+					writeHTTPError(w, r, NewHTTPError(err, http.StatusUnprocessableEntity))
+				case errors.Is(err, domain.ErrIdempotencyRequestInProgress):
+					writeHTTPError(w, r, NewHTTPError(err, http.StatusConflict))
+				default:
+					writeHTTPError(w, r, NewHTTPError(errors.New("internal server error"), http.StatusInternalServerError))
+				}
+				return
+			}
+			if record != nil {
+				if record.ContentType != "" {
+					w.Header().Set("Content-Type", record.ContentType)
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(record.StatusCode)
+				_, _ = w.Write(record.Body)
+				return
+			}
+
+			// 3. Procesamiento y registro de la respuesta. El registro y la
+			// liberación de la clave no dependen de la conexión del cliente: si
+			// se desconecta, la clave no debe quedar reservada hasta expirar.
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			storeCtx := context.WithoutCancel(r.Context())
+			completed := false
+			defer func() {
+				if !completed {
+					if err := service.Release(storeCtx, key); err != nil {
+						log.Printf("[Idempotency] failed to release key: %v", err)
+					}
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+
+			if recorder.status >= http.StatusInternalServerError {
+				return
+			}
+			if err := service.Complete(storeCtx, key, recorder.status, recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+				log.Printf("[Idempotency] failed to store response: %v", err)
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// responseRecorder escribe la respuesta al cliente y, a la vez, conserva el
+// código de estado y el cuerpo para guardarlos.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// WriteHeader registra el código de estado antes de enviarlo.
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write copia el cuerpo antes de enviarlo.
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
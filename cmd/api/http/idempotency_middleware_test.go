@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+// memoryIdempotency implementa domain.IdempotencyRepository en memoria.
+type memoryIdempotency map[string]domain.IdempotencyRecord
+
+func (m memoryIdempotency) Create(record *domain.IdempotencyRecord) error {
+	if _, ok := m[record.Scope+"|"+record.Key]; ok {
+		return domain.ErrIdempotencyKeyExists
+	}
+	m[record.Scope+"|"+record.Key] = *record
+	return nil
+}
+
+func (m memoryIdempotency) Find(scope, key string) (*domain.IdempotencyRecord, error) {
+	record, ok := m[scope+"|"+key]
+	if !ok {
+		return nil, domain.ErrIdempotencyKeyNotFound
+	}
+	return &record, nil
+}
+
+func (m memoryIdempotency) Complete(scope, key string, statusCode int, contentType string, body []byte) error {
+	record := m[scope+"|"+key]
+	record.StatusCode, record.ContentType, record.Body = statusCode, contentType, body
+	m[scope+"|"+key] = record
+	return nil
+}
+
+func (m memoryIdempotency) Delete(scope, key string) error {
+	delete(m, scope+"|"+key)
+	return nil
+}
+
+func (m memoryIdempotency) PurgeExpired(time.Time) (int64, error) { return 0, nil }
+
+// countingHandler responde con status y cuenta sus invocaciones.
+func countingHandler(calls *int, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"id":"u1"}`))
+	})
+}
+
+// postWithKey envía un POST /users con la clave de idempotencia indicada.
+func postWithKey(handler http.Handler, ctx context.Context, key, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body)).WithContext(ctx)
+	r.Header.Set(IdempotencyKeyHeader, key)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestIdempotencyMiddlewareReplaysTheOriginalResponse(t *testing.T) {
+	calls := 0
+	service := application.NewIdempotencyService(memoryIdempotency{}, time.Hour)
+	handler := IdempotencyMiddleware(service)(countingHandler(&calls, http.StatusCreated))
+	ctx := context.Background()
+
+	first := postWithKey(handler, ctx, "k1", `{"username":"jane"}`)
+	retry := postWithKey(handler, ctx, "k1", `{"username":"jane"}`)
+
+	if calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() || retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("retry = %d %q (replayed %q)", retry.Code, retry.Body, retry.Header().Get(IdempotentReplayedHeader))
+	}
+
+	if reused := postWithKey(handler, ctx, "k1", `{"username":"john"}`); reused.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reused key with another body: status = %d, want 422", reused.Code)
+	}
+
+	// La misma clave de otro actor es independiente.
+	other := domain.WithPrincipal(ctx, domain.Principal{Subject: "bob"})
+	if w := postWithKey(handler, other, "k1", `{"username":"john"}`); w.Code != http.StatusCreated || calls != 2 {
+		t.Errorf("other actor: status = %d, calls = %d", w.Code, calls)
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesTheKeyOnServerErrors(t *testing.T) {
+	calls := 0
+	repo := memoryIdempotency{}
+	handler := IdempotencyMiddleware(application.NewIdempotencyService(repo, time.Hour))(countingHandler(&calls, http.StatusServiceUnavailable))
+
+	postWithKey(handler, context.Background(), "k1", `{}`)
+	postWithKey(handler, context.Background(), "k1", `{}`)
+
+	if calls != 2 || len(repo) != 0 {
+		t.Errorf("calls = %d, stored keys = %d; want the failed request to be retried", calls, len(repo))
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesTheKeyWhenTheClientDisconnects(t *testing.T) {
+	repo := memoryIdempotency{}
+	ctx, cancel := context.WithCancel(context.Background())
+	disconnecting := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	handler := IdempotencyMiddleware(application.NewIdempotencyService(repo, time.Hour))(disconnecting)
+
+	postWithKey(handler, ctx, "k1", `{}`)
+
+	if len(repo) != 0 {
+		t.Errorf("key still reserved after the client disconnected: %+v", repo)
+	}
+}
+
+func TestIdempotencyMiddlewareRejectsConcurrentRetries(t *testing.T) {
+	repo := memoryIdempotency{}
+	service := application.NewIdempotencyService(repo, time.Hour)
+	var retry *httptest.ResponseRecorder
+
+	var handler http.Handler
+	handler = IdempotencyMiddleware(service)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// El reintento llega mientras la petición original está en curso.
+		retry = postWithKey(handler, context.Background(), "k1", `{}`)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	if w := postWithKey(handler, context.Background(), "k1", `{}`); w.Code != http.StatusCreated {
+		t.Fatalf("original: status = %d", w.Code)
+	}
+	if retry.Code != http.StatusConflict {
+		t.Errorf("concurrent retry: status = %d, want 409", retry.Code)
+	}
+
+	long := postWithKey(handler, context.Background(), strings.Repeat("k", 256), `{}`)
+	if long.Code != http.StatusBadRequest {
+		t.Errorf("long key: status = %d, want 400", long.Code)
+	}
+}
@@ -1,45 +1,58 @@
 package http
 
 import (
+	"errors"
 	"log"
+	"math/rand"
 	"net/http"
-	"os"
 	"time"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/oklog/ulid/v2"
 )
 
+// RequestIDHeader es la cabecera que transporta el ID de correlación de la petición.
+const RequestIDHeader = "X-Request-ID"
+
 // Package http define los controladores (handlers), wrappers de error y middleware
 // para la capa de presentación HTTP.
 
-// AuthAndLoggingMiddleware es un middleware que combina dos responsabilidades:
-//  1. **Autenticación Básica (Basic Auth):** Verifica las credenciales Basic Auth
-//     contra variables de entorno (BASIC_AUTH_USER y BASIC_AUTH_PASS).
-//     Si las variables no están seteadas, la autenticación es omitida (Warning).
+// AuthAndLoggingMiddleware crea un middleware que combina dos responsabilidades:
+//  1. **Autenticación:** Verifica una clave de API (Authorization: Bearer) o
+//     las credenciales Basic Auth contra variables de entorno
+//     (BASIC_AUTH_USER y BASIC_AUTH_PASS). Si las variables no están
+//     seteadas, solo se aceptan claves de API (ver application.AuthenticateBasic).
 //  2. **Logging de Peticiones:** Registra el método HTTP, la URL, el protocolo y
 //     el tiempo que tardó el procesamiento del request.
 //
-// Recibe un http.Handler (next) y retorna un nuevo http.Handler.
-func AuthAndLoggingMiddleware(next http.Handler) http.Handler {
+// apiKeys puede ser nil, en cuyo caso solo se acepta Basic Auth.
+func AuthAndLoggingMiddleware(apiKeys *application.APIKeyService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return authAndLogging(apiKeys, next)
+	}
+}
+
+// authAndLogging es el handler de AuthAndLoggingMiddleware.
+func authAndLogging(apiKeys *application.APIKeyService, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		userEnv := os.Getenv("BASIC_AUTH_USER")
-		passEnv := os.Getenv("BASIC_AUTH_PASS")
-
-		// --- Lógica de Autenticación Básica ---
-		if userEnv == "" || passEnv == "" {
-			log.Println("WARNING: BASIC_AUTH environment variables not set. Skipping authentication.")
-		} else {
-			user, pass, ok := r.BasicAuth()
-			// Verifica que las credenciales coincidan con las variables de entorno.
-			if !ok || user != userEnv || pass != passEnv {
-				w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
-				return // Detiene el flujo si la autenticación falla.
+		// --- Lógica de Autenticación ---
+		// Compartida con el servidor gRPC (ver application.AuthenticateRequest).
+		principal, err := application.AuthenticateRequest(r.Context(), apiKeys, r.Header.Get("Authorization"))
+		if err != nil {
+			if !errors.Is(err, application.ErrUnauthenticated) {
+				log.Printf("[Auth] failed to verify credentials: %v", err)
 			}
+			w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
+			w.Header().Add("WWW-Authenticate", `Bearer realm="Restricted"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return // Detiene el flujo si la autenticación falla.
 		}
 
-		// Llama al siguiente handler/middleware en la cadena.
-		next.ServeHTTP(w, r)
+		// Llama al siguiente handler/middleware en la cadena, con el principal en el contexto.
+		next.ServeHTTP(w, r.WithContext(domain.WithPrincipal(r.Context(), principal)))
 
 		// --- Lógica de Logging (ejecutada después de next.ServeHTTP) ---
 		duration := time.Since(start)
@@ -57,3 +70,52 @@ func AuthAndLoggingMiddleware(next http.Handler) http.Handler {
 		)
 	})
 }
+
+// RequestIDMiddleware asigna a cada petición un ID de correlación: reutiliza
+// el recibido en X-Request-ID o genera un ULID nuevo. El ID se propaga en el
+// contexto (ver domain.RequestIDFromContext) y se devuelve en la respuesta.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			t := time.Now()
+			entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
+			requestID = ulid.MustNew(ulid.Timestamp(t), entropy).String()
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+
+		next.ServeHTTP(w, r.WithContext(domain.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// TenantHeader es la cabecera con la que un operador elige la organización
+// sobre la que opera la petición.
+const TenantHeader = "X-Tenant-ID"
+
+// TenantMiddleware resuelve la organización de la petición (ver
+// application.TenantService.Resolve) a partir del principal autenticado, la
+// cabecera X-Tenant-ID o, en su defecto, el subdominio del host, y la
+// propaga en el contexto (ver domain.TenantFromContext) y en la respuesta.
+// Debe ejecutarse después de AuthAndLoggingMiddleware.
+func TenantMiddleware(tenants *application.TenantService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested := r.Header.Get(TenantHeader)
+			if requested == "" {
+				requested = tenants.TenantFromHost(r.Host)
+			}
+
+			principal, _ := domain.PrincipalFromContext(r.Context())
+			tenantID, err := tenants.Resolve(r.Context(), principal, requested)
+			if err != nil {
+				writeHTTPError(w, r, mapTenantError(err))
+				return
+			}
+
+			w.Header().Set(TenantHeader, tenantID)
+
+			next.ServeHTTP(w, r.WithContext(domain.WithTenant(r.Context(), tenantID)))
+		})
+	}
+}
@@ -0,0 +1,813 @@
+package http
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// apiOperation documenta una ruta de la tabla de NewRouter. Los cuerpos y
+// parámetros se describen con un valor del tipo Go que los representa; su
+// esquema se genera por reflexión a partir de los tags json y validate.
+type apiOperation struct {
+	id      string
+	summary string
+	tag     string
+	// public indica que la ruta no requiere autenticación.
+	public bool
+	// negotiated indica que la ruta usa NegotiationMiddleware: los cuerpos
+	// (incluidos los de error) admiten todos los formatos de codecs.
+	negotiated bool
+
+	parameters []apiParameter
+	request    *apiBody
+	responses  []apiResponse
+	// failures son los códigos de error que puede responder, además de
+	// 401/406/415, que se agregan según public y negotiated.
+	failures []int
+}
+
+// apiParameter es un parámetro de query o cabecera. Los parámetros de ruta
+// se derivan del patrón ({id}).
+type apiParameter struct {
+	name        string
+	in          string
+	of          any
+	description string
+}
+
+// apiBody es el cuerpo de una petición o respuesta. Si mediaTypes es nil
+// se usan los formatos de la operación.
+type apiBody struct {
+	of         any
+	mediaTypes []string
+	// optional indica que el cuerpo de la petición puede omitirse.
+	optional bool
+}
+
+// apiResponse es una respuesta exitosa de una operación.
+type apiResponse struct {
+	status      int
+	description string
+	body        *apiBody
+}
+
+// query y header construyen parámetros de la operación.
+func query(name string, of any, description string) apiParameter {
+	return apiParameter{name: name, in: "query", of: of, description: description}
+}
+
+func header(name string, of any, description string) apiParameter {
+	return apiParameter{name: name, in: "header", of: of, description: description}
+}
+
+// userFilterParameters son los parámetros de userFilterFromQuery.
+var userFilterParameters = []apiParameter{
+	query("include_deleted", false, "Include soft-deleted users (admin only)."),
+	query("created_after", time.Time{}, "Inclusive lower bound of created_at (RFC 3339)."),
+	query("created_before", time.Time{}, "Inclusive upper bound of created_at (RFC 3339)."),
+	query("updated_after", time.Time{}, "Inclusive lower bound of updated_at (RFC 3339)."),
+	query("updated_before", time.Time{}, "Inclusive upper bound of updated_at (RFC 3339)."),
+	query("status", "", "Comma-separated list of account statuses to include (pending, active, suspended, locked, deactivated)."),
+	query("fields", "", "Comma-separated list of fields to return (v1: "+strings.Join(domain.UserFieldNames(), ", ")+"; v2: "+strings.Join(userV2FieldNames, ", ")+")."),
+}
+
+// idempotencyKeyParameter es la cabecera aceptada por IdempotencyMiddleware.
+var idempotencyKeyParameter = header("Idempotency-Key", "",
+	"Retries with the same key and body replay the original response instead of repeating the operation.")
+
+// tenantParameter es la cabecera aceptada por TenantMiddleware en toda ruta autenticada.
+var tenantParameter = header(TenantHeader, "",
+	"Tenant to operate on (operators only; defaults to the subdomain or to the default tenant). Keys bound to a tenant may only name their own.")
+
+// transitiveParameter es el parámetro de las consultas de membresía.
+var transitiveParameter = query("transitive", false, "Include memberships through nested groups.")
+
+// userStatusOperation documenta la ruta de una acción del ciclo de vida de la cuenta.
+func userStatusOperation(action domain.UserStatusAction, summary string) apiOperation {
+	return apiOperation{
+		id: string(action) + "User", summary: summary + " (admin only)", tag: "users", negotiated: true,
+		request:   &apiBody{of: domain.UserStatusChangeRequest{}, optional: true},
+		responses: []apiResponse{{http.StatusOK, "The user with its new status.", &apiBody{of: domain.User{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	}
+}
+
+// apiOperations documenta cada ruta de NewRouter por "MÉTODO patrón". Toda
+// ruta registrada debe figurar aquí y viceversa (ver OpenAPIDocument).
+var apiOperations = map[string]apiOperation{
+	"GET /openapi.json": {
+		id: "getOpenAPI", summary: "OpenAPI 3.1 document generated from the route table", tag: "documentation", public: true,
+		responses: []apiResponse{{http.StatusOK, "The OpenAPI document.", &apiBody{of: map[string]any{}, mediaTypes: []string{"application/json"}}}},
+	},
+	"GET /docs": {
+		id: "getDocs", summary: "Interactive API reference (Swagger UI)", tag: "documentation", public: true,
+		responses: []apiResponse{{http.StatusOK, "HTML page that renders /openapi.json.", &apiBody{of: "", mediaTypes: []string{"text/html"}}}},
+	},
+	"GET /metrics": {
+		id: "getMetrics", summary: "Request counters by API version (Prometheus text format)", tag: "documentation",
+		responses: []apiResponse{{http.StatusOK, "user_api_requests_total by version, selection method, route and status.", &apiBody{of: "", mediaTypes: []string{"text/plain"}}}},
+	},
+	"POST /graphql": {
+		id: "graphql", summary: "GraphQL queries and mutations over users (errors are returned in the body)", tag: "graphql",
+		request:   &apiBody{of: GraphQLRequest{}, mediaTypes: []string{"application/json"}},
+		responses: []apiResponse{{http.StatusOK, "The result of the operation, with data and/or errors.", &apiBody{of: GraphQLResponse{}, mediaTypes: []string{"application/json"}}}},
+		failures:  []int{http.StatusBadRequest},
+	},
+	"POST /users": {
+		id: "createUser", summary: "Create a new user", tag: "users", negotiated: true,
+		parameters: []apiParameter{idempotencyKeyParameter},
+		request:    &apiBody{of: domain.UserCreateRequest{}},
+		responses:  []apiResponse{{http.StatusCreated, "The created user.", &apiBody{of: domain.User{}}}},
+		failures:   []int{http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	},
+	"GET /users": {
+		id: "listUsers", summary: "Retrieve all users", tag: "users", negotiated: true,
+		parameters: userFilterParameters,
+		responses:  []apiResponse{{http.StatusOK, "The users matching the filters.", &apiBody{of: []domain.User{}}}},
+		failures:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	"PUT /users": {
+		id: "updateUser", summary: "Update an existing user (the ID goes in the body)", tag: "users", negotiated: true,
+		request:   &apiBody{of: domain.User{}},
+		responses: []apiResponse{{http.StatusOK, "The updated user.", &apiBody{of: domain.User{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	"GET /users/search": {
+		id: "searchUsers", summary: "Full-text and fuzzy search", tag: "users", negotiated: true,
+		parameters: []apiParameter{
+			query("q", "", "Text searched (partially or approximately) in name, username and email."),
+			query("limit", 0, "Page size."),
+			query("offset", 0, "Number of results to skip."),
+		},
+		responses: []apiResponse{{http.StatusOK, "A page of results ordered by relevance.", &apiBody{of: domain.UserSearchResult{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	"GET /users/export": {
+		id: "exportUsers", summary: "Stream users as CSV, NDJSON or JSON", tag: "users",
+		parameters: append([]apiParameter{
+			query("format", "", "csv, ndjson or json; defaults to the Accept header."),
+			query("gzip", false, "Compress the response even without Accept-Encoding: gzip."),
+		}, userFilterParameters...),
+		responses: []apiResponse{
+			{http.StatusOK, "The exported users.", &apiBody{of: []domain.User{}, mediaTypes: []string{"application/json", "application/x-ndjson", "text/csv"}}},
+		},
+		failures: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotAcceptable},
+	},
+	"GET /users/events": {
+		id: "streamUserEvents", summary: "Server-Sent Events change feed (resumable with Last-Event-ID)", tag: "events",
+		parameters: []apiParameter{
+			query("types", "", "Comma-separated event types to receive (e.g. user.created,user.deleted)."),
+			query("last_event_id", int64(0), "Resume after this sequence (alternative to the Last-Event-ID header)."),
+			header("Last-Event-ID", int64(0), "Resume after this sequence."),
+		},
+		responses: []apiResponse{{http.StatusOK, "Stream of domain.UserEvent messages, each with its sequence as id.", &apiBody{of: "", mediaTypes: []string{"text/event-stream"}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	"POST /users/imports": {
+		id: "importUsers", summary: "Import a CSV or NDJSON file (admin only)", tag: "imports", negotiated: true,
+		parameters: []apiParameter{
+			query("format", "", "csv or ndjson; defaults to the Content-Type."),
+			query("dry_run", false, "Validate the file and return the report without creating users."),
+			query("map_name", "", "CSV column holding the name."),
+			query("map_username", "", "CSV column holding the username."),
+			query("map_email", "", "CSV column holding the email."),
+		},
+		request: &apiBody{of: "", mediaTypes: []string{"text/csv", "application/x-ndjson"}},
+		responses: []apiResponse{
+			{http.StatusOK, "Validation report (dry run).", &apiBody{of: domain.ImportReport{}}},
+			{http.StatusAccepted, "The import, processed in the background.", &apiBody{of: domain.UserImport{}}},
+		},
+		failures: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusRequestEntityTooLarge},
+	},
+	"GET /users/imports/{id}": {
+		id: "getImport", summary: "Import status and progress", tag: "imports", negotiated: true,
+		responses: []apiResponse{{http.StatusOK, "The import.", &apiBody{of: domain.UserImport{}}}},
+		failures:  []int{http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"GET /users/imports/{id}/errors": {
+		id: "getImportErrors", summary: "Download the error report (CSV)", tag: "imports",
+		responses: []apiResponse{{http.StatusOK, "One row per rejected line: row, field, message.", &apiBody{of: "", mediaTypes: []string{"text/csv"}}}},
+		failures:  []int{http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"POST /users/imports/{id}/resume": {
+		id: "resumeImport", summary: "Resume a failed import", tag: "imports", negotiated: true,
+		responses: []apiResponse{{http.StatusAccepted, "The import, queued again.", &apiBody{of: domain.UserImport{}}}},
+		failures:  []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	"GET /users/{id}": {
+		id: "getUser", summary: "Retrieve a specific user by ID", tag: "users", negotiated: true,
+		parameters: []apiParameter{query("fields", "", "Comma-separated list of fields to return.")},
+		responses:  []apiResponse{{http.StatusOK, "The user.", &apiBody{of: domain.User{}}}},
+		failures:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"DELETE /users/{id}": {
+		id: "deleteUser", summary: "Soft-delete a specific user by ID", tag: "users", negotiated: true,
+		responses: []apiResponse{{http.StatusNoContent, "The user was deleted.", nil}},
+		failures:  []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"POST /users/{id}/restore": {
+		id: "restoreUser", summary: "Undo a soft delete (admin only)", tag: "users", negotiated: true,
+		responses: []apiResponse{{http.StatusOK, "The restored user.", &apiBody{of: domain.User{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	"POST /users/{id}:activate":   userStatusOperation(domain.StatusActivate, "Activate a pending account or reactivate a suspended or deactivated one"),
+	"POST /users/{id}:suspend":    userStatusOperation(domain.StatusSuspend, "Suspend an active or locked account (reason required)"),
+	"POST /users/{id}:lock":       userStatusOperation(domain.StatusLock, "Lock an active account (reason required)"),
+	"POST /users/{id}:unlock":     userStatusOperation(domain.StatusUnlock, "Unlock a locked account"),
+	"POST /users/{id}:deactivate": userStatusOperation(domain.StatusDeactivate, "Deactivate an account (reason required)"),
+	"POST /users/{id}/verify-email": {
+		id: "verifyUserEmail", summary: "Confirm the user's email with the token sent by mail", tag: "users", negotiated: true,
+		request:   &apiBody{of: domain.EmailVerificationRequest{}},
+		responses: []apiResponse{{http.StatusOK, "The user with its email verified.", &apiBody{of: domain.User{}}}},
+		failures: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity,
+			http.StatusInternalServerError},
+	},
+	"POST /users/{id}:send-verification-email": {
+		id: "sendUserVerificationEmail", summary: "Issue a new token and send the verification email again", tag: "users", negotiated: true,
+		responses: []apiResponse{{http.StatusAccepted, "The message was handed to the mail server.", nil}},
+		failures: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError,
+			http.StatusServiceUnavailable},
+	},
+	"GET /users/{id}/history": {
+		id: "getUserHistory", summary: "Audit trail of a user (admin/auditor only)", tag: "audit", negotiated: true,
+		parameters: []apiParameter{query("limit", 0, "Maximum number of records.")},
+		responses:  []apiResponse{{http.StatusOK, "The user's mutations, newest first.", &apiBody{of: []domain.AuditRecord{}}}},
+		failures:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	"POST /users:batch": {
+		id: "batchUsers", summary: "Bulk create/update/delete (atomic or best_effort)", tag: "users", negotiated: true,
+		parameters: []apiParameter{idempotencyKeyParameter},
+		request:    &apiBody{of: UserBatchRequest{}},
+		responses: []apiResponse{
+			{http.StatusOK, "Every operation was applied.", &apiBody{of: UserBatchResponse{}}},
+			{http.StatusMultiStatus, "Per-operation results (best_effort).", &apiBody{of: UserBatchResponse{}}},
+		},
+		failures: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusRequestEntityTooLarge,
+			http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	},
+	"GET /audit": {
+		id: "findAudit", summary: "Query the audit log (admin/auditor only)", tag: "audit", negotiated: true,
+		parameters: []apiParameter{
+			query("actor", "", "Only records made by this actor."),
+			query("since", time.Time{}, "Only records at or after this instant (RFC 3339)."),
+			query("limit", 0, "Maximum number of records."),
+		},
+		responses: []apiResponse{{http.StatusOK, "The matching records, newest first.", &apiBody{of: []domain.AuditRecord{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	"POST /webhooks": {
+		id: "createWebhook", summary: "Subscribe a webhook (the signing secret is returned only here)", tag: "webhooks", negotiated: true,
+		request:   &apiBody{of: domain.WebhookCreateRequest{}},
+		responses: []apiResponse{{http.StatusCreated, "The subscription, including its secret.", &apiBody{of: domain.Webhook{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	"GET /webhooks": {
+		id: "listWebhooks", summary: "List subscriptions", tag: "webhooks", negotiated: true,
+		responses: []apiResponse{{http.StatusOK, "The subscriptions.", &apiBody{of: []domain.Webhook{}}}},
+		failures:  []int{http.StatusForbidden, http.StatusInternalServerError},
+	},
+	"GET /webhooks/{id}": {
+		id: "getWebhook", summary: "Retrieve a subscription", tag: "webhooks", negotiated: true,
+		responses: []apiResponse{{http.StatusOK, "The subscription.", &apiBody{of: domain.Webhook{}}}},
+		failures:  []int{http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"PATCH /webhooks/{id}": {
+		id: "updateWebhook", summary: "Change URL, event filter, secret or active flag", tag: "webhooks", negotiated: true,
+		request:   &apiBody{of: domain.WebhookUpdateRequest{}},
+		responses: []apiResponse{{http.StatusOK, "The updated subscription.", &apiBody{of: domain.Webhook{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"DELETE /webhooks/{id}": {
+		id: "deleteWebhook", summary: "Unsubscribe", tag: "webhooks", negotiated: true,
+		responses: []apiResponse{{http.StatusNoContent, "The subscription was deleted.", nil}},
+		failures:  []int{http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"GET /webhooks/{id}/deliveries": {
+		id: "listWebhookDeliveries", summary: "Delivery log with response codes", tag: "webhooks", negotiated: true,
+		parameters: []apiParameter{query("limit", 0, "Maximum number of deliveries.")},
+		responses:  []apiResponse{{http.StatusOK, "The deliveries, newest first.", &apiBody{of: []domain.WebhookDelivery{}}}},
+		failures:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"POST /webhooks/{id}/deliveries/{deliveryId}/redeliver": {
+		id: "redeliverWebhook", summary: "Manual redelivery", tag: "webhooks", negotiated: true,
+		responses: []apiResponse{{http.StatusAccepted, "The delivery, queued again.", &apiBody{of: domain.WebhookDelivery{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"GET /users/{id}/groups": {
+		id: "listUserGroups", summary: "Groups of a user (direct or through nested groups)", tag: "groups", negotiated: true,
+		parameters: []apiParameter{transitiveParameter},
+		responses:  []apiResponse{{http.StatusOK, "The groups, ordered by name.", &apiBody{of: []domain.Group{}}}},
+		failures:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"POST /groups": {
+		id: "createGroup", summary: "Create a group (admin only)", tag: "groups", negotiated: true,
+		request:   &apiBody{of: domain.GroupCreateRequest{}},
+		responses: []apiResponse{{http.StatusCreated, "The created group.", &apiBody{of: domain.Group{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusConflict, http.StatusInternalServerError},
+	},
+	"GET /groups": {
+		id: "listGroups", summary: "List groups", tag: "groups", negotiated: true,
+		responses: []apiResponse{{http.StatusOK, "The groups, ordered by name.", &apiBody{of: []domain.Group{}}}},
+		failures:  []int{http.StatusInternalServerError},
+	},
+	"GET /groups/{id}": {
+		id: "getGroup", summary: "Retrieve a group", tag: "groups", negotiated: true,
+		responses: []apiResponse{{http.StatusOK, "The group.", &apiBody{of: domain.Group{}}}},
+		failures:  []int{http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"PATCH /groups/{id}": {
+		id: "updateGroup", summary: "Rename a group or change its description (admin only)", tag: "groups", negotiated: true,
+		request:   &apiBody{of: domain.GroupUpdateRequest{}},
+		responses: []apiResponse{{http.StatusOK, "The updated group.", &apiBody{of: domain.Group{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	"DELETE /groups/{id}": {
+		id: "deleteGroup", summary: "Delete a group and its memberships (admin only)", tag: "groups", negotiated: true,
+		responses: []apiResponse{{http.StatusNoContent, "The group was deleted.", nil}},
+		failures:  []int{http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"GET /groups/{id}/members": {
+		id: "listGroupMembers", summary: "Direct members, or effective users with transitive=true", tag: "groups", negotiated: true,
+		parameters: []apiParameter{transitiveParameter},
+		responses:  []apiResponse{{http.StatusOK, "The members: groups first (by name), then users (by ID).", &apiBody{of: []domain.GroupMember{}}}},
+		failures:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"PATCH /groups/{id}/members": {
+		id: "changeGroupMembers", summary: "Add and remove members atomically (admin only)", tag: "groups", negotiated: true,
+		request:   &apiBody{of: domain.GroupMembershipChange{}},
+		responses: []apiResponse{{http.StatusOK, "The resulting direct members.", &apiBody{of: []domain.GroupMember{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	},
+	"POST /tenants": {
+		id: "createTenant", summary: "Create a tenant (operators only)", tag: "tenants", negotiated: true,
+		request:   &apiBody{of: domain.TenantCreateRequest{}},
+		responses: []apiResponse{{http.StatusCreated, "The created tenant.", &apiBody{of: domain.Tenant{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusConflict, http.StatusInternalServerError},
+	},
+	"GET /tenants": {
+		id: "listTenants", summary: "List tenants (operators only)", tag: "tenants", negotiated: true,
+		responses: []apiResponse{{http.StatusOK, "The tenants, ordered by ID.", &apiBody{of: []domain.Tenant{}}}},
+		failures:  []int{http.StatusForbidden, http.StatusInternalServerError},
+	},
+	"GET /tenants/{id}": {
+		id: "getTenant", summary: "Retrieve a tenant (operators only)", tag: "tenants", negotiated: true,
+		responses: []apiResponse{{http.StatusOK, "The tenant.", &apiBody{of: domain.Tenant{}}}},
+		failures:  []int{http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"PUT /tenants/{id}": {
+		id: "updateTenant", summary: "Rename a tenant (operators only)", tag: "tenants", negotiated: true,
+		request:   &apiBody{of: domain.TenantUpdateRequest{}},
+		responses: []apiResponse{{http.StatusOK, "The updated tenant.", &apiBody{of: domain.Tenant{}}}},
+		failures:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	"DELETE /tenants/{id}": {
+		id: "deleteTenant", summary: "Delete a tenant without users (operators only)", tag: "tenants", negotiated: true,
+		responses: []apiResponse{{http.StatusNoContent, "The tenant was deleted.", nil}},
+		failures:  []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+}
+
+// pathParameterPattern captura los parámetros de ruta de chi ({id}).
+var pathParameterPattern = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// OpenAPIDocument genera la especificación OpenAPI 3.1 de las rutas
+// registradas en routes. Retorna un error si una ruta registrada no figura
+// en apiOperations o si una operación documentada no está registrada, de
+// modo que la especificación no pueda divergir de la tabla de rutas. Las
+// rutas de cada versión (/v1, /v2) se documentan con la operación sin
+// prefijo y los esquemas de su versión (ver apiVersionTypes).
+func OpenAPIDocument(routes chi.Routes) (map[string]any, error) {
+	// 1. Recorrido de la tabla de rutas
+	type route struct {
+		method, pattern string
+	}
+
+	registered, versioned := make(map[string]bool), make(map[string]bool)
+	walked := make([]route, 0)
+	var problems []string
+
+	err := chi.Walk(routes, func(method, pattern string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		pattern = normalizeRoute(pattern)
+		version, base := splitVersion(pattern)
+
+		key := method + " " + base
+		if version != 0 {
+			versioned[key] = true
+		}
+		if _, ok := apiOperations[key]; !ok {
+			problems = append(problems, "route "+method+" "+pattern+" is not documented")
+		}
+		registered[key] = true
+		walked = append(walked, route{method: method, pattern: pattern})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range apiOperations {
+		if !registered[key] {
+			problems = append(problems, "documented operation "+key+" is not routed")
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, errors.New("openapi: the route table and the specification diverge: " + strings.Join(problems, "; "))
+	}
+
+	// 2. Generación de las operaciones y los esquemas
+	schemas := newSchemaRegistry()
+	paths := make(map[string]any)
+
+	for _, route := range walked {
+		version, base := splitVersion(route.pattern)
+
+		item, ok := paths[route.pattern].(map[string]any)
+		if !ok {
+			item = make(map[string]any)
+			paths[route.pattern] = item
+		}
+		key := route.method + " " + base
+		deprecated := versioned[key] && max(version, APIVersion1) < latestAPIVersion
+		item[strings.ToLower(route.method)] = schemas.operation(route.pattern, apiOperations[key], version, deprecated)
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":   "User API",
+			"version": "1.0.0",
+			"description": "RESTful API to manage users. IDs are ULIDs. Unless stated otherwise, bodies are negotiated via Accept and Content-Type (JSON, XML, CBOR, MessagePack or YAML). " +
+				"Each API version is served under its prefix (/v1, /v2); unversioned paths serve v1 unless a versioned media type " +
+				"(e.g. application/vnd.user-api.v2+json) selects another. v2 splits name into given_name and family_name.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas.components,
+			"securitySchemes": map[string]any{
+				"basicAuth": map[string]any{"type": "http", "scheme": "basic"},
+			},
+		},
+		"security": []any{map[string]any{"basicAuth": []string{}}},
+	}, nil
+}
+
+// normalizeRoute quita la barra final que chi agrega a la raíz de un
+// subrouter ("/users/" → "/users").
+func normalizeRoute(route string) string {
+	if len(route) > 1 {
+		return strings.TrimSuffix(route, "/")
+	}
+	return route
+}
+
+// schemaRegistry genera los esquemas JSON de los tipos Go y acumula los de
+// los structs con nombre en components.schemas.
+type schemaRegistry struct {
+	components map[string]any
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{components: make(map[string]any)}
+}
+
+// operation construye el Operation Object de una ruta de la versión indicada
+// (0 para las rutas sin prefijo, que sirven la v1 por defecto); deprecated
+// marca las operaciones de versiones anteriores a la vigente.
+func (s *schemaRegistry) operation(pattern string, operation apiOperation, version int, deprecated bool) map[string]any {
+	mediaTypes := []string{"application/json"}
+	if operation.negotiated {
+		mediaTypes = negotiatedMediaTypes()
+	}
+
+	// Parámetros de ruta, derivados del patrón, seguidos de los declarados.
+	parameters := make([]any, 0)
+	for _, match := range pathParameterPattern.FindAllStringSubmatch(pattern, -1) {
+		parameters = append(parameters, map[string]any{
+			"name": match[1], "in": "path", "required": true,
+			"description": "Resource ID (ULID).",
+			"schema":      map[string]any{"type": "string"},
+		})
+	}
+	operationParameters := operation.parameters
+	if !operation.public {
+		operationParameters = append([]apiParameter{tenantParameter}, operationParameters...)
+	}
+	for _, parameter := range operationParameters {
+		parameters = append(parameters, map[string]any{
+			"name": parameter.name, "in": parameter.in, "description": parameter.description,
+			"schema": s.schema(reflect.TypeOf(parameter.of)),
+		})
+	}
+
+	responses := make(map[string]any)
+	for _, response := range operation.responses {
+		responses[strconv.Itoa(response.status)] = s.response(response.description, response.body, mediaTypes, version)
+	}
+
+	failures := append([]int(nil), operation.failures...)
+	if !operation.public {
+		// 403/404: la organización pedida no es accesible o no existe (ver TenantMiddleware).
+		failures = append(failures, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound)
+	}
+	if operation.negotiated {
+		failures = append(failures, http.StatusNotAcceptable)
+		if operation.request != nil {
+			failures = append(failures, http.StatusUnsupportedMediaType)
+		}
+	}
+	for _, status := range failures {
+		responses[strconv.Itoa(status)] = s.response(http.StatusText(status), &apiBody{of: ErrorResponse{}}, mediaTypes, version)
+	}
+
+	operationID := operation.id
+	if version != 0 {
+		operationID = "v" + strconv.Itoa(version) + strings.ToUpper(operationID[:1]) + operationID[1:]
+	}
+
+	result := map[string]any{
+		"operationId": operationID,
+		"summary":     operation.summary,
+		"tags":        []string{operation.tag},
+		"responses":   responses,
+	}
+	if len(parameters) > 0 {
+		result["parameters"] = parameters
+	}
+	if operation.request != nil {
+		result["requestBody"] = map[string]any{
+			"required": !operation.request.optional,
+			"content":  s.content(operation.request, mediaTypes, version),
+		}
+	}
+	if operation.public {
+		result["security"] = []any{}
+	}
+	if deprecated {
+		result["deprecated"] = true
+	}
+
+	return result
+}
+
+// response construye un Response Object; body nil indica una respuesta sin cuerpo.
+func (s *schemaRegistry) response(description string, body *apiBody, mediaTypes []string, version int) map[string]any {
+	response := map[string]any{"description": description}
+	if body != nil {
+		response["content"] = s.content(body, mediaTypes, version)
+	}
+	return response
+}
+
+// content asocia el esquema del cuerpo, en la representación de la
+// versión, con cada media type admitido.
+func (s *schemaRegistry) content(body *apiBody, mediaTypes []string, version int) map[string]any {
+	if body.mediaTypes != nil {
+		mediaTypes = body.mediaTypes
+	}
+
+	bodyType := reflect.TypeOf(body.of)
+	if versioned, ok := apiVersionTypes[version][bodyType]; ok {
+		bodyType = versioned
+	}
+
+	schema := s.schema(bodyType)
+	content := make(map[string]any, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = map[string]any{"schema": schema}
+	}
+	return content
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// schemaEnums son los valores admitidos por los tipos enumerados del dominio.
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(domain.EventType("")): {
+		string(domain.EventUserCreated), string(domain.EventUserUpdated), string(domain.EventUserDeleted),
+	},
+	reflect.TypeOf(domain.BatchOperationType("")): {
+		string(domain.BatchCreate), string(domain.BatchUpdate), string(domain.BatchDelete),
+	},
+	reflect.TypeOf(domain.ImportFormat("")): {string(domain.ImportCSV), string(domain.ImportNDJSON)},
+	reflect.TypeOf(domain.ImportStatus("")): {
+		string(domain.ImportPending), string(domain.ImportRunning), string(domain.ImportCompleted), string(domain.ImportFailed),
+	},
+	reflect.TypeOf(domain.UserStatus("")): {
+		string(domain.UserPending), string(domain.UserActive), string(domain.UserSuspended), string(domain.UserLocked), string(domain.UserDeactivated),
+	},
+	reflect.TypeOf(domain.WebhookDeliveryState("")): {
+		string(domain.DeliveryPending), string(domain.DeliverySucceeded), string(domain.DeliveryFailed),
+	},
+}
+
+// schema retorna el esquema JSON (draft 2020-12) del tipo t. Los structs con
+// nombre se registran en components y se referencian con $ref.
+func (s *schemaRegistry) schema(t reflect.Type) map[string]any {
+	if t == nil {
+		return map[string]any{}
+	}
+	if values, ok := schemaEnums[t]; ok {
+		return map[string]any{"type": "string", "enum": values}
+	}
+
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(s.schema(t.Elem()))
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Array:
+		return map[string]any{"type": "array", "items": s.schema(t.Elem())}
+	case reflect.Slice:
+		// encoding/json codifica los slices nil como null.
+		return map[string]any{"type": []string{"array", "null"}, "items": s.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": []string{"object", "null"}, "additionalProperties": s.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		if _, ok := s.components[t.Name()]; !ok {
+			// Se reserva el nombre antes de recorrer los campos para admitir tipos recursivos.
+			s.components[t.Name()] = map[string]any{}
+			s.components[t.Name()] = s.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+// object genera el esquema de un struct a partir de sus tags json y validate.
+func (s *schemaRegistry) object(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	required := make([]string, 0)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := s.schema(field.Type)
+		if applyValidation(property, field.Type, field.Tag.Get("validate")) {
+			required = append(required, name)
+		}
+		properties[name] = property
+	}
+
+	object := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	return object
+}
+
+// applyValidation traduce las reglas de validator del campo a palabras
+// clave de JSON Schema sobre property. Retorna true si el campo es requerido.
+func applyValidation(property map[string]any, t reflect.Type, tag string) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			required = true
+			if t.Kind() == reflect.String {
+				property["minLength"] = 1
+			}
+		case "email":
+			property["format"] = "email"
+		case "url", "http_url":
+			property["format"] = "uri"
+		case "excludesall":
+			property["pattern"] = "^[^" + regexp.QuoteMeta(param) + "]*$"
+		case "oneof":
+			property["enum"] = strings.Fields(param)
+		case "min", "max":
+			limit, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			property[lengthKeyword(name, t.Kind())] = limit
+		}
+	}
+
+	return required
+}
+
+// lengthKeyword retorna la palabra clave de JSON Schema de una regla min o
+// max según el tipo del campo (longitud, cantidad de ítems o valor).
+func lengthKeyword(rule string, kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return rule + "Length"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rule + "Items"
+	default:
+		return map[string]string{"min": "minimum", "max": "maximum"}[rule]
+	}
+}
+
+// nullable permite además null en el esquema (OpenAPI 3.1 no usa "nullable").
+func nullable(schema map[string]any) map[string]any {
+	switch kind := schema["type"].(type) {
+	case string:
+		schema["type"] = []string{kind, "null"}
+		return schema
+	case []string:
+		return schema
+	}
+	if len(schema) == 0 {
+		return schema
+	}
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+}
+
+// negotiatedMediaTypes retorna los media types de codecs.
+func negotiatedMediaTypes() []string {
+	mediaTypes := make([]string, 0, len(codecs))
+	for _, c := range codecs {
+		mediaTypes = append(mediaTypes, c.mediaType)
+	}
+	return mediaTypes
+}
+
+//go:embed openapi_docs.html
+var openAPIDocsPage []byte
+
+// OpenAPIHandler sirve la especificación generada por NewRouter y la página
+// de referencia interactiva que la presenta.
+type OpenAPIHandler struct {
+	document []byte
+}
+
+// load serializa la especificación que sirve Spec.
+func (h *OpenAPIHandler) load(document map[string]any) error {
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("openapi: %w", err)
+	}
+	h.document = data
+	return nil
+}
+
+// Spec maneja la petición GET /openapi.json.
+func (h *OpenAPIHandler) Spec(w http.ResponseWriter, r *http.Request) *HTTPError {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(h.document); err != nil {
+		return NewHTTPError(errors.New("error writing response"), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// Docs maneja la petición GET /docs, una página Swagger UI sobre /openapi.json.
+func (h *OpenAPIHandler) Docs(w http.ResponseWriter, r *http.Request) *HTTPError {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write(openAPIDocsPage); err != nil {
+		return NewHTTPError(errors.New("error writing response"), http.StatusInternalServerError)
+	}
+	return nil
+}
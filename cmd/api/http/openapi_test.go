@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"user-api-restful/internal/application"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// newStubHandlers construye los handlers de NewRouter sobre servicios stub;
+// los que dependen de servicios concretos quedan sin servicio, ya que la
+// especificación solo recorre la tabla de rutas.
+func newStubHandlers() Handlers {
+	var users application.UserService = &stubFindService{}
+	return Handlers{
+		Users:        NewUserHandler(users),
+		Verification: NewEmailVerificationHandler(nil),
+		Batch:        NewBatchHandler(users, 10),
+		Imports:      NewUserImportHandler(nil, 1<<20),
+		Audit:        NewAuditHandler(&stubAuditService{}),
+		Events:       NewEventHandler(nil),
+		Webhooks:     NewWebhookHandler(nil),
+		Groups:       NewGroupHandler(nil),
+		Tenants:      NewTenantHandler(nil),
+		GraphQL:      NewGraphQLHandler(users, nil, GraphQLLimits{}),
+	}
+}
+
+func TestOpenAPIDocumentMatchesTheRouter(t *testing.T) {
+	router, err := NewRouter(newStubHandlers(), RouterOptions{})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	document, err := OpenAPIDocument(router)
+	if err != nil {
+		t.Fatalf("OpenAPIDocument: %v", err)
+	}
+
+	paths := document["paths"].(map[string]any)
+	for _, path := range []string{"/users", "/users/{id}", "/v1/users/{id}", "/v2/users/{id}", "/users/events", "/openapi.json"} {
+		if _, ok := paths[path]; !ok {
+			t.Errorf("path %s is missing", path)
+		}
+	}
+
+	schemas := document["components"].(map[string]any)["schemas"].(map[string]any)
+	create := schemas["UserCreateRequest"].(map[string]any)
+	if required := create["required"]; !reflect.DeepEqual(required, []string{"name", "username", "email"}) {
+		t.Errorf("UserCreateRequest.required = %v", required)
+	}
+	email := create["properties"].(map[string]any)["email"].(map[string]any)
+	if email["format"] != "email" {
+		t.Errorf("email schema = %v, want format email from the validate tag", email)
+	}
+	if _, ok := schemas["UserV2"]; !ok {
+		t.Error("the v2 user schema is missing")
+	}
+}
+
+func TestOpenAPIDocumentReportsDivergingRoutes(t *testing.T) {
+	router := chi.NewRouter()
+	apiRoutes(router, newStubHandlers())
+	// Ruta sin documentar; las rutas documentadas fuera de apiRoutes
+	// (e.g., /openapi.json) no están registradas.
+	router.Get("/users/{id}/avatar", func(http.ResponseWriter, *http.Request) {})
+
+	_, err := OpenAPIDocument(router)
+	if err == nil {
+		t.Fatal("OpenAPIDocument succeeded on a diverging route table")
+	}
+	for _, problem := range []string{
+		"route GET /users/{id}/avatar is not documented",
+		"documented operation GET /openapi.json is not routed",
+	} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("error %q does not report %q", err, problem)
+		}
+	}
+}
+
+func TestOpenAPISpecIsServed(t *testing.T) {
+	router, err := NewRouter(newStubHandlers(), RouterOptions{})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	var document map[string]any
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &document) != nil || document["openapi"] != "3.1.0" {
+		t.Errorf("GET /openapi.json = %d %.80s", w.Code, w.Body)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "/openapi.json") {
+		t.Errorf("GET /docs = %d", w.Code)
+	}
+}
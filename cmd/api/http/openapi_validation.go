@@ -0,0 +1,576 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// OpenAPIValidation selecciona qué valida el middleware de NewRouter contra
+// la especificación publicada en /openapi.json.
+type OpenAPIValidation struct {
+	// Requests valida parámetros de ruta, query, cabeceras y cuerpo; las
+	// violaciones se responden con 400 Bad Request.
+	Requests bool
+	// Responses valida el código de estado y el cuerpo de las respuestas;
+	// una violación se registra y se responde 500 en su lugar. Pensado para
+	// pruebas y entornos de staging, donde detecta divergencias del contrato.
+	Responses bool
+}
+
+// openAPIValidator valida las peticiones y respuestas de las rutas
+// documentadas contra el documento OpenAPI servido.
+type openAPIValidator struct {
+	options  OpenAPIValidation
+	routes   chi.Routes
+	document map[string]any
+}
+
+// load prepara el validador con la tabla de rutas y el documento servido,
+// decodificado de su JSON para validar exactamente lo que se publica.
+func (v *openAPIValidator) load(routes chi.Routes, document []byte) error {
+	v.routes = routes
+	if err := json.Unmarshal(document, &v.document); err != nil {
+		return fmt.Errorf("openapi: %w", err)
+	}
+	return nil
+}
+
+// Middleware valida cada petición dirigida a una operación documentada y,
+// si está habilitado, su respuesta. Las rutas desconocidas se delegan sin
+// validar (chi responde 404/405).
+func (v *openAPIValidator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 1. Búsqueda de la operación
+		rctx := chi.NewRouteContext()
+		pattern := normalizeRoute(v.routes.Find(rctx, r.Method, r.URL.Path))
+		if version, _ := splitVersion(pattern); version == 0 && pattern != "" && apiVersion(r) != APIVersion1 {
+			// Las rutas sin prefijo se validan con los esquemas de la versión
+			// elegida por media type.
+			pattern = "/v" + strconv.Itoa(apiVersion(r)) + pattern
+		}
+		operation := v.operation(r.Method, pattern)
+		if operation == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// 2. Validación de la petición
+		if v.options.Requests {
+			if violations := v.validateRequest(r, rctx, operation); len(violations) > 0 {
+				writeHTTPError(w, r, NewHTTPError(
+					errors.New("request does not match the API specification: "+strings.Join(violations, "; ")),
+					http.StatusBadRequest,
+				))
+				return
+			}
+		}
+
+		// 3. Validación de la respuesta (solo de las que pueden retenerse en memoria)
+		if !v.options.Responses || !bufferable(operation) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buffered := &bufferedResponse{header: make(http.Header)}
+		next.ServeHTTP(buffered, r)
+
+		if violations := v.validateResponse(buffered, operation); len(violations) > 0 {
+			log.Printf("[OpenAPI] %s %s: response does not match the specification: %s",
+				r.Method, r.URL.Path, strings.Join(violations, "; "))
+			writeHTTPError(w, r, NewHTTPError(
+				errors.New("response does not match the API specification: "+strings.Join(violations, "; ")),
+				http.StatusInternalServerError,
+			))
+			return
+		}
+
+		buffered.flushTo(w)
+	})
+}
+
+// operation retorna el Operation Object del método y patrón, o nil.
+func (v *openAPIValidator) operation(method, pattern string) map[string]any {
+	if pattern == "" {
+		return nil
+	}
+	item, _ := lookup(v.document, "paths", pattern).(map[string]any)
+	operation, _ := item[strings.ToLower(method)].(map[string]any)
+	return operation
+}
+
+// validateRequest valida los parámetros y el cuerpo de la petición.
+func (v *openAPIValidator) validateRequest(r *http.Request, rctx *chi.Context, operation map[string]any) []string {
+	violations := make([]string, 0)
+
+	parameters, _ := operation["parameters"].([]any)
+	for _, raw := range parameters {
+		parameter, _ := raw.(map[string]any)
+		name, _ := parameter["name"].(string)
+		in, _ := parameter["in"].(string)
+		schema, _ := parameter["schema"].(map[string]any)
+
+		var value string
+		var present bool
+		switch in {
+		case "path":
+			value = rctx.URLParam(name)
+			present = value != ""
+		case "query":
+			present = r.URL.Query().Has(name)
+			value = r.URL.Query().Get(name)
+		case "header":
+			value = r.Header.Get(name)
+			present = value != ""
+		}
+
+		if !present {
+			if required, _ := parameter["required"].(bool); required {
+				violations = append(violations, in+" parameter "+name+" is required")
+			}
+			continue
+		}
+
+		for _, violation := range v.validateParameter(schema, value) {
+			violations = append(violations, in+" parameter "+name+violation)
+		}
+	}
+
+	body, ok := operation["requestBody"].(map[string]any)
+	if !ok {
+		return violations
+	}
+
+	schema, selected := v.requestSchema(r, body)
+	if selected == nil || selected == xmlCodec() {
+		// Los formatos sin codec (e.g., CSV) y XML, cuyos valores no tienen
+		// tipo, se dejan al handler.
+		return violations
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return append(violations, "body could not be read")
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		if required, _ := body["required"].(bool); !required {
+			return violations
+		}
+		return append(violations, "body is required")
+	}
+
+	value, err := genericValue(selected, data)
+	if err != nil {
+		return append(violations, "body is not valid "+selected.mediaType)
+	}
+
+	for _, violation := range v.validate(schema, value, "") {
+		violations = append(violations, "body"+violation)
+	}
+	return violations
+}
+
+// requestSchema retorna el esquema del cuerpo para el Content-Type de la
+// petición (JSON si no se indica) y su codec, o nil si no corresponde validarlo.
+func (v *openAPIValidator) requestSchema(r *http.Request, body map[string]any) (map[string]any, *codec) {
+	selected := codecs[0]
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, nil
+		}
+		if selected = findCodec(mediaType); selected == nil {
+			return nil, nil
+		}
+	}
+
+	schema, ok := lookup(body, "content", selected.mediaType, "schema").(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	return schema, selected
+}
+
+// validateParameter valida un parámetro, recibido como texto, según el tipo de su esquema.
+func (v *openAPIValidator) validateParameter(schema map[string]any, raw string) []string {
+	var value any = raw
+	switch {
+	case hasType(schema, "integer"):
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			return []string{": must be an integer"}
+		}
+		value = json.Number(raw)
+	case hasType(schema, "number"):
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return []string{": must be a number"}
+		}
+		value = json.Number(raw)
+	case hasType(schema, "boolean"):
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return []string{": must be a boolean"}
+		}
+		value = parsed
+	}
+
+	return v.validate(schema, value, "")
+}
+
+// validateResponse valida el código de estado y el cuerpo de una respuesta.
+func (v *openAPIValidator) validateResponse(response *bufferedResponse, operation map[string]any) []string {
+	status := response.statusCode()
+	documented, ok := lookup(operation, "responses", strconv.Itoa(status)).(map[string]any)
+	if !ok {
+		if status == http.StatusInternalServerError {
+			return nil
+		}
+		return []string{"status " + strconv.Itoa(status) + " is not documented"}
+	}
+
+	content, ok := documented["content"].(map[string]any)
+	if !ok {
+		if response.body.Len() > 0 {
+			return []string{"status " + strconv.Itoa(status) + " is documented without a body"}
+		}
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(response.header.Get("Content-Type"))
+	schema, ok := lookup(content, mediaType, "schema").(map[string]any)
+	if !ok {
+		return []string{"Content-Type " + mediaType + " is not documented for status " + strconv.Itoa(status)}
+	}
+
+	selected := findCodec(mediaType)
+	if selected == nil || selected == xmlCodec() {
+		return nil
+	}
+
+	value, err := genericValue(selected, response.body.Bytes())
+	if err != nil {
+		return []string{"body is not valid " + mediaType}
+	}
+
+	violations := make([]string, 0)
+	for _, violation := range v.validate(schema, value, "") {
+		violations = append(violations, "body"+violation)
+	}
+	return violations
+}
+
+// validate valida value contra el subconjunto de JSON Schema 2020-12 que
+// genera OpenAPIDocument. Cada violación comienza con su ubicación como
+// JSON Pointer (e.g., " /email: must be a valid email address").
+func (v *openAPIValidator) validate(schema map[string]any, value any, pointer string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		target, ok := lookup(v.document, strings.Split(strings.TrimPrefix(ref, "#/"), "/")...).(map[string]any)
+		if !ok {
+			return []string{at(pointer) + "unresolvable reference " + ref}
+		}
+		return v.validate(target, value, pointer)
+	}
+
+	if alternatives, ok := schema["anyOf"].([]any); ok {
+		for _, alternative := range alternatives {
+			if candidate, ok := alternative.(map[string]any); ok && len(v.validate(candidate, value, pointer)) == 0 {
+				return nil
+			}
+		}
+		return []string{at(pointer) + "does not match any of the allowed schemas"}
+	}
+
+	if _, ok := schema["type"]; ok && !hasType(schema, jsonType(value)) {
+		if !(jsonType(value) == "integer" && hasType(schema, "number")) {
+			return []string{at(pointer) + "must be of type " + describeType(schema["type"])}
+		}
+	}
+
+	violations := make([]string, 0)
+	if enum, ok := schema["enum"].([]any); ok && !containsValue(enum, value) {
+		violations = append(violations, at(pointer)+"must be one of "+joinValues(enum))
+	}
+
+	switch typed := value.(type) {
+	case string:
+		violations = append(violations, validateString(schema, typed, pointer)...)
+	case json.Number:
+		number, _ := typed.Float64()
+		if minimum, ok := schema["minimum"].(float64); ok && number < minimum {
+			violations = append(violations, at(pointer)+"must be at least "+strconv.FormatFloat(minimum, 'f', -1, 64))
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && number > maximum {
+			violations = append(violations, at(pointer)+"must be at most "+strconv.FormatFloat(maximum, 'f', -1, 64))
+		}
+	case []any:
+		if minItems, ok := schema["minItems"].(float64); ok && float64(len(typed)) < minItems {
+			violations = append(violations, at(pointer)+"must have at least "+strconv.Itoa(int(minItems))+" items")
+		}
+		if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(typed)) > maxItems {
+			violations = append(violations, at(pointer)+"must have at most "+strconv.Itoa(int(maxItems))+" items")
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range typed {
+				violations = append(violations, v.validate(items, item, pointer+"/"+strconv.Itoa(i))...)
+			}
+		}
+	case map[string]any:
+		if required, ok := schema["required"].([]any); ok {
+			for _, name := range required {
+				if _, present := typed[name.(string)]; !present {
+					violations = append(violations, at(pointer+"/"+name.(string))+"is required")
+				}
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]any)
+		additional, _ := schema["additionalProperties"].(map[string]any)
+
+		names := make([]string, 0, len(typed))
+		for name := range typed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			child := pointer + "/" + escapePointer(name)
+			if property, ok := properties[name].(map[string]any); ok {
+				violations = append(violations, v.validate(property, typed[name], child)...)
+			} else if additional != nil {
+				violations = append(violations, v.validate(additional, typed[name], child)...)
+			}
+		}
+	}
+
+	return violations
+}
+
+// validateString aplica las restricciones de longitud, patrón y formato.
+func validateString(schema map[string]any, value, pointer string) []string {
+	violations := make([]string, 0)
+	length := len([]rune(value))
+
+	if minLength, ok := schema["minLength"].(float64); ok && float64(length) < minLength {
+		if minLength == 1 {
+			violations = append(violations, at(pointer)+"cannot be blank")
+		} else {
+			violations = append(violations, at(pointer)+"must be at least "+strconv.Itoa(int(minLength))+" characters long")
+		}
+	}
+	if maxLength, ok := schema["maxLength"].(float64); ok && float64(length) > maxLength {
+		violations = append(violations, at(pointer)+"must be at most "+strconv.Itoa(int(maxLength))+" characters long")
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if matcher, err := regexp.Compile(pattern); err == nil && !matcher.MatchString(value) {
+			violations = append(violations, at(pointer)+"must match the pattern "+pattern)
+		}
+	}
+
+	switch schema["format"] {
+	case "email":
+		if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+			violations = append(violations, at(pointer)+"must be a valid email address")
+		}
+	case "uri":
+		if parsed, err := url.Parse(value); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			violations = append(violations, at(pointer)+"must be an absolute URI")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			violations = append(violations, at(pointer)+"must be an RFC 3339 date-time")
+		}
+	}
+
+	return violations
+}
+
+// genericValue decodifica data con el codec y lo normaliza a su
+// representación JSON, con los números como json.Number.
+func genericValue(selected *codec, data []byte) (any, error) {
+	if selected != codecs[0] {
+		value, err := selected.unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// jsonType retorna el tipo JSON Schema de un valor genérico.
+func jsonType(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := typed.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return ""
+	}
+}
+
+// hasType indica si el esquema admite el tipo indicado.
+func hasType(schema map[string]any, kind string) bool {
+	switch declared := schema["type"].(type) {
+	case string:
+		return declared == kind
+	case []any:
+		for _, candidate := range declared {
+			if candidate == kind {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// describeType retorna el tipo declarado en un esquema para los mensajes de error.
+func describeType(declared any) string {
+	if kinds, ok := declared.([]any); ok {
+		return joinValues(kinds)
+	}
+	return fmt.Sprint(declared)
+}
+
+// containsValue indica si value es uno de los valores de enum.
+func containsValue(enum []any, value any) bool {
+	for _, candidate := range enum {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// joinValues enumera valores para los mensajes de error.
+func joinValues(values []any) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprint(value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// at prefija el mensaje con la ubicación del valor.
+func at(pointer string) string {
+	if pointer == "" {
+		return ": "
+	}
+	return " " + pointer + ": "
+}
+
+// escapePointer escapa un nombre de propiedad para un JSON Pointer (RFC 6901).
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+// lookup recorre claves anidadas de un documento decodificado de JSON.
+func lookup(value any, keys ...string) any {
+	for _, key := range keys {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+// xmlCodec retorna el codec XML, cuyos valores decodificados no tienen tipo.
+func xmlCodec() *codec {
+	return findCodec("application/xml")
+}
+
+// bufferable indica si todas las respuestas de la operación usan formatos de
+// codecs; las de streaming (SSE, exportación) no se retienen para validarse.
+func bufferable(operation map[string]any) bool {
+	responses, _ := operation["responses"].(map[string]any)
+	for _, response := range responses {
+		content, _ := lookup(response, "content").(map[string]any)
+		for mediaType := range content {
+			if findCodec(mediaType) == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// bufferedResponse retiene la respuesta del handler hasta validarla.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header retorna las cabeceras retenidas.
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+// WriteHeader retiene el primer código de estado.
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+// Write retiene el cuerpo.
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// statusCode retorna el código de estado (200 si el handler no lo indicó).
+func (b *bufferedResponse) statusCode() int {
+	if b.status == 0 {
+		return http.StatusOK
+	}
+	return b.status
+}
+
+// flushTo envía la respuesta retenida al cliente.
+func (b *bufferedResponse) flushTo(w http.ResponseWriter) {
+	for key, values := range b.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(b.statusCode())
+	_, _ = w.Write(b.body.Bytes())
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+// stubContractService retorna user desde FindById, válido o no según la prueba.
+type stubContractService struct {
+	application.UserService
+	user domain.User
+}
+
+func (s *stubContractService) FindById(_ context.Context, id string, _ ...string) (*domain.User, error) {
+	user := s.user
+	user.ID = id
+	return &user, nil
+}
+
+// newValidatingRouter construye el router con la validación completa de
+// peticiones y respuestas sobre el servicio indicado.
+func newValidatingRouter(t *testing.T, users application.UserService) http.Handler {
+	t.Helper()
+	t.Setenv("AUTH_DISABLED", "true")
+
+	handlers := newStubHandlers()
+	handlers.Users = NewUserHandler(users)
+	router, err := NewRouter(handlers, RouterOptions{Validation: OpenAPIValidation{Requests: true, Responses: true}})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	return router
+}
+
+// serveRequest ejecuta la petición sobre handler.
+func serveRequest(handler http.Handler, method, target, contentType, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestResponseValidationAcceptsConformingResponses(t *testing.T) {
+	service := &stubContractService{user: domain.User{Name: "Jane", Username: "jane", Email: "jane@example.com", Status: domain.UserActive}}
+	router := newValidatingRouter(t, service)
+
+	for _, target := range []string{"/users/01HZX3Y4Z5", "/v2/users/01HZX3Y4Z5", "/users/01HZX3Y4Z5?fields=id,status"} {
+		if w := serveRequest(router, http.MethodGet, target, "", ""); w.Code != http.StatusOK {
+			t.Errorf("GET %s = %d %s", target, w.Code, w.Body)
+		}
+	}
+}
+
+func TestResponseValidationReportsSchemaViolations(t *testing.T) {
+	// Un estado fuera del enum del esquema de User viola el contrato.
+	service := &stubContractService{user: domain.User{Name: "Jane", Username: "jane", Email: "jane@example.com", Status: "archived"}}
+	router := newValidatingRouter(t, service)
+
+	w := serveRequest(router, http.MethodGet, "/users/01HZX3Y4Z5", "", "")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500 for a response that violates the schema", w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "response does not match the API specification") || !strings.Contains(body, "/status") {
+		t.Errorf("body = %s, want the violation of /status", body)
+	}
+}
+
+func TestRequestValidationReportsViolationsInTheErrorFormat(t *testing.T) {
+	router := newValidatingRouter(t, &stubContractService{})
+
+	tests := []struct {
+		name, method, target, body string
+		want                       string
+	}{
+		{"invalid email", http.MethodPost, "/users", `{"name":"Jane","username":"jane","email":"not-an-email"}`, "/email"},
+		{"missing field", http.MethodPost, "/users", `{"name":"Jane","email":"jane@example.com"}`, "username"},
+		{"invalid query", http.MethodGet, "/users?include_deleted=maybe", "", "include_deleted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveRequest(router, tt.method, tt.target, "application/json", tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", w.Code)
+			}
+			body := w.Body.String()
+			if !strings.Contains(body, `"status":400`) || !strings.Contains(body, "request does not match the API specification") || !strings.Contains(body, tt.want) {
+				t.Errorf("body = %s, want an ErrorResponse mentioning %s", body, tt.want)
+			}
+		})
+	}
+}
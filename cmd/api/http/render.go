@@ -0,0 +1,402 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// codec serializa y deserializa los cuerpos en un formato (media type).
+//
+// Todos los formatos parten de la representación JSON de los valores (los
+// tags json de los DTOs), de modo que los nombres de campo y los valores son
+// los mismos en cualquier formato.
+type codec struct {
+	// mediaType es el Content-Type con el que se responde.
+	mediaType string
+	// aliases son los media types adicionales aceptados para este formato.
+	aliases []string
+	// marshal convierte la representación genérica (decodificada de JSON) al formato.
+	marshal func(value any) ([]byte, error)
+	// unmarshal convierte el formato a una representación genérica codificable en JSON.
+	unmarshal func(data []byte) (any, error)
+}
+
+// codecs son los formatos soportados, en orden de preferencia; el primero
+// (JSON) es el formato por defecto.
+var codecs = []*codec{
+	{
+		mediaType: "application/json",
+		marshal:   json.Marshal,
+		unmarshal: func(data []byte) (any, error) {
+			var value any
+			err := json.Unmarshal(data, &value)
+			return value, err
+		},
+	},
+	{
+		mediaType: "application/xml",
+		aliases:   []string{"text/xml"},
+		marshal:   marshalXML,
+		unmarshal: unmarshalXML,
+	},
+	{
+		mediaType: "application/cbor",
+		marshal:   cbor.Marshal,
+		unmarshal: func(data []byte) (any, error) {
+			var value any
+			err := cborDecMode.Unmarshal(data, &value)
+			return value, err
+		},
+	},
+	{
+		mediaType: "application/msgpack",
+		aliases:   []string{"application/x-msgpack", "application/vnd.msgpack"},
+		marshal:   msgpack.Marshal,
+		unmarshal: func(data []byte) (any, error) {
+			decoder := msgpack.NewDecoder(bytes.NewReader(data))
+			decoder.SetMapDecoder(func(d *msgpack.Decoder) (any, error) {
+				return d.DecodeUntypedMap()
+			})
+			return decoder.DecodeInterface()
+		},
+	},
+	{
+		mediaType: "application/yaml",
+		aliases:   []string{"application/x-yaml", "text/yaml"},
+		marshal:   yaml.Marshal,
+		unmarshal: func(data []byte) (any, error) {
+			var value any
+			err := yaml.Unmarshal(data, &value)
+			return value, err
+		},
+	},
+}
+
+// cborDecMode decodifica los mapas CBOR como map[string]any, codificables en JSON.
+var cborDecMode, _ = cbor.DecOptions{DefaultMapType: reflect.TypeOf(map[string]any(nil))}.DecMode()
+
+// codecKey es la clave privada del codec negociado en el context.Context.
+type codecKey struct{}
+
+// NegotiationMiddleware negocia el formato de la respuesta según la cabecera
+// Accept y lo guarda en el contexto para render. Responde 406 Not Acceptable
+// si ninguno de los formatos aceptados está soportado.
+func NegotiationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		selected := negotiate(r.Header.Get("Accept"))
+		if selected == nil {
+			writeHTTPError(w, r, NewHTTPError(errors.New("none of the accepted media types is supported; use "+supportedMediaTypes()), http.StatusNotAcceptable))
+			return
+		}
+
+		w.Header().Add("Vary", "Accept")
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), codecKey{}, selected)))
+	})
+}
+
+// render escribe value con el código de estado indicado en el formato
+// negociado para la petición (JSON por defecto).
+func render(w http.ResponseWriter, r *http.Request, status int, value any) *HTTPError {
+	body, err := encodeBody(responseCodec(r), value)
+	if err != nil {
+		return NewHTTPError(errors.New("error encoding response"), http.StatusInternalServerError)
+	}
+
+	w.Header().Set("Content-Type", responseCodec(r).mediaType)
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+
+	return nil
+}
+
+// decodeBody deserializa el cuerpo de la petición en target según su
+// Content-Type (JSON si no se indica). Retorna 415 si el formato no está
+// soportado y 400 si el cuerpo es inválido.
+func decodeBody(r *http.Request, target any) *HTTPError {
+	selected := codecs[0]
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if selected = findCodec(mediaType); err != nil || selected == nil {
+			return NewHTTPError(errors.New("unsupported Content-Type; use "+supportedMediaTypes()), http.StatusUnsupportedMediaType)
+		}
+	}
+
+	if selected == codecs[0] {
+		if err := json.NewDecoder(r.Body).Decode(target); err != nil {
+			return NewHTTPError(errors.New("invalid request body format"), http.StatusBadRequest)
+		}
+		return nil
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return NewHTTPError(errors.New("invalid request body format"), http.StatusBadRequest)
+	}
+
+	// Los demás formatos se convierten a JSON para aplicar los mismos tags y reglas.
+	value, err := selected.unmarshal(data)
+	if err == nil {
+		data, err = json.Marshal(value)
+	}
+	if err == nil {
+		err = json.Unmarshal(data, target)
+	}
+	if err != nil {
+		return NewHTTPError(errors.New("invalid request body format"), http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// responseCodec retorna el codec negociado para la petición, o JSON.
+func responseCodec(r *http.Request) *codec {
+	if selected, ok := r.Context().Value(codecKey{}).(*codec); ok {
+		return selected
+	}
+	if selected := negotiate(r.Header.Get("Accept")); selected != nil {
+		return selected
+	}
+	return codecs[0]
+}
+
+// encodeBody serializa value con el codec indicado, a partir de su representación JSON.
+func encodeBody(selected *codec, value any) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil || selected == codecs[0] {
+		return append(data, '\n'), err
+	}
+
+	var generic any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return selected.marshal(normalizeNumbers(generic))
+}
+
+// negotiate elige el codec preferido según la cabecera Accept (RFC 9110,
+// con pesos q). Una cabecera vacía acepta JSON; retorna nil si ningún
+// formato aceptado está soportado.
+func negotiate(accept string) *codec {
+	if strings.TrimSpace(accept) == "" {
+		return codecs[0]
+	}
+
+	type candidate struct {
+		mediaType string
+		quality   float64
+		order     int
+	}
+
+	candidates := make([]candidate, 0)
+	for i, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{mediaType: mediaType, quality: quality, order: i})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	for _, c := range candidates {
+		if c.mediaType == "*/*" || c.mediaType == "application/*" {
+			return codecs[0]
+		}
+		if selected := findCodec(c.mediaType); selected != nil {
+			return selected
+		}
+	}
+
+	return nil
+}
+
+// findCodec retorna el codec del media type indicado, o nil.
+func findCodec(mediaType string) *codec {
+	for _, c := range codecs {
+		if c.mediaType == mediaType {
+			return c
+		}
+		for _, alias := range c.aliases {
+			if alias == mediaType {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// supportedMediaTypes lista los media types soportados.
+func supportedMediaTypes() string {
+	mediaTypes := make([]string, len(codecs))
+	for i, c := range codecs {
+		mediaTypes[i] = c.mediaType
+	}
+	return strings.Join(mediaTypes, ", ")
+}
+
+// normalizeNumbers convierte los json.Number de la representación genérica a
+// int64 o float64, para que cada formato los codifique como números nativos.
+func normalizeNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return value
+}
+
+// marshalXML codifica la representación genérica como XML: la raíz es
+// <response>, cada clave de un objeto es un elemento y cada elemento de un
+// arreglo es un <item>. Los valores nulos se omiten.
+func marshalXML(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(&buffer)
+	if err := encodeXMLElement(encoder, "response", value); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// encodeXMLElement escribe value como el elemento name.
+func encodeXMLElement(encoder *xml.Encoder, name string, value any) error {
+	if value == nil {
+		return nil
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := encodeXMLElement(encoder, key, v[key]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := encodeXMLElement(encoder, "item", item); err != nil {
+				return err
+			}
+		}
+	default:
+		text, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if s, ok := v.(string); ok {
+			text = []byte(s)
+		}
+		if err := encoder.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+// unmarshalXML decodifica un documento XML a la representación genérica:
+// los elementos con hijos son objetos (los hijos <item> forman arreglos) y
+// los elementos hoja son strings.
+func unmarshalXML(data []byte) (any, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return decodeXMLElement(decoder, start)
+		}
+	}
+}
+
+// decodeXMLElement lee el contenido del elemento start hasta su cierre.
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement) (any, error) {
+	var text strings.Builder
+	object := map[string]any{}
+	items := make([]any, 0)
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(decoder, t)
+			if err != nil {
+				return nil, err
+			}
+			if t.Name.Local == "item" {
+				items = append(items, child)
+			} else {
+				object[t.Name.Local] = child
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			switch {
+			case len(items) > 0:
+				return items, nil
+			case len(object) > 0:
+				return object, nil
+			default:
+				return strings.TrimSpace(text.String()), nil
+			}
+		}
+	}
+}
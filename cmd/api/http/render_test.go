@@ -0,0 +1,143 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-api-restful/internal/domain"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", "application/json"},
+		{"application/xml", "application/xml"},
+		{"text/xml", "application/xml"},
+		{"application/x-msgpack", "application/msgpack"},
+		{"text/yaml;q=0.5, application/cbor;q=0.9", "application/cbor"},
+		{"text/html, application/yaml", "application/yaml"},
+		{"text/html, */*;q=0.1", "application/json"},
+		{"application/*", "application/json"},
+		{"application/xml;q=0, application/json", "application/json"},
+	}
+	for _, tt := range tests {
+		selected := negotiate(tt.accept)
+		if selected == nil || selected.mediaType != tt.want {
+			t.Errorf("negotiate(%q) = %v, want %s", tt.accept, selected, tt.want)
+		}
+	}
+
+	for _, accept := range []string{"text/html", "application/xml;q=0"} {
+		if selected := negotiate(accept); selected != nil {
+			t.Errorf("negotiate(%q) = %s, want nil", accept, selected.mediaType)
+		}
+	}
+}
+
+// roundTripBody es un cuerpo de prueba con los tipos de valores habituales.
+type roundTripBody struct {
+	Name    string   `json:"name"`
+	Count   int      `json:"count"`
+	Ratio   float64  `json:"ratio"`
+	Enabled bool     `json:"enabled"`
+	Tags    []string `json:"tags"`
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	want := roundTripBody{Name: "Jane <Doe>", Count: 42, Ratio: 0.5, Enabled: true, Tags: []string{"a", "b"}}
+
+	for _, selected := range codecs {
+		t.Run(selected.mediaType, func(t *testing.T) {
+			value := any(want)
+			if selected.mediaType == "application/xml" {
+				// En XML las hojas son strings: solo se envían campos de texto.
+				value = map[string]any{"name": want.Name, "tags": want.Tags}
+			}
+			body, err := encodeBody(selected, value)
+			if err != nil {
+				t.Fatalf("encodeBody: %v", err)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			r.Header.Set("Content-Type", selected.mediaType+"; charset=utf-8")
+			var got roundTripBody
+			if httpErr := decodeBody(r, &got); httpErr != nil {
+				t.Fatalf("decodeBody(%s): %v", body, httpErr.Error)
+			}
+
+			if selected.mediaType == "application/xml" {
+				if got.Name != want.Name || len(got.Tags) != 2 {
+					t.Errorf("got %+v", got)
+				}
+				return
+			}
+			if got.Name != want.Name || got.Count != want.Count || got.Ratio != want.Ratio || !got.Enabled || len(got.Tags) != 2 {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestMarshalXML(t *testing.T) {
+	body, err := encodeBody(findCodec("application/xml"), map[string]any{"name": "Jane & co", "tags": []string{"a"}, "missing": nil, "count": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<response><count>2</count><name>Jane &amp; co</name><tags><item>a</item></tags></response>`
+	if !strings.HasSuffix(string(body), want) {
+		t.Errorf("xml = %s, want suffix %s", body, want)
+	}
+}
+
+func TestDecodeBodyRejectsUnsupportedAndInvalidBodies(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+		want        int
+	}{
+		{"text/plain", "hello", http.StatusUnsupportedMediaType},
+		{"not a media type;;", "{}", http.StatusUnsupportedMediaType},
+		{"application/json", "{", http.StatusBadRequest},
+		{"application/yaml", "name: [", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+		r.Header.Set("Content-Type", tt.contentType)
+		var target roundTripBody
+		if httpErr := decodeBody(r, &target); httpErr == nil || httpErr.Status != tt.want {
+			t.Errorf("%s %q: err = %v, want status %d", tt.contentType, tt.body, httpErr, tt.want)
+		}
+	}
+}
+
+func TestNegotiationMiddlewareRendersResponsesAndErrors(t *testing.T) {
+	handler := NegotiationMiddleware(ErrorHandlerWrapper(func(w http.ResponseWriter, r *http.Request) *HTTPError {
+		if r.URL.Query().Get("fail") != "" {
+			return NewHTTPError(domain.ErrUserNotFound, http.StatusNotFound)
+		}
+		return render(w, r, http.StatusOK, map[string]string{"name": "jane"})
+	}))
+
+	serve := func(target, accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := serve("/", "application/yaml"); w.Header().Get("Content-Type") != "application/yaml" || w.Body.String() != "name: jane\n" {
+		t.Errorf("yaml: %q %q", w.Header().Get("Content-Type"), w.Body)
+	}
+	if w := serve("/?fail=1", "application/xml"); w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "<message>user not found</message>") {
+		t.Errorf("xml error: %d %q", w.Code, w.Body)
+	}
+	if w := serve("/", "text/html"); w.Code != http.StatusNotAcceptable || w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("unsupported Accept: %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,250 @@
+package http
+
+import (
+	"strconv"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Handlers agrupa los handlers y servicios que NewRouter expone como rutas.
+type Handlers struct {
+	Users *UserHandler
+	// Verification confirma y reenvía la verificación del email.
+	Verification *EmailVerificationHandler
+	Batch        *BatchHandler
+	Imports      *UserImportHandler
+	Audit        *AuditHandler
+	Events       *EventHandler
+	Webhooks     *WebhookHandler
+	Groups       *GroupHandler
+	Tenants      *TenantHandler
+	GraphQL      *GraphQLHandler
+	Idempotency  *application.IdempotencyService
+	// APIKeys verifica las claves de API; nil = solo Basic Auth.
+	APIKeys *application.APIKeyService
+	// TenantResolver resuelve la organización de cada petición; nil = todas
+	// operan sobre la organización por defecto.
+	TenantResolver *application.TenantService
+}
+
+// RouterOptions configura el comportamiento transversal de NewRouter.
+type RouterOptions struct {
+	// Validation indica qué se valida contra la especificación OpenAPI.
+	Validation OpenAPIValidation
+	// Deprecations anuncia, por versión de la API, su obsolescencia y retiro.
+	Deprecations map[int]APIDeprecation
+}
+
+// NewRouter construye la tabla de rutas de la API. Es la fuente de la
+// especificación OpenAPI publicada en /openapi.json: falla si alguna ruta
+// registrada no está documentada o si se documenta una ruta inexistente
+// (ver OpenAPIDocument).
+//
+// Las rutas de la API se montan bajo cada versión (/v1, /v2) y sin prefijo;
+// en este último caso la versión se elige por media type (e.g., Accept:
+// application/vnd.user-api.v2+json) y por defecto es la v1.
+func NewRouter(h Handlers, options RouterOptions) (*chi.Mux, error) {
+	router := chi.NewRouter()
+
+	router.Use(RequestIDMiddleware)
+
+	openAPIHandler := &OpenAPIHandler{}
+	validator := &openAPIValidator{options: options.Validation}
+	metrics := NewVersionMetrics()
+
+	// GET /openapi.json - OpenAPI 3.1 document generated from this route table
+	router.Get("/openapi.json", ErrorHandlerWrapper(openAPIHandler.Spec))
+
+	// GET /docs - Interactive API reference (Swagger UI)
+	router.Get("/docs", ErrorHandlerWrapper(openAPIHandler.Docs))
+
+	router.Group(func(router chi.Router) {
+		router.Use(AuthAndLoggingMiddleware(h.APIKeys))
+		if h.TenantResolver != nil {
+			router.Use(TenantMiddleware(h.TenantResolver))
+		}
+
+		// GET /metrics - Request counters per API version (Prometheus text format)
+		router.Get("/metrics", ErrorHandlerWrapper(metrics.Metrics))
+
+		// POST /graphql - GraphQL queries and mutations over users
+		router.Post("/graphql", ErrorHandlerWrapper(h.GraphQL.Execute))
+
+		// /v1/..., /v2/... - Versioned route trees
+		for _, version := range apiVersions {
+			router.Route("/v"+strconv.Itoa(version), func(r chi.Router) {
+				r.Use(versionMiddleware(version, options.Deprecations, metrics))
+				r.Use(validator.Middleware)
+				apiRoutes(r, h)
+			})
+		}
+
+		// Unversioned routes - version negotiated via media type (v1 by default)
+		router.Group(func(r chi.Router) {
+			r.Use(versionMiddleware(0, options.Deprecations, metrics))
+			r.Use(validator.Middleware)
+			apiRoutes(r, h)
+		})
+	})
+
+	// La especificación se genera de las rutas efectivamente registradas.
+	document, err := OpenAPIDocument(router)
+	if err != nil {
+		return nil, err
+	}
+	if err := openAPIHandler.load(document); err != nil {
+		return nil, err
+	}
+	if err := validator.load(router, openAPIHandler.document); err != nil {
+		return nil, err
+	}
+
+	return router, nil
+}
+
+// apiRoutes registra las rutas de la API, comunes a todas las versiones; los
+// handlers adaptan la representación a la versión de la petición.
+func apiRoutes(router chi.Router, h Handlers) {
+	router.Route("/users", func(r chi.Router) {
+		// GET /users/export?format=&fields= - Stream users as CSV, NDJSON or JSON
+		r.Get("/export", ErrorHandlerWrapper(h.Users.Export))
+
+		// GET /users/events?types= - Server-Sent Events change feed (resumable with Last-Event-ID)
+		r.Get("/events", ErrorHandlerWrapper(h.Events.Stream))
+
+		// GET /users/imports/{id}/errors - Download the error report (CSV)
+		r.Get("/imports/{id}/errors", ErrorHandlerWrapper(h.Imports.Errors))
+
+		// The remaining resources render their responses in the format negotiated
+		// via Accept (JSON, XML, CBOR, MessagePack or YAML).
+		r.Group(func(r chi.Router) {
+			r.Use(NegotiationMiddleware)
+
+			// POST /users - Create a new user (retries with the same Idempotency-Key replay the original response)
+			r.With(IdempotencyMiddleware(h.Idempotency)).Post("/", ErrorHandlerWrapper(h.Users.CreateUser))
+
+			// GET /users - Retrieve all users (FindAll)
+			r.Get("/", ErrorHandlerWrapper(h.Users.FindAll))
+
+			// GET /users/search?q= - Full-text and fuzzy search (Search)
+			r.Get("/search", ErrorHandlerWrapper(h.Users.Search))
+
+			// POST /users/imports?format=&dry_run= - Import a CSV/NDJSON file (admin only)
+			r.Post("/imports", ErrorHandlerWrapper(h.Imports.Create))
+
+			// GET /users/imports/{id} - Import status and progress
+			r.Get("/imports/{id}", ErrorHandlerWrapper(h.Imports.FindById))
+
+			// POST /users/imports/{id}/resume - Resume a failed import
+			r.Post("/imports/{id}/resume", ErrorHandlerWrapper(h.Imports.Resume))
+
+			// PUT /users - Update an existing user (Update)
+			// Common pattern: Use PUT to replace the entire resource, often including the ID in the body.
+			r.Put("/", ErrorHandlerWrapper(h.Users.Update))
+
+			// GET /users/{id} - Retrieve a specific user by ID (FindById)
+			// The '{id}' is a URL parameter that FindById needs to extract.
+			r.Get("/{id}", ErrorHandlerWrapper(h.Users.FindById))
+
+			// DELETE /users/{id} - Soft-delete a specific user by ID (Delete)
+			r.Delete("/{id}", ErrorHandlerWrapper(h.Users.Delete))
+
+			// POST /users/{id}/restore - Undo a soft delete (Restore, admin only)
+			r.Post("/{id}/restore", ErrorHandlerWrapper(h.Users.Restore))
+
+			// POST /users/{id}:activate, :suspend, :lock, :unlock, :deactivate - Account status transitions (admin only)
+			for _, action := range domain.UserStatusActions {
+				r.Post("/{id}:"+string(action), ErrorHandlerWrapper(h.Users.ChangeStatus(action)))
+			}
+
+			// POST /users/{id}/verify-email - Confirm the user's email with the token sent by mail
+			r.Post("/{id}/verify-email", ErrorHandlerWrapper(h.Verification.Verify))
+
+			// POST /users/{id}:send-verification-email - Issue a new token and send the verification email again
+			r.Post("/{id}:send-verification-email", ErrorHandlerWrapper(h.Verification.Resend))
+
+			// GET /users/{id}/history - Audit trail of a user (admin/auditor only)
+			r.Get("/{id}/history", ErrorHandlerWrapper(h.Audit.History))
+
+			// GET /users/{id}/groups?transitive= - Groups of a user (direct or through nested groups)
+			r.Get("/{id}/groups", ErrorHandlerWrapper(h.Groups.UserGroups))
+		})
+	})
+
+	// POST /users:batch - Bulk create/update/delete (atomic or best_effort)
+	router.With(NegotiationMiddleware, IdempotencyMiddleware(h.Idempotency)).Post("/users:batch", ErrorHandlerWrapper(h.Batch.Batch))
+
+	// GET /audit?actor=&since= - Query the audit log (admin/auditor only)
+	router.With(NegotiationMiddleware).Get("/audit", ErrorHandlerWrapper(h.Audit.Find))
+
+	router.Route("/webhooks", func(r chi.Router) {
+		r.Use(NegotiationMiddleware)
+
+		// POST /webhooks - Subscribe a webhook (the signing secret is returned only here)
+		r.Post("/", ErrorHandlerWrapper(h.Webhooks.Create))
+
+		// GET /webhooks - List subscriptions
+		r.Get("/", ErrorHandlerWrapper(h.Webhooks.FindAll))
+
+		// GET /webhooks/{id} - Retrieve a subscription
+		r.Get("/{id}", ErrorHandlerWrapper(h.Webhooks.FindById))
+
+		// PATCH /webhooks/{id} - Change URL, event filter, secret or active flag
+		r.Patch("/{id}", ErrorHandlerWrapper(h.Webhooks.Update))
+
+		// DELETE /webhooks/{id} - Unsubscribe
+		r.Delete("/{id}", ErrorHandlerWrapper(h.Webhooks.Delete))
+
+		// GET /webhooks/{id}/deliveries - Delivery log with response codes
+		r.Get("/{id}/deliveries", ErrorHandlerWrapper(h.Webhooks.Deliveries))
+
+		// POST /webhooks/{id}/deliveries/{deliveryId}/redeliver - Manual redelivery
+		r.Post("/{id}/deliveries/{deliveryId}/redeliver", ErrorHandlerWrapper(h.Webhooks.Redeliver))
+	})
+
+	router.Route("/groups", func(r chi.Router) {
+		r.Use(NegotiationMiddleware)
+
+		// POST /groups - Create a group (admin only)
+		r.Post("/", ErrorHandlerWrapper(h.Groups.Create))
+
+		// GET /groups - List groups
+		r.Get("/", ErrorHandlerWrapper(h.Groups.FindAll))
+
+		// GET /groups/{id} - Retrieve a group
+		r.Get("/{id}", ErrorHandlerWrapper(h.Groups.FindById))
+
+		// PATCH /groups/{id} - Rename a group or change its description (admin only)
+		r.Patch("/{id}", ErrorHandlerWrapper(h.Groups.Update))
+
+		// DELETE /groups/{id} - Delete a group and its memberships (admin only)
+		r.Delete("/{id}", ErrorHandlerWrapper(h.Groups.Delete))
+
+		// GET /groups/{id}/members?transitive= - Direct members, or effective users with transitive=true
+		r.Get("/{id}/members", ErrorHandlerWrapper(h.Groups.Members))
+
+		// PATCH /groups/{id}/members - Add and remove members atomically (admin only)
+		r.Patch("/{id}/members", ErrorHandlerWrapper(h.Groups.ChangeMembers))
+	})
+
+	router.Route("/tenants", func(r chi.Router) {
+		r.Use(NegotiationMiddleware)
+
+		// POST /tenants - Create a tenant (operators only)
+		r.Post("/", ErrorHandlerWrapper(h.Tenants.Create))
+
+		// GET /tenants - List tenants (operators only)
+		r.Get("/", ErrorHandlerWrapper(h.Tenants.FindAll))
+
+		// GET /tenants/{id} - Retrieve a tenant (operators only)
+		r.Get("/{id}", ErrorHandlerWrapper(h.Tenants.FindById))
+
+		// PUT /tenants/{id} - Rename a tenant (operators only)
+		r.Put("/{id}", ErrorHandlerWrapper(h.Tenants.Update))
+
+		// DELETE /tenants/{id} - Delete a tenant without users (operators only)
+		r.Delete("/{id}", ErrorHandlerWrapper(h.Tenants.Delete))
+	})
+}
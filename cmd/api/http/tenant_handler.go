@@ -0,0 +1,147 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// errOperatorRequired es el error retornado cuando el principal no es un
+// operador (administrador no restringido a una organización).
+var errOperatorRequired = errors.New("operator privileges required to manage tenants")
+
+// TenantHandler maneja las peticiones HTTP de administración de
+// organizaciones. Todas sus operaciones requieren un operador.
+type TenantHandler struct {
+	tenantService *application.TenantService
+	validator     *validator.Validate
+}
+
+// NewTenantHandler crea una nueva instancia de TenantHandler con el servicio inyectado.
+func NewTenantHandler(service *application.TenantService) *TenantHandler {
+	return &TenantHandler{
+		tenantService: service,
+		validator:     validator.New(),
+	}
+}
+
+// Create maneja la petición POST /tenants.
+func (h *TenantHandler) Create(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isOperator(r) {
+		return NewHTTPError(errOperatorRequired, http.StatusForbidden)
+	}
+
+	// 1. Deserialización y validación
+	var request domain.TenantCreateRequest
+	if err := decodeBody(r, &request); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(request); err != nil {
+		return NewHTTPError(errors.New("id and name are required"), http.StatusBadRequest)
+	}
+
+	// 2. Llamada al servicio
+	tenant, err := h.tenantService.Create(r.Context(), &request)
+	if err != nil {
+		return mapTenantError(err)
+	}
+
+	// 3. Respuesta exitosa (201 Created)
+	return render(w, r, http.StatusCreated, tenant)
+}
+
+// FindAll maneja la petición GET /tenants.
+func (h *TenantHandler) FindAll(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isOperator(r) {
+		return NewHTTPError(errOperatorRequired, http.StatusForbidden)
+	}
+
+	tenants, err := h.tenantService.FindAll(r.Context())
+	if err != nil {
+		return mapTenantError(err)
+	}
+
+	return render(w, r, http.StatusOK, tenants)
+}
+
+// FindById maneja la petición GET /tenants/{id}.
+func (h *TenantHandler) FindById(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isOperator(r) {
+		return NewHTTPError(errOperatorRequired, http.StatusForbidden)
+	}
+
+	tenant, err := h.tenantService.FindById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return mapTenantError(err)
+	}
+
+	return render(w, r, http.StatusOK, tenant)
+}
+
+// Update maneja la petición PUT /tenants/{id}; solo el nombre es modificable.
+func (h *TenantHandler) Update(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isOperator(r) {
+		return NewHTTPError(errOperatorRequired, http.StatusForbidden)
+	}
+
+	// 1. Deserialización y validación
+	var request domain.TenantUpdateRequest
+	if err := decodeBody(r, &request); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(request); err != nil {
+		return NewHTTPError(errors.New("name is required"), http.StatusBadRequest)
+	}
+
+	// 2. Llamada al servicio
+	tenant, err := h.tenantService.Update(r.Context(), chi.URLParam(r, "id"), &request)
+	if err != nil {
+		return mapTenantError(err)
+	}
+
+	// 3. Respuesta exitosa (200 OK)
+	return render(w, r, http.StatusOK, tenant)
+}
+
+// Delete maneja la petición DELETE /tenants/{id}. Solo pueden eliminarse
+// organizaciones sin usuarios.
+func (h *TenantHandler) Delete(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isOperator(r) {
+		return NewHTTPError(errOperatorRequired, http.StatusForbidden)
+	}
+
+	if err := h.tenantService.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		return mapTenantError(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+// isOperator indica si el principal de la petición puede operar sobre
+// cualquier organización.
+func isOperator(r *http.Request) bool {
+	principal, ok := domain.PrincipalFromContext(r.Context())
+	return ok && principal.IsOperator()
+}
+
+// mapTenantError traduce los errores de dominio de organizaciones a HTTP.
+func mapTenantError(err error) *HTTPError {
+	switch {
+	case errors.Is(err, domain.ErrTenantNotFound):
+		return NewHTTPError(errors.New(err.Error()), http.StatusNotFound)
+	case errors.Is(err, domain.ErrTenantForbidden):
+		return NewHTTPError(errors.New(err.Error()), http.StatusForbidden)
+	case errors.Is(err, domain.ErrTenantIDInUse), errors.Is(err, domain.ErrTenantNotEmpty), errors.Is(err, domain.ErrDefaultTenant):
+		return NewHTTPError(errors.New(err.Error()), http.StatusConflict)
+	case errors.Is(err, domain.ErrInvalidTenantID), errors.As(err, new(domain.ErrValueNotNullable)):
+		return NewHTTPError(errors.New(err.Error()), http.StatusBadRequest)
+	default:
+		return NewHTTPError(errors.New(err.Error()), http.StatusInternalServerError)
+	}
+}
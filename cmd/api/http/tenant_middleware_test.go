@@ -0,0 +1,78 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+// stubTenantRepository es un registro fijo de organizaciones. Solo
+// implementa la búsqueda que usa application.TenantService.Resolve.
+type stubTenantRepository struct {
+	domain.TenantRepository
+}
+
+func (stubTenantRepository) FindById(id string) (*domain.Tenant, error) {
+	if id != domain.DefaultTenantID && id != "acme" {
+		return nil, domain.ErrTenantNotFound
+	}
+	return &domain.Tenant{ID: id}, nil
+}
+
+func TestTenantMiddlewareResolvesTheTenant(t *testing.T) {
+	middleware := TenantMiddleware(application.NewTenantService(stubTenantRepository{}, "users.example.com"))
+
+	tests := []struct {
+		name       string
+		tenantID   string
+		nonAdmin   bool
+		header     string
+		host       string
+		wantStatus int
+		wantTenant string
+	}{
+		{name: "default tenant", wantStatus: http.StatusOK, wantTenant: domain.DefaultTenantID},
+		{name: "header", header: "acme", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "subdomain", host: "acme.users.example.com", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "header wins over subdomain", header: domain.DefaultTenantID, host: "acme.users.example.com", wantStatus: http.StatusOK, wantTenant: domain.DefaultTenantID},
+		{name: "unknown tenant", header: "globex", wantStatus: http.StatusNotFound},
+		{name: "restricted principal", tenantID: "acme", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "restricted principal requests another tenant", tenantID: "acme", header: domain.DefaultTenantID, wantStatus: http.StatusForbidden},
+		{name: "non-operator", nonAdmin: true, wantStatus: http.StatusOK, wantTenant: domain.DefaultTenantID},
+		{name: "non-operator requests a tenant", nonAdmin: true, header: "acme", wantStatus: http.StatusForbidden},
+		{name: "non-operator on a subdomain", nonAdmin: true, host: "acme.users.example.com", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = domain.TenantFromContext(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/users", nil)
+			principal := domain.Principal{Subject: "tester", Roles: []string{domain.RoleAdmin}, TenantID: tt.tenantID}
+			if tt.nonAdmin {
+				principal.Roles = nil
+			}
+			r = r.WithContext(domain.WithPrincipal(r.Context(), principal))
+			if tt.header != "" {
+				r.Header.Set(TenantHeader, tt.header)
+			}
+			if tt.host != "" {
+				r.Host = tt.host
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus || got != tt.wantTenant {
+				t.Errorf("status = %d, tenant = %q; want %d, %q", w.Code, got, tt.wantStatus, tt.wantTenant)
+			}
+			if tt.wantStatus == http.StatusOK && w.Header().Get(TenantHeader) != tt.wantTenant {
+				t.Errorf("%s = %q, want %q", TenantHeader, w.Header().Get(TenantHeader), tt.wantTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// BatchModeAtomic aplica todas las operaciones del lote o ninguna.
+	BatchModeAtomic = "atomic"
+	// BatchModeBestEffort aplica cada operación de forma independiente.
+	BatchModeBestEffort = "best_effort"
+)
+
+// UserBatchRequest es el cuerpo de POST /users:batch.
+type UserBatchRequest struct {
+	// Mode es "atomic" (por defecto) o "best_effort".
+	Mode       string                  `json:"mode"`
+	Operations []UserBatchOperationDTO `json:"operations"`
+}
+
+// UserBatchOperationDTO es una operación del lote: "create" y "update" llevan
+// el usuario en User (update con su id); "delete" lleva el ID.
+type UserBatchOperationDTO struct {
+	Op   domain.BatchOperationType `json:"op"`
+	ID   string                    `json:"id,omitempty"`
+	User json.RawMessage           `json:"user,omitempty"`
+}
+
+// UserBatchResponse es la respuesta de POST /users:batch.
+type UserBatchResponse struct {
+	Mode      string                `json:"mode"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   []UserBatchItemResult `json:"results"`
+}
+
+// UserBatchItemResult es el resultado de una operación, con el código de
+// estado que tendría la petición individual equivalente y, si falló, el detalle del error.
+type UserBatchItemResult struct {
+	Index  int                       `json:"index"`
+	Op     domain.BatchOperationType `json:"op"`
+	Status int                       `json:"status"`
+	User   *domain.User              `json:"user,omitempty"`
+	Error  *ErrorResponse            `json:"error,omitempty"`
+}
+
+// BatchHandler maneja las operaciones en lote sobre usuarios.
+type BatchHandler struct {
+	userService   application.UserService
+	validator     *validator.Validate
+	maxOperations int
+}
+
+// NewBatchHandler crea un BatchHandler que acepta lotes de hasta maxOperations operaciones.
+func NewBatchHandler(service application.UserService, maxOperations int) *BatchHandler {
+	return &BatchHandler{
+		userService:   service,
+		validator:     validator.New(),
+		maxOperations: maxOperations,
+	}
+}
+
+// Batch maneja la petición POST /users:batch. En modo atómico responde 200 si
+// todas las operaciones se aplicaron o, si no, el código de la operación que
+// falló (el resto reporta 424). En modo best_effort responde 207 con el
+// resultado individual de cada operación.
+func (h *BatchHandler) Batch(w http.ResponseWriter, r *http.Request) *HTTPError {
+	// 1. Deserialización y validación del lote
+	var request UserBatchRequest
+	if err := decodeBody(r, &request); err != nil {
+		return err
+	}
+
+	if request.Mode == "" {
+		request.Mode = BatchModeAtomic
+	}
+	if request.Mode != BatchModeAtomic && request.Mode != BatchModeBestEffort {
+		return NewHTTPError(errors.New("mode must be atomic or best_effort"), http.StatusBadRequest)
+	}
+	if len(request.Operations) == 0 {
+		return NewHTTPError(errors.New("operations must not be empty"), http.StatusBadRequest)
+	}
+	if len(request.Operations) > h.maxOperations {
+		return NewHTTPError(fmt.Errorf("a batch accepts at most %d operations", h.maxOperations), http.StatusRequestEntityTooLarge)
+	}
+
+	// 2. Validación de cada operación
+	results := make([]UserBatchItemResult, len(request.Operations))
+	operations := make([]domain.UserBatchOperation, 0, len(request.Operations))
+	invalid := false
+
+	for i, dto := range request.Operations {
+		results[i] = UserBatchItemResult{Index: i, Op: dto.Op}
+
+		operation, err := h.toOperation(i, dto, apiVersion(r))
+		if err != nil {
+			results[i].Status, results[i].Error = http.StatusBadRequest, &ErrorResponse{Status: http.StatusBadRequest, Message: err.Error()}
+			invalid = true
+			continue
+		}
+		operations = append(operations, operation)
+	}
+
+	// Un lote atómico con operaciones inválidas se rechaza sin aplicar ninguna.
+	if invalid && request.Mode == BatchModeAtomic {
+		for i := range results {
+			if results[i].Error == nil {
+				results[i].Status = http.StatusFailedDependency
+				results[i].Error = &ErrorResponse{Status: http.StatusFailedDependency, Message: domain.ErrBatchNotApplied.Error()}
+			}
+		}
+		return render(w, r, http.StatusBadRequest, presentBatchResponse(r, newUserBatchResponse(request.Mode, results)))
+	}
+
+	// 3. Llamada al servicio
+	for _, result := range h.userService.Batch(r.Context(), operations, request.Mode == BatchModeAtomic) {
+		item := &results[result.Index]
+		if result.Err != nil {
+			httpErr := mapBatchError(result.Err)
+			item.Status, item.Error = httpErr.Status, &ErrorResponse{Status: httpErr.Status, Message: httpErr.Error.Error()}
+			continue
+		}
+
+		item.User = result.User
+		switch result.Op {
+		case domain.BatchCreate:
+			item.Status = http.StatusCreated
+		case domain.BatchUpdate:
+			item.Status = http.StatusOK
+		case domain.BatchDelete:
+			item.Status = http.StatusNoContent
+		}
+	}
+
+	// 4. Respuesta
+	response := newUserBatchResponse(request.Mode, results)
+	if request.Mode == BatchModeBestEffort {
+		return render(w, r, http.StatusMultiStatus, presentBatchResponse(r, response))
+	}
+
+	status := http.StatusOK
+	for _, item := range results {
+		if item.Error != nil && item.Status != http.StatusFailedDependency {
+			status = item.Status
+			break
+		}
+	}
+
+	return render(w, r, status, presentBatchResponse(r, response))
+}
+
+// toOperation valida una operación del lote y la convierte a su forma de
+// dominio. El usuario se lee en la representación de la versión indicada.
+func (h *BatchHandler) toOperation(index int, dto UserBatchOperationDTO, version int) (domain.UserBatchOperation, error) {
+	operation := domain.UserBatchOperation{Index: index, Op: dto.Op, ID: dto.ID}
+
+	switch dto.Op {
+	case domain.BatchCreate:
+		user, payload, err := unmarshalBatchCreate(dto.User, version)
+		if err != nil {
+			return operation, errors.New("user must be a valid user object")
+		}
+		if err := h.validator.Struct(payload); err != nil {
+			return operation, errors.New("user requires non-blank name, username and a valid email")
+		}
+		operation.Create = &user
+	case domain.BatchUpdate:
+		user, err := unmarshalBatchUpdate(dto.User, version)
+		if err != nil {
+			return operation, errors.New("user must be a valid user object")
+		}
+		if user.ID == "" {
+			return operation, errors.New("user.id is required to update")
+		}
+		operation.Update = &user
+	case domain.BatchDelete:
+		if dto.ID == "" {
+			return operation, errors.New("id is required to delete")
+		}
+	default:
+		return operation, errors.New("op must be create, update or delete")
+	}
+
+	return operation, nil
+}
+
+// newUserBatchResponse arma la respuesta del lote con sus totales.
+func newUserBatchResponse(mode string, results []UserBatchItemResult) UserBatchResponse {
+	response := UserBatchResponse{Mode: mode, Results: results}
+	for _, item := range results {
+		if item.Error != nil {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+	return response
+}
+
+// mapBatchError traduce el error de una operación del lote al código de
+// estado de la petición individual equivalente.
+func mapBatchError(err error) *HTTPError {
+	var errNotNullable domain.ErrValueNotNullable
+
+	switch {
+	case errors.Is(err, domain.ErrBatchNotApplied):
+		return NewHTTPError(err, http.StatusFailedDependency)
+	case errors.Is(err, domain.ErrUserNotFound):
+		return NewHTTPError(err, http.StatusNotFound)
+	case errors.Is(err, domain.ErrIdInUse), errors.Is(err, domain.ErrEmailInUse), errors.Is(err, domain.ErrUsernameInUse):
+		return NewHTTPError(err, http.StatusConflict)
+	case errors.As(err, &errNotNullable):
+		return NewHTTPError(err, http.StatusBadRequest)
+	default:
+		return NewHTTPError(errors.New("internal server error"), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+// stubBatchService falla las operaciones cuyo índice está en fail, aplica
+// las demás y registra el lote recibido.
+type stubBatchService struct {
+	application.UserService
+	operations []domain.UserBatchOperation
+	atomic     bool
+	fail       map[int]error
+}
+
+func (s *stubBatchService) Batch(_ context.Context, operations []domain.UserBatchOperation, atomic bool) []domain.UserBatchResult {
+	s.operations, s.atomic = operations, atomic
+	results := make([]domain.UserBatchResult, len(operations))
+	for i, operation := range operations {
+		results[i] = domain.UserBatchResult{Index: operation.Index, Op: operation.Op, Err: s.fail[operation.Index]}
+		if results[i].Err == nil && operation.Create != nil {
+			results[i].User = &domain.User{ID: "u1", Username: operation.Create.Username}
+		}
+	}
+	return results
+}
+
+// postBatch envía el lote y decodifica la respuesta.
+func postBatch(t *testing.T, handler *BatchHandler, body string) (int, UserBatchResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	ErrorHandlerWrapper(handler.Batch)(w, httptest.NewRequest(http.MethodPost, "/users:batch", strings.NewReader(body)))
+
+	var response UserBatchResponse
+	if w.Code != http.StatusRequestEntityTooLarge {
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("body %q: %v", w.Body, err)
+		}
+	}
+	return w.Code, response
+}
+
+const validBatch = `{"mode":"%s","operations":[
+	{"op":"create","user":{"name":"Ann","username":"ann","email":"ann@example.com"}},
+	{"op":"delete","id":"u9"}]}`
+
+func TestBatchHandlerBestEffortReportsEachOperation(t *testing.T) {
+	service := &stubBatchService{fail: map[int]error{1: domain.ErrUserNotFound}}
+	status, response := postBatch(t, NewBatchHandler(service, 10), strings.Replace(validBatch, "%s", BatchModeBestEffort, 1))
+
+	if status != http.StatusMultiStatus || service.atomic {
+		t.Fatalf("status = %d, atomic = %v", status, service.atomic)
+	}
+	if response.Succeeded != 1 || response.Failed != 1 {
+		t.Errorf("totals = %d/%d, want 1/1", response.Succeeded, response.Failed)
+	}
+	if response.Results[0].Status != http.StatusCreated || response.Results[1].Status != http.StatusNotFound || response.Results[1].Error == nil {
+		t.Errorf("results = %+v", response.Results)
+	}
+}
+
+func TestBatchHandlerAtomicRespondsWithTheFailingStatus(t *testing.T) {
+	service := &stubBatchService{fail: map[int]error{0: domain.ErrBatchNotApplied, 1: domain.ErrUsernameInUse}}
+	status, response := postBatch(t, NewBatchHandler(service, 10), strings.Replace(validBatch, "%s", BatchModeAtomic, 1))
+
+	if status != http.StatusConflict || !service.atomic {
+		t.Fatalf("status = %d, atomic = %v; want 409", status, service.atomic)
+	}
+	if response.Results[0].Status != http.StatusFailedDependency {
+		t.Errorf("not applied operation status = %d, want 424", response.Results[0].Status)
+	}
+}
+
+func TestBatchHandlerRejectsInvalidBatches(t *testing.T) {
+	handler := NewBatchHandler(&stubBatchService{}, 2)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"unknown mode", `{"mode":"eventually","operations":[{"op":"delete","id":"u1"}]}`, http.StatusBadRequest},
+		{"empty", `{"operations":[]}`, http.StatusBadRequest},
+		{"too many", `{"operations":[{"op":"delete","id":"1"},{"op":"delete","id":"2"},{"op":"delete","id":"3"}]}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ErrorHandlerWrapper(handler.Batch)(w, httptest.NewRequest(http.MethodPost, "/users:batch", strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchHandlerRejectsAtomicBatchesWithInvalidOperations(t *testing.T) {
+	service := &stubBatchService{}
+	status, response := postBatch(t, NewBatchHandler(service, 10),
+		`{"operations":[{"op":"delete","id":"u1"},{"op":"update","user":{"name":"No ID"}},{"op":"rename"}]}`)
+
+	if status != http.StatusBadRequest || service.operations != nil {
+		t.Fatalf("status = %d, service called = %v; want 400 without applying", status, service.operations != nil)
+	}
+	want := []int{http.StatusFailedDependency, http.StatusBadRequest, http.StatusBadRequest}
+	for i, result := range response.Results {
+		if result.Status != want[i] {
+			t.Errorf("operation %d status = %d, want %d", i, result.Status, want[i])
+		}
+	}
+}
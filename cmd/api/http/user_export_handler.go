@@ -0,0 +1,279 @@
+package http
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// Formatos de exportación de usuarios.
+const (
+	exportCSV    = "csv"
+	exportNDJSON = "ndjson"
+	exportJSON   = "json"
+)
+
+// exportContentTypes asocia cada formato de exportación con su Content-Type.
+var exportContentTypes = map[string]string{
+	exportCSV:    "text/csv; charset=utf-8",
+	exportNDJSON: "application/x-ndjson",
+	exportJSON:   "application/json",
+}
+
+// Export maneja la petición GET /users/export. Transmite los usuarios desde
+// un cursor de la base de datos directamente a la respuesta, sin cargarlos
+// en memoria, en CSV, NDJSON o un arreglo JSON según ?format= o la cabecera
+// Accept. Acepta los filtros de GET /users, ?fields= para elegir los campos
+// y comprime con gzip si el cliente lo acepta (Accept-Encoding) o con ?gzip=true.
+func (h *UserHandler) Export(w http.ResponseWriter, r *http.Request) *HTTPError {
+	// 1. Extracción de parámetros
+	format, httpErr := exportFormat(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	// La proyección de ?fields= se aplica en el SELECT (ver userFilterFromQuery).
+	filter, fields, httpErr := userFilterFromQuery(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if fields == nil {
+		fields = defaultUserFields(r)
+	}
+
+	// 2. Cabeceras y compresión
+	w.Header().Set("Content-Type", exportContentTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "users."+format))
+	w.Header().Add("Vary", "Accept")
+	w.Header().Add("Vary", "Accept-Encoding")
+
+	var out io.Writer = w
+	if acceptsGzip(r) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		out = gz
+	}
+
+	buffered := bufio.NewWriterSize(out, 32*1024)
+	defer buffered.Flush()
+
+	w.WriteHeader(http.StatusOK)
+
+	// 3. Transmisión de las filas
+	encoder := newUserExportEncoder(format, fields, buffered)
+	err := encoder.begin()
+	if err == nil {
+		err = h.userService.Export(r.Context(), filter, encoder.encode)
+		if err == nil {
+			err = encoder.end()
+		}
+	}
+	if err != nil {
+		// La respuesta ya comenzó: solo se registra el error; el cliente
+		// recibe un documento truncado.
+		log.Printf("[Export] export aborted: %v", err)
+	}
+
+	return nil
+}
+
+// exportFormat determina el formato por ?format= o, si no, por la cabecera
+// Accept. Un ?format= inválido es un error del cliente (400); un Accept sin
+// ningún formato soportado, 406.
+func exportFormat(r *http.Request) (string, *HTTPError) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		if _, ok := exportContentTypes[format]; !ok {
+			return "", NewHTTPError(errors.New("format must be csv, ndjson or json"), http.StatusBadRequest)
+		}
+		return format, nil
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return exportJSON, nil
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "text/csv":
+			return exportCSV, nil
+		case "application/x-ndjson", "application/ndjson":
+			return exportNDJSON, nil
+		case "application/json", "*/*", "application/*":
+			return exportJSON, nil
+		}
+	}
+
+	return "", NewHTTPError(errors.New("supported formats are text/csv, application/x-ndjson and application/json"), http.StatusNotAcceptable)
+}
+
+// acceptsGzip indica si la respuesta debe comprimirse con gzip.
+func acceptsGzip(r *http.Request) bool {
+	if enabled, err := strconv.ParseBool(r.URL.Query().Get("gzip")); err == nil {
+		return enabled
+	}
+
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(coding, "gzip") && strings.ReplaceAll(params, " ", "") != "q=0" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exportValue retorna el valor de un campo exportable del usuario.
+func exportValue(user *domain.User, field string) any {
+	switch field {
+	case "id":
+		return user.ID
+	case "name":
+		return user.Name
+	case "given_name":
+		given, _ := splitName(user.Name)
+		return given
+	case "family_name":
+		_, family := splitName(user.Name)
+		return family
+	case "username":
+		return user.Username
+	case "email":
+		return user.Email
+	case "created_at":
+		return user.CreatedAt
+	case "updated_at":
+		return user.UpdatedAt
+	case "created_by":
+		return user.CreatedBy
+	case "updated_by":
+		return user.UpdatedBy
+	case "deleted_at":
+		return user.DeletedAt
+	case "status":
+		return user.Status
+	case "status_reason":
+		return user.StatusReason
+	case "status_changed_at":
+		return user.StatusChangedAt
+	case "email_verified":
+		return user.EmailVerified
+	case "email_verified_at":
+		return user.EmailVerifiedAt
+	default:
+		return nil
+	}
+}
+
+// userExportEncoder escribe los usuarios exportados en un formato.
+type userExportEncoder struct {
+	format string
+	fields []string
+	out    *bufio.Writer
+	csv    *csv.Writer
+	count  int
+}
+
+// newUserExportEncoder crea el encoder del formato indicado.
+func newUserExportEncoder(format string, fields []string, out *bufio.Writer) *userExportEncoder {
+	encoder := &userExportEncoder{format: format, fields: fields, out: out}
+	if format == exportCSV {
+		encoder.csv = csv.NewWriter(out)
+	}
+	return encoder
+}
+
+// begin escribe el encabezado del documento.
+func (e *userExportEncoder) begin() error {
+	switch e.format {
+	case exportCSV:
+		return e.csv.Write(e.fields)
+	case exportJSON:
+		return e.out.WriteByte('[')
+	}
+	return nil
+}
+
+// encode escribe un usuario.
+func (e *userExportEncoder) encode(user *domain.User) error {
+	defer func() { e.count++ }()
+
+	if e.format == exportCSV {
+		record := make([]string, len(e.fields))
+		for i, field := range e.fields {
+			switch value := exportValue(user, field).(type) {
+			case time.Time:
+				record[i] = value.UTC().Format(time.RFC3339Nano)
+			case *time.Time:
+				if value != nil {
+					record[i] = value.UTC().Format(time.RFC3339Nano)
+				}
+			case nil:
+			default:
+				record[i] = fmt.Sprint(value)
+			}
+		}
+		return e.csv.Write(record)
+	}
+
+	if e.format == exportJSON && e.count > 0 {
+		if err := e.out.WriteByte(','); err != nil {
+			return err
+		}
+	}
+
+	// Objeto JSON con los campos en el orden solicitado.
+	e.out.WriteByte('{')
+	for i, field := range e.fields {
+		if i > 0 {
+			e.out.WriteByte(',')
+		}
+		value, err := json.Marshal(exportValue(user, field))
+		if err != nil {
+			return err
+		}
+		e.out.WriteString(strconv.Quote(field) + ":")
+		e.out.Write(value)
+	}
+	e.out.WriteByte('}')
+
+	if e.format == exportNDJSON {
+		e.out.WriteByte('\n')
+	}
+
+	// Los errores de escritura quedan retenidos en el bufio.Writer.
+	_, err := e.out.Write(nil)
+	return err
+}
+
+// end cierra el documento.
+func (e *userExportEncoder) end() error {
+	switch e.format {
+	case exportCSV:
+		e.csv.Flush()
+		return e.csv.Error()
+	case exportJSON:
+		if err := e.out.WriteByte(']'); err != nil {
+			return err
+		}
+		return e.out.WriteByte('\n')
+	}
+	return nil
+}
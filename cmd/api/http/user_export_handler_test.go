@@ -0,0 +1,128 @@
+package http
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+// stubExportService transmite users y registra el filtro recibido.
+type stubExportService struct {
+	application.UserService
+	users  []domain.User
+	filter domain.UserFilter
+}
+
+func (s *stubExportService) Export(_ context.Context, filter domain.UserFilter, fn func(user *domain.User) error) error {
+	s.filter = filter
+	for i := range s.users {
+		if err := fn(&s.users[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportUsers son los usuarios de prueba de la exportación.
+func exportUsers() []domain.User {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []domain.User{
+		{ID: "u1", Name: "Jane", Username: "jane", Email: "jane@example.com", Status: domain.UserActive, EmailVerified: true, CreatedAt: createdAt},
+		{ID: "u2", Name: "John, Jr.", Username: "john", Email: "john@example.com", Status: domain.UserSuspended, CreatedAt: createdAt},
+	}
+}
+
+// export ejecuta GET target con las cabeceras indicadas.
+func export(service application.UserService, target string, headers map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, value := range headers {
+		r.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	ErrorHandlerWrapper(NewUserHandler(service).Export)(w, r)
+	return w
+}
+
+func TestExportCSVWithSelectedFields(t *testing.T) {
+	service := &stubExportService{users: exportUsers()}
+	w := export(service, "/users/export?format=csv&fields=id,name,status,email_verified,created_at&status=active,suspended", nil)
+
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("status = %d, Content-Type = %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	want := [][]string{
+		{"id", "name", "status", "email_verified", "created_at"},
+		{"u1", "Jane", "active", "true", "2026-01-02T03:04:05Z"},
+		{"u2", "John, Jr.", "suspended", "false", "2026-01-02T03:04:05Z"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+	if len(service.filter.Statuses) != 2 || len(service.filter.Fields) == 0 {
+		t.Errorf("filter = %+v, want the status filter and the field projection", service.filter)
+	}
+}
+
+func TestExportNDJSONByAcceptHeader(t *testing.T) {
+	w := export(&stubExportService{users: exportUsers()}, "/users/export?fields=username,email", map[string]string{"Accept": "application/x-ndjson"})
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if len(lines) != 2 || lines[0] != `{"username":"jane","email":"jane@example.com"}` {
+		t.Errorf("lines = %q", lines)
+	}
+}
+
+func TestExportJSONArrayWithGzip(t *testing.T) {
+	w := export(&stubExportService{users: exportUsers()}, "/users/export", map[string]string{"Accept-Encoding": "br, gzip"})
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", w.Header().Get("Content-Encoding"))
+	}
+	reader, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(reader)
+
+	var users []map[string]any
+	if err := json.Unmarshal(body, &users); err != nil {
+		t.Fatalf("body %q: %v", body, err)
+	}
+	if len(users) != 2 || users[1]["name"] != "John, Jr." || users[0]["email_verified"] != true {
+		t.Errorf("users = %v", users)
+	}
+}
+
+func TestExportEmptyJSONArray(t *testing.T) {
+	w := export(&stubExportService{}, "/users/export?gzip=false", map[string]string{"Accept-Encoding": "gzip"})
+	if strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("body = %q, want []", w.Body)
+	}
+}
+
+func TestExportRejectsUnsupportedFormats(t *testing.T) {
+	if w := export(&stubExportService{}, "/users/export?format=xml", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("format=xml: status = %d, want 400", w.Code)
+	}
+	// ?format= tiene prioridad sobre Accept
+	if w := export(&stubExportService{}, "/users/export?format=csv", map[string]string{"Accept": "application/xml"}); w.Code != http.StatusOK {
+		t.Errorf("format=csv with Accept xml: status = %d, want 200", w.Code)
+	}
+	if w := export(&stubExportService{}, "/users/export", map[string]string{"Accept": "application/xml"}); w.Code != http.StatusNotAcceptable {
+		t.Errorf("Accept xml: status = %d, want 406", w.Code)
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// stubFindService retorna un usuario fijo y registra los campos pedidos.
+type stubFindService struct {
+	application.UserService
+	fields []string
+}
+
+func (s *stubFindService) FindById(_ context.Context, id string, fields ...string) (*domain.User, error) {
+	s.fields = fields
+	return &domain.User{ID: id, Name: "Jane Doe", Username: "jane", Email: "jane@example.com", Status: domain.UserActive}, nil
+}
+
+func (s *stubFindService) FindAll(_ context.Context, filter domain.UserFilter) (*[]domain.User, error) {
+	s.fields = filter.Fields
+	users := []domain.User{{ID: "u1", Username: "jane", EmailVerified: true}}
+	return &users, nil
+}
+
+// getUser ejecuta GET /users/u1?fields= en la versión indicada y retorna los campos de la respuesta.
+func getUser(t *testing.T, service *stubFindService, version int, fields string) (int, []string) {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, "/users/u1?fields="+fields, nil)
+	routeContext := chi.NewRouteContext()
+	routeContext.URLParams.Add("id", "u1")
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, routeContext)
+	r = r.WithContext(context.WithValue(ctx, apiVersionKey{}, version))
+
+	w := httptest.NewRecorder()
+	ErrorHandlerWrapper(NewUserHandler(service).FindById)(w, r)
+
+	var body map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	keys := make([]string, 0, len(body))
+	for key := range body {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return w.Code, keys
+}
+
+func TestFindByIdProjectsFieldsAndPushesThemDown(t *testing.T) {
+	service := &stubFindService{}
+
+	status, keys := getUser(t, service, APIVersion1, "id,username,status")
+	if status != http.StatusOK || !reflect.DeepEqual(keys, []string{"id", "status", "username"}) {
+		t.Errorf("v1: status = %d, keys = %v", status, keys)
+	}
+	if !reflect.DeepEqual(service.fields, []string{"id", "username", "status"}) {
+		t.Errorf("v1: service fields = %v", service.fields)
+	}
+
+	status, keys = getUser(t, service, APIVersion2, "id,family_name,given_name")
+	if status != http.StatusOK || !reflect.DeepEqual(keys, []string{"family_name", "given_name", "id"}) {
+		t.Errorf("v2: status = %d, keys = %v", status, keys)
+	}
+	if !reflect.DeepEqual(service.fields, []string{"id", "name"}) {
+		t.Errorf("v2: service fields = %v, want the name column once", service.fields)
+	}
+
+	if status, _ := getUser(t, service, APIVersion1, "given_name"); status != http.StatusBadRequest {
+		t.Errorf("v2 field in v1: status = %d, want 400", status)
+	}
+	if status, _ := getUser(t, service, APIVersion2, "name"); status != http.StatusBadRequest {
+		t.Errorf("v1 field in v2: status = %d, want 400", status)
+	}
+}
+
+func TestFindAllProjectsFields(t *testing.T) {
+	service := &stubFindService{}
+	w := httptest.NewRecorder()
+	ErrorHandlerWrapper(NewUserHandler(service).FindAll)(w, httptest.NewRequest(http.MethodGet, "/users?fields=id,email_verified", nil))
+
+	if w.Body.String() != `[{"email_verified":true,"id":"u1"}]`+"\n" {
+		t.Errorf("body = %q", w.Body)
+	}
+	if !reflect.DeepEqual(service.fields, []string{"id", "email_verified"}) {
+		t.Errorf("service fields = %v", service.fields)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 	"user-api-restful/internal/application"
 	"user-api-restful/internal/domain"
 
@@ -13,6 +15,10 @@ import (
 
 // Package http define los controladores (handlers) para la API REST.
 
+// errStatusAdminRequired es el error retornado cuando un principal sin
+// privilegios de administrador intenta cambiar el estado de una cuenta.
+var errStatusAdminRequired = errors.New("admin privileges required to change the status of users")
+
 // UserHandler maneja todas las peticiones HTTP relacionadas con la gestión de usuarios.
 // Depende de la interfaz application.UserService para la lógica de negocio.
 type UserHandler struct {
@@ -29,19 +35,17 @@ func NewUserHandler(service application.UserService) *UserHandler {
 }
 
 // CreateUser maneja la petición POST para crear un nuevo usuario.
-// Se encarga de la deserialización (JSON), la validación del request body,
+// Se encarga de la deserialización (según el Content-Type), la validación del request body,
 // el llamado al servicio y el mapeo de errores de dominio a respuestas HTTP.
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) *HTTPError {
-	var request domain.UserCreateRequest
-
-	// 1. Deserialización JSON
-	err := json.NewDecoder(r.Body).Decode(&request)
-	if err != nil {
-		return NewHTTPError(errors.New("invalid request body format"), http.StatusBadRequest)
+	// 1. Deserialización según el Content-Type y la versión de la API
+	request, payload, httpErr := decodeUserCreateRequest(r)
+	if httpErr != nil {
+		return httpErr
 	}
 
 	// 2. Validación de la estructura
-	err = h.validator.Struct(request)
+	err := h.validator.Struct(payload)
 
 	if err != nil {
 		var validationErrors validator.ValidationErrors
@@ -71,7 +75,7 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) *HTTPEr
 	}
 
 	// 3. Llamada al servicio de aplicación
-	userResponse, err := h.userService.Create(&request)
+	userResponse, err := h.userService.Create(r.Context(), &request)
 
 	// 4. Mapeo de errores de dominio a HTTP Status Codes
 	if err != nil {
@@ -79,7 +83,8 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) *HTTPEr
 			// 409 Conflict para errores de unicidad/recurso existente.
 			return NewHTTPError(errors.New(err.Error()), http.StatusConflict)
 		}
-		if errors.Is(err, domain.ErrValueNotNullable{}) {
+		var errNotNullable domain.ErrValueNotNullable
+		if errors.As(err, &errNotNullable) {
 			// 400 Bad Request para errores de validación (valores nulos/vacíos).
 			return NewHTTPError(errors.New(err.Error()), http.StatusBadRequest)
 		}
@@ -88,49 +93,53 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) *HTTPEr
 	}
 
 	// 5. Respuesta exitosa (201 Created)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-
-	err = json.NewEncoder(w).Encode(userResponse)
-	if err != nil {
-		return NewHTTPError(errors.New("error json encoding response"), http.StatusInternalServerError)
-	}
-
-	return nil
+	return render(w, r, http.StatusCreated, presentUser(r, userResponse))
 }
 
 // FindAll maneja la petición GET para obtener todos los usuarios.
+// Con ?include_deleted=true (solo administradores) incluye los eliminados,
+// con ?status=suspended,locked filtra por estado de la cuenta y con
+// ?fields=id,username retorna solo esos campos.
 func (h *UserHandler) FindAll(w http.ResponseWriter, r *http.Request) *HTTPError {
-	// Llamada al servicio
-	userResponse, err := h.userService.FindAll()
+	// 1. Construcción del filtro a partir de la query
+	filter, fields, httpErr := userFilterFromQuery(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	// 2. Llamada al servicio
+	userResponse, err := h.userService.FindAll(r.Context(), filter)
 
 	if err != nil {
 		return NewHTTPError(errors.New(err.Error()), http.StatusInternalServerError)
 	}
 
-	// Respuesta exitosa (200 OK)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
-	err = json.NewEncoder(w).Encode(userResponse)
-	if err != nil {
-		return NewHTTPError(errors.New("error json encoding response"), http.StatusInternalServerError)
+	if fields != nil {
+		// Respuesta exitosa (200 OK) con los campos solicitados
+		return render(w, r, http.StatusOK, projectUsers(r, *userResponse, fields))
 	}
 
-	return nil
+	// Respuesta exitosa (200 OK)
+	return render(w, r, http.StatusOK, presentUsers(r, *userResponse))
 }
 
 // FindById maneja la petición GET para obtener un usuario por ID.
+// Con ?fields=id,username retorna solo esos campos.
 func (h *UserHandler) FindById(w http.ResponseWriter, r *http.Request) *HTTPError {
-	// 1. Extracción del parámetro de la URL
+	// 1. Extracción de los parámetros de la URL
 	id := chi.URLParam(r, "id")
 
 	if id == "" {
 		return NewHTTPError(errors.New("user ID is required in the request path or query"), http.StatusBadRequest)
 	}
 
+	fields, domainFields, err := userFields(r)
+	if err != nil {
+		return NewHTTPError(err, http.StatusBadRequest)
+	}
+
 	// 2. Llamada al servicio
-	userResponse, err := h.userService.FindById(id)
+	userResponse, err := h.userService.FindById(r.Context(), id, domainFields...)
 
 	// 3. Mapeo de errores
 	if err != nil {
@@ -142,35 +151,28 @@ func (h *UserHandler) FindById(w http.ResponseWriter, r *http.Request) *HTTPErro
 	}
 
 	// 4. Respuesta exitosa (200 OK)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
-	err = json.NewEncoder(w).Encode(userResponse)
-	if err != nil {
-		return NewHTTPError(errors.New("error json encoding response"), http.StatusInternalServerError)
+	if fields != nil {
+		return render(w, r, http.StatusOK, projectUser(r, userResponse, fields))
 	}
-
-	return nil
+	return render(w, r, http.StatusOK, presentUser(r, userResponse))
 }
 
 // Update maneja la petición PUT para actualizar un usuario.
 func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) *HTTPError {
-	var request domain.User
-
-	// 1. Deserialización JSON
-	err := json.NewDecoder(r.Body).Decode(&request)
-	if err != nil {
-		return NewHTTPError(errors.New("invalid request body format"), http.StatusBadRequest)
+	// 1. Deserialización según el Content-Type y la versión de la API
+	request, payload, httpErr := decodeUser(r)
+	if httpErr != nil {
+		return httpErr
 	}
 
 	// 2. Validación (más simple aquí)
-	err = h.validator.Struct(request)
+	err := h.validator.Struct(payload)
 	if err != nil {
 		return NewHTTPError(errors.New("validation failed on update fields"), http.StatusBadRequest)
 	}
 
 	// 3. Llamada al servicio
-	userResponse, err := h.userService.Update(&request)
+	userResponse, err := h.userService.Update(r.Context(), &request)
 
 	// 4. Mapeo de errores
 	if err != nil {
@@ -184,15 +186,7 @@ func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) *HTTPError
 	}
 
 	// 5. Respuesta exitosa (200 OK)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK) // 200 OK for successful update
-
-	err = json.NewEncoder(w).Encode(userResponse)
-	if err != nil {
-		return NewHTTPError(errors.New("error json encoding response"), http.StatusInternalServerError)
-	}
-
-	return nil
+	return render(w, r, http.StatusOK, presentUser(r, userResponse))
 }
 
 // Delete maneja la petición DELETE para eliminar un usuario por ID.
@@ -205,7 +199,7 @@ func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) *HTTPError
 	}
 
 	// 2. Llamada al servicio
-	err := h.userService.Delete(id)
+	err := h.userService.Delete(r.Context(), id)
 
 	// 3. Mapeo de errores
 	if err != nil {
@@ -221,3 +215,231 @@ func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) *HTTPError
 
 	return nil
 }
+
+// Restore maneja la petición POST /users/{id}/restore para revertir la
+// eliminación lógica de un usuario. Solo disponible para administradores.
+func (h *UserHandler) Restore(w http.ResponseWriter, r *http.Request) *HTTPError {
+	// 1. Autorización y extracción del parámetro de la URL
+	if !isAdmin(r) {
+		return NewHTTPError(errors.New("admin privileges required to restore users"), http.StatusForbidden)
+	}
+
+	id := chi.URLParam(r, "id")
+
+	if id == "" {
+		return NewHTTPError(errors.New("user ID is required in the request path or query"), http.StatusBadRequest)
+	}
+
+	// 2. Llamada al servicio
+	userResponse, err := h.userService.Restore(r.Context(), id)
+
+	// 3. Mapeo de errores
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return NewHTTPError(errors.New(err.Error()), http.StatusNotFound)
+		}
+		if errors.Is(err, domain.ErrUserNotDeleted) || errors.Is(err, domain.ErrEmailInUse) || errors.Is(err, domain.ErrUsernameInUse) {
+			// 409 Conflict: el usuario no estaba eliminado o su identidad fue tomada.
+			return NewHTTPError(errors.New(err.Error()), http.StatusConflict)
+		}
+		return NewHTTPError(errors.New(err.Error()), http.StatusInternalServerError)
+	}
+
+	// 4. Respuesta exitosa (200 OK)
+	return render(w, r, http.StatusOK, presentUser(r, userResponse))
+}
+
+// ChangeStatus retorna el handler de POST /users/{id}:<acción>, que aplica
+// una acción del ciclo de vida de la cuenta (solo administradores). El cuerpo
+// es opcional: {"reason": "..."}, obligatorio para suspend, lock y deactivate.
+func (h *UserHandler) ChangeStatus(action domain.UserStatusAction) func(w http.ResponseWriter, r *http.Request) *HTTPError {
+	return func(w http.ResponseWriter, r *http.Request) *HTTPError {
+		// 1. Autorización y extracción del parámetro de la URL
+		if !isAdmin(r) {
+			return NewHTTPError(errStatusAdminRequired, http.StatusForbidden)
+		}
+
+		id := chi.URLParam(r, "id")
+
+		// 2. Deserialización (opcional) y validación del motivo
+		var request domain.UserStatusChangeRequest
+		if r.ContentLength != 0 {
+			if err := decodeBody(r, &request); err != nil {
+				return err
+			}
+		}
+		if err := h.validator.Struct(request); err != nil {
+			return NewHTTPError(errors.New("reason must have at most 500 characters"), http.StatusBadRequest)
+		}
+
+		// 3. Llamada al servicio
+		userResponse, err := h.userService.ChangeStatus(r.Context(), id, action, request.Reason)
+
+		// 4. Mapeo de errores
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrUserNotFound):
+				return NewHTTPError(errors.New(err.Error()), http.StatusNotFound)
+			case errors.Is(err, domain.ErrInvalidStatusTransition):
+				// 409 Conflict: la acción no se admite desde el estado actual.
+				return NewHTTPError(errors.New(err.Error()), http.StatusConflict)
+			case errors.Is(err, domain.ErrStatusReasonRequired):
+				return NewHTTPError(errors.New(err.Error()), http.StatusBadRequest)
+			}
+			return NewHTTPError(errors.New(err.Error()), http.StatusInternalServerError)
+		}
+
+		// 5. Respuesta exitosa (200 OK)
+		return render(w, r, http.StatusOK, presentUser(r, userResponse))
+	}
+}
+
+// userFilterFromQuery construye el filtro de listado de usuarios a partir de
+// la query: include_deleted (solo administradores), los rangos RFC 3339
+// created_after, created_before, updated_after y updated_before, los estados
+// de cuenta status y la proyección de campos fields. Retorna además los
+// campos solicitados en la representación de la versión de la petición
+// (nil = todos).
+func userFilterFromQuery(r *http.Request) (domain.UserFilter, []string, *HTTPError) {
+	var filter domain.UserFilter
+
+	if raw := r.URL.Query().Get("include_deleted"); raw != "" {
+		includeDeleted, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, nil, NewHTTPError(errors.New("include_deleted must be a boolean"), http.StatusBadRequest)
+		}
+		if includeDeleted && !isAdmin(r) {
+			return filter, nil, NewHTTPError(errors.New("admin privileges required to include deleted users"), http.StatusForbidden)
+		}
+		filter.IncludeDeleted = includeDeleted
+	}
+
+	var err error
+	for name, target := range map[string]**time.Time{
+		"created_after":  &filter.CreatedAfter,
+		"created_before": &filter.CreatedBefore,
+		"updated_after":  &filter.UpdatedAfter,
+		"updated_before": &filter.UpdatedBefore,
+	} {
+		if *target, err = queryTime(r, name); err != nil {
+			return filter, nil, NewHTTPError(err, http.StatusBadRequest)
+		}
+	}
+
+	if filter.Statuses, err = domain.ParseUserStatuses(r.URL.Query().Get("status")); err != nil {
+		return filter, nil, NewHTTPError(err, http.StatusBadRequest)
+	}
+
+	fields, domainFields, err := userFields(r)
+	if err != nil {
+		return filter, nil, NewHTTPError(err, http.StatusBadRequest)
+	}
+	filter.Fields = domainFields
+
+	return filter, fields, nil
+}
+
+// projectUser retorna solo los campos solicitados del usuario, con los
+// mismos nombres y valores que su representación completa en la versión de
+// la petición.
+func projectUser(r *http.Request, user *domain.User, fields []string) map[string]any {
+	var full map[string]any
+	data, _ := json.Marshal(presentUser(r, user))
+	_ = json.Unmarshal(data, &full)
+
+	projected := make(map[string]any, len(fields))
+	for _, field := range fields {
+		projected[field] = full[field]
+	}
+
+	return projected
+}
+
+// projectUsers aplica projectUser a cada usuario de la lista.
+func projectUsers(r *http.Request, users []domain.User, fields []string) []map[string]any {
+	projected := make([]map[string]any, len(users))
+	for i := range users {
+		projected[i] = projectUser(r, &users[i], fields)
+	}
+	return projected
+}
+
+// isAdmin indica si el principal autenticado de la petición es administrador.
+func isAdmin(r *http.Request) bool {
+	principal, ok := domain.PrincipalFromContext(r.Context())
+	return ok && principal.IsAdmin()
+}
+
+// Search maneja la petición GET /users/search?q=&limit=&offset= para buscar
+// usuarios por coincidencia parcial o aproximada.
+func (h *UserHandler) Search(w http.ResponseWriter, r *http.Request) *HTTPError {
+	// 1. Extracción de los parámetros de la query
+	query := domain.UserSearchQuery{Query: r.URL.Query().Get("q")}
+
+	var err error
+	if query.Limit, err = queryInt(r, "limit"); err != nil {
+		return NewHTTPError(err, http.StatusBadRequest)
+	}
+	if query.Offset, err = queryInt(r, "offset"); err != nil {
+		return NewHTTPError(err, http.StatusBadRequest)
+	}
+
+	// 2. Llamada al servicio
+	result, err := h.userService.Search(r.Context(), query)
+
+	// 3. Mapeo de errores
+	if err != nil {
+		if errors.Is(err, domain.ErrSearchQueryTooShort) {
+			return NewHTTPError(err, http.StatusBadRequest)
+		}
+		return NewHTTPError(errors.New(err.Error()), http.StatusInternalServerError)
+	}
+
+	// 4. Respuesta exitosa (200 OK)
+	return render(w, r, http.StatusOK, presentSearchResult(r, result))
+}
+
+// queryTime lee un parámetro de fecha en formato RFC 3339. Retorna nil si está ausente.
+func queryTime(r *http.Request, name string) (*time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, errors.New(name + " must be an RFC 3339 timestamp")
+	}
+
+	return &value, nil
+}
+
+// queryInt lee un parámetro entero no negativo de la query. Retorna 0 si está ausente.
+func queryInt(r *http.Request, name string) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, errors.New(name + " must be a non-negative integer")
+	}
+
+	return value, nil
+}
+
+// queryBool lee un parámetro booleano de la query. Retorna false si está ausente.
+func queryBool(r *http.Request, name string) (bool, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, errors.New(name + " must be a boolean")
+	}
+
+	return value, nil
+}
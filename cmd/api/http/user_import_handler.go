@@ -0,0 +1,171 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// UserImportHandler maneja las importaciones de usuarios desde archivos CSV o
+// NDJSON. Todas sus operaciones requieren privilegios de administrador.
+type UserImportHandler struct {
+	importService *application.UserImportService
+	maxBytes      int64
+}
+
+// NewUserImportHandler crea un UserImportHandler que acepta archivos de hasta maxBytes bytes.
+func NewUserImportHandler(service *application.UserImportService, maxBytes int64) *UserImportHandler {
+	return &UserImportHandler{importService: service, maxBytes: maxBytes}
+}
+
+// Create maneja la petición POST /users/imports. El cuerpo es el archivo; el
+// formato se toma de ?format= (csv, ndjson) o del Content-Type. En CSV,
+// ?map_name=, ?map_username= y ?map_email= indican la columna de cada campo.
+// Con ?dry_run=true responde 200 con el reporte de validación sin crear
+// usuarios; si no, 202 con la importación, que se procesa en segundo plano.
+func (h *UserImportHandler) Create(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errors.New("admin privileges required to import users"), http.StatusForbidden)
+	}
+
+	// 1. Extracción de parámetros
+	format, err := importFormat(r)
+	if err != nil {
+		return NewHTTPError(err, http.StatusBadRequest)
+	}
+
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			return NewHTTPError(errors.New("dry_run must be a boolean"), http.StatusBadRequest)
+		}
+	}
+
+	mapping := domain.ImportColumnMapping{
+		Name:     r.URL.Query().Get("map_name"),
+		Username: r.URL.Query().Get("map_username"),
+		Email:    r.URL.Query().Get("map_email"),
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return NewHTTPError(fmt.Errorf("import file exceeds %d bytes", h.maxBytes), http.StatusRequestEntityTooLarge)
+		}
+		return NewHTTPError(errors.New("invalid request body"), http.StatusBadRequest)
+	}
+
+	// 2. Validación (dry run) o inicio de la importación
+	if dryRun {
+		report, err := h.importService.DryRun(r.Context(), format, mapping, payload)
+		if err != nil {
+			return mapImportError(err)
+		}
+		return render(w, r, http.StatusOK, report)
+	}
+
+	userImport, err := h.importService.Start(r.Context(), format, mapping, payload)
+	if err != nil {
+		return mapImportError(err)
+	}
+
+	// 3. Respuesta (202 Accepted)
+	w.Header().Set("Location", "/users/imports/"+userImport.ID)
+	return render(w, r, http.StatusAccepted, userImport)
+}
+
+// FindById maneja la petición GET /users/imports/{id} con el estado y el progreso.
+func (h *UserImportHandler) FindById(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errors.New("admin privileges required to import users"), http.StatusForbidden)
+	}
+
+	userImport, err := h.importService.FindById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return mapImportError(err)
+	}
+
+	return render(w, r, http.StatusOK, userImport)
+}
+
+// Errors maneja la petición GET /users/imports/{id}/errors y descarga el
+// reporte de errores como CSV (row, field, message).
+func (h *UserImportHandler) Errors(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errors.New("admin privileges required to import users"), http.StatusForbidden)
+	}
+
+	id := chi.URLParam(r, "id")
+	rowErrors, err := h.importService.Errors(r.Context(), id)
+	if err != nil {
+		return mapImportError(err)
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "import-"+id+"-errors.csv"))
+	w.WriteHeader(http.StatusOK)
+
+	if err := application.WriteImportErrorsCSV(w, rowErrors); err != nil {
+		return NewHTTPError(errors.New("error csv encoding response"), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// Resume maneja la petición POST /users/imports/{id}/resume para reanudar una
+// importación fallida desde la última fila procesada.
+func (h *UserImportHandler) Resume(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errors.New("admin privileges required to import users"), http.StatusForbidden)
+	}
+
+	userImport, err := h.importService.Resume(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return mapImportError(err)
+	}
+
+	return render(w, r, http.StatusAccepted, userImport)
+}
+
+// importFormat determina el formato del archivo por ?format= o por el Content-Type.
+func importFormat(r *http.Request) (domain.ImportFormat, error) {
+	switch format := domain.ImportFormat(r.URL.Query().Get("format")); format {
+	case domain.ImportCSV, domain.ImportNDJSON:
+		return format, nil
+	case "":
+	default:
+		return "", errors.New("format must be csv or ndjson")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return domain.ImportCSV, nil
+	case "application/x-ndjson", "application/ndjson":
+		return domain.ImportNDJSON, nil
+	default:
+		return "", errors.New("set ?format=csv|ndjson or a text/csv or application/x-ndjson Content-Type")
+	}
+}
+
+// mapImportError traduce los errores del servicio de importación a HTTP.
+func mapImportError(err error) *HTTPError {
+	switch {
+	case errors.Is(err, domain.ErrImportNotFound):
+		return NewHTTPError(err, http.StatusNotFound)
+	case errors.Is(err, domain.ErrInvalidImportFile):
+		return NewHTTPError(err, http.StatusBadRequest)
+	case errors.Is(err, domain.ErrImportNotResumable):
+		return NewHTTPError(err, http.StatusConflict)
+	default:
+		return NewHTTPError(errors.New("internal server error"), http.StatusInternalServerError)
+	}
+}
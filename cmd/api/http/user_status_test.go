@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+// stubStatusService registra la última acción del ciclo de vida y retorna
+// err si está definido.
+type stubStatusService struct {
+	application.UserService
+	err    error
+	action domain.UserStatusAction
+	reason string
+}
+
+func (s *stubStatusService) ChangeStatus(_ context.Context, id string, action domain.UserStatusAction, reason string) (*domain.User, error) {
+	s.action, s.reason = action, reason
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &domain.User{ID: id, Username: "jane", Status: domain.UserSuspended, StatusReason: reason}, nil
+}
+
+// changeStatus ejecuta POST /users/u1:<acción> con el cuerpo y los roles indicados.
+func changeStatus(service application.UserService, action domain.UserStatusAction, body string, roles ...string) *httptest.ResponseRecorder {
+	r := withPrincipal(httptest.NewRequest(http.MethodPost, "/users/u1:"+string(action), strings.NewReader(body)), roles...)
+	if body != "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	ErrorHandlerWrapper(NewUserHandler(service).ChangeStatus(action))(w, r)
+	return w
+}
+
+func TestChangeStatusAppliesTheAction(t *testing.T) {
+	service := &stubStatusService{}
+
+	w := changeStatus(service, domain.StatusSuspend, `{"reason":"chargeback"}`, domain.RoleAdmin)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body)
+	}
+	if service.action != domain.StatusSuspend || service.reason != "chargeback" {
+		t.Errorf("service called with %s %q, want suspend chargeback", service.action, service.reason)
+	}
+	var user domain.User
+	if err := json.Unmarshal(w.Body.Bytes(), &user); err != nil || user.Status != domain.UserSuspended || user.StatusReason != "chargeback" {
+		t.Errorf("body = %s, want the suspended user", w.Body)
+	}
+
+	// El cuerpo es opcional
+	if w := changeStatus(service, domain.StatusActivate, "", domain.RoleAdmin); w.Code != http.StatusOK || service.reason != "" {
+		t.Errorf("without body: status = %d, reason = %q", w.Code, service.reason)
+	}
+}
+
+func TestChangeStatusErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		body  string
+		roles []string
+		want  int
+	}{
+		{name: "not an admin", roles: []string{"user"}, want: http.StatusForbidden},
+		{name: "reason too long", body: `{"reason":"` + strings.Repeat("x", 501) + `"}`, want: http.StatusBadRequest},
+		{name: "reason required", err: domain.ErrStatusReasonRequired, want: http.StatusBadRequest},
+		{name: "transition not allowed", err: domain.ErrInvalidStatusTransition, want: http.StatusConflict},
+		{name: "unknown user", err: domain.ErrUserNotFound, want: http.StatusNotFound},
+		{name: "unexpected", err: errors.New("connection refused"), want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles := tt.roles
+			if roles == nil {
+				roles = []string{domain.RoleAdmin}
+			}
+			if w := changeStatus(&stubStatusService{err: tt.err}, domain.StatusLock, tt.body, roles...); w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,374 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// UserV2 es la representación de un usuario en la v2 de la API: el nombre
+// se expone separado en nombre de pila y apellido.
+type UserV2 struct {
+	ID              string            `json:"id"`
+	GivenName       string            `json:"given_name"`
+	FamilyName      string            `json:"family_name"`
+	Username        string            `json:"username"`
+	Email           string            `json:"email"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	CreatedBy       string            `json:"created_by"`
+	UpdatedBy       string            `json:"updated_by"`
+	DeletedAt       *time.Time        `json:"deleted_at,omitempty"`
+	Status          domain.UserStatus `json:"status"`
+	StatusReason    string            `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time        `json:"status_changed_at,omitempty"`
+	EmailVerified   bool              `json:"email_verified"`
+	EmailVerifiedAt *time.Time        `json:"email_verified_at,omitempty"`
+}
+
+// UserCreateRequestV2 es la petición de creación de un usuario en la v2.
+type UserCreateRequestV2 struct {
+	GivenName  string            `json:"given_name" validate:"required,excludesall= "`
+	FamilyName string            `json:"family_name" validate:"excludesall= "`
+	Username   string            `json:"username" validate:"required,excludesall= "`
+	Email      string            `json:"email" validate:"required,excludesall= ,email"`
+	Status     domain.UserStatus `json:"status,omitempty" validate:"omitempty,oneof=pending active"`
+}
+
+// UserSearchHitV2 es un resultado de búsqueda en la v2.
+type UserSearchHitV2 struct {
+	User       UserV2            `json:"user"`
+	Rank       float64           `json:"rank"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// UserSearchResultV2 es una página de resultados de búsqueda en la v2.
+type UserSearchResultV2 struct {
+	Hits   []UserSearchHitV2 `json:"results"`
+	Total  int64             `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+// UserBatchItemResultV2 es el resultado de una operación del lote en la v2.
+type UserBatchItemResultV2 struct {
+	Index  int                       `json:"index"`
+	Op     domain.BatchOperationType `json:"op"`
+	Status int                       `json:"status"`
+	User   *UserV2                   `json:"user,omitempty"`
+	Error  *ErrorResponse            `json:"error,omitempty"`
+}
+
+// UserBatchResponseV2 es la respuesta de POST /v2/users:batch.
+type UserBatchResponseV2 struct {
+	Mode      string                  `json:"mode"`
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+	Results   []UserBatchItemResultV2 `json:"results"`
+}
+
+// apiVersionTypes asocia, por versión, los tipos de la representación v1
+// con su equivalente. La especificación OpenAPI los usa para documentar
+// cada árbol de rutas con sus propios esquemas.
+var apiVersionTypes = map[int]map[reflect.Type]reflect.Type{
+	APIVersion2: {
+		reflect.TypeOf(domain.User{}):              reflect.TypeOf(UserV2{}),
+		reflect.TypeOf([]domain.User{}):            reflect.TypeOf([]UserV2{}),
+		reflect.TypeOf(domain.UserCreateRequest{}): reflect.TypeOf(UserCreateRequestV2{}),
+		reflect.TypeOf(domain.UserSearchResult{}):  reflect.TypeOf(UserSearchResultV2{}),
+		reflect.TypeOf(UserBatchResponse{}):        reflect.TypeOf(UserBatchResponseV2{}),
+	},
+}
+
+// userV2FieldNames son los campos que pueden solicitarse con ?fields= en la v2.
+var userV2FieldNames = jsonFieldNames(reflect.TypeOf(UserV2{}))
+
+// jsonFieldNames retorna los nombres JSON de los campos de un struct, en orden.
+func jsonFieldNames(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// splitName separa el nombre en nombre de pila y apellido por el primer espacio.
+func splitName(name string) (given, family string) {
+	given, family, _ = strings.Cut(name, " ")
+	return given, family
+}
+
+// joinName compone el nombre de dominio a partir del nombre de pila y el apellido.
+func joinName(given, family string) string {
+	if family == "" {
+		return given
+	}
+	return given + " " + family
+}
+
+// newUserV2 convierte un usuario de dominio a su representación v2.
+func newUserV2(user *domain.User) UserV2 {
+	given, family := splitName(user.Name)
+	return UserV2{
+		ID:         user.ID,
+		GivenName:  given,
+		FamilyName: family,
+		Username:   user.Username,
+		Email:      user.Email,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+		CreatedBy:  user.CreatedBy,
+		UpdatedBy:  user.UpdatedBy,
+		DeletedAt:  user.DeletedAt,
+
+		Status:          user.Status,
+		StatusReason:    user.StatusReason,
+		StatusChangedAt: user.StatusChangedAt,
+
+		EmailVerified:   user.EmailVerified,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+	}
+}
+
+// toDomain convierte la representación v2 al usuario de dominio.
+func (u UserV2) toDomain() domain.User {
+	return domain.User{
+		ID:        u.ID,
+		Name:      joinName(u.GivenName, u.FamilyName),
+		Username:  u.Username,
+		Email:     u.Email,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+		CreatedBy: u.CreatedBy,
+		UpdatedBy: u.UpdatedBy,
+		DeletedAt: u.DeletedAt,
+	}
+}
+
+// toDomain convierte la petición de creación v2 a la de dominio.
+func (r UserCreateRequestV2) toDomain() domain.UserCreateRequest {
+	return domain.UserCreateRequest{
+		Name:     joinName(r.GivenName, r.FamilyName),
+		Username: r.Username,
+		Email:    r.Email,
+		Status:   r.Status,
+	}
+}
+
+// decodeUserCreateRequest deserializa la petición de creación en la
+// representación de la versión de la petición. Retorna la petición de
+// dominio y el valor decodificado, sobre el que se aplican los tags validate.
+func decodeUserCreateRequest(r *http.Request) (domain.UserCreateRequest, any, *HTTPError) {
+	if apiVersion(r) >= APIVersion2 {
+		var request UserCreateRequestV2
+		if err := decodeBody(r, &request); err != nil {
+			return domain.UserCreateRequest{}, nil, err
+		}
+		return request.toDomain(), request, nil
+	}
+
+	var request domain.UserCreateRequest
+	if err := decodeBody(r, &request); err != nil {
+		return request, nil, err
+	}
+	return request, request, nil
+}
+
+// decodeUser deserializa un usuario completo (PUT /users) en la
+// representación de la versión de la petición.
+func decodeUser(r *http.Request) (domain.User, any, *HTTPError) {
+	if apiVersion(r) >= APIVersion2 {
+		var user UserV2
+		if err := decodeBody(r, &user); err != nil {
+			return domain.User{}, nil, err
+		}
+		return user.toDomain(), user, nil
+	}
+
+	var user domain.User
+	if err := decodeBody(r, &user); err != nil {
+		return user, nil, err
+	}
+	return user, user, nil
+}
+
+// unmarshalBatchCreate deserializa el usuario de una operación create del
+// lote en la representación de la versión indicada. Retorna la petición de
+// dominio y el valor decodificado, sobre el que se aplican los tags validate.
+func unmarshalBatchCreate(data []byte, version int) (domain.UserCreateRequest, any, error) {
+	if version >= APIVersion2 {
+		var request UserCreateRequestV2
+		err := json.Unmarshal(data, &request)
+		return request.toDomain(), request, err
+	}
+
+	var request domain.UserCreateRequest
+	err := json.Unmarshal(data, &request)
+	return request, request, err
+}
+
+// unmarshalBatchUpdate deserializa el usuario de una operación update del
+// lote en la representación de la versión indicada.
+func unmarshalBatchUpdate(data []byte, version int) (domain.User, error) {
+	if version >= APIVersion2 {
+		var user UserV2
+		err := json.Unmarshal(data, &user)
+		return user.toDomain(), err
+	}
+
+	var user domain.User
+	err := json.Unmarshal(data, &user)
+	return user, err
+}
+
+// userFields interpreta ?fields= según la versión de la petición. Retorna
+// los campos de la respuesta y los campos de dominio que deben leerse
+// (given_name y family_name se leen de name); nil si no se pidió ninguno.
+func userFields(r *http.Request) (fields, domainFields []string, err error) {
+	raw := r.URL.Query().Get("fields")
+	if apiVersion(r) < APIVersion2 {
+		fields, err = domain.ParseUserFields(raw)
+		return fields, fields, err
+	}
+
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil, nil
+	}
+
+	known := make(map[string]bool, len(userV2FieldNames))
+	for _, name := range userV2FieldNames {
+		known[name] = true
+	}
+
+	seen, domainSeen := map[string]bool{}, map[string]bool{}
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if !known[field] {
+			return nil, nil, fmt.Errorf("%w %q; available fields: %s", domain.ErrUnknownField, field, strings.Join(userV2FieldNames, ","))
+		}
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
+		fields = append(fields, field)
+
+		source := field
+		if field == "given_name" || field == "family_name" {
+			source = "name"
+		}
+		if !domainSeen[source] {
+			domainSeen[source] = true
+			domainFields = append(domainFields, source)
+		}
+	}
+
+	return fields, domainFields, nil
+}
+
+// defaultUserFields retorna todos los campos de la representación de la versión.
+func defaultUserFields(r *http.Request) []string {
+	if apiVersion(r) >= APIVersion2 {
+		return append([]string(nil), userV2FieldNames...)
+	}
+	return domain.UserFieldNames()
+}
+
+// presentUser adapta un usuario a la representación de la versión de la petición.
+func presentUser(r *http.Request, user *domain.User) any {
+	if apiVersion(r) >= APIVersion2 {
+		return newUserV2(user)
+	}
+	return user
+}
+
+// presentUsers adapta una lista de usuarios a la representación de la versión.
+func presentUsers(r *http.Request, users []domain.User) any {
+	if apiVersion(r) < APIVersion2 {
+		return users
+	}
+
+	presented := make([]UserV2, len(users))
+	for i := range users {
+		presented[i] = newUserV2(&users[i])
+	}
+	return presented
+}
+
+// presentSearchResult adapta una página de búsqueda a la representación de
+// la versión. El resaltado de name se reparte entre given_name y family_name.
+func presentSearchResult(r *http.Request, result *domain.UserSearchResult) any {
+	if apiVersion(r) < APIVersion2 {
+		return result
+	}
+
+	presented := UserSearchResultV2{
+		Hits:   make([]UserSearchHitV2, len(result.Hits)),
+		Total:  result.Total,
+		Limit:  result.Limit,
+		Offset: result.Offset,
+	}
+	for i, hit := range result.Hits {
+		presented.Hits[i] = UserSearchHitV2{User: newUserV2(&hit.User), Rank: hit.Rank}
+		if hit.Highlights == nil {
+			continue
+		}
+
+		presented.Hits[i].Highlights = make(map[string]string, len(hit.Highlights)+1)
+		for field, highlight := range hit.Highlights {
+			if field != "name" {
+				presented.Hits[i].Highlights[field] = highlight
+				continue
+			}
+
+			given, family := splitHighlight(highlight)
+			if strings.Contains(given, "<mark>") {
+				presented.Hits[i].Highlights["given_name"] = given
+			}
+			if strings.Contains(family, "<mark>") {
+				presented.Hits[i].Highlights["family_name"] = family
+			}
+		}
+	}
+
+	return presented
+}
+
+// splitHighlight separa un valor resaltado como splitName, cerrando y
+// reabriendo la marca si la coincidencia abarca el espacio.
+func splitHighlight(highlight string) (given, family string) {
+	given, family, found := strings.Cut(highlight, " ")
+	if found && strings.Count(given, "<mark>") > strings.Count(given, "</mark>") {
+		given, family = given+"</mark>", "<mark>"+family
+	}
+	return given, family
+}
+
+// presentBatchResponse adapta la respuesta del lote a la representación de la versión.
+func presentBatchResponse(r *http.Request, response UserBatchResponse) any {
+	if apiVersion(r) < APIVersion2 {
+		return response
+	}
+
+	presented := UserBatchResponseV2{
+		Mode:      response.Mode,
+		Succeeded: response.Succeeded,
+		Failed:    response.Failed,
+		Results:   make([]UserBatchItemResultV2, len(response.Results)),
+	}
+	for i, item := range response.Results {
+		presented.Results[i] = UserBatchItemResultV2{Index: item.Index, Op: item.Op, Status: item.Status, Error: item.Error}
+		if item.User != nil {
+			user := newUserV2(item.User)
+			presented.Results[i].User = &user
+		}
+	}
+
+	return presented
+}
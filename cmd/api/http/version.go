@@ -0,0 +1,277 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Versiones de la API. La v2 separa name en given_name y family_name.
+const (
+	APIVersion1 = 1
+	APIVersion2 = 2
+
+	// latestAPIVersion es la versión vigente; las anteriores se anuncian como obsoletas.
+	latestAPIVersion = APIVersion2
+)
+
+// apiVersions son los árboles de rutas con prefijo (/v1, /v2).
+var apiVersions = []int{APIVersion1, APIVersion2}
+
+// Formas en que una petición selecciona la versión, registradas en las métricas.
+const (
+	versionViaPath      = "path"
+	versionViaMediaType = "media_type"
+	versionViaDefault   = "default"
+)
+
+// versionedMediaType reconoce los media types con versión, e.g.,
+// application/vnd.user-api.v2+json; sin sufijo se entiende JSON.
+var versionedMediaType = regexp.MustCompile(`^application/vnd\.user-api\.v(\d+)(?:\+([a-z0-9.-]+))?$`)
+
+// versionedRoute separa el prefijo de versión de un patrón de ruta.
+var versionedRoute = regexp.MustCompile(`^/v(\d+)(/.*)$`)
+
+// APIDeprecation anuncia la obsolescencia de una versión con las cabeceras
+// Deprecation (RFC 9745) y Sunset (RFC 8594). Los instantes cero se omiten.
+type APIDeprecation struct {
+	DeprecatedAt time.Time
+	Sunset       time.Time
+}
+
+// apiVersionKey es la clave privada de la versión de la API en el context.Context.
+type apiVersionKey struct{}
+
+// apiVersion retorna la versión de la API de la petición (v1 por defecto).
+func apiVersion(r *http.Request) int {
+	if version, ok := r.Context().Value(apiVersionKey{}).(int); ok {
+		return version
+	}
+	return APIVersion1
+}
+
+// splitVersion separa "/v2/users" en (2, "/users"). Las rutas sin prefijo
+// retornan la versión 0.
+func splitVersion(route string) (int, string) {
+	match := versionedRoute.FindStringSubmatch(route)
+	if match == nil {
+		return 0, route
+	}
+	version, _ := strconv.Atoi(match[1])
+	return version, match[2]
+}
+
+// versionMiddleware fija la versión de la API de la petición: la del
+// prefijo de la ruta o, en las rutas sin prefijo (version 0), la indicada
+// por un media type versionado en Accept o Content-Type, o v1. Los media
+// types versionados se reescriben a su formato base para la negociación.
+// Agrega API-Version, las cabeceras de obsolescencia y registra el uso.
+func versionMiddleware(version int, deprecations map[int]APIDeprecation, metrics *VersionMetrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// 1. Resolución de la versión
+			via := versionViaPath
+			requested := 0
+
+			r = r.Clone(r.Context())
+			for _, name := range []string{"Accept", "Content-Type"} {
+				value, mediaVersion := unversionMediaTypes(r.Header.Get(name))
+				if mediaVersion != 0 {
+					r.Header.Set(name, value)
+					if requested == 0 {
+						requested = mediaVersion
+					}
+				}
+			}
+
+			selected := version
+			if selected == 0 {
+				selected, via = APIVersion1, versionViaDefault
+				if requested != 0 {
+					selected, via = requested, versionViaMediaType
+				}
+			}
+			if selected < APIVersion1 || selected > latestAPIVersion {
+				writeHTTPError(w, r, NewHTTPError(fmt.Errorf("API version %d is not supported; use 1 to %d", selected, latestAPIVersion), http.StatusNotAcceptable))
+				return
+			}
+
+			// 2. Cabeceras de versión y obsolescencia
+			w.Header().Set("API-Version", strconv.Itoa(selected))
+			if deprecation, ok := deprecations[selected]; ok && selected < latestAPIVersion {
+				setDeprecationHeaders(w.Header(), deprecation, successorPath(r.URL.Path, version))
+			}
+
+			// 3. Registro del uso
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			r = r.WithContext(context.WithValue(r.Context(), apiVersionKey{}, selected))
+			next.ServeHTTP(recorder, r)
+
+			metrics.record(versionMetricKey{version: selected, via: via, method: r.Method, route: metricRoute(r), status: recorder.status})
+		})
+	}
+}
+
+// unversionMediaTypes reemplaza en una cabecera Accept o Content-Type los
+// media types versionados por su formato base y retorna la versión indicada.
+func unversionMediaTypes(header string) (string, int) {
+	if !strings.Contains(header, "vnd.user-api.") {
+		return header, 0
+	}
+
+	version := 0
+	parts := strings.Split(header, ",")
+	for i, part := range parts {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		match := versionedMediaType.FindStringSubmatch(mediaType)
+		if match == nil {
+			continue
+		}
+
+		if version == 0 {
+			version, _ = strconv.Atoi(match[1])
+		}
+		format := match[2]
+		if format == "" {
+			format = "json"
+		}
+		parts[i] = mime.FormatMediaType("application/"+format, params)
+	}
+
+	return strings.Join(parts, ","), version
+}
+
+// metricRoute retorna el patrón de la ruta de la petición. Si la petición
+// se respondió antes de completar el enrutamiento (e.g., la rechazó la
+// validación), el patrón se busca en la tabla de rutas.
+func metricRoute(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return r.URL.Path
+	}
+
+	pattern := rctx.RoutePattern()
+	if (pattern == "" || strings.HasSuffix(pattern, "/*")) && rctx.Routes != nil {
+		pattern = rctx.Routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path)
+	}
+	if pattern == "" {
+		return r.URL.Path
+	}
+	return normalizeRoute(pattern)
+}
+
+// setDeprecationHeaders anuncia la obsolescencia de la versión y enlaza la
+// ruta equivalente de la versión vigente.
+func setDeprecationHeaders(header http.Header, deprecation APIDeprecation, successor string) {
+	if !deprecation.DeprecatedAt.IsZero() {
+		header.Set("Deprecation", "@"+strconv.FormatInt(deprecation.DeprecatedAt.Unix(), 10))
+	}
+	if !deprecation.Sunset.IsZero() {
+		header.Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+	}
+	header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+	header.Add("Link", `</docs>; rel="deprecation"; type="text/html"`)
+}
+
+// successorPath retorna la ruta de la versión vigente equivalente a path.
+func successorPath(path string, version int) string {
+	if version != 0 {
+		_, path = splitVersion(path)
+	}
+	return "/v" + strconv.Itoa(latestAPIVersion) + path
+}
+
+// statusRecorder conserva el código de estado de la respuesta. Implementa
+// http.Flusher para no interrumpir el streaming (SSE, exportación).
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader registra el código de estado antes de enviarlo.
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write marca la respuesta como iniciada (200 si no se indicó otro código).
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush delega en el ResponseWriter subyacente si admite streaming.
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// versionMetricKey identifica una serie de VersionMetrics.
+type versionMetricKey struct {
+	version int
+	via     string
+	method  string
+	route   string
+	status  int
+}
+
+// VersionMetrics cuenta las peticiones por versión de la API, forma de
+// selección, ruta y código de estado, para seguir la migración de los
+// consumidores antes de retirar una versión.
+type VersionMetrics struct {
+	mu     sync.Mutex
+	counts map[versionMetricKey]int64
+}
+
+// NewVersionMetrics crea un VersionMetrics vacío.
+func NewVersionMetrics() *VersionMetrics {
+	return &VersionMetrics{counts: make(map[versionMetricKey]int64)}
+}
+
+// record incrementa la serie de la petición.
+func (m *VersionMetrics) record(key versionMetricKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[key]++
+}
+
+// Metrics maneja la petición GET /metrics, que expone los contadores en el
+// formato de texto de Prometheus.
+func (m *VersionMetrics) Metrics(w http.ResponseWriter, r *http.Request) *HTTPError {
+	m.mu.Lock()
+	lines := make([]string, 0, len(m.counts))
+	for key, count := range m.counts {
+		lines = append(lines, fmt.Sprintf(
+			`user_api_requests_total{version="v%d",via=%q,method=%q,route=%q,status="%d"} %d`,
+			key.version, key.via, key.method, key.route, key.status, count,
+		))
+	}
+	m.mu.Unlock()
+	sort.Strings(lines)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "# HELP user_api_requests_total Requests served, by API version and how it was selected.")
+	fmt.Fprintln(w, "# TYPE user_api_requests_total counter")
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+
+	return nil
+}
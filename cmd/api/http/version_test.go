@@ -0,0 +1,172 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// newVersionedRouter construye el router con v1 obsoleta desde deprecatedAt
+// y retirada en sunset.
+func newVersionedRouter(t *testing.T, deprecatedAt, sunset time.Time) http.Handler {
+	t.Helper()
+	t.Setenv("AUTH_DISABLED", "true")
+
+	handlers := newStubHandlers()
+	handlers.Users = NewUserHandler(&stubContractService{user: domain.User{Name: "Jane Doe", Username: "jane", Email: "jane@example.com", Status: domain.UserActive}})
+	router, err := NewRouter(handlers, RouterOptions{
+		Deprecations: map[int]APIDeprecation{APIVersion1: {DeprecatedAt: deprecatedAt, Sunset: sunset}},
+	})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	return router
+}
+
+// getWithAccept ejecuta GET target sobre handler con la cabecera Accept indicada.
+func getWithAccept(handler http.Handler, target, accept string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestVersionSelectsTheRepresentation(t *testing.T) {
+	router := newVersionedRouter(t, time.Time{}, time.Time{})
+
+	tests := []struct {
+		name        string
+		target      string
+		accept      string
+		wantVersion string
+		wantFields  []string
+		wantAbsent  []string
+	}{
+		{name: "v1 path", target: "/v1/users/01HZX3Y4Z5", wantVersion: "1", wantFields: []string{"name"}, wantAbsent: []string{"given_name"}},
+		{name: "v2 path", target: "/v2/users/01HZX3Y4Z5", wantVersion: "2", wantFields: []string{"given_name", "family_name"}, wantAbsent: []string{"name"}},
+		{name: "unversioned path defaults to v1", target: "/users/01HZX3Y4Z5", wantVersion: "1", wantFields: []string{"name"}},
+		{name: "media type selects v2", target: "/users/01HZX3Y4Z5", accept: "application/vnd.user-api.v2+json", wantVersion: "2", wantFields: []string{"given_name"}, wantAbsent: []string{"name"}},
+		{name: "media type without suffix is JSON", target: "/users/01HZX3Y4Z5", accept: "application/vnd.user-api.v2", wantVersion: "2", wantFields: []string{"given_name"}},
+		{name: "path wins over media type", target: "/v1/users/01HZX3Y4Z5", accept: "application/vnd.user-api.v2+json", wantVersion: "1", wantFields: []string{"name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := getWithAccept(router, tt.target, tt.accept)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d %s", w.Code, w.Body)
+			}
+			if got := w.Header().Get("API-Version"); got != tt.wantVersion {
+				t.Errorf("API-Version = %q, want %q", got, tt.wantVersion)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %s: %v", w.Body, err)
+			}
+			for _, field := range tt.wantFields {
+				if _, ok := body[field]; !ok {
+					t.Errorf("body %v is missing %q", body, field)
+				}
+			}
+			for _, field := range tt.wantAbsent {
+				if _, ok := body[field]; ok {
+					t.Errorf("body %v has %q", body, field)
+				}
+			}
+		})
+	}
+
+	w := getWithAccept(router, "/v2/users/01HZX3Y4Z5", "")
+	if !strings.Contains(w.Body.String(), `"given_name":"Jane"`) || !strings.Contains(w.Body.String(), `"family_name":"Doe"`) {
+		t.Errorf("v2 body = %s, want the name split into given and family names", w.Body)
+	}
+}
+
+func TestVersionRejectsUnsupportedMediaTypeVersions(t *testing.T) {
+	router := newVersionedRouter(t, time.Time{}, time.Time{})
+
+	w := getWithAccept(router, "/users/01HZX3Y4Z5", "application/vnd.user-api.v9+json")
+
+	if w.Code != http.StatusNotAcceptable {
+		t.Errorf("status = %d, want 406 for an unknown API version", w.Code)
+	}
+}
+
+func TestVersionAnnouncesDeprecation(t *testing.T) {
+	deprecatedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	router := newVersionedRouter(t, deprecatedAt, sunset)
+
+	w := getWithAccept(router, "/v1/users/01HZX3Y4Z5", "")
+	if got, want := w.Header().Get("Deprecation"), "@1767225600"; got != want {
+		t.Errorf("Deprecation = %q, want %q", got, want)
+	}
+	if got, want := w.Header().Get("Sunset"), "Thu, 31 Dec 2026 00:00:00 GMT"; got != want {
+		t.Errorf("Sunset = %q, want %q", got, want)
+	}
+	links := strings.Join(w.Header().Values("Link"), ", ")
+	if !strings.Contains(links, `</v2/users/01HZX3Y4Z5>; rel="successor-version"`) {
+		t.Errorf("Link = %q, want the v2 successor", links)
+	}
+
+	// La ruta sin prefijo resuelta a v1 también se anuncia como obsoleta.
+	w = getWithAccept(router, "/users/01HZX3Y4Z5", "")
+	if w.Header().Get("Deprecation") == "" || !strings.Contains(strings.Join(w.Header().Values("Link"), ", "), "</v2/users/01HZX3Y4Z5>") {
+		t.Errorf("unversioned v1 headers = %v, want the deprecation headers", w.Header())
+	}
+
+	// La versión vigente no se anuncia como obsoleta.
+	w = getWithAccept(router, "/v2/users/01HZX3Y4Z5", "")
+	if w.Header().Get("Deprecation") != "" || w.Header().Get("Sunset") != "" || len(w.Header().Values("Link")) != 0 {
+		t.Errorf("v2 headers = %v, want no deprecation headers", w.Header())
+	}
+}
+
+func TestVersionMetricsCountRequestsPerVersion(t *testing.T) {
+	router := newVersionedRouter(t, time.Time{}, time.Time{})
+
+	getWithAccept(router, "/v1/users/01HZX3Y4Z5", "")
+	getWithAccept(router, "/v1/users/01HZX3Y4Z5", "")
+	getWithAccept(router, "/v2/users/01HZX3Y4Z5", "")
+	getWithAccept(router, "/users/01HZX3Y4Z5", "application/vnd.user-api.v2+json")
+	getWithAccept(router, "/users/01HZX3Y4Z5", "")
+
+	w := getWithAccept(router, "/metrics", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /metrics = %d", w.Code)
+	}
+	for _, want := range []string{
+		`user_api_requests_total{version="v1",via="path",method="GET",route="/v1/users/{id}",status="200"} 2`,
+		`user_api_requests_total{version="v2",via="path",method="GET",route="/v2/users/{id}",status="200"} 1`,
+		`user_api_requests_total{version="v2",via="media_type",method="GET",route="/users/{id}",status="200"} 1`,
+		`user_api_requests_total{version="v1",via="default",method="GET",route="/users/{id}",status="200"} 1`,
+	} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("metrics = %s\nwant line %s", w.Body, want)
+		}
+	}
+}
+
+func TestSuccessorPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		version int
+		want    string
+	}{
+		{path: "/v1/users/42", version: APIVersion1, want: "/v2/users/42"},
+		{path: "/users/42", version: 0, want: "/v2/users/42"},
+	}
+	for _, tt := range tests {
+		if got := successorPath(tt.path, tt.version); got != tt.want {
+			t.Errorf("successorPath(%q, %d) = %q, want %q", tt.path, tt.version, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// WebhookHandler maneja las peticiones HTTP de administración de webhooks.
+// Todas sus operaciones requieren privilegios de administrador.
+type WebhookHandler struct {
+	webhookService application.WebhookService
+	validator      *validator.Validate
+}
+
+// NewWebhookHandler crea una nueva instancia de WebhookHandler con el servicio inyectado.
+func NewWebhookHandler(service application.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: service,
+		validator:      validator.New(),
+	}
+}
+
+// Create maneja la petición POST /webhooks. La respuesta incluye el secreto
+// de firma, que no vuelve a exponerse.
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errors.New("admin privileges required to manage webhooks"), http.StatusForbidden)
+	}
+
+	// 1. Deserialización y validación
+	var request domain.WebhookCreateRequest
+	if err := decodeBody(r, &request); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(request); err != nil {
+		return NewHTTPError(errors.New("url must be a valid http(s) URL and secret at least 16 characters"), http.StatusBadRequest)
+	}
+
+	// 2. Llamada al servicio
+	webhook, err := h.webhookService.Create(r.Context(), &request)
+	if err != nil {
+		return mapWebhookError(err)
+	}
+
+	// 3. Respuesta exitosa (201 Created)
+	return render(w, r, http.StatusCreated, webhook)
+}
+
+// FindAll maneja la petición GET /webhooks.
+func (h *WebhookHandler) FindAll(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errors.New("admin privileges required to manage webhooks"), http.StatusForbidden)
+	}
+
+	webhooks, err := h.webhookService.FindAll(r.Context())
+	if err != nil {
+		return mapWebhookError(err)
+	}
+
+	return render(w, r, http.StatusOK, webhooks)
+}
+
+// FindById maneja la petición GET /webhooks/{id}.
+func (h *WebhookHandler) FindById(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errors.New("admin privileges required to manage webhooks"), http.StatusForbidden)
+	}
+
+	webhook, err := h.webhookService.FindById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return mapWebhookError(err)
+	}
+
+	return render(w, r, http.StatusOK, webhook)
+}
+
+// Update maneja la petición PATCH /webhooks/{id}. Enviar "active": true
+// reactiva un webhook desactivado por fallos.
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errors.New("admin privileges required to manage webhooks"), http.StatusForbidden)
+	}
+
+	// 1. Deserialización y validación
+	var request domain.WebhookUpdateRequest
+	if err := decodeBody(r, &request); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(request); err != nil {
+		return NewHTTPError(errors.New("url must be a valid http(s) URL and secret at least 16 characters"), http.StatusBadRequest)
+	}
+
+	// 2. Llamada al servicio
+	webhook, err := h.webhookService.Update(r.Context(), chi.URLParam(r, "id"), &request)
+	if err != nil {
+		return mapWebhookError(err)
+	}
+
+	// 3. Respuesta exitosa (200 OK)
+	return render(w, r, http.StatusOK, webhook)
+}
+
+// Delete maneja la petición DELETE /webhooks/{id}.
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errors.New("admin privileges required to manage webhooks"), http.StatusForbidden)
+	}
+
+	if err := h.webhookService.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		return mapWebhookError(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+// Deliveries maneja la petición GET /webhooks/{id}/deliveries?limit= que
+// retorna el registro de entregas con sus códigos de respuesta.
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errors.New("admin privileges required to manage webhooks"), http.StatusForbidden)
+	}
+
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		return NewHTTPError(err, http.StatusBadRequest)
+	}
+
+	deliveries, err := h.webhookService.Deliveries(r.Context(), chi.URLParam(r, "id"), limit)
+	if err != nil {
+		return mapWebhookError(err)
+	}
+
+	return render(w, r, http.StatusOK, deliveries)
+}
+
+// Redeliver maneja la petición POST /webhooks/{id}/deliveries/{deliveryId}/redeliver.
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) *HTTPError {
+	if !isAdmin(r) {
+		return NewHTTPError(errors.New("admin privileges required to manage webhooks"), http.StatusForbidden)
+	}
+
+	delivery, err := h.webhookService.Redeliver(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "deliveryId"))
+	if err != nil {
+		return mapWebhookError(err)
+	}
+
+	// 202 Accepted: la entrega se realizará de forma asíncrona.
+	return render(w, r, http.StatusAccepted, delivery)
+}
+
+// mapWebhookError traduce los errores de dominio de webhooks a HTTP.
+func mapWebhookError(err error) *HTTPError {
+	switch {
+	case errors.Is(err, domain.ErrWebhookNotFound), errors.Is(err, domain.ErrWebhookDeliveryNotFound):
+		return NewHTTPError(errors.New(err.Error()), http.StatusNotFound)
+	case errors.Is(err, domain.ErrInvalidEventType), errors.Is(err, domain.ErrWebhookTargetNotAllowed):
+		return NewHTTPError(errors.New(err.Error()), http.StatusBadRequest)
+	default:
+		return NewHTTPError(errors.New(err.Error()), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,187 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SchemaAttribute describe un atributo de un esquema (RFC 7643, sección 7).
+type SchemaAttribute struct {
+	Name          string            `json:"name"`
+	Type          string            `json:"type"`
+	MultiValued   bool              `json:"multiValued"`
+	Description   string            `json:"description"`
+	Required      bool              `json:"required"`
+	CaseExact     bool              `json:"caseExact"`
+	Mutability    string            `json:"mutability"`
+	Returned      string            `json:"returned"`
+	Uniqueness    string            `json:"uniqueness"`
+	SubAttributes []SchemaAttribute `json:"subAttributes,omitempty"`
+}
+
+// attribute crea un SchemaAttribute de lectura y escritura, retornado por
+// defecto y sin restricción de unicidad.
+func attribute(name, attributeType, description string, subAttributes ...SchemaAttribute) SchemaAttribute {
+	return SchemaAttribute{
+		Name:          name,
+		Type:          attributeType,
+		Description:   description,
+		Mutability:    "readWrite",
+		Returned:      "default",
+		Uniqueness:    "none",
+		SubAttributes: subAttributes,
+	}
+}
+
+// userSchema es la definición del esquema User con los atributos admitidos.
+var userSchema = func() map[string]any {
+	userName := attribute("userName", "string", "Unique identifier for the user, used to sign in. Must not contain spaces.")
+	userName.Required, userName.Uniqueness = true, "server"
+
+	emails := attribute("emails", "complex", "Email address of the user. Only one (primary, type work) is kept.",
+		attribute("value", "string", "Email address."),
+		attribute("type", "string", "Always work."),
+		attribute("primary", "boolean", "Always true."),
+	)
+	emails.MultiValued, emails.Required, emails.Uniqueness = true, true, "server"
+
+	return map[string]any{
+		"schemas":     []string{SchemaSchema},
+		"id":          UserSchema,
+		"name":        "User",
+		"description": "User account",
+		"attributes": []SchemaAttribute{
+			userName,
+			attribute("name", "complex", "Name of the user. formatted takes precedence over givenName and familyName.",
+				attribute("formatted", "string", "Full name."),
+				attribute("givenName", "string", "Given name (up to the first space of the full name)."),
+				attribute("familyName", "string", "Family name (after the first space of the full name)."),
+			),
+			attribute("displayName", "string", "Same as name.formatted."),
+			emails,
+			attribute("active", "boolean", "Whether the account can authenticate (status active). false deactivates the account and true activates or unlocks it."),
+		},
+		"meta": map[string]any{
+			"resourceType": "Schema",
+			"location":     BasePath + "/Schemas/" + UserSchema,
+		},
+	}
+}()
+
+// userResourceType es la definición del tipo de recurso User.
+var userResourceType = map[string]any{
+	"schemas":     []string{ResourceTypeSchema},
+	"id":          "User",
+	"name":        "User",
+	"endpoint":    "/Users",
+	"description": "User account",
+	"schema":      UserSchema,
+	"meta": map[string]any{
+		"resourceType": "ResourceType",
+		"location":     BasePath + "/ResourceTypes/User",
+	},
+}
+
+// groupSchema es la definición del esquema Group con los atributos admitidos.
+var groupSchema = func() map[string]any {
+	displayName := attribute("displayName", "string", "Name of the group, unique within the tenant.")
+	displayName.Required, displayName.Uniqueness = true, "server"
+
+	members := attribute("members", "complex", "Direct members of the group: users and nested groups.",
+		attribute("value", "string", "Identifier of the member."),
+		attribute("$ref", "reference", "URI of the member resource."),
+		attribute("display", "string", "userName of a user or displayName of a group."),
+		attribute("type", "string", "User (default) or Group."),
+	)
+	members.MultiValued = true
+
+	return map[string]any{
+		"schemas":     []string{SchemaSchema},
+		"id":          GroupSchema,
+		"name":        "Group",
+		"description": "Group",
+		"attributes":  []SchemaAttribute{displayName, members},
+		"meta": map[string]any{
+			"resourceType": "Schema",
+			"location":     BasePath + "/Schemas/" + GroupSchema,
+		},
+	}
+}()
+
+// groupResourceType es la definición del tipo de recurso Group.
+var groupResourceType = map[string]any{
+	"schemas":     []string{ResourceTypeSchema},
+	"id":          "Group",
+	"name":        "Group",
+	"endpoint":    "/Groups",
+	"description": "Group",
+	"schema":      GroupSchema,
+	"meta": map[string]any{
+		"resourceType": "ResourceType",
+		"location":     BasePath + "/ResourceTypes/Group",
+	},
+}
+
+// serviceProviderConfig maneja GET /ServiceProviderConfig.
+func serviceProviderConfig(w http.ResponseWriter, r *http.Request) *Error {
+	render(w, http.StatusOK, map[string]any{
+		"schemas":          []string{ServiceProviderConfigSchema},
+		"documentationUri": "/docs",
+		"patch":            map[string]any{"supported": true},
+		"bulk":             map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":           map[string]any{"supported": true, "maxResults": maxCount},
+		"changePassword":   map[string]any{"supported": false},
+		"sort":             map[string]any{"supported": false},
+		"etag":             map[string]any{"supported": false},
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "Static bearer token configured in SCIM_BEARER_TOKEN",
+			"primary":     true,
+		}},
+		"meta": map[string]any{
+			"resourceType": "ServiceProviderConfig",
+			"location":     BasePath + "/ServiceProviderConfig",
+		},
+	})
+	return nil
+}
+
+// schemas maneja GET /Schemas.
+func schemas(w http.ResponseWriter, r *http.Request) *Error {
+	render(w, http.StatusOK, newListResponse(userSchema, groupSchema))
+	return nil
+}
+
+// schemaByID maneja GET /Schemas/{id}.
+func schemaByID(w http.ResponseWriter, r *http.Request) *Error {
+	switch chi.URLParam(r, "id") {
+	case UserSchema:
+		render(w, http.StatusOK, userSchema)
+	case GroupSchema:
+		render(w, http.StatusOK, groupSchema)
+	default:
+		return newError(http.StatusNotFound, "", "schema "+chi.URLParam(r, "id")+" not found")
+	}
+	return nil
+}
+
+// resourceTypes maneja GET /ResourceTypes.
+func resourceTypes(w http.ResponseWriter, r *http.Request) *Error {
+	render(w, http.StatusOK, newListResponse(userResourceType, groupResourceType))
+	return nil
+}
+
+// resourceTypeByID maneja GET /ResourceTypes/{id}.
+func resourceTypeByID(w http.ResponseWriter, r *http.Request) *Error {
+	switch chi.URLParam(r, "id") {
+	case "User":
+		render(w, http.StatusOK, userResourceType)
+	case "Group":
+		render(w, http.StatusOK, groupResourceType)
+	default:
+		return newError(http.StatusNotFound, "", "resource type "+chi.URLParam(r, "id")+" not found")
+	}
+	return nil
+}
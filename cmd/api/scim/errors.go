@@ -0,0 +1,132 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Valores de scimType (RFC 7644, sección 3.12) usados en las respuestas de error.
+const (
+	scimTypeInvalidFilter = "invalidFilter"
+	scimTypeInvalidSyntax = "invalidSyntax"
+	scimTypeInvalidPath   = "invalidPath"
+	scimTypeInvalidValue  = "invalidValue"
+	scimTypeNoTarget      = "noTarget"
+	scimTypeMutability    = "mutability"
+	scimTypeUniqueness    = "uniqueness"
+)
+
+// ErrorResponse es el cuerpo de una respuesta de error SCIM. Status es un
+// string por definición del protocolo.
+type ErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// Error es un error de un handler SCIM con su código HTTP y su scimType.
+type Error struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+// newError crea un Error con el código, el scimType (opcional) y el detalle indicados.
+func newError(status int, scimType, detail string) *Error {
+	return &Error{Status: status, ScimType: scimType, Detail: detail}
+}
+
+// HandlerFunc es la firma de los handlers SCIM: retornan un *Error que
+// errorHandlerWrapper escribe en el formato del protocolo.
+type HandlerFunc func(http.ResponseWriter, *http.Request) *Error
+
+// errorHandlerWrapper adapta un HandlerFunc a http.HandlerFunc, escribiendo
+// el error retornado como ErrorResponse.
+func errorHandlerWrapper(handler HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := handler(w, r); err != nil {
+			writeError(w, err)
+		}
+	}
+}
+
+// writeError escribe el error como ErrorResponse.
+func writeError(w http.ResponseWriter, err *Error) {
+	render(w, err.Status, ErrorResponse{
+		Schemas:  []string{ErrorSchema},
+		Status:   strconv.Itoa(err.Status),
+		ScimType: err.ScimType,
+		Detail:   err.Detail,
+	})
+}
+
+// errorFrom traduce un error de dominio al error SCIM equivalente. Los
+// errores inesperados se registran en el log y se responden sin su detalle.
+func errorFrom(err error) *Error {
+	var errNotNullable domain.ErrValueNotNullable
+	var validationErrors validator.ValidationErrors
+
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		return newError(http.StatusNotFound, "", err.Error())
+	case errors.Is(err, domain.ErrIdInUse), errors.Is(err, domain.ErrEmailInUse), errors.Is(err, domain.ErrUsernameInUse):
+		return newError(http.StatusConflict, scimTypeUniqueness, err.Error())
+	case errors.Is(err, domain.ErrInvalidStatusTransition):
+		return newError(http.StatusConflict, "", err.Error())
+	case errors.Is(err, domain.ErrGroupNotFound):
+		return newError(http.StatusNotFound, "", err.Error())
+	case errors.Is(err, domain.ErrGroupNameInUse):
+		return newError(http.StatusConflict, scimTypeUniqueness, err.Error())
+	case errors.Is(err, domain.ErrGroupCycle), errors.Is(err, domain.ErrMemberNotFound), errors.Is(err, domain.ErrInvalidMemberType):
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, err.Error())
+	case errors.As(err, &errNotNullable):
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, err.Error())
+	case errors.As(err, &validationErrors):
+		details := make([]string, len(validationErrors))
+		for i, fieldError := range validationErrors {
+			details[i] = attributeNames[fieldError.Field()] + ": failed on the '" + fieldError.Tag() + "' rule"
+		}
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, strings.Join(details, "; "))
+	default:
+		log.Printf("[SCIM] internal error: %v", err)
+		return newError(http.StatusInternalServerError, "", "internal server error")
+	}
+}
+
+// render escribe value como JSON con el media type SCIM.
+func render(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", MediaType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("[SCIM] failed to write response: %v", err)
+	}
+}
+
+// maxBodyBytes es el tamaño máximo del cuerpo de una petición.
+const maxBodyBytes = 1 << 20
+
+// decodeBody deserializa el cuerpo JSON de la petición en target.
+func decodeBody(r *http.Request, target any) *Error {
+	if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodyBytes)).Decode(target); err != nil {
+		return newError(http.StatusBadRequest, scimTypeInvalidSyntax, "invalid request payload: "+err.Error())
+	}
+	return nil
+}
+
+// requireSchema verifica que el mensaje declare el esquema indicado.
+func requireSchema(declared []string, schema string) *Error {
+	for _, name := range declared {
+		if strings.EqualFold(name, schema) {
+			return nil
+		}
+	}
+	return newError(http.StatusBadRequest, scimTypeInvalidSyntax, "schemas must include "+schema)
+}
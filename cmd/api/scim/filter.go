@@ -0,0 +1,486 @@
+package scim
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter es una expresión de filtro SCIM (RFC 7644, sección 3.4.2.2) ya
+// analizada, que se evalúa sobre la representación JSON de un recurso.
+type Filter interface {
+	// Matches indica si el recurso cumple la expresión.
+	Matches(resource map[string]any) bool
+}
+
+// comparisonOperators son los operadores de comparación admitidos.
+var comparisonOperators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true,
+}
+
+// caseExactAttributes son los atributos que se comparan distinguiendo
+// mayúsculas; el resto (e.g., userName, emails) no las distingue.
+var caseExactAttributes = map[string]bool{
+	"id":         true,
+	"externalid": true,
+}
+
+// ParseFilter analiza una expresión de filtro, e.g.:
+//
+//	userName eq "bjensen"
+//	emails[type eq "work" and value co "@example.com"] or not (active eq false)
+//	meta.lastModified gt "2025-01-01T00:00:00Z"
+func ParseFilter(raw string) (Filter, error) {
+	tokens, err := tokenizeFilter(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &filterParser{tokens: tokens}
+	filter, err := parser.or()
+	if err != nil {
+		return nil, err
+	}
+	if token, ok := parser.peek(); ok {
+		return nil, fmt.Errorf("unexpected %q", token.text)
+	}
+
+	return filter, nil
+}
+
+// filterToken es un elemento léxico de un filtro: un literal de texto, un
+// delimitador ("(", ")", "[", "]") o una palabra (atributo, operador,
+// palabra clave, número, true, false o null).
+type filterToken struct {
+	text    string
+	literal bool
+}
+
+// tokenizeFilter separa un filtro en sus elementos léxicos.
+func tokenizeFilter(raw string) ([]filterToken, error) {
+	tokens := make([]filterToken, 0)
+
+	for i := 0; i < len(raw); {
+		switch c := raw[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			tokens = append(tokens, filterToken{text: string(c)})
+			i++
+		case c == '"':
+			// Literal JSON: se busca la comilla de cierre no escapada.
+			end := i + 1
+			for end < len(raw) && raw[end] != '"' {
+				if raw[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(raw) {
+				return nil, errors.New("unterminated string literal")
+			}
+			var text string
+			if err := json.Unmarshal([]byte(raw[i:end+1]), &text); err != nil {
+				return nil, fmt.Errorf("invalid string literal %s", raw[i:end+1])
+			}
+			tokens = append(tokens, filterToken{text: text, literal: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(raw) && !strings.ContainsRune(" \t()[]\"", rune(raw[end])) {
+				end++
+			}
+			tokens = append(tokens, filterToken{text: raw[i:end]})
+			i = end
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, errors.New("empty filter")
+	}
+	return tokens, nil
+}
+
+// filterParser es un analizador descendente recursivo de la gramática:
+//
+//	or    = and *("or" and)
+//	and   = unary *("and" unary)
+//	unary = "not" "(" or ")" / "(" or ")" / attrPath "[" or "]" /
+//	        attrPath "pr" / attrPath compareOp compValue
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+// peek retorna el próximo elemento sin consumirlo.
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// next consume y retorna el próximo elemento.
+func (p *filterParser) next() (filterToken, error) {
+	token, ok := p.peek()
+	if !ok {
+		return filterToken{}, errors.New("unexpected end of filter")
+	}
+	p.pos++
+	return token, nil
+}
+
+// keyword consume el próximo elemento si es la palabra indicada.
+func (p *filterParser) keyword(word string) bool {
+	token, ok := p.peek()
+	if ok && !token.literal && strings.EqualFold(token.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect consume el delimitador indicado o retorna un error.
+func (p *filterParser) expect(delimiter string) error {
+	token, err := p.next()
+	if err != nil {
+		return fmt.Errorf("expected %q: %w", delimiter, err)
+	}
+	if token.literal || token.text != delimiter {
+		return fmt.Errorf("expected %q, found %q", delimiter, token.text)
+	}
+	return nil
+}
+
+// or analiza una disyunción.
+func (p *filterParser) or() (Filter, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orFilter{left, right}
+	}
+	return left, nil
+}
+
+// and analiza una conjunción.
+func (p *filterParser) and() (Filter, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = andFilter{left, right}
+	}
+	return left, nil
+}
+
+// unary analiza una negación, un grupo, un filtro de valores o una comparación.
+func (p *filterParser) unary() (Filter, error) {
+	if p.keyword("not") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		filter, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return notFilter{filter}, p.expect(")")
+	}
+
+	token, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if token.text == "(" && !token.literal {
+		filter, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return filter, p.expect(")")
+	}
+	if token.literal || strings.ContainsAny(token.text, "()[]") {
+		return nil, fmt.Errorf("expected an attribute path, found %q", token.text)
+	}
+
+	path := parseAttributePath(token.text)
+
+	if next, ok := p.peek(); ok && !next.literal && next.text == "[" {
+		p.pos++
+		filter, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return valuePathFilter{attribute: path.attribute, filter: filter}, p.expect("]")
+	}
+
+	operator, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	op := strings.ToLower(operator.text)
+	if op == "pr" && !operator.literal {
+		return presentFilter{path}, nil
+	}
+	if operator.literal || !comparisonOperators[op] {
+		return nil, fmt.Errorf("unknown operator %q", operator.text)
+	}
+
+	value, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	compValue, err := parseCompValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return comparisonFilter{path: path, op: op, value: compValue}, nil
+}
+
+// parseCompValue interpreta el valor de una comparación: texto, número,
+// true, false o null.
+func parseCompValue(token filterToken) (any, error) {
+	if token.literal {
+		return token.text, nil
+	}
+	switch token.text {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	number, err := strconv.ParseFloat(token.text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid comparison value %q", token.text)
+	}
+	return number, nil
+}
+
+// attributePath es la ruta a un atributo (e.g., name.givenName), con los
+// nombres en minúsculas.
+type attributePath struct {
+	attribute    string
+	subAttribute string
+}
+
+// parseAttributePath interpreta una ruta de atributo, opcionalmente
+// calificada con la URN del esquema User o Group.
+func parseAttributePath(raw string) attributePath {
+	raw = strings.ToLower(raw)
+	for _, schema := range []string{UserSchema, GroupSchema} {
+		if rest, ok := strings.CutPrefix(raw, strings.ToLower(schema)+":"); ok {
+			raw = rest
+		}
+	}
+	attribute, subAttribute, _ := strings.Cut(raw, ".")
+	return attributePath{attribute: attribute, subAttribute: subAttribute}
+}
+
+// values retorna los valores simples del atributo en el recurso. En los
+// atributos multivaluados se recorren todos los elementos y, si no se
+// indica un subatributo, se usa su subatributo value.
+func (a attributePath) values(resource map[string]any) []any {
+	value, ok := lookup(resource, a.attribute)
+	if !ok {
+		return nil
+	}
+
+	elements, multiValued := value.([]any)
+	if !multiValued {
+		elements = []any{value}
+	}
+
+	values := make([]any, 0, len(elements))
+	for _, element := range elements {
+		complexValue, isComplex := element.(map[string]any)
+		switch {
+		case isComplex && a.subAttribute != "":
+			if sub, ok := lookup(complexValue, a.subAttribute); ok {
+				values = append(values, sub)
+			}
+		case isComplex && multiValued:
+			if sub, ok := lookup(complexValue, "value"); ok {
+				values = append(values, sub)
+			}
+		case !isComplex && a.subAttribute == "":
+			values = append(values, element)
+		}
+	}
+	return values
+}
+
+// lookup busca un atributo por nombre sin distinguir mayúsculas.
+func lookup(object map[string]any, name string) (any, bool) {
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value, value != nil
+		}
+	}
+	return nil, false
+}
+
+// andFilter se cumple si se cumplen ambas expresiones.
+type andFilter struct{ left, right Filter }
+
+// Matches implementa Filter.
+func (f andFilter) Matches(resource map[string]any) bool {
+	return f.left.Matches(resource) && f.right.Matches(resource)
+}
+
+// orFilter se cumple si se cumple alguna de las expresiones.
+type orFilter struct{ left, right Filter }
+
+// Matches implementa Filter.
+func (f orFilter) Matches(resource map[string]any) bool {
+	return f.left.Matches(resource) || f.right.Matches(resource)
+}
+
+// notFilter niega una expresión.
+type notFilter struct{ filter Filter }
+
+// Matches implementa Filter.
+func (f notFilter) Matches(resource map[string]any) bool {
+	return !f.filter.Matches(resource)
+}
+
+// presentFilter (pr) se cumple si el atributo tiene algún valor no vacío.
+type presentFilter struct{ path attributePath }
+
+// Matches implementa Filter.
+func (f presentFilter) Matches(resource map[string]any) bool {
+	for _, value := range f.path.values(resource) {
+		if text, ok := value.(string); !ok || text != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// valuePathFilter (e.g., emails[type eq "work"]) se cumple si algún
+// elemento del atributo multivaluado cumple la expresión interna.
+type valuePathFilter struct {
+	attribute string
+	filter    Filter
+}
+
+// Matches implementa Filter.
+func (f valuePathFilter) Matches(resource map[string]any) bool {
+	value, ok := lookup(resource, f.attribute)
+	if !ok {
+		return false
+	}
+	elements, multiValued := value.([]any)
+	if !multiValued {
+		elements = []any{value}
+	}
+	for _, element := range elements {
+		complexValue, isComplex := element.(map[string]any)
+		if !isComplex {
+			complexValue = map[string]any{"value": element}
+		}
+		if f.filter.Matches(complexValue) {
+			return true
+		}
+	}
+	return false
+}
+
+// comparisonFilter compara un atributo con un valor. Se cumple si algún
+// valor del atributo cumple la comparación (ne: si ninguno es igual).
+type comparisonFilter struct {
+	path  attributePath
+	op    string
+	value any
+}
+
+// Matches implementa Filter.
+func (f comparisonFilter) Matches(resource map[string]any) bool {
+	values := f.path.values(resource)
+
+	if f.value == nil {
+		// "eq null" equivale a "not pr" y "ne null" a "pr".
+		return (len(values) == 0) == (f.op == "eq")
+	}
+	if f.op == "ne" {
+		return !(comparisonFilter{path: f.path, op: "eq", value: f.value}).Matches(resource)
+	}
+
+	caseExact := caseExactAttributes[f.path.attribute]
+	for _, value := range values {
+		if compare(f.op, value, f.value, caseExact) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare aplica el operador a un valor del recurso y al valor del filtro.
+// Los textos con formato RFC 3339 se comparan como instantes.
+func compare(op string, actual, expected any, caseExact bool) bool {
+	switch expected := expected.(type) {
+	case bool:
+		actual, ok := actual.(bool)
+		return ok && op == "eq" && actual == expected
+	case float64:
+		actual, ok := actual.(float64)
+		return ok && order(op, cmp.Compare(actual, expected))
+	case string:
+		actual, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		actualTime, actualErr := time.Parse(time.RFC3339Nano, actual)
+		expectedTime, expectedErr := time.Parse(time.RFC3339Nano, expected)
+		if actualErr == nil && expectedErr == nil {
+			return order(op, actualTime.Compare(expectedTime))
+		}
+		if !caseExact {
+			actual, expected = strings.ToLower(actual), strings.ToLower(expected)
+		}
+		switch op {
+		case "co":
+			return strings.Contains(actual, expected)
+		case "sw":
+			return strings.HasPrefix(actual, expected)
+		case "ew":
+			return strings.HasSuffix(actual, expected)
+		default:
+			return order(op, strings.Compare(actual, expected))
+		}
+	}
+	return false
+}
+
+// order aplica un operador de orden (eq, gt, ge, lt, le) al resultado de una comparación.
+func order(op string, comparison int) bool {
+	switch op {
+	case "eq":
+		return comparison == 0
+	case "gt":
+		return comparison > 0
+	case "ge":
+		return comparison >= 0
+	case "lt":
+		return comparison < 0
+	case "le":
+		return comparison <= 0
+	}
+	return false
+}
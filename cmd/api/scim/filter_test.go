@@ -0,0 +1,78 @@
+package scim
+
+import "testing"
+
+// bjensen es el recurso de ejemplo de RFC 7643, reducido a los atributos usados.
+var bjensen = map[string]any{
+	"id":         "2819c223",
+	"externalId": "bjensen",
+	"userName":   "Bjensen@example.com",
+	"name":       map[string]any{"givenName": "Barbara", "familyName": "Jensen"},
+	"emails": []any{
+		map[string]any{"value": "bjensen@example.com", "type": "work", "primary": true},
+		map[string]any{"value": "babs@jensen.org", "type": "home"},
+	},
+	"active": true,
+	"meta":   map[string]any{"lastModified": "2025-06-01T10:00:00Z"},
+}
+
+func TestParseFilterMatches(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{filter: `userName eq "bjensen@example.com"`, want: true},
+		{filter: `userName eq "someone@example.com"`, want: false},
+		{filter: `USERNAME Eq "BJENSEN@EXAMPLE.COM"`, want: true},
+		{filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName sw "bjensen"`, want: true},
+		{filter: `id eq "2819C223"`, want: false},
+		{filter: `externalId eq "bjensen"`, want: true},
+		{filter: `name.familyName co "ens"`, want: true},
+		{filter: `name.givenName ew "ra"`, want: true},
+		{filter: `emails co "jensen.org"`, want: true},
+		{filter: `emails[type eq "work" and value co "@example.com"]`, want: true},
+		{filter: `emails[type eq "home" and value co "@example.com"]`, want: false},
+		{filter: `emails.type eq "home"`, want: true},
+		{filter: `active eq true`, want: true},
+		{filter: `not (active eq true)`, want: false},
+		{filter: `title pr`, want: false},
+		{filter: `title eq null`, want: true},
+		{filter: `userName ne "bjensen@example.com"`, want: false},
+		{filter: `meta.lastModified gt "2025-01-01T00:00:00Z"`, want: true},
+		{filter: `meta.lastModified lt "2025-06-01T09:00:00-02:00"`, want: true},
+		{filter: `userName eq "x" or (active eq true and name.givenName eq "Barbara")`, want: true},
+		{filter: `userName eq "x" or active eq true and name.givenName eq "Nobody"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := ParseFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("ParseFilter: %v", err)
+			}
+			if got := filter.Matches(bjensen); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterRejectsInvalidExpressions(t *testing.T) {
+	for _, raw := range []string{
+		``,
+		`userName`,
+		`userName eq`,
+		`userName equals "x"`,
+		`userName eq "x`,
+		`userName eq bjensen`,
+		`(userName eq "x"`,
+		`userName eq "x")`,
+		`not userName eq "x"`,
+		`"userName" eq "x"`,
+		`emails[type eq "work"`,
+	} {
+		if _, err := ParseFilter(raw); err == nil {
+			t.Errorf("ParseFilter(%q) succeeded, want an error", raw)
+		}
+	}
+}
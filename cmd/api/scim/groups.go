@@ -0,0 +1,489 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GroupResource es la representación SCIM de un grupo (esquema core Group).
+// members contiene los miembros directos: usuarios y grupos anidados.
+type GroupResource struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	DisplayName string        `json:"displayName"`
+	Members     []GroupMember `json:"members,omitempty"`
+	Meta        *Meta         `json:"meta,omitempty"`
+}
+
+// GroupMember es un elemento del atributo multivaluado members. type es
+// "User" (por defecto, si no se indica) o "Group".
+type GroupMember struct {
+	Value   string `json:"value"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+// GroupHandler implementa el recurso /Groups sobre application.GroupService.
+type GroupHandler struct {
+	groupService application.GroupService
+}
+
+// NewGroupHandler crea una nueva instancia de GroupHandler.
+func NewGroupHandler(service application.GroupService) *GroupHandler {
+	return &GroupHandler{groupService: service}
+}
+
+// List maneja GET /Groups. El filtro se evalúa sobre la representación SCIM
+// de cada grupo, en orden de nombre. Con excludedAttributes=members (como
+// suelen pedir los proveedores de identidad) no se leen los miembros.
+func (h *GroupHandler) List(w http.ResponseWriter, r *http.Request) *Error {
+	// 1. Parámetros de filtro y paginación
+	query := r.URL.Query()
+
+	var filter Filter
+	if raw := query.Get("filter"); raw != "" {
+		var err error
+		if filter, err = ParseFilter(raw); err != nil {
+			return newError(http.StatusBadRequest, scimTypeInvalidFilter, "invalid filter: "+err.Error())
+		}
+	}
+
+	startIndex, err := intParam(query.Get("startIndex"), 1)
+	if err != nil {
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "startIndex must be an integer")
+	}
+	count, err := intParam(query.Get("count"), defaultCount)
+	if err != nil {
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "count must be an integer")
+	}
+	startIndex, count = max(startIndex, 1), min(max(count, 0), maxCount)
+
+	excluded := strings.Split(strings.ToLower(query.Get("excludedAttributes")), ",")
+	withMembers := !slices.Contains(excluded, "members")
+
+	// 2. Recorrido de los grupos
+	groups, err := h.groupService.FindAll(r.Context())
+	if err != nil {
+		return errorFrom(err)
+	}
+
+	response := ListResponse{Schemas: []string{ListResponseSchema}, StartIndex: startIndex, Resources: []any{}}
+	for i := range groups {
+		resource := newGroupResource(&groups[i], nil)
+		if withMembers {
+			if resource, err = h.resource(r.Context(), &groups[i]); err != nil {
+				return errorFrom(err)
+			}
+		}
+		if filter != nil && !filter.Matches(attributesOf(resource)) {
+			continue
+		}
+
+		response.TotalResults++
+		if response.TotalResults >= startIndex && len(response.Resources) < count {
+			response.Resources = append(response.Resources, resource)
+		}
+	}
+
+	response.ItemsPerPage = len(response.Resources)
+	render(w, http.StatusOK, response)
+	return nil
+}
+
+// Create maneja POST /Groups: crea el grupo y agrega sus miembros.
+func (h *GroupHandler) Create(w http.ResponseWriter, r *http.Request) *Error {
+	// 1. Deserialización y validación
+	var resource GroupResource
+	if err := decodeBody(r, &resource); err != nil {
+		return err
+	}
+	if err := requireSchema(resource.Schemas, GroupSchema); err != nil {
+		return err
+	}
+	add, scimErr := memberRefs(resource.Members)
+	if scimErr != nil {
+		return scimErr
+	}
+
+	// 2. Creación y membresías
+	group, err := h.groupService.Create(r.Context(), &domain.GroupCreateRequest{Name: resource.DisplayName})
+	if err != nil {
+		return errorFrom(err)
+	}
+	if len(add) > 0 {
+		if _, err := h.groupService.ChangeMembers(r.Context(), group.ID, &domain.GroupMembershipChange{Add: add}); err != nil {
+			// El grupo no debe quedar creado con una membresía parcial.
+			_ = h.groupService.Delete(r.Context(), group.ID)
+			return errorFrom(err)
+		}
+	}
+
+	return h.respond(w, r.Context(), http.StatusCreated, group.ID)
+}
+
+// Get maneja GET /Groups/{id}.
+func (h *GroupHandler) Get(w http.ResponseWriter, r *http.Request) *Error {
+	return h.respond(w, r.Context(), http.StatusOK, chi.URLParam(r, "id"))
+}
+
+// Replace maneja PUT /Groups/{id}: reemplaza el nombre y los miembros.
+func (h *GroupHandler) Replace(w http.ResponseWriter, r *http.Request) *Error {
+	// 1. Lectura del estado actual
+	state, err := h.state(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return errorFrom(err)
+	}
+
+	// 2. Deserialización del reemplazo
+	var resource GroupResource
+	if err := decodeBody(r, &resource); err != nil {
+		return err
+	}
+	if err := requireSchema(resource.Schemas, GroupSchema); err != nil {
+		return err
+	}
+	members, scimErr := memberRefs(resource.Members)
+	if scimErr != nil {
+		return scimErr
+	}
+
+	// 3. Persistencia
+	replaced := groupState{name: resource.DisplayName, members: members}
+	if err := h.save(r.Context(), state, replaced); err != nil {
+		return err
+	}
+
+	return h.respond(w, r.Context(), http.StatusOK, state.id)
+}
+
+// Patch maneja PATCH /Groups/{id}: aplica las operaciones sobre el nombre y
+// los miembros y persiste el resultado solo si todas son válidas. Los
+// cambios de membresía se aplican en una sola transacción.
+func (h *GroupHandler) Patch(w http.ResponseWriter, r *http.Request) *Error {
+	// 1. Lectura del estado actual
+	state, err := h.state(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return errorFrom(err)
+	}
+
+	// 2. Deserialización y aplicación de las operaciones
+	var request PatchRequest
+	if err := decodeBody(r, &request); err != nil {
+		return err
+	}
+	if err := requireSchema(request.Schemas, PatchOpSchema); err != nil {
+		return err
+	}
+
+	patched := state.clone()
+	if err := patched.apply(request.Operations); err != nil {
+		return err
+	}
+
+	// 3. Persistencia
+	if err := h.save(r.Context(), state, patched); err != nil {
+		return err
+	}
+
+	return h.respond(w, r.Context(), http.StatusOK, state.id)
+}
+
+// Delete maneja DELETE /Groups/{id}. Los miembros no se eliminan.
+func (h *GroupHandler) Delete(w http.ResponseWriter, r *http.Request) *Error {
+	if err := h.groupService.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		return errorFrom(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// save persiste el nuevo estado de un grupo: primero los cambios de
+// membresía (atómicos) y luego el nombre.
+func (h *GroupHandler) save(ctx context.Context, current, next groupState) *Error {
+	// 1. Membresías
+	change := current.diff(next)
+	if len(change.Add) > 0 || len(change.Remove) > 0 {
+		if _, err := h.groupService.ChangeMembers(ctx, current.id, &change); err != nil {
+			return errorFrom(err)
+		}
+	}
+
+	// 2. Nombre
+	if next.name != current.name {
+		if _, err := h.groupService.Update(ctx, current.id, &domain.GroupUpdateRequest{Name: &next.name}); err != nil {
+			return errorFrom(err)
+		}
+	}
+
+	return nil
+}
+
+// state lee el nombre y los miembros directos de un grupo.
+func (h *GroupHandler) state(ctx context.Context, id string) (groupState, error) {
+	group, err := h.groupService.FindById(ctx, id)
+	if err != nil {
+		return groupState{}, err
+	}
+	members, err := h.groupService.Members(ctx, id, false)
+	if err != nil {
+		return groupState{}, err
+	}
+
+	state := groupState{id: group.ID, name: group.Name, members: make([]domain.MemberRef, len(members))}
+	for i, member := range members {
+		state.members[i] = domain.MemberRef{Type: member.Type, ID: member.ID}
+	}
+	return state, nil
+}
+
+// resource retorna la representación SCIM del grupo con sus miembros.
+func (h *GroupHandler) resource(ctx context.Context, group *domain.Group) (GroupResource, error) {
+	members, err := h.groupService.Members(ctx, group.ID, false)
+	if err != nil {
+		return GroupResource{}, err
+	}
+	return newGroupResource(group, members), nil
+}
+
+// respond relee el grupo y lo escribe como recurso SCIM con su Location.
+func (h *GroupHandler) respond(w http.ResponseWriter, ctx context.Context, status int, id string) *Error {
+	group, err := h.groupService.FindById(ctx, id)
+	if err != nil {
+		return errorFrom(err)
+	}
+	resource, err := h.resource(ctx, group)
+	if err != nil {
+		return errorFrom(err)
+	}
+
+	w.Header().Set("Location", resource.Meta.Location)
+	render(w, status, resource)
+	return nil
+}
+
+// newGroupResource convierte un grupo de dominio y sus miembros a su representación SCIM.
+func newGroupResource(group *domain.Group, members []domain.GroupMember) GroupResource {
+	resource := GroupResource{
+		Schemas:     []string{GroupSchema},
+		ID:          group.ID,
+		DisplayName: group.Name,
+		Meta: &Meta{
+			ResourceType: "Group",
+			Created:      group.CreatedAt,
+			LastModified: group.UpdatedAt,
+			Location:     BasePath + "/Groups/" + group.ID,
+		},
+	}
+	for _, member := range members {
+		resource.Members = append(resource.Members, newGroupMember(member))
+	}
+	return resource
+}
+
+// newGroupMember convierte un miembro de dominio a su representación SCIM.
+func newGroupMember(member domain.GroupMember) GroupMember {
+	if member.Type == domain.MemberGroup {
+		return GroupMember{Value: member.ID, Ref: BasePath + "/Groups/" + member.ID, Display: member.Display, Type: "Group"}
+	}
+	return GroupMember{Value: member.ID, Ref: BasePath + "/Users/" + member.ID, Display: member.Display, Type: "User"}
+}
+
+// memberRefs convierte los miembros SCIM recibidos a referencias de dominio.
+func memberRefs(members []GroupMember) ([]domain.MemberRef, *Error) {
+	refs := make([]domain.MemberRef, 0, len(members))
+	for _, member := range members {
+		if member.Value == "" {
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "members require a value")
+		}
+		switch strings.ToLower(member.Type) {
+		case "", "user":
+			refs = append(refs, domain.MemberRef{Type: domain.MemberUser, ID: member.Value})
+		case "group":
+			refs = append(refs, domain.MemberRef{Type: domain.MemberGroup, ID: member.Value})
+		default:
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "member type must be User or Group, got "+strconv.Quote(member.Type))
+		}
+	}
+	return refs, nil
+}
+
+// attributesOf retorna el recurso como un objeto JSON genérico, sobre el que
+// se evalúan los filtros.
+func attributesOf(resource any) map[string]any {
+	raw, _ := json.Marshal(resource)
+	var attributes map[string]any
+	_ = json.Unmarshal(raw, &attributes)
+	return attributes
+}
+
+// groupState son los atributos de un grupo que el proveedor de identidad
+// puede modificar, sobre los que se aplican PUT y PATCH.
+type groupState struct {
+	id      string
+	name    string
+	members []domain.MemberRef
+}
+
+// clone retorna una copia independiente del estado.
+func (s groupState) clone() groupState {
+	s.members = slices.Clone(s.members)
+	return s
+}
+
+// diff retorna los miembros a agregar y quitar para pasar de s a next.
+func (s groupState) diff(next groupState) domain.GroupMembershipChange {
+	change := domain.GroupMembershipChange{}
+	for _, member := range next.members {
+		if !slices.Contains(s.members, member) && !slices.Contains(change.Add, member) {
+			change.Add = append(change.Add, member)
+		}
+	}
+	for _, member := range s.members {
+		if !slices.Contains(next.members, member) {
+			change.Remove = append(change.Remove, member)
+		}
+	}
+	return change
+}
+
+// apply aplica las operaciones, en orden, sobre el estado del grupo.
+func (s *groupState) apply(operations []PatchOperation) *Error {
+	if len(operations) == 0 {
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "Operations must not be empty")
+	}
+
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "replace" && op != "remove" {
+			return newError(http.StatusBadRequest, scimTypeInvalidSyntax, "unknown operation "+strconv.Quote(operation.Op))
+		}
+
+		// 1. Sin ruta: el valor es un objeto con los atributos a asignar.
+		if operation.Path == "" {
+			if op == "remove" {
+				return newError(http.StatusBadRequest, scimTypeNoTarget, "remove requires a path")
+			}
+			var attributes map[string]json.RawMessage
+			if err := json.Unmarshal(operation.Value, &attributes); err != nil {
+				return newError(http.StatusBadRequest, scimTypeInvalidValue, "value must be an object when no path is given")
+			}
+			for name, value := range attributes {
+				path, err := parsePatchPath(name)
+				if err != nil {
+					return err
+				}
+				if err := s.set(op, path, value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// 2. Con ruta: se modifica el atributo indicado.
+		path, err := parsePatchPath(operation.Path)
+		if err != nil {
+			return err
+		}
+		if op == "remove" {
+			err = s.remove(path, operation.Value)
+		} else {
+			err = s.set(op, path, operation.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// set aplica add o replace al atributo indicado: add agrega miembros y
+// replace reemplaza la lista completa.
+func (s *groupState) set(op string, path patchPath, raw json.RawMessage) *Error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "value is required")
+	}
+
+	switch {
+	case path.String() == "displayname":
+		return decodeValue(raw, &s.name)
+	case path.String() == "id", path.String() == "externalid":
+		// Algunos proveedores reenvían atributos inmutables o no admitidos: se ignoran.
+		return nil
+	case path.String() == "members" && path.filter == nil:
+		var members []GroupMember
+		if err := decodeValue(raw, &members); err != nil {
+			return err
+		}
+		refs, err := memberRefs(members)
+		if err != nil {
+			return err
+		}
+		if op == "replace" {
+			s.members = nil
+		}
+		for _, ref := range refs {
+			if !slices.Contains(s.members, ref) {
+				s.members = append(s.members, ref)
+			}
+		}
+		return nil
+	default:
+		return newError(http.StatusBadRequest, scimTypeInvalidPath, "attribute "+path.String()+" is not supported")
+	}
+}
+
+// remove quita miembros: los seleccionados por el filtro de la ruta
+// (members[value eq "..."]), los listados en el valor o, sin ninguno de
+// los dos, todos. displayName no puede eliminarse.
+func (s *groupState) remove(path patchPath, raw json.RawMessage) *Error {
+	switch path.String() {
+	case "members":
+	case "displayname":
+		return newError(http.StatusBadRequest, scimTypeMutability, "attribute displayName cannot be removed")
+	default:
+		return newError(http.StatusBadRequest, scimTypeInvalidPath, "attribute "+path.String()+" is not supported")
+	}
+
+	// 1. Miembros seleccionados por el filtro
+	if path.filter != nil {
+		s.members = slices.DeleteFunc(s.members, func(member domain.MemberRef) bool {
+			return path.filter.Matches(attributesOf(newGroupMember(domain.GroupMember{Type: member.Type, ID: member.ID})))
+		})
+		return nil
+	}
+
+	// 2. Miembros listados en el valor, o todos
+	if len(raw) == 0 || string(raw) == "null" {
+		s.members = nil
+		return nil
+	}
+	var members []GroupMember
+	if err := decodeValue(raw, &members); err != nil {
+		return err
+	}
+	refs, err := memberRefs(members)
+	if err != nil {
+		return err
+	}
+	s.members = slices.DeleteFunc(s.members, func(member domain.MemberRef) bool {
+		// Sin tipo, el valor identifica al miembro sea cual sea su tipo.
+		for i, ref := range refs {
+			if ref.ID == member.ID && (ref == member || members[i].Type == "") {
+				return true
+			}
+		}
+		return false
+	})
+	return nil
+}
@@ -0,0 +1,239 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PatchRequest es el cuerpo de PATCH /Users/{id} (RFC 7644, sección 3.5.2).
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation es una operación add, replace o remove sobre un atributo.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// patchPath es la ruta de una operación: un atributo, opcionalmente con un
+// filtro sobre sus elementos (e.g., emails[type eq "work"].value).
+type patchPath struct {
+	attributePath
+	filter Filter
+}
+
+// parsePatchPath interpreta la ruta de una operación.
+func parsePatchPath(raw string) (patchPath, *Error) {
+	open := strings.IndexByte(raw, '[')
+	if open < 0 {
+		return patchPath{attributePath: parseAttributePath(raw)}, nil
+	}
+
+	closing := strings.LastIndexByte(raw, ']')
+	if closing < open {
+		return patchPath{}, newError(http.StatusBadRequest, scimTypeInvalidPath, "invalid path "+raw)
+	}
+
+	filter, err := ParseFilter(raw[open+1 : closing])
+	if err != nil {
+		return patchPath{}, newError(http.StatusBadRequest, scimTypeInvalidPath, "invalid path "+raw+": "+err.Error())
+	}
+
+	path := patchPath{attributePath: parseAttributePath(raw[:open]), filter: filter}
+	if rest := raw[closing+1:]; rest != "" {
+		subAttribute, ok := strings.CutPrefix(rest, ".")
+		if !ok || path.subAttribute != "" {
+			return patchPath{}, newError(http.StatusBadRequest, scimTypeInvalidPath, "invalid path "+raw)
+		}
+		path.subAttribute = strings.ToLower(subAttribute)
+	}
+	return path, nil
+}
+
+// applyPatch aplica las operaciones, en orden, sobre el estado del usuario.
+// Si alguna falla, el estado no debe persistirse.
+func applyPatch(state *userState, operations []PatchOperation) *Error {
+	if len(operations) == 0 {
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "Operations must not be empty")
+	}
+
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "replace" && op != "remove" {
+			return newError(http.StatusBadRequest, scimTypeInvalidSyntax, "unknown operation "+strconv.Quote(operation.Op))
+		}
+
+		// 1. Sin ruta: el valor es un objeto con los atributos a asignar.
+		if operation.Path == "" {
+			if op == "remove" {
+				return newError(http.StatusBadRequest, scimTypeNoTarget, "remove requires a path")
+			}
+			var attributes map[string]json.RawMessage
+			if err := json.Unmarshal(operation.Value, &attributes); err != nil {
+				return newError(http.StatusBadRequest, scimTypeInvalidValue, "value must be an object when no path is given")
+			}
+			for name, value := range attributes {
+				path, err := parsePatchPath(name)
+				if err != nil {
+					return err
+				}
+				if err := state.set(path, value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// 2. Con ruta: se modifica el atributo indicado.
+		path, err := parsePatchPath(operation.Path)
+		if err != nil {
+			return err
+		}
+		if err := state.matchTarget(path); err != nil {
+			return err
+		}
+
+		if op == "remove" {
+			err = state.remove(path)
+		} else {
+			err = state.set(path, operation.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchTarget verifica que el filtro de la ruta, si lo hay, seleccione el
+// email del usuario (el único elemento de emails).
+func (s *userState) matchTarget(path patchPath) *Error {
+	if path.filter == nil {
+		return nil
+	}
+	if path.attribute != "emails" {
+		return newError(http.StatusBadRequest, scimTypeInvalidPath, "filters are only supported on emails")
+	}
+	email := map[string]any{"value": s.Email, "type": "work", "primary": true}
+	if !path.filter.Matches(email) {
+		return newError(http.StatusBadRequest, scimTypeNoTarget, "no email matches the path filter")
+	}
+	return nil
+}
+
+// set asigna (add o replace) el valor al atributo indicado.
+func (s *userState) set(path patchPath, raw json.RawMessage) *Error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "value is required")
+	}
+
+	given, family, _ := strings.Cut(s.Name, " ")
+
+	switch path.attribute + "." + path.subAttribute {
+	case "username.":
+		return decodeValue(raw, &s.Username)
+	case "displayname.", "name.formatted":
+		return decodeValue(raw, &s.Name)
+	case "name.givenname":
+		if err := decodeValue(raw, &given); err != nil {
+			return err
+		}
+		s.Name = joinName(given, family)
+	case "name.familyname":
+		if err := decodeValue(raw, &family); err != nil {
+			return err
+		}
+		s.Name = joinName(given, family)
+	case "name.":
+		var name Name
+		if err := decodeValue(raw, &name); err != nil {
+			return err
+		}
+		if name.Formatted != "" {
+			s.Name = name.Formatted
+		} else if name.GivenName != "" {
+			s.Name = joinName(name.GivenName, name.FamilyName)
+		}
+	case "externalid.":
+		return decodeValue(raw, &s.ExternalID)
+	case "active.":
+		return decodeBool(raw, &s.Active)
+	case "emails.":
+		// Con filtro el valor es el elemento seleccionado; sin filtro, la lista.
+		if path.filter != nil {
+			var email Email
+			if err := decodeValue(raw, &email); err != nil {
+				return err
+			}
+			s.Email = email.Value
+			return nil
+		}
+		var emails []Email
+		if err := decodeValue(raw, &emails); err != nil {
+			return err
+		}
+		if email := primaryEmail(emails); email != "" {
+			s.Email = email
+		}
+	case "emails.value":
+		return decodeValue(raw, &s.Email)
+	case "emails.type", "emails.primary", "emails.display":
+		// El único email es siempre el primario, de tipo work.
+	default:
+		return newError(http.StatusBadRequest, scimTypeInvalidPath, "attribute "+path.String()+" is not supported")
+	}
+	return nil
+}
+
+// remove elimina el valor del atributo indicado. Solo familyName es
+// opcional; externalId no puede eliminarse, solo reemplazarse.
+func (s *userState) remove(path patchPath) *Error {
+	switch path.attribute + "." + path.subAttribute {
+	case "name.familyname":
+		given, _, _ := strings.Cut(s.Name, " ")
+		s.Name = given
+		return nil
+	case "username.", "displayname.", "name.", "name.formatted", "name.givenname", "emails.", "emails.value",
+		"externalid.", "active.":
+		return newError(http.StatusBadRequest, scimTypeMutability, "attribute "+path.String()+" cannot be removed")
+	default:
+		return newError(http.StatusBadRequest, scimTypeInvalidPath, "attribute "+path.String()+" is not supported")
+	}
+}
+
+// String retorna la ruta en notación SCIM (e.g., name.givenname).
+func (p patchPath) String() string {
+	if p.subAttribute == "" {
+		return p.attribute
+	}
+	return p.attribute + "." + p.subAttribute
+}
+
+// decodeValue deserializa el valor de una operación en target.
+func decodeValue(raw json.RawMessage, target any) *Error {
+	if err := json.Unmarshal(raw, target); err != nil {
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "invalid value "+string(raw)+": "+err.Error())
+	}
+	return nil
+}
+
+// decodeBool deserializa un booleano, aceptando también "True"/"False" como
+// texto (algunos proveedores de identidad envían así el atributo active).
+func decodeBool(raw json.RawMessage, target *bool) *Error {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		value, err := strconv.ParseBool(strings.ToLower(text))
+		if err != nil {
+			return newError(http.StatusBadRequest, scimTypeInvalidValue, "invalid boolean "+string(raw))
+		}
+		*target = value
+		return nil
+	}
+	return decodeValue(raw, target)
+}
@@ -0,0 +1,182 @@
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// UserResource es la representación SCIM de un usuario (esquema core User).
+// El nombre de dominio se expone como name.formatted y displayName, y se
+// separa en givenName y familyName por el primer espacio. active refleja el
+// estado de la cuenta: solo las cuentas que pueden autenticarse son activas.
+type UserResource struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []Email  `json:"emails,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// Name es el atributo complejo name de un usuario.
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email es un elemento del atributo multivaluado emails. El usuario tiene un
+// único email, que se expone como primario y de tipo work.
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Meta son los metadatos de un recurso.
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+// ListResponse es la respuesta paginada de una consulta.
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// newListResponse crea una ListResponse con todos los recursos en una página.
+func newListResponse(resources ...any) ListResponse {
+	return ListResponse{
+		Schemas:      []string{ListResponseSchema},
+		TotalResults: len(resources),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// newUserResource convierte un usuario de dominio a su representación SCIM.
+func newUserResource(user *domain.User) UserResource {
+	given, family, _ := strings.Cut(user.Name, " ")
+	active := user.Status.CanAuthenticate()
+
+	return UserResource{
+		Schemas:     []string{UserSchema},
+		ID:          user.ID,
+		ExternalID:  user.ExternalID,
+		UserName:    user.Username,
+		Name:        &Name{Formatted: user.Name, GivenName: given, FamilyName: family},
+		DisplayName: user.Name,
+		Emails:      []Email{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     BasePath + "/Users/" + user.ID,
+		},
+	}
+}
+
+// attributes retorna el recurso como un objeto JSON genérico, sobre el que
+// se evalúan los filtros.
+func (u UserResource) attributes() map[string]any {
+	raw, _ := json.Marshal(u)
+	var attributes map[string]any
+	_ = json.Unmarshal(raw, &attributes)
+	return attributes
+}
+
+// userState son los atributos de un usuario que el proveedor de identidad
+// puede modificar. Es el estado sobre el que se aplican PUT y PATCH antes de
+// persistir los cambios.
+type userState struct {
+	Name       string `validate:"required"`
+	Username   string `validate:"required,excludesall= "`
+	Email      string `validate:"required,email"`
+	ExternalID string
+	Active     bool
+}
+
+// attributeNames asocia los campos de userState con el atributo SCIM
+// correspondiente, para los mensajes de validación.
+var attributeNames = map[string]string{
+	"Name":     "name.formatted",
+	"Username": "userName",
+	"Email":    "emails",
+}
+
+// stateOf retorna el estado modificable de un usuario de dominio.
+func stateOf(user *domain.User) userState {
+	return userState{
+		Name:       user.Name,
+		Username:   user.Username,
+		Email:      user.Email,
+		ExternalID: user.ExternalID,
+		Active:     user.Status.CanAuthenticate(),
+	}
+}
+
+// stateFrom construye el estado descrito por un recurso recibido. Los
+// atributos de nombre se resuelven, en orden, de name.formatted, de
+// name.givenName y name.familyName, de displayName y, por último, de
+// userName. El email es el primario o, si ninguno lo es, el primero.
+// active ausente conserva el valor indicado en activeByDefault.
+func stateFrom(resource *UserResource, activeByDefault bool) userState {
+	state := userState{
+		Username:   resource.UserName,
+		ExternalID: resource.ExternalID,
+		Active:     activeByDefault,
+	}
+
+	switch {
+	case resource.Name != nil && resource.Name.Formatted != "":
+		state.Name = resource.Name.Formatted
+	case resource.Name != nil && resource.Name.GivenName != "":
+		state.Name = joinName(resource.Name.GivenName, resource.Name.FamilyName)
+	case resource.DisplayName != "":
+		state.Name = resource.DisplayName
+	default:
+		state.Name = resource.UserName
+	}
+
+	state.Email = primaryEmail(resource.Emails)
+
+	if resource.Active != nil {
+		state.Active = *resource.Active
+	}
+
+	return state
+}
+
+// primaryEmail retorna el email primario o, si ninguno lo es, el primero.
+func primaryEmail(emails []Email) string {
+	for _, email := range emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(emails) > 0 {
+		return emails[0].Value
+	}
+	return ""
+}
+
+// joinName compone el nombre a partir del nombre de pila y el apellido.
+func joinName(given, family string) string {
+	if family == "" {
+		return given
+	}
+	return given + " " + family
+}
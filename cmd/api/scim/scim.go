@@ -0,0 +1,202 @@
+// Package scim expone application.UserService como service provider SCIM 2.0
+// (RFC 7643 y RFC 7644) para el aprovisionamiento de cuentas desde un
+// proveedor de identidad, autenticado con un bearer token.
+package scim
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// URNs de los esquemas y mensajes SCIM utilizados.
+const (
+	UserSchema                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	GroupSchema                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ServiceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	ResourceTypeSchema          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SchemaSchema                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+	ListResponseSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	PatchOpSchema               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ErrorSchema                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// MediaType es el media type de las respuestas SCIM.
+const MediaType = "application/scim+json"
+
+// BasePath es la ruta bajo la que se montan los endpoints SCIM.
+const BasePath = "/scim/v2"
+
+// newPrincipal retorna la identidad con la que se atribuyen los cambios
+// realizados por el proveedor de identidad, restringida a su organización.
+func newPrincipal(tenantID string) domain.Principal {
+	return domain.Principal{Subject: "scim", Roles: []string{domain.RoleAdmin}, TenantID: tenantID}
+}
+
+// NewHandler construye la tabla de rutas SCIM, relativa a BasePath. Todas
+// las rutas requieren el bearer token indicado, que pertenece a la
+// organización tenantID (vacío = la organización por defecto): las
+// peticiones operan sobre ella y se rechazan si la cabecera X-Tenant-ID o el
+// subdominio (ver application.TenantService) indican otra. tenants nil =
+// no se consulta la organización pedida y se opera siempre sobre tenantID.
+func NewHandler(users application.UserService, groups application.GroupService, tenants *application.TenantService, bearerToken, tenantID string) http.Handler {
+	if tenantID == "" {
+		tenantID = domain.DefaultTenantID
+	}
+	principal := newPrincipal(tenantID)
+
+	router := chi.NewRouter()
+	router.Use(authAndLoggingMiddleware(bearerToken, principal))
+	if tenants != nil {
+		router.Use(tenantMiddleware(tenants, principal))
+	} else {
+		router.Use(fixedTenantMiddleware(tenantID))
+	}
+
+	userHandler := NewUserHandler(users)
+	groupHandler := NewGroupHandler(groups)
+
+	// GET /ServiceProviderConfig - Supported features and authentication schemes
+	router.Get("/ServiceProviderConfig", errorHandlerWrapper(serviceProviderConfig))
+
+	// GET /Schemas, /Schemas/{id} - Schema definitions (core User and Group)
+	router.Get("/Schemas", errorHandlerWrapper(schemas))
+	router.Get("/Schemas/{id}", errorHandlerWrapper(schemaByID))
+
+	// GET /ResourceTypes, /ResourceTypes/{id} - Resource types (User, Group)
+	router.Get("/ResourceTypes", errorHandlerWrapper(resourceTypes))
+	router.Get("/ResourceTypes/{id}", errorHandlerWrapper(resourceTypeByID))
+
+	router.Route("/Users", func(r chi.Router) {
+		// GET /Users?filter=&startIndex=&count= - Filtered, paginated listing
+		r.Get("/", errorHandlerWrapper(userHandler.List))
+
+		// POST /Users - Provision a user
+		r.Post("/", errorHandlerWrapper(userHandler.Create))
+
+		// GET /Users/{id} - Retrieve a user (including deactivated ones)
+		r.Get("/{id}", errorHandlerWrapper(userHandler.Get))
+
+		// PUT /Users/{id} - Replace a user's attributes
+		r.Put("/{id}", errorHandlerWrapper(userHandler.Replace))
+
+		// PATCH /Users/{id} - Apply add/replace/remove operations
+		r.Patch("/{id}", errorHandlerWrapper(userHandler.Patch))
+
+		// DELETE /Users/{id} - Deprovision (deactivate) a user
+		r.Delete("/{id}", errorHandlerWrapper(userHandler.Delete))
+	})
+
+	router.Route("/Groups", func(r chi.Router) {
+		// GET /Groups?filter=&startIndex=&count=&excludedAttributes= - Filtered, paginated listing
+		r.Get("/", errorHandlerWrapper(groupHandler.List))
+
+		// POST /Groups - Provision a group with its members
+		r.Post("/", errorHandlerWrapper(groupHandler.Create))
+
+		// GET /Groups/{id} - Retrieve a group with its direct members
+		r.Get("/{id}", errorHandlerWrapper(groupHandler.Get))
+
+		// PUT /Groups/{id} - Replace a group's name and members
+		r.Put("/{id}", errorHandlerWrapper(groupHandler.Replace))
+
+		// PATCH /Groups/{id} - Apply add/replace/remove operations (members in one transaction)
+		r.Patch("/{id}", errorHandlerWrapper(groupHandler.Patch))
+
+		// DELETE /Groups/{id} - Delete a group (its members are kept)
+		r.Delete("/{id}", errorHandlerWrapper(groupHandler.Delete))
+	})
+
+	router.NotFound(errorHandlerWrapper(func(w http.ResponseWriter, r *http.Request) *Error {
+		return newError(http.StatusNotFound, "", "resource "+r.URL.Path+" not found")
+	}))
+	router.MethodNotAllowed(errorHandlerWrapper(func(w http.ResponseWriter, r *http.Request) *Error {
+		return newError(http.StatusMethodNotAllowed, "", "method "+r.Method+" not allowed")
+	}))
+
+	return router
+}
+
+// authAndLoggingMiddleware verifica el bearer token (en tiempo constante),
+// propaga el principal del proveedor de identidad y registra cada petición
+// con su código de estado.
+func authAndLoggingMiddleware(bearerToken string, principal domain.Principal) func(http.Handler) http.Handler {
+	expected := sha256.Sum256([]byte(bearerToken))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			received := sha256.Sum256([]byte(token))
+			if !ok || subtle.ConstantTimeCompare(received[:], expected[:]) != 1 {
+				recorder.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+				writeError(recorder, newError(http.StatusUnauthorized, "", "invalid or missing bearer token"))
+			} else {
+				next.ServeHTTP(recorder, r.WithContext(domain.WithPrincipal(r.Context(), principal)))
+			}
+
+			log.Printf("[SCIM] %s %s | Status: %d | Duration: %v", r.Method, r.URL.Path, recorder.status, time.Since(start))
+		})
+	}
+}
+
+// tenantMiddleware resuelve la organización de la petición: la del
+// principal, que la cabecera X-Tenant-ID o, en su defecto, el subdominio del
+// host no pueden contradecir (403).
+func tenantMiddleware(tenants *application.TenantService, principal domain.Principal) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested := r.Header.Get("X-Tenant-ID")
+			if requested == "" {
+				requested = tenants.TenantFromHost(r.Host)
+			}
+
+			tenantID, err := tenants.Resolve(r.Context(), principal, requested)
+			if errors.Is(err, domain.ErrTenantNotFound) {
+				writeError(w, newError(http.StatusNotFound, "", err.Error()))
+				return
+			}
+			if errors.Is(err, domain.ErrTenantForbidden) {
+				writeError(w, newError(http.StatusForbidden, "", err.Error()))
+				return
+			}
+			if err != nil {
+				writeError(w, newError(http.StatusInternalServerError, "", err.Error()))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(domain.WithTenant(r.Context(), tenantID)))
+		})
+	}
+}
+
+// fixedTenantMiddleware asigna a todas las peticiones la organización indicada.
+func fixedTenantMiddleware(tenantID string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(domain.WithTenant(r.Context(), tenantID)))
+		})
+	}
+}
+
+// statusRecorder conserva el código de estado escrito, para el log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader registra el código de estado antes de escribirlo.
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,106 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+// stubTenants es un registro fijo de organizaciones. Solo implementa la
+// búsqueda que usa application.TenantService.Resolve.
+type stubTenants struct {
+	domain.TenantRepository
+	ids map[string]bool
+}
+
+func (s stubTenants) FindById(id string) (*domain.Tenant, error) {
+	if !s.ids[id] {
+		return nil, domain.ErrTenantNotFound
+	}
+	return &domain.Tenant{ID: id}, nil
+}
+
+// contextRecorder registra la organización y el principal con los que se
+// invoca el servicio.
+type contextRecorder struct {
+	application.UserService
+	tenantID  string
+	principal domain.Principal
+}
+
+func (s *contextRecorder) Export(ctx context.Context, _ domain.UserFilter, _ func(user *domain.User) error) error {
+	s.tenantID = domain.TenantFromContext(ctx)
+	s.principal, _ = domain.PrincipalFromContext(ctx)
+	return nil
+}
+
+func TestHandlerOperatesOnTheTenantOfTheToken(t *testing.T) {
+	tenants := application.NewTenantService(stubTenants{ids: map[string]bool{"default": true, "acme": true, "globex": true}}, "users.example.com")
+
+	tests := []struct {
+		name        string
+		tenantID    string
+		header      string
+		host        string
+		wantStatus  int
+		wantTenant  string
+		withTenants bool
+	}{
+		{name: "no tenant requested", tenantID: "acme", withTenants: true, wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "same tenant in the header", tenantID: "acme", header: "acme", withTenants: true, wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "same tenant in the subdomain", tenantID: "acme", host: "acme.users.example.com", withTenants: true, wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "other tenant in the header", tenantID: "acme", header: "globex", withTenants: true, wantStatus: http.StatusForbidden},
+		{name: "other tenant in the subdomain", tenantID: "acme", host: "globex.users.example.com", withTenants: true, wantStatus: http.StatusForbidden},
+		{name: "unknown tenant", tenantID: "initech", withTenants: true, wantStatus: http.StatusNotFound},
+		{name: "default tenant", tenantID: "", header: "default", withTenants: true, wantStatus: http.StatusOK, wantTenant: "default"},
+		{name: "default tenant rejects others", tenantID: "", header: "acme", withTenants: true, wantStatus: http.StatusForbidden},
+		{name: "without tenant service", tenantID: "acme", header: "globex", wantStatus: http.StatusOK, wantTenant: "acme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &contextRecorder{}
+			var service *application.TenantService
+			if tt.withTenants {
+				service = tenants
+			}
+			handler := NewHandler(users, nil, service, testToken, tt.tenantID)
+
+			r := httptest.NewRequest(http.MethodGet, "/Users", nil)
+			r.Header.Set("Authorization", "Bearer "+testToken)
+			if tt.header != "" {
+				r.Header.Set("X-Tenant-ID", tt.header)
+			}
+			if tt.host != "" {
+				r.Host = tt.host
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d %s, want %d", w.Code, w.Body, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				var body ErrorResponse
+				decodeResponse(t, w, &body)
+				if body.Status != strconv.Itoa(tt.wantStatus) || len(body.Schemas) != 1 || body.Schemas[0] != ErrorSchema {
+					t.Errorf("body = %+v, want a SCIM error", body)
+				}
+				if users.tenantID != "" {
+					t.Errorf("the service was called for tenant %q", users.tenantID)
+				}
+				return
+			}
+			if users.tenantID != tt.wantTenant {
+				t.Errorf("tenant = %q, want %q", users.tenantID, tt.wantTenant)
+			}
+			if users.principal.Subject != "scim" || users.principal.TenantID != tt.wantTenant || users.principal.IsOperator() {
+				t.Errorf("principal = %+v, want the scim principal restricted to %s", users.principal, tt.wantTenant)
+			}
+		})
+	}
+}
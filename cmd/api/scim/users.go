@@ -0,0 +1,270 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// defaultCount y maxCount acotan el tamaño de página de GET /Users.
+	defaultCount = 100
+	maxCount     = 500
+
+	// deactivationReason es el motivo registrado al desactivar un usuario con
+	// active=false.
+	deactivationReason = "deactivated by the identity provider (SCIM active=false)"
+)
+
+// UserHandler implementa el recurso /Users sobre application.UserService.
+type UserHandler struct {
+	userService application.UserService
+	validator   *validator.Validate
+}
+
+// NewUserHandler crea una nueva instancia de UserHandler.
+func NewUserHandler(service application.UserService) *UserHandler {
+	return &UserHandler{
+		userService: service,
+		validator:   validator.New(),
+	}
+}
+
+// List maneja GET /Users. El filtro se evalúa sobre la representación SCIM
+// de cada usuario (incluidos los inactivos), recorriéndolos en orden de ID;
+// startIndex (desde 1) y count seleccionan la página.
+func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) *Error {
+	// 1. Parámetros de filtro y paginación
+	query := r.URL.Query()
+
+	var filter Filter
+	if raw := query.Get("filter"); raw != "" {
+		var err error
+		if filter, err = ParseFilter(raw); err != nil {
+			return newError(http.StatusBadRequest, scimTypeInvalidFilter, "invalid filter: "+err.Error())
+		}
+	}
+
+	startIndex, err := intParam(query.Get("startIndex"), 1)
+	if err != nil {
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "startIndex must be an integer")
+	}
+	count, err := intParam(query.Get("count"), defaultCount)
+	if err != nil {
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "count must be an integer")
+	}
+	startIndex, count = max(startIndex, 1), min(max(count, 0), maxCount)
+
+	// 2. Recorrido de los usuarios
+	response := ListResponse{Schemas: []string{ListResponseSchema}, StartIndex: startIndex, Resources: []any{}}
+	err = h.userService.Export(r.Context(), domain.UserFilter{}, func(user *domain.User) error {
+		resource := newUserResource(user)
+		if filter != nil && !filter.Matches(resource.attributes()) {
+			return nil
+		}
+
+		response.TotalResults++
+		if response.TotalResults >= startIndex && len(response.Resources) < count {
+			response.Resources = append(response.Resources, resource)
+		}
+		return nil
+	})
+	if err != nil {
+		return errorFrom(err)
+	}
+
+	response.ItemsPerPage = len(response.Resources)
+	render(w, http.StatusOK, response)
+	return nil
+}
+
+// Create maneja POST /Users. Un usuario creado con active=false queda
+// pendiente de activación (status pending).
+func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) *Error {
+	// 1. Deserialización y validación
+	var resource UserResource
+	if err := decodeBody(r, &resource); err != nil {
+		return err
+	}
+	if err := requireSchema(resource.Schemas, UserSchema); err != nil {
+		return err
+	}
+
+	state := stateFrom(&resource, true)
+	if err := h.validator.Struct(state); err != nil {
+		return errorFrom(err)
+	}
+
+	// 2. Creación
+	request := &domain.UserCreateRequest{
+		Name:       state.Name,
+		Username:   state.Username,
+		Email:      state.Email,
+		ExternalID: state.ExternalID,
+	}
+	if !state.Active {
+		request.Status = domain.UserPending
+	}
+	user, err := h.userService.Create(r.Context(), request)
+	if err != nil {
+		return errorFrom(err)
+	}
+
+	return h.respond(w, r.Context(), http.StatusCreated, user.ID)
+}
+
+// Get maneja GET /Users/{id}.
+func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) *Error {
+	return h.respond(w, r.Context(), http.StatusOK, chi.URLParam(r, "id"))
+}
+
+// Replace maneja PUT /Users/{id}: reemplaza los atributos modificables del
+// usuario. Si active no se indica, se conserva.
+func (h *UserHandler) Replace(w http.ResponseWriter, r *http.Request) *Error {
+	// 1. Lectura del estado actual
+	user, err := h.find(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return errorFrom(err)
+	}
+
+	// 2. Deserialización del reemplazo
+	var resource UserResource
+	if err := decodeBody(r, &resource); err != nil {
+		return err
+	}
+	if err := requireSchema(resource.Schemas, UserSchema); err != nil {
+		return err
+	}
+
+	// 3. Persistencia
+	if err := h.save(r.Context(), user, stateFrom(&resource, user.Status.CanAuthenticate())); err != nil {
+		return err
+	}
+
+	return h.respond(w, r.Context(), http.StatusOK, user.ID)
+}
+
+// Patch maneja PATCH /Users/{id}: aplica las operaciones sobre el estado
+// actual y persiste el resultado solo si todas son válidas.
+func (h *UserHandler) Patch(w http.ResponseWriter, r *http.Request) *Error {
+	// 1. Lectura del estado actual
+	user, err := h.find(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return errorFrom(err)
+	}
+
+	// 2. Deserialización y aplicación de las operaciones
+	var request PatchRequest
+	if err := decodeBody(r, &request); err != nil {
+		return err
+	}
+	if err := requireSchema(request.Schemas, PatchOpSchema); err != nil {
+		return err
+	}
+
+	state := stateOf(user)
+	if err := applyPatch(&state, request.Operations); err != nil {
+		return err
+	}
+
+	// 3. Persistencia
+	if err := h.save(r.Context(), user, state); err != nil {
+		return err
+	}
+
+	return h.respond(w, r.Context(), http.StatusOK, user.ID)
+}
+
+// Delete maneja DELETE /Users/{id}. El usuario se elimina lógicamente y deja
+// de estar visible en SCIM; para desactivarlo sin eliminarlo se usa active=false.
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) *Error {
+	if err := h.userService.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		return errorFrom(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// save persiste el nuevo estado de un usuario: actualiza los atributos y,
+// si active cambia, aplica la acción del ciclo de vida correspondiente
+// (deactivate o, según el estado actual, activate o unlock).
+func (h *UserHandler) save(ctx context.Context, user *domain.User, state userState) *Error {
+	// 1. Validación del nuevo estado
+	if err := h.validator.Struct(state); err != nil {
+		return errorFrom(err)
+	}
+	if state.ExternalID == "" && user.ExternalID != "" {
+		return newError(http.StatusBadRequest, scimTypeMutability, "externalId cannot be removed")
+	}
+
+	current := stateOf(user)
+	active := current.Active
+	current.Active = state.Active
+
+	// 2. Actualización de los atributos
+	if current != state {
+		updated := *user
+		updated.Name, updated.Username, updated.Email, updated.ExternalID = state.Name, state.Username, state.Email, state.ExternalID
+		if _, err := h.userService.Update(ctx, &updated); err != nil {
+			return errorFrom(err)
+		}
+	}
+
+	// 3. Cambio de estado de la cuenta
+	switch {
+	case active && !state.Active:
+		if _, err := h.userService.ChangeStatus(ctx, user.ID, domain.StatusDeactivate, deactivationReason); err != nil {
+			return errorFrom(err)
+		}
+	case !active && state.Active:
+		action := domain.StatusActivate
+		if user.Status == domain.UserLocked {
+			action = domain.StatusUnlock
+		}
+		if _, err := h.userService.ChangeStatus(ctx, user.ID, action, ""); err != nil {
+			return errorFrom(err)
+		}
+	}
+
+	return nil
+}
+
+// find busca un usuario por ID, incluidos los inactivos; los eliminados
+// lógicamente no existen para SCIM.
+func (h *UserHandler) find(ctx context.Context, id string) (*domain.User, error) {
+	users, err := h.userService.FindAll(ctx, domain.UserFilter{IDs: []string{id}})
+	if err != nil {
+		return nil, err
+	}
+	if len(*users) == 0 {
+		return nil, domain.ErrUserNotFound
+	}
+	return &(*users)[0], nil
+}
+
+// respond relee el usuario y lo escribe como recurso SCIM con su Location.
+func (h *UserHandler) respond(w http.ResponseWriter, ctx context.Context, status int, id string) *Error {
+	user, err := h.find(ctx, id)
+	if err != nil {
+		return errorFrom(err)
+	}
+
+	resource := newUserResource(user)
+	w.Header().Set("Location", resource.Meta.Location)
+	render(w, status, resource)
+	return nil
+}
+
+// intParam interpreta un parámetro entero opcional.
+func intParam(raw string, fallback int) (int, error) {
+	if raw == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(raw)
+}
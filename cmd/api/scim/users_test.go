@@ -0,0 +1,501 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/domain"
+)
+
+// testToken es el bearer token con el que se construye el handler de las pruebas.
+const testToken = "scim-secret"
+
+// memoryUsers es un UserService en memoria con las operaciones que usa el
+// recurso /Users. Los usuarios eliminados conservan DeletedAt y actions
+// registra las acciones del ciclo de vida aplicadas.
+type memoryUsers struct {
+	application.UserService
+	users   map[string]domain.User
+	nextID  int
+	actions []domain.UserStatusAction
+}
+
+func newMemoryUsers(users ...domain.User) *memoryUsers {
+	service := &memoryUsers{users: make(map[string]domain.User)}
+	for _, user := range users {
+		if user.Status == "" {
+			user.Status = domain.UserActive
+		}
+		service.users[user.ID] = user
+	}
+	return service
+}
+
+func (s *memoryUsers) Create(_ context.Context, request *domain.UserCreateRequest) (*domain.User, error) {
+	for _, user := range s.users {
+		if user.Username == request.Username {
+			return nil, domain.ErrUsernameInUse
+		}
+	}
+	s.nextID++
+	user := domain.User{
+		ID: "new-" + strconv.Itoa(s.nextID), Name: request.Name, Username: request.Username, Email: request.Email,
+		ExternalID: request.ExternalID, Status: domain.UserActive,
+	}
+	if request.Status != "" {
+		user.Status = request.Status
+	}
+	s.users[user.ID] = user
+	return &user, nil
+}
+
+func (s *memoryUsers) FindAll(_ context.Context, filter domain.UserFilter) (*[]domain.User, error) {
+	users := make([]domain.User, 0)
+	err := s.Export(context.Background(), filter, func(user *domain.User) error {
+		if filter.IDs == nil || containsID(filter.IDs, user.ID) {
+			users = append(users, *user)
+		}
+		return nil
+	})
+	return &users, err
+}
+
+func (s *memoryUsers) Export(_ context.Context, filter domain.UserFilter, fn func(user *domain.User) error) error {
+	ids := make([]string, 0, len(s.users))
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		user := s.users[id]
+		if user.DeletedAt != nil && !filter.IncludeDeleted {
+			continue
+		}
+		if err := fn(&user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryUsers) Update(_ context.Context, user *domain.User) (*domain.User, error) {
+	current, ok := s.users[user.ID]
+	if !ok || current.DeletedAt != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	s.users[user.ID] = *user
+	return user, nil
+}
+
+func (s *memoryUsers) Delete(_ context.Context, id string) error {
+	user, ok := s.users[id]
+	if !ok || user.DeletedAt != nil {
+		return domain.ErrUserNotFound
+	}
+	now := time.Now()
+	user.DeletedAt = &now
+	s.users[id] = user
+	return nil
+}
+
+func (s *memoryUsers) Restore(_ context.Context, id string) (*domain.User, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.DeletedAt == nil {
+		return nil, domain.ErrUserNotDeleted
+	}
+	user.DeletedAt = nil
+	s.users[id] = user
+	return &user, nil
+}
+
+// ChangeStatus aplica las transiciones de activate, unlock y deactivate.
+func (s *memoryUsers) ChangeStatus(_ context.Context, id string, action domain.UserStatusAction, reason string) (*domain.User, error) {
+	user, ok := s.users[id]
+	if !ok || user.DeletedAt != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	allowed := map[domain.UserStatusAction][]domain.UserStatus{
+		domain.StatusActivate:   {domain.UserPending, domain.UserSuspended, domain.UserDeactivated},
+		domain.StatusUnlock:     {domain.UserLocked},
+		domain.StatusDeactivate: {domain.UserPending, domain.UserActive, domain.UserSuspended, domain.UserLocked},
+	}
+	if !slices.Contains(allowed[action], user.Status) {
+		return nil, domain.ErrInvalidStatusTransition
+	}
+	user.Status, user.StatusReason = domain.UserActive, reason
+	if action == domain.StatusDeactivate {
+		user.Status = domain.UserDeactivated
+	}
+	s.users[id] = user
+	s.actions = append(s.actions, action)
+	return &user, nil
+}
+
+// containsID indica si ids contiene id.
+func containsID(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// scimRequest ejecuta la petición autenticada sobre handler.
+func scimRequest(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer "+testToken)
+	r.Header.Set("Content-Type", MediaType)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+// decodeResponse deserializa el cuerpo de la respuesta en target.
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder, target any) {
+	t.Helper()
+	if err := json.Unmarshal(w.Body.Bytes(), target); err != nil {
+		t.Fatalf("decode %s: %v", w.Body, err)
+	}
+}
+
+// patchBody construye un PatchOp con las operaciones indicadas (JSON).
+func patchBody(operations ...string) string {
+	return `{"schemas":["` + PatchOpSchema + `"],"Operations":[` + strings.Join(operations, ",") + `]}`
+}
+
+func TestHandlerRequiresTheBearerToken(t *testing.T) {
+	handler := NewHandler(newMemoryUsers(), nil, nil, testToken, "")
+
+	for _, authorization := range []string{"", "Bearer wrong", "Basic " + testToken} {
+		r := httptest.NewRequest(http.MethodGet, "/Users", nil)
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%q: status = %d, WWW-Authenticate = %q", authorization, w.Code, w.Header().Get("WWW-Authenticate"))
+		}
+		var body ErrorResponse
+		decodeResponse(t, w, &body)
+		if body.Status != "401" || len(body.Schemas) != 1 || body.Schemas[0] != ErrorSchema {
+			t.Errorf("%q: body = %+v, want a SCIM error", authorization, body)
+		}
+	}
+}
+
+func TestListFiltersAndPaginates(t *testing.T) {
+	deletedAt := time.Now()
+	users := newMemoryUsers(
+		domain.User{ID: "u1", Name: "Ann Lee", Username: "ann", Email: "ann@example.com"},
+		domain.User{ID: "u2", Name: "Bob Lee", Username: "bob", Email: "bob@example.com"},
+		domain.User{ID: "u3", Name: "Cid Lee", Username: "cid", Email: "cid@example.com", DeletedAt: &deletedAt},
+		domain.User{ID: "u4", Name: "Dee Roe", Username: "dee", Email: "dee@other.org"},
+		domain.User{ID: "u5", Name: "Eve Lee", Username: "eve", Email: "eve@example.com", Status: domain.UserSuspended},
+	)
+	handler := NewHandler(users, nil, nil, testToken, "")
+
+	tests := []struct {
+		name      string
+		query     string
+		wantTotal int
+		wantIDs   []string
+	}{
+		{name: "all but deleted, including inactive", query: "", wantTotal: 4, wantIDs: []string{"u1", "u2", "u4", "u5"}},
+		{name: "userName eq", query: `filter=userName eq "bob"`, wantTotal: 1, wantIDs: []string{"u2"}},
+		{name: "inactive users", query: `filter=active eq false`, wantTotal: 1, wantIDs: []string{"u5"}},
+		{name: "page", query: `filter=name.familyName eq "Lee"&startIndex=2&count=1`, wantTotal: 3, wantIDs: []string{"u2"}},
+		{name: "count zero", query: "count=0", wantTotal: 4, wantIDs: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/Users?" + strings.NewReplacer(" ", "%20", `"`, "%22").Replace(tt.query)
+			w := scimRequest(handler, http.MethodGet, target, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d %s", w.Code, w.Body)
+			}
+
+			var response struct {
+				TotalResults int
+				ItemsPerPage int
+				Resources    []UserResource
+			}
+			decodeResponse(t, w, &response)
+			ids := make([]string, len(response.Resources))
+			for i, resource := range response.Resources {
+				ids[i] = resource.ID
+			}
+			if response.TotalResults != tt.wantTotal || strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") || response.ItemsPerPage != len(ids) {
+				t.Errorf("total = %d, ids = %v, itemsPerPage = %d; want %d, %v", response.TotalResults, ids, response.ItemsPerPage, tt.wantTotal, tt.wantIDs)
+			}
+		})
+	}
+
+	for _, query := range []string{"filter=userName%20equals%20%22x%22", "startIndex=first", "count=ten"} {
+		w := scimRequest(handler, http.MethodGet, "/Users?"+query, "")
+		var body ErrorResponse
+		decodeResponse(t, w, &body)
+		if w.Code != http.StatusBadRequest || body.ScimType == "" {
+			t.Errorf("%s: status = %d, body = %+v, want 400 with a scimType", query, w.Code, body)
+		}
+	}
+}
+
+func TestCreateProvisionsUsers(t *testing.T) {
+	users := newMemoryUsers()
+	handler := NewHandler(users, nil, nil, testToken, "")
+
+	body := `{"schemas":["` + UserSchema + `"],"userName":"bjensen","externalId":"ext-1",` +
+		`"name":{"givenName":"Barbara","familyName":"Jensen"},` +
+		`"emails":[{"value":"home@jensen.org"},{"value":"bjensen@example.com","primary":true}]}`
+	w := scimRequest(handler, http.MethodPost, "/Users", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d %s", w.Code, w.Body)
+	}
+
+	var resource UserResource
+	decodeResponse(t, w, &resource)
+	if w.Header().Get("Location") != BasePath+"/Users/"+resource.ID || w.Header().Get("Content-Type") != MediaType {
+		t.Errorf("headers = %v", w.Header())
+	}
+	user := users.users[resource.ID]
+	if user.Name != "Barbara Jensen" || user.Email != "bjensen@example.com" || user.ExternalID != "ext-1" {
+		t.Errorf("user = %+v, want the primary email and the joined name", user)
+	}
+	if resource.Active == nil || !*resource.Active {
+		t.Errorf("active = %v, want true", resource.Active)
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		scimType string
+	}{
+		{name: "duplicate userName", body: body, status: http.StatusConflict, scimType: scimTypeUniqueness},
+		{name: "missing schema", body: `{"userName":"x","emails":[{"value":"x@example.com"}]}`, status: http.StatusBadRequest, scimType: scimTypeInvalidSyntax},
+		{name: "invalid email", body: `{"schemas":["` + UserSchema + `"],"userName":"x","emails":[{"value":"x"}]}`, status: http.StatusBadRequest, scimType: scimTypeInvalidValue},
+		{name: "malformed JSON", body: `{`, status: http.StatusBadRequest, scimType: scimTypeInvalidSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := scimRequest(handler, http.MethodPost, "/Users", tt.body)
+			var response ErrorResponse
+			decodeResponse(t, w, &response)
+			if w.Code != tt.status || response.ScimType != tt.scimType || response.Status != strconv.Itoa(tt.status) {
+				t.Errorf("status = %d, body = %+v; want %d %s", w.Code, response, tt.status, tt.scimType)
+			}
+		})
+	}
+}
+
+func TestPatchAppliesOperations(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []string
+		want       domain.User
+	}{
+		{
+			name:       "replace userName",
+			operations: []string{`{"op":"replace","path":"userName","value":"barbara"}`},
+			want:       domain.User{Name: "Barbara Jensen", Username: "barbara", Email: "bjensen@example.com"},
+		},
+		{
+			name:       "replace givenName keeps familyName",
+			operations: []string{`{"op":"Replace","path":"name.givenName","value":"Babs"}`},
+			want:       domain.User{Name: "Babs Jensen", Username: "bjensen", Email: "bjensen@example.com"},
+		},
+		{
+			name:       "filtered email value",
+			operations: []string{`{"op":"replace","path":"emails[type eq \"work\"].value","value":"babs@example.com"}`},
+			want:       domain.User{Name: "Barbara Jensen", Username: "bjensen", Email: "babs@example.com"},
+		},
+		{
+			name:       "no path, several attributes",
+			operations: []string{`{"op":"add","value":{"displayName":"Babs","externalId":"ext-2"}}`},
+			want:       domain.User{Name: "Babs", Username: "bjensen", Email: "bjensen@example.com", ExternalID: "ext-2"},
+		},
+		{
+			name:       "remove familyName",
+			operations: []string{`{"op":"remove","path":"name.familyName"}`},
+			want:       domain.User{Name: "Barbara", Username: "bjensen", Email: "bjensen@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := newMemoryUsers(domain.User{ID: "u1", Name: "Barbara Jensen", Username: "bjensen", Email: "bjensen@example.com"})
+			handler := NewHandler(users, nil, nil, testToken, "")
+
+			w := scimRequest(handler, http.MethodPatch, "/Users/u1", patchBody(tt.operations...))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d %s", w.Code, w.Body)
+			}
+			got := users.users["u1"]
+			if got.Name != tt.want.Name || got.Username != tt.want.Username || got.Email != tt.want.Email || got.ExternalID != tt.want.ExternalID {
+				t.Errorf("user = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchRejectsInvalidOperationsWithoutApplyingAny(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []string
+		scimType   string
+	}{
+		{name: "unknown op", operations: []string{`{"op":"move","path":"userName","value":"x"}`}, scimType: scimTypeInvalidSyntax},
+		{name: "unsupported attribute", operations: []string{`{"op":"replace","path":"title","value":"x"}`}, scimType: scimTypeInvalidPath},
+		{name: "remove required attribute", operations: []string{`{"op":"remove","path":"userName"}`}, scimType: scimTypeMutability},
+		{name: "remove without path", operations: []string{`{"op":"remove"}`}, scimType: scimTypeNoTarget},
+		{name: "filter matches nothing", operations: []string{`{"op":"replace","path":"emails[type eq \"home\"].value","value":"x@example.com"}`}, scimType: scimTypeNoTarget},
+		{name: "invalid value", operations: []string{`{"op":"replace","path":"active","value":"maybe"}`}, scimType: scimTypeInvalidValue},
+		{name: "later operation fails", operations: []string{
+			`{"op":"replace","path":"userName","value":"changed"}`,
+			`{"op":"replace","path":"emails","value":[{"value":"not-an-email"}]}`,
+		}, scimType: scimTypeInvalidValue},
+		{name: "no operations", operations: nil, scimType: scimTypeInvalidValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := domain.User{ID: "u1", Name: "Barbara Jensen", Username: "bjensen", Email: "bjensen@example.com", Status: domain.UserActive}
+			users := newMemoryUsers(original)
+			handler := NewHandler(users, nil, nil, testToken, "")
+
+			w := scimRequest(handler, http.MethodPatch, "/Users/u1", patchBody(tt.operations...))
+
+			var response ErrorResponse
+			decodeResponse(t, w, &response)
+			if w.Code != http.StatusBadRequest || response.ScimType != tt.scimType {
+				t.Errorf("status = %d, body = %+v; want 400 %s", w.Code, response, tt.scimType)
+			}
+			if users.users["u1"] != original {
+				t.Errorf("user = %+v, want it unchanged", users.users["u1"])
+			}
+		})
+	}
+}
+
+func TestActiveFollowsAccountStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      domain.UserStatus
+		operation   string
+		wantStatus  domain.UserStatus
+		wantActions []domain.UserStatusAction
+	}{
+		{name: "active=false deactivates", status: domain.UserActive, operation: `{"op":"replace","path":"active","value":false}`,
+			wantStatus: domain.UserDeactivated, wantActions: []domain.UserStatusAction{domain.StatusDeactivate}},
+		{name: "active=true reactivates", status: domain.UserDeactivated, operation: `{"op":"replace","path":"active","value":true}`,
+			wantStatus: domain.UserActive, wantActions: []domain.UserStatusAction{domain.StatusActivate}},
+		{name: "active=true activates a pending account", status: domain.UserPending, operation: `{"op":"replace","value":{"active":true}}`,
+			wantStatus: domain.UserActive, wantActions: []domain.UserStatusAction{domain.StatusActivate}},
+		{name: "active=true unlocks", status: domain.UserLocked, operation: `{"op":"replace","path":"active","value":true}`,
+			wantStatus: domain.UserActive, wantActions: []domain.UserStatusAction{domain.StatusUnlock}},
+		{name: "unchanged active", status: domain.UserSuspended, operation: `{"op":"replace","path":"active","value":false}`,
+			wantStatus: domain.UserSuspended},
+		{name: "inactive users can be modified", status: domain.UserDeactivated, operation: `{"op":"replace","path":"userName","value":"barbara"}`,
+			wantStatus: domain.UserDeactivated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := newMemoryUsers(domain.User{ID: "u1", Name: "Barbara Jensen", Username: "bjensen", Email: "bjensen@example.com", Status: tt.status})
+			handler := NewHandler(users, nil, nil, testToken, "")
+
+			w := scimRequest(handler, http.MethodPatch, "/Users/u1", patchBody(tt.operation))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d %s", w.Code, w.Body)
+			}
+
+			var resource UserResource
+			decodeResponse(t, w, &resource)
+			user := users.users["u1"]
+			if user.Status != tt.wantStatus || user.DeletedAt != nil || !slices.Equal(users.actions, tt.wantActions) {
+				t.Errorf("status = %s, deleted = %v, actions = %v; want %s, not deleted, %v", user.Status, user.DeletedAt != nil, users.actions, tt.wantStatus, tt.wantActions)
+			}
+			if resource.Active == nil || *resource.Active != tt.wantStatus.CanAuthenticate() {
+				t.Errorf("active = %v, want %t", resource.Active, tt.wantStatus.CanAuthenticate())
+			}
+			if len(tt.wantActions) > 0 && tt.wantActions[0] == domain.StatusDeactivate && user.StatusReason != deactivationReason {
+				t.Errorf("reason = %q, want %q", user.StatusReason, deactivationReason)
+			}
+		})
+	}
+}
+
+func TestCreateInactiveUserIsPending(t *testing.T) {
+	users := newMemoryUsers()
+	handler := NewHandler(users, nil, nil, testToken, "")
+
+	w := scimRequest(handler, http.MethodPost, "/Users", `{"schemas":["`+UserSchema+`"],"userName":"bjensen","active":false,"emails":[{"value":"bjensen@example.com"}]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d %s", w.Code, w.Body)
+	}
+	var resource UserResource
+	decodeResponse(t, w, &resource)
+	if user := users.users[resource.ID]; user.Status != domain.UserPending || user.DeletedAt != nil {
+		t.Errorf("user = %+v, want a pending, not deleted account", user)
+	}
+	if resource.Active == nil || *resource.Active {
+		t.Errorf("active = %v, want false", resource.Active)
+	}
+}
+
+func TestDeleteHidesTheUser(t *testing.T) {
+	users := newMemoryUsers(domain.User{ID: "u1", Name: "Barbara Jensen", Username: "bjensen", Email: "bjensen@example.com"})
+	handler := NewHandler(users, nil, nil, testToken, "")
+
+	if w := scimRequest(handler, http.MethodDelete, "/Users/u1", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE = %d %s", w.Code, w.Body)
+	}
+	if user := users.users["u1"]; user.DeletedAt == nil || user.Status != domain.UserActive {
+		t.Errorf("user = %+v, want soft-deleted with its status unchanged", user)
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPatch, http.MethodDelete} {
+		if w := scimRequest(handler, method, "/Users/u1", patchBody(`{"op":"replace","path":"active","value":true}`)); w.Code != http.StatusNotFound {
+			t.Errorf("%s after DELETE = %d, want 404", method, w.Code)
+		}
+	}
+}
+
+func TestUnknownUserIsNotFound(t *testing.T) {
+	handler := NewHandler(newMemoryUsers(), nil, nil, testToken, "")
+
+	for _, method := range []string{http.MethodGet, http.MethodPatch, http.MethodDelete} {
+		w := scimRequest(handler, method, "/Users/missing", patchBody(`{"op":"replace","path":"userName","value":"x"}`))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s: status = %d, want 404", method, w.Code)
+		}
+	}
+}
+
+func TestDiscoveryEndpoints(t *testing.T) {
+	handler := NewHandler(newMemoryUsers(), nil, nil, testToken, "")
+
+	for _, target := range []string{"/ServiceProviderConfig", "/Schemas", "/Schemas/" + UserSchema, "/ResourceTypes", "/ResourceTypes/User"} {
+		if w := scimRequest(handler, http.MethodGet, target, ""); w.Code != http.StatusOK || w.Header().Get("Content-Type") != MediaType {
+			t.Errorf("GET %s = %d %q", target, w.Code, w.Header().Get("Content-Type"))
+		}
+	}
+	if w := scimRequest(handler, http.MethodGet, "/Schemas/urn:unknown", ""); w.Code != http.StatusNotFound {
+		t.Errorf("unknown schema = %d, want 404", w.Code)
+	}
+}
@@ -1,79 +1,234 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
-	"os"
+	grpcHandler "user-api-restful/cmd/api/grpc"
 	httpHandler "user-api-restful/cmd/api/http"
+	scimHandler "user-api-restful/cmd/api/scim"
 	"user-api-restful/internal/application"
+	"user-api-restful/internal/config"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/messaging"
 	"user-api-restful/internal/persistence/database"
-	"user-api-restful/internal/persistence/entity"
-
-	"github.com/go-chi/chi/v5"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
 func main() {
-	dbUser := os.Getenv("POSTGRES_USER")
-	dbPassword := os.Getenv("PASSWORD_")
-	dbHost := os.Getenv("DB_HOST")
-	dbName := "users_db"
-	dbPort := "5432"
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	cfg := config.Load()
+
+	// Sin credenciales de Basic Auth solo se aceptan claves de API, salvo el
+	// acceso anónimo explícito de AUTH_DISABLED.
+	if cfg.BasicAuthUser == "" || cfg.BasicAuthPass == "" {
+		if cfg.AuthDisabled {
+			log.Println("WARNING: AUTH_DISABLED is set and BASIC_AUTH credentials are missing: every request is served as an anonymous administrator.")
+		} else {
+			log.Println("BASIC_AUTH credentials not set: Basic Auth is disabled, only API keys are accepted.")
+		}
 	}
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=America/New_York",
-		dbHost, dbUser, dbPassword, dbName, dbPort)
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := database.Connect(cfg.DSN())
 
 	if err != nil {
 		log.Fatal("failed to connect to database: ", err)
 	}
 
-	err = db.AutoMigrate(&entity.UserEntity{})
+	err = database.Migrate(db)
 
 	if err != nil {
-		log.Fatal("failed to auto migrate users: ", err)
+		log.Fatal("failed to migrate database: ", err)
 	}
 
-	userRepository := database.NewPostgresRepository(db)
+	// Las políticas de row-level security complementan el filtro por
+	// organización de cada consulta (ver TENANT_ROW_LEVEL_SECURITY).
+	err = database.ConfigureRowLevelSecurity(db, cfg.TenantRowLevelSecurity)
+
+	if err != nil {
+		log.Fatal("failed to configure row-level security: ", err)
+	}
+
+	userRepository := database.NewPostgresRepository(db).WithRowLevelSecurity(cfg.TenantRowLevelSecurity)
+
+	normalizer := application.NewNormalizer(cfg.NormalizeGmailAddresses)
+
+	identityPolicy := application.DeletedIdentityPolicy(cfg.DeletedIdentityPolicy)
+
+	userService := application.NewUserServiceImpl(userRepository, userRepository, normalizer, identityPolicy)
+
+	// Purga en segundo plano los usuarios eliminados que superaron la retención.
+	go application.NewRetentionJob(userService, cfg.DeletedUserRetention, cfg.PurgeInterval).Run(context.Background())
+
+	// Publica en segundo plano los eventos del outbox, tanto en el publisher
+	// configurado como hacia los webhooks suscriptos.
+	publisher, err := newEventPublisher(cfg)
+
+	if err != nil {
+		log.Fatal("failed to create event publisher: ", err)
+	}
 
-	userService := application.NewUserServiceImpl(userRepository, userRepository)
+	// Los usuarios creados o con un nuevo email reciben un mensaje de verificación.
+	mailer, err := newMailer(cfg)
+
+	if err != nil {
+		log.Fatal("failed to create mailer: ", err)
+	}
+
+	emailTemplates, err := application.NewEmailTemplates(cfg.MailTemplateDir)
+
+	if err != nil {
+		log.Fatal("failed to load email templates: ", err)
+	}
+
+	emailVerificationService := application.NewEmailVerificationService(userRepository, mailer, emailTemplates,
+		cfg.EmailVerificationTokenTTL, cfg.EmailVerificationURL)
+
+	webhookRepository := database.NewPostgresWebhookRepository(db)
+	broker := messaging.NewBroker(256)
+	publisher = messaging.NewFanoutPublisher(publisher, application.NewWebhookDispatcher(webhookRepository),
+		emailVerificationService, broker)
+
+	webhookSender := messaging.NewHTTPWebhookSender(cfg.WebhookTimeout, cfg.WebhookAllowPrivateTargets)
+	go application.NewWebhookDeliveryWorker(webhookRepository, webhookSender, cfg.WebhookPollInterval,
+		cfg.WebhookMaxAttempts, cfg.WebhookDisableAfter, cfg.WebhookMaxBackoff).Run(context.Background())
+
+	outbox := database.NewPostgresOutboxRepository(db)
+	go application.NewOutboxRelay(outbox, publisher, cfg.OutboxBatchSize, cfg.OutboxPollInterval, cfg.OutboxMaxBackoff).Run(context.Background())
+
+	// Conserva los eventos publicados como registro del feed SSE durante la retención.
+	go application.NewEventLogRetentionJob(outbox, cfg.EventLogRetention, cfg.PurgeInterval).Run(context.Background())
 
 	userHandler := httpHandler.NewUserHandler(userService)
 
-	router := chi.NewRouter()
+	emailVerificationHandler := httpHandler.NewEmailVerificationHandler(emailVerificationService)
+
+	// Las claves Idempotency-Key expiradas se eliminan junto con la purga periódica.
+	idempotencyService := application.NewIdempotencyService(database.NewPostgresIdempotencyRepository(db), cfg.IdempotencyKeyTTL)
+	go idempotencyService.RunCleanup(context.Background(), cfg.PurgeInterval)
 
-	router.Use(httpHandler.AuthAndLoggingMiddleware)
+	batchHandler := httpHandler.NewBatchHandler(userService, cfg.BatchMaxOperations)
 
-	router.Route("/users", func(r chi.Router) {
-		// POST /users - Create a new user
-		r.Post("/", httpHandler.ErrorHandlerWrapper(userHandler.CreateUser))
+	// Procesa en segundo plano las importaciones pendientes o interrumpidas.
+	importService := application.NewUserImportService(userService, userRepository,
+		database.NewPostgresUserImportRepository(db), normalizer, cfg.ImportChunkSize)
+	go application.NewImportWorker(importService, cfg.ImportPollInterval).Run(context.Background())
 
-		// GET /users - Retrieve all users (FindAll)
-		r.Get("/", httpHandler.ErrorHandlerWrapper(userHandler.FindAll))
+	importHandler := httpHandler.NewUserImportHandler(importService, int64(cfg.ImportMaxBytes))
 
-		// PUT /users - Update an existing user (Update)
-		// Common pattern: Use PUT to replace the entire resource, often including the ID in the body.
-		r.Put("/", httpHandler.ErrorHandlerWrapper(userHandler.Update))
+	auditService := application.NewAuditServiceImpl(database.NewPostgresAuditRepository(db))
 
-		// GET /users/{id} - Retrieve a specific user by ID (FindById)
-		// The '{id}' is a URL parameter that userHandler.FindById needs to extract.
-		r.Get("/{id}", httpHandler.ErrorHandlerWrapper(userHandler.FindById))
+	auditHandler := httpHandler.NewAuditHandler(auditService)
 
-		// DELETE /users/{id} - Delete a specific user by ID (Delete)
-		r.Delete("/{id}", httpHandler.ErrorHandlerWrapper(userHandler.Delete))
+	eventFeed := application.NewEventFeed(outbox, broker)
+
+	eventHandler := httpHandler.NewEventHandler(eventFeed)
+
+	webhookHandler := httpHandler.NewWebhookHandler(application.NewWebhookServiceImpl(webhookRepository, cfg.WebhookAllowPrivateTargets))
+
+	// Las integraciones se autentican con claves de API (ver cmd/userctl).
+	apiKeyService := application.NewAPIKeyService(database.NewPostgresAPIKeyRepository(db), userRepository)
+
+	// Cada petición opera sobre una organización (ver TenantMiddleware).
+	tenantService := application.NewTenantService(database.NewPostgresTenantRepository(db), cfg.TenantBaseDomain)
+
+	tenantHandler := httpHandler.NewTenantHandler(tenantService)
+
+	groupService := application.NewGroupServiceImpl(database.NewPostgresGroupRepository(db), userRepository)
+
+	groupHandler := httpHandler.NewGroupHandler(groupService)
+
+	graphQLHandler := httpHandler.NewGraphQLHandler(userService, groupService, httpHandler.GraphQLLimits{
+		MaxDepth:      cfg.GraphQLMaxDepth,
+		MaxComplexity: cfg.GraphQLMaxComplexity,
+	})
+
+	// La tabla de rutas se valida contra la especificación OpenAPI al iniciar.
+	router, err := httpHandler.NewRouter(httpHandler.Handlers{
+		Users:          userHandler,
+		Verification:   emailVerificationHandler,
+		Batch:          batchHandler,
+		Imports:        importHandler,
+		Audit:          auditHandler,
+		Events:         eventHandler,
+		Webhooks:       webhookHandler,
+		Tenants:        tenantHandler,
+		Groups:         groupHandler,
+		GraphQL:        graphQLHandler,
+		Idempotency:    idempotencyService,
+		APIKeys:        apiKeyService,
+		TenantResolver: tenantService,
+	}, httpHandler.RouterOptions{
+		Validation: httpHandler.OpenAPIValidation{
+			Requests:  cfg.ValidateRequests,
+			Responses: cfg.ValidateResponses,
+		},
+		Deprecations: map[int]httpHandler.APIDeprecation{
+			httpHandler.APIVersion1: {DeprecatedAt: cfg.APIV1DeprecatedAt, Sunset: cfg.APIV1Sunset},
+		},
 	})
 
-	log.Printf("Server starting on port :%s", port)
+	if err != nil {
+		log.Fatal("failed to build router: ", err)
+	}
+
+	// Los endpoints SCIM no forman parte de la especificación OpenAPI: se
+	// describen a sí mismos en /scim/v2/Schemas y usan su propia autenticación.
+	if cfg.SCIMBearerToken != "" {
+		router.Mount(scimHandler.BasePath, scimHandler.NewHandler(userService, groupService, tenantService, cfg.SCIMBearerToken, cfg.SCIMTenantID))
+	} else {
+		log.Println("SCIM_BEARER_TOKEN not set. SCIM provisioning endpoints are disabled.")
+	}
+
+	// El servidor gRPC expone el mismo UserService en un puerto separado.
+	if cfg.GRPCPort != "" {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+
+		if err != nil {
+			log.Fatal("failed to listen for gRPC: ", err)
+		}
+
+		grpcServer := grpcHandler.NewServer(userService, eventFeed, apiKeyService, tenantService)
+		go func() {
+			log.Printf("gRPC server starting on port :%s", cfg.GRPCPort)
+
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("gRPC server failed: %v", err)
+			}
+		}()
+	}
+
+	log.Printf("Server starting on port :%s", cfg.Port)
 
-	if err := http.ListenAndServe(":"+port, router); err != nil {
+	if err := http.ListenAndServe(":"+cfg.Port, router); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
+
+// newEventPublisher crea el EventPublisher seleccionado en la configuración.
+func newEventPublisher(cfg config.Config) (domain.EventPublisher, error) {
+	switch cfg.EventPublisher {
+	case "file":
+		return messaging.NewFilePublisher(cfg.EventFilePath)
+	case "none":
+		return messaging.NoopPublisher{}, nil
+	case "stdout":
+		return messaging.NewStdoutPublisher(), nil
+	default:
+		return nil, fmt.Errorf("unknown EVENT_PUBLISHER %q", cfg.EventPublisher)
+	}
+}
+
+// newMailer crea el domain.Mailer seleccionado en la configuración.
+func newMailer(cfg config.Config) (domain.Mailer, error) {
+	switch cfg.Mailer {
+	case "smtp":
+		return messaging.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom, cfg.SMTPTimeout), nil
+	case "file":
+		return messaging.NewFileMailer(cfg.MailFilePath, cfg.MailFrom)
+	case "log":
+		return messaging.NewLogMailer(cfg.MailFrom), nil
+	default:
+		return nil, fmt.Errorf("unknown MAILER %q", cfg.Mailer)
+	}
+}
@@ -0,0 +1,47 @@
+// Command openapi escribe la especificación OpenAPI 3.1 de la API, la misma
+// que se sirve en GET /openapi.json, sin conectarse a la base de datos.
+//
+// Termina con error si la tabla de rutas y la especificación divergen, por lo
+// que puede usarse como verificación en CI:
+//
+//	go run ./cmd/openapi -o openapi.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	httpHandler "user-api-restful/cmd/api/http"
+)
+
+func main() {
+	output := flag.String("o", "", "write the document to this file (default: stdout)")
+	flag.Parse()
+
+	// Los handlers no se invocan: solo se recorre la tabla de rutas.
+	router, err := httpHandler.NewRouter(httpHandler.Handlers{}, httpHandler.RouterOptions{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	document, err := httpHandler.OpenAPIDocument(router)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		log.Fatal("failed to encode the document: ", err)
+	}
+	data = append(data, '\n')
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(*output, data, 0o644)
+	}
+	if err != nil {
+		log.Fatal("failed to write the document: ", err)
+	}
+}
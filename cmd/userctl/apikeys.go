@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// apiKeyUsage describe los subcomandos de apikey.
+const apiKeyUsage = `usage:
+  userctl apikey create -name <name> [-roles admin,auditor] [-key-tenant id] [-user id] [-ttl 720h]
+  userctl apikey list [-o table|json] [-all]
+  userctl apikey rotate [-grace 24h] <id>
+  userctl apikey revoke <id>`
+
+// apiKey despacha los subcomandos de administración de claves de API.
+func apiKey(a *app, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, apiKeyUsage)
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "create":
+		return createAPIKey(a, args[1:])
+	case "list":
+		return listAPIKeys(a, args[1:])
+	case "rotate":
+		return rotateAPIKey(a, args[1:])
+	case "revoke":
+		return revokeAPIKey(a, args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, apiKeyUsage)
+		os.Exit(2)
+	}
+	return nil
+}
+
+// createAPIKey emite una clave e imprime su secreto, que no vuelve a mostrarse.
+func createAPIKey(a *app, args []string) error {
+	flags := newFlagSet("apikey create")
+	name := flags.String("name", "", "name of the integration using the key")
+	roles := flags.String("roles", "", "comma-separated roles: admin, auditor (default: none)")
+	tenantID := flags.String("key-tenant", "", "tenant the key is restricted to (default: operator key, valid for every tenant; requires -roles admin)")
+	userID := flags.String("user", "", "user the key belongs to, in -key-tenant or else -tenant; the key only works while the account is active")
+	ttl := flags.Duration("ttl", 0, "validity of the key (0 = no expiration)")
+	flags.Parse(args)
+
+	// Una clave de usuario pertenece a la organización del usuario.
+	if *userID != "" && *tenantID == "" {
+		*tenantID = domain.TenantFromContext(a.ctx)
+	}
+	if *tenantID != "" {
+		if _, err := a.tenants.FindById(a.ctx, *tenantID); err != nil {
+			return err
+		}
+	}
+
+	issued, err := a.apiKeys.Issue(a.ctx, *name, *tenantID, *userID, splitRoles(*roles), *ttl)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "api key %s (%s) created; store the secret now, it is not shown again\n", issued.ID, issued.Name)
+	fmt.Println(issued.Secret)
+	return nil
+}
+
+// listAPIKeys lista las claves sin sus secretos.
+func listAPIKeys(a *app, args []string) error {
+	flags := newFlagSet("apikey list")
+	output := flags.String("o", "table", "output format: table or json")
+	all := flags.Bool("all", false, "include revoked keys")
+	flags.Parse(args)
+
+	if err := outputFormat(*output); err != nil {
+		return err
+	}
+
+	keys, err := a.apiKeys.FindAll(a.ctx, *all)
+	if err != nil {
+		return err
+	}
+
+	if *output == "json" {
+		return printJSON(keys)
+	}
+
+	now := time.Now()
+	rows := make([][]string, len(keys))
+	for i, key := range keys {
+		state := "active"
+		switch {
+		case key.RevokedAt != nil:
+			state = "revoked"
+		case !key.ValidAt(now):
+			state = "expired"
+		case key.ExpiresAt != nil:
+			state = "expires " + key.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+		tenantID := key.TenantID
+		if tenantID == "" {
+			tenantID = "*"
+		}
+		userID := key.UserID
+		if userID == "" {
+			userID = "-"
+		}
+		rows[i] = []string{key.ID, key.Name, key.Prefix + "…", tenantID, userID, strings.Join(key.Roles, ","), key.CreatedAt.UTC().Format(time.RFC3339), state}
+	}
+	return printTable([]string{"ID", "NAME", "PREFIX", "TENANT", "USER", "ROLES", "CREATED_AT", "STATE"}, rows)
+}
+
+// rotateAPIKey reemplaza la clave indicada por una nueva con los mismos
+// nombre, organización, usuario, roles y validez, e imprime su secreto; las
+// claves vigentes de la integración siguen siendo válidas durante el período
+// de gracia.
+func rotateAPIKey(a *app, args []string) error {
+	flags := newFlagSet("apikey rotate")
+	grace := flags.Duration("grace", 24*time.Hour, "time during which the previous keys remain valid")
+	flags.Parse(args)
+
+	id, err := singleArg(flags, "id")
+	if err != nil {
+		return err
+	}
+
+	issued, err := a.apiKeys.Rotate(a.ctx, id, *grace)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "api key %s (%s) created; previous keys expire at %s\n",
+		issued.ID, issued.Name, time.Now().Add(*grace).UTC().Format(time.RFC3339))
+	fmt.Println(issued.Secret)
+	return nil
+}
+
+// revokeAPIKey revoca una clave de inmediato.
+func revokeAPIKey(a *app, args []string) error {
+	flags := newFlagSet("apikey revoke")
+	flags.Parse(args)
+
+	id, err := singleArg(flags, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := a.apiKeys.Revoke(a.ctx, id); err != nil {
+		return err
+	}
+
+	fmt.Println("revoked", id)
+	return nil
+}
+
+// splitRoles separa una lista de roles separada por comas.
+func splitRoles(raw string) []string {
+	roles := make([]string, 0)
+	for _, role := range strings.Split(raw, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
@@ -0,0 +1,193 @@
+// Command userctl administra usuarios y claves de API desde la línea de
+// comandos, con el mismo application.UserService, el mismo repositorio y la
+// misma configuración (variables de entorno) que el servidor, de modo que
+// los cambios se validan, auditan y publican como eventos igual que los de
+// la API.
+//
+//	userctl [-actor name] [-tenant id] <command> [flags] [args]
+//
+// Comandos de usuarios: create, get, list, update, status, delete, import,
+// export; operan sobre la organización indicada con -tenant (por defecto, la
+// organización por defecto). Organizaciones: tenant create|list|rename|delete.
+// Claves de API: apikey create|list|rotate|revoke. Esquema: migrate.
+// Salvo migrate, los comandos no modifican el esquema de la base de datos.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/config"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/database"
+
+	"gorm.io/gorm"
+)
+
+// command es un subcomando de userctl.
+type command struct {
+	usage string
+	run   func(app *app, args []string) error
+}
+
+// commands son los subcomandos disponibles, por nombre.
+var commands = map[string]command{
+	"create":  {"create -name <name> -username <username> -email <email>", createUser},
+	"get":     {"get [-o table|json] <id>", getUser},
+	"list":    {"list [-o table|json] [-include-deleted] [-created-after RFC3339] [-status s1,s2] [-limit n]", listUsers},
+	"update":  {"update [-name <name>] [-username <username>] [-email <email>] <id>", updateUser},
+	"status":  {statusUsage, changeUserStatus},
+	"delete":  {"delete <id>", deleteUser},
+	"import":  {"import -file <path> [-format csv|ndjson] [-dry-run]", importUsers},
+	"export":  {"export [-format csv|ndjson|json] [-out <path>] [-include-deleted]", exportUsers},
+	"migrate": {"migrate", migrate},
+	"tenant":  {"tenant create|list|rename|delete ... (see userctl tenant -h)", tenant},
+	"apikey":  {"apikey create|list|rotate|revoke ... (see userctl apikey -h)", apiKey},
+}
+
+// app agrupa la configuración y los servicios compartidos por los comandos.
+type app struct {
+	ctx context.Context
+	cfg config.Config
+	db  *gorm.DB
+
+	users   application.UserService
+	imports *application.UserImportService
+	tenants *application.TenantService
+	apiKeys *application.APIKeyService
+}
+
+func main() {
+	actor := flag.String("actor", domain.SystemActor, "actor recorded as the author of the changes")
+	tenantID := flag.String("tenant", domain.DefaultTenantID, "tenant the user commands operate on")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+
+	db, err := database.Connect(cfg.DSN())
+	if err != nil {
+		log.Fatal("failed to connect to database: ", err)
+	}
+
+	// Mismo cableado que el servidor (ver cmd/main).
+	repo := database.NewPostgresRepository(db).WithRowLevelSecurity(cfg.TenantRowLevelSecurity)
+	normalizer := application.NewNormalizer(cfg.NormalizeGmailAddresses)
+	userService := application.NewUserServiceImpl(repo, repo, normalizer, application.DeletedIdentityPolicy(cfg.DeletedIdentityPolicy))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	a := &app{
+		ctx:     domain.WithPrincipal(ctx, domain.Principal{Subject: *actor, Roles: []string{domain.RoleAdmin}}),
+		cfg:     cfg,
+		db:      db,
+		users:   userService,
+		imports: application.NewUserImportService(userService, repo, database.NewPostgresUserImportRepository(db), normalizer, cfg.ImportChunkSize),
+		tenants: application.NewTenantService(database.NewPostgresTenantRepository(db), cfg.TenantBaseDomain),
+		apiKeys: application.NewAPIKeyService(database.NewPostgresAPIKeyRepository(db), repo),
+	}
+
+	// Los comandos de usuarios operan sobre la organización indicada, que debe
+	// existir (salvo para migrate, que puede crearla).
+	if flag.Arg(0) != "migrate" {
+		// userctl opera como operador del despliegue: puede elegir cualquier organización.
+		principal, _ := domain.PrincipalFromContext(a.ctx)
+		resolved, err := a.tenants.Resolve(a.ctx, principal, *tenantID)
+		if err != nil {
+			log.Fatalf("tenant %q: %v", *tenantID, err)
+		}
+		a.ctx = domain.WithTenant(a.ctx, resolved)
+	}
+
+	if err := cmd.run(a, flag.Args()[1:]); err != nil {
+		log.Fatalf("%s: %v", flag.Arg(0), err)
+	}
+}
+
+// usage imprime la ayuda general, con los comandos en orden alfabético.
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: userctl [-actor name] [-tenant id] <command> [flags] [args]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, name := range slices.Sorted(maps.Keys(commands)) {
+		fmt.Fprintln(os.Stderr, "  "+commands[name].usage)
+	}
+}
+
+// newFlagSet crea el conjunto de flags de un subcomando.
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet("userctl "+name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: userctl %s [flags] [args]\n", name)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// singleArg retorna el único argumento posicional de un subcomando.
+func singleArg(flags *flag.FlagSet, name string) (string, error) {
+	if flags.NArg() != 1 {
+		return "", fmt.Errorf("expected exactly one argument: <%s>", name)
+	}
+	return flags.Arg(0), nil
+}
+
+// migrate aplica las migraciones pendientes del esquema y habilita o
+// deshabilita las políticas de row-level security según la configuración.
+func migrate(a *app, args []string) error {
+	newFlagSet("migrate").Parse(args)
+
+	if err := database.Migrate(a.db); err != nil {
+		return err
+	}
+	if err := database.ConfigureRowLevelSecurity(a.db, a.cfg.TenantRowLevelSecurity); err != nil {
+		return err
+	}
+	fmt.Println("schema up to date")
+	return nil
+}
+
+// outputFormat valida el formato de salida de los comandos de consulta.
+func outputFormat(format string) error {
+	if format != "table" && format != "json" {
+		return fmt.Errorf("-o must be table or json")
+	}
+	return nil
+}
+
+// printJSON escribe value como JSON indentado.
+func printJSON(value any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// printTable escribe las filas como una tabla alineada con encabezado.
+func printTable(header []string, rows [][]string) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+	return writer.Flush()
+}
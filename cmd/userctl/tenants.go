@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// tenantUsage describe los subcomandos de tenant.
+const tenantUsage = `usage:
+  userctl tenant create -id <id> -name <name>
+  userctl tenant list [-o table|json]
+  userctl tenant rename -name <name> <id>
+  userctl tenant delete <id>`
+
+// tenant despacha los subcomandos de administración de organizaciones.
+func tenant(a *app, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, tenantUsage)
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "create":
+		return createTenant(a, args[1:])
+	case "list":
+		return listTenants(a, args[1:])
+	case "rename":
+		return renameTenant(a, args[1:])
+	case "delete":
+		return deleteTenant(a, args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, tenantUsage)
+		os.Exit(2)
+	}
+	return nil
+}
+
+// createTenant crea una organización.
+func createTenant(a *app, args []string) error {
+	flags := newFlagSet("tenant create")
+	id := flags.String("id", "", "tenant ID (lowercase slug, also used as subdomain)")
+	name := flags.String("name", "", "display name")
+	flags.Parse(args)
+
+	created, err := a.tenants.Create(a.ctx, &domain.TenantCreateRequest{ID: *id, Name: *name})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("created", created.ID)
+	return nil
+}
+
+// listTenants lista las organizaciones.
+func listTenants(a *app, args []string) error {
+	flags := newFlagSet("tenant list")
+	output := flags.String("o", "table", "output format: table or json")
+	flags.Parse(args)
+
+	if err := outputFormat(*output); err != nil {
+		return err
+	}
+
+	tenants, err := a.tenants.FindAll(a.ctx)
+	if err != nil {
+		return err
+	}
+
+	if *output == "json" {
+		return printJSON(tenants)
+	}
+
+	rows := make([][]string, len(tenants))
+	for i, tenant := range tenants {
+		rows[i] = []string{tenant.ID, tenant.Name, tenant.CreatedAt.UTC().Format(time.RFC3339)}
+	}
+	return printTable([]string{"ID", "NAME", "CREATED_AT"}, rows)
+}
+
+// renameTenant cambia el nombre de una organización.
+func renameTenant(a *app, args []string) error {
+	flags := newFlagSet("tenant rename")
+	name := flags.String("name", "", "new display name")
+	flags.Parse(args)
+
+	id, err := singleArg(flags, "id")
+	if err != nil {
+		return err
+	}
+
+	if _, err := a.tenants.Update(a.ctx, id, &domain.TenantUpdateRequest{Name: *name}); err != nil {
+		return err
+	}
+
+	fmt.Println("renamed", id)
+	return nil
+}
+
+// deleteTenant elimina una organización sin usuarios.
+func deleteTenant(a *app, args []string) error {
+	flags := newFlagSet("tenant delete")
+	flags.Parse(args)
+
+	id, err := singleArg(flags, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := a.tenants.Delete(a.ctx, id); err != nil {
+		return err
+	}
+
+	fmt.Println("deleted", id)
+	return nil
+}
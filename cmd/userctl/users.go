@@ -0,0 +1,388 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate aplica a los datos de un usuario las mismas reglas que POST /users.
+var validate = validator.New()
+
+// createUser crea un usuario.
+func createUser(a *app, args []string) error {
+	flags := newFlagSet("create")
+	name := flags.String("name", "", "name")
+	username := flags.String("username", "", "username")
+	email := flags.String("email", "", "email")
+	flags.Parse(args)
+
+	request := domain.UserCreateRequest{Name: *name, Username: *username, Email: *email}
+	if err := validate.Struct(request); err != nil {
+		return err
+	}
+
+	user, err := a.users.Create(a.ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(user.ID)
+	return nil
+}
+
+// getUser muestra un usuario.
+func getUser(a *app, args []string) error {
+	flags := newFlagSet("get")
+	output := flags.String("o", "table", "output format: table or json")
+	flags.Parse(args)
+
+	id, err := singleArg(flags, "id")
+	if err != nil {
+		return err
+	}
+	if err := outputFormat(*output); err != nil {
+		return err
+	}
+
+	user, err := a.users.FindById(a.ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return printUsers(*output, []domain.User{*user})
+}
+
+// listUsers lista los usuarios en orden de ID.
+func listUsers(a *app, args []string) error {
+	flags := newFlagSet("list")
+	output := flags.String("o", "table", "output format: table or json")
+	includeDeleted := flags.Bool("include-deleted", false, "include soft-deleted users")
+	createdAfter := flags.String("created-after", "", "only users created at or after this instant (RFC 3339)")
+	status := flags.String("status", "", "only users in these account statuses (comma-separated)")
+	limit := flags.Int("limit", 0, "maximum number of users (0 = all)")
+	flags.Parse(args)
+
+	if err := outputFormat(*output); err != nil {
+		return err
+	}
+
+	statuses, err := domain.ParseUserStatuses(*status)
+	if err != nil {
+		return err
+	}
+
+	filter := domain.UserFilter{IncludeDeleted: *includeDeleted, Statuses: statuses, Limit: *limit}
+	if *createdAfter != "" {
+		t, err := time.Parse(time.RFC3339, *createdAfter)
+		if err != nil {
+			return fmt.Errorf("-created-after must be an RFC 3339 instant: %w", err)
+		}
+		filter.CreatedAfter = &t
+	}
+
+	// Sin límite, el orden lo aplica Export; con límite, FindAll pagina por ID.
+	users := make([]domain.User, 0)
+	if *limit > 0 {
+		page, err := a.users.FindAll(a.ctx, filter)
+		if err != nil {
+			return err
+		}
+		users = *page
+	} else if err := a.users.Export(a.ctx, filter, func(user *domain.User) error {
+		users = append(users, *user)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return printUsers(*output, users)
+}
+
+// updateUser modifica los campos indicados de un usuario.
+func updateUser(a *app, args []string) error {
+	flags := newFlagSet("update")
+	name := flags.String("name", "", "new name")
+	username := flags.String("username", "", "new username")
+	email := flags.String("email", "", "new email")
+	flags.Parse(args)
+
+	id, err := singleArg(flags, "id")
+	if err != nil {
+		return err
+	}
+
+	user, err := a.users.FindById(a.ctx, id)
+	if err != nil {
+		return err
+	}
+	if *name != "" {
+		user.Name = *name
+	}
+	if *username != "" {
+		user.Username = *username
+	}
+	if *email != "" {
+		user.Email = *email
+	}
+
+	if err := validate.Struct(domain.UserCreateRequest{Name: user.Name, Username: user.Username, Email: user.Email}); err != nil {
+		return err
+	}
+
+	updated, err := a.users.Update(a.ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return printUsers("table", []domain.User{*updated})
+}
+
+// statusUsage describe el comando status.
+const statusUsage = "status activate|suspend|lock|unlock|deactivate [-reason <reason>] <id>"
+
+// changeUserStatus aplica una acción del ciclo de vida a la cuenta de un
+// usuario (activate, suspend, lock, unlock o deactivate).
+func changeUserStatus(a *app, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: userctl %s", statusUsage)
+	}
+	action := domain.UserStatusAction(args[0])
+
+	flags := newFlagSet("status " + args[0])
+	reason := flags.String("reason", "", "reason (required for suspend, lock and deactivate)")
+	flags.Parse(args[1:])
+
+	id, err := singleArg(flags, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := validate.Struct(domain.UserStatusChangeRequest{Reason: *reason}); err != nil {
+		return err
+	}
+
+	user, err := a.users.ChangeStatus(a.ctx, id, action, *reason)
+	if err != nil {
+		return err
+	}
+
+	return printUsers("table", []domain.User{*user})
+}
+
+// deleteUser elimina lógicamente un usuario.
+func deleteUser(a *app, args []string) error {
+	flags := newFlagSet("delete")
+	flags.Parse(args)
+
+	id, err := singleArg(flags, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := a.users.Delete(a.ctx, id); err != nil {
+		return err
+	}
+
+	fmt.Println("deleted", id)
+	return nil
+}
+
+// importUsers importa un archivo CSV o NDJSON con las reglas de POST /users/imports.
+func importUsers(a *app, args []string) error {
+	flags := newFlagSet("import")
+	file := flags.String("file", "", "CSV or NDJSON file to import")
+	format := flags.String("format", "", "file format: csv or ndjson (default: from the file extension)")
+	dryRun := flags.Bool("dry-run", false, "validate the file and report errors without creating users")
+	flags.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("-file is required")
+	}
+
+	payload, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	importFormat := domain.ImportFormat(*format)
+	if importFormat == "" {
+		importFormat = domain.ImportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), "."))
+	}
+
+	// 1. Validación sin efectos
+	if *dryRun {
+		report, err := a.imports.DryRun(a.ctx, importFormat, domain.ImportColumnMapping{}, payload)
+		if err != nil {
+			return err
+		}
+		printRowErrors(report.Errors)
+		fmt.Printf("rows: %d, valid: %d, invalid: %d\n", report.TotalRows, report.ValidRows, report.InvalidRows)
+		return nil
+	}
+
+	// 2. Registro y procesamiento de la importación
+	userImport, err := a.imports.Start(a.ctx, importFormat, domain.ImportColumnMapping{}, payload)
+	if err != nil {
+		return err
+	}
+
+	processed, err := a.imports.Process(a.ctx, userImport.ID)
+	if err != nil {
+		return fmt.Errorf("import %s failed (resume with userimport -resume %s): %w", userImport.ID, userImport.ID, err)
+	}
+	if processed == nil {
+		return fmt.Errorf("import %s is being processed by another process", userImport.ID)
+	}
+
+	rowErrors, err := a.imports.Errors(a.ctx, userImport.ID)
+	if err != nil {
+		return err
+	}
+	printRowErrors(rowErrors)
+
+	fmt.Printf("import %s %s: processed %d/%d, created %d, failed %d\n", processed.ID, processed.Status,
+		processed.ProcessedRows, processed.TotalRows, processed.CreatedRows, processed.FailedRows)
+	return nil
+}
+
+// printRowErrors imprime los errores por fila de una importación.
+func printRowErrors(rowErrors []domain.ImportRowError) {
+	for _, rowError := range rowErrors {
+		fmt.Printf("row %d: %s %s\n", rowError.Row, rowError.Field, rowError.Message)
+	}
+}
+
+// exportFields son las columnas de la exportación, en orden.
+var exportFields = []string{"id", "name", "username", "email", "created_at", "updated_at", "created_by", "updated_by", "deleted_at",
+	"status", "status_reason", "status_changed_at", "email_verified", "email_verified_at"}
+
+// exportUsers escribe los usuarios en CSV, NDJSON o un arreglo JSON,
+// recorriéndolos desde un cursor de la base de datos.
+func exportUsers(a *app, args []string) error {
+	flags := newFlagSet("export")
+	format := flags.String("format", "csv", "output format: csv, ndjson or json")
+	out := flags.String("out", "", "write to this file (default: stdout)")
+	includeDeleted := flags.Bool("include-deleted", false, "include soft-deleted users")
+	flags.Parse(args)
+
+	if *format != "csv" && *format != "ndjson" && *format != "json" {
+		return fmt.Errorf("-format must be csv, ndjson or json")
+	}
+
+	var writer io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+	buffered := bufio.NewWriter(writer)
+	defer buffered.Flush()
+
+	filter := domain.UserFilter{IncludeDeleted: *includeDeleted}
+	count := 0
+
+	switch *format {
+	case "csv":
+		csvWriter := csv.NewWriter(buffered)
+		if err := csvWriter.Write(exportFields); err != nil {
+			return err
+		}
+		err := a.users.Export(a.ctx, filter, func(user *domain.User) error {
+			count++
+			return csvWriter.Write(userRow(user))
+		})
+		csvWriter.Flush()
+		if err != nil {
+			return err
+		}
+		if err := csvWriter.Error(); err != nil {
+			return err
+		}
+	default:
+		if *format == "json" {
+			buffered.WriteString("[")
+		}
+		encoder := json.NewEncoder(buffered)
+		err := a.users.Export(a.ctx, filter, func(user *domain.User) error {
+			if *format == "json" && count > 0 {
+				buffered.WriteString(",")
+			}
+			count++
+			return encoder.Encode(user)
+		})
+		if err != nil {
+			return err
+		}
+		if *format == "json" {
+			buffered.WriteString("]\n")
+		}
+	}
+
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d users\n", count)
+	return nil
+}
+
+// printUsers imprime los usuarios como tabla o como JSON.
+func printUsers(format string, users []domain.User) error {
+	if format == "json" {
+		return printJSON(users)
+	}
+
+	rows := make([][]string, len(users))
+	for i := range users {
+		row := userRow(&users[i])
+		// Tabla: id, name, username, email, status, created_at y deleted_at.
+		rows[i] = []string{row[0], row[1], row[2], row[3], row[9], row[4], row[8]}
+	}
+	return printTable([]string{"ID", "NAME", "USERNAME", "EMAIL", "STATUS", "CREATED_AT", "DELETED_AT"}, rows)
+}
+
+// userRow retorna los valores de exportFields de un usuario como texto.
+func userRow(user *domain.User) []string {
+	deletedAt := ""
+	if user.DeletedAt != nil {
+		deletedAt = user.DeletedAt.UTC().Format(time.RFC3339)
+	}
+	statusChangedAt := ""
+	if user.StatusChangedAt != nil {
+		statusChangedAt = user.StatusChangedAt.UTC().Format(time.RFC3339)
+	}
+	emailVerifiedAt := ""
+	if user.EmailVerifiedAt != nil {
+		emailVerifiedAt = user.EmailVerifiedAt.UTC().Format(time.RFC3339)
+	}
+
+	return []string{
+		user.ID,
+		user.Name,
+		user.Username,
+		user.Email,
+		user.CreatedAt.UTC().Format(time.RFC3339),
+		user.UpdatedAt.UTC().Format(time.RFC3339),
+		user.CreatedBy,
+		user.UpdatedBy,
+		deletedAt,
+		string(user.Status),
+		user.StatusReason,
+		statusChangedAt,
+		strconv.FormatBool(user.EmailVerified),
+		emailVerifiedAt,
+	}
+}
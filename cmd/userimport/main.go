@@ -0,0 +1,149 @@
+// Command userimport importa usuarios desde un archivo CSV o NDJSON con las
+// mismas reglas que POST /users/imports.
+//
+// Con -dry-run solo valida el archivo y reporta los errores. Si no, registra
+// la importación y la procesa por bloques; si se interrumpe, -resume <id> la
+// continúa desde el último bloque confirmado. -errors escribe el reporte de
+// errores como CSV. -tenant indica la organización de los usuarios importados.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/config"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/database"
+)
+
+func main() {
+	file := flag.String("file", "", "CSV or NDJSON file to import")
+	format := flag.String("format", "", "file format: csv or ndjson (default: from the file extension)")
+	mapName := flag.String("map-name", "", "CSV column holding the name (default: name)")
+	mapUsername := flag.String("map-username", "", "CSV column holding the username (default: username)")
+	mapEmail := flag.String("map-email", "", "CSV column holding the email (default: email)")
+	dryRun := flag.Bool("dry-run", false, "validate the file and report errors without creating users")
+	resume := flag.String("resume", "", "ID of an interrupted import to resume")
+	errorsOut := flag.String("errors", "", "write the error report to this CSV file")
+	actor := flag.String("actor", domain.SystemActor, "actor recorded as the creator of the imported users")
+	tenantID := flag.String("tenant", domain.DefaultTenantID, "tenant the users are imported into")
+	flag.Parse()
+
+	if *file == "" && *resume == "" {
+		log.Fatal("either -file or -resume is required")
+	}
+
+	cfg := config.Load()
+
+	db, err := database.Connect(cfg.DSN())
+	if err != nil {
+		log.Fatal("failed to connect to database: ", err)
+	}
+
+	if err := database.Migrate(db); err != nil {
+		log.Fatal("failed to migrate database: ", err)
+	}
+
+	repo := database.NewPostgresRepository(db).WithRowLevelSecurity(cfg.TenantRowLevelSecurity)
+	normalizer := application.NewNormalizer(cfg.NormalizeGmailAddresses)
+	userService := application.NewUserServiceImpl(repo, repo, normalizer, application.DeletedIdentityPolicy(cfg.DeletedIdentityPolicy))
+	importService := application.NewUserImportService(userService, repo, database.NewPostgresUserImportRepository(db), normalizer, cfg.ImportChunkSize)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx = domain.WithPrincipal(ctx, domain.Principal{Subject: *actor})
+
+	tenants := application.NewTenantService(database.NewPostgresTenantRepository(db), cfg.TenantBaseDomain)
+	// userimport opera como operador del despliegue: puede elegir cualquier organización.
+	resolved, err := tenants.Resolve(ctx, domain.Principal{Subject: *actor, Roles: []string{domain.RoleAdmin}}, *tenantID)
+	if err != nil {
+		log.Fatalf("tenant %q: %v", *tenantID, err)
+	}
+	ctx = domain.WithTenant(ctx, resolved)
+
+	// 1. Importación a reanudar o archivo nuevo
+	id := *resume
+	if id == "" {
+		payload, err := os.ReadFile(*file)
+		if err != nil {
+			log.Fatal("failed to read file: ", err)
+		}
+
+		importFormat := domain.ImportFormat(*format)
+		if importFormat == "" {
+			importFormat = domain.ImportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), "."))
+		}
+		mapping := domain.ImportColumnMapping{Name: *mapName, Username: *mapUsername, Email: *mapEmail}
+
+		if *dryRun {
+			report, err := importService.DryRun(ctx, importFormat, mapping, payload)
+			if err != nil {
+				log.Fatal("dry run failed: ", err)
+			}
+			for _, rowError := range report.Errors {
+				fmt.Printf("row %d: %s %s\n", rowError.Row, rowError.Field, rowError.Message)
+			}
+			fmt.Printf("rows: %d, valid: %d, invalid: %d\n", report.TotalRows, report.ValidRows, report.InvalidRows)
+			writeErrors(*errorsOut, report.Errors)
+			if report.InvalidRows > 0 {
+				os.Exit(1)
+			}
+			return
+		}
+
+		userImport, err := importService.Start(ctx, importFormat, mapping, payload)
+		if err != nil {
+			log.Fatal("failed to start import: ", err)
+		}
+		id = userImport.ID
+		fmt.Printf("import %s: %d rows\n", id, userImport.TotalRows)
+	} else if _, err := importService.Resume(ctx, id); err != nil {
+		log.Fatal("failed to resume import: ", err)
+	}
+
+	// 2. Procesamiento
+	userImport, err := importService.Process(ctx, id)
+	if err != nil {
+		log.Fatalf("import %s failed (resume with -resume %s): %v", id, id, err)
+	}
+	if userImport == nil {
+		log.Fatalf("import %s is being processed by another process", id)
+	}
+
+	fmt.Printf("import %s %s: processed %d/%d, created %d, failed %d\n", id, userImport.Status,
+		userImport.ProcessedRows, userImport.TotalRows, userImport.CreatedRows, userImport.FailedRows)
+
+	rowErrors, err := importService.Errors(ctx, id)
+	if err != nil {
+		log.Fatal("failed to load error report: ", err)
+	}
+	writeErrors(*errorsOut, rowErrors)
+
+	if userImport.Status != domain.ImportCompleted {
+		fmt.Printf("interrupted; resume with -resume %s\n", id)
+		os.Exit(1)
+	}
+}
+
+// writeErrors escribe el reporte de errores en path, si se indicó.
+func writeErrors(path string, rowErrors []domain.ImportRowError) {
+	if path == "" {
+		return
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		log.Fatal("failed to create error report: ", err)
+	}
+	defer out.Close()
+
+	if err := application.WriteImportErrorsCSV(out, rowErrors); err != nil {
+		log.Fatal("failed to write error report: ", err)
+	}
+}
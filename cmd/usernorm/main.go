@@ -0,0 +1,126 @@
+// Command usernorm completa las formas canónicas de username y email de los
+// usuarios existentes y reporta las colisiones que impiden hacerlo.
+//
+// Por defecto solo genera el reporte; con -apply escribe los valores
+// normalizados de los usuarios que no colisionan con ningún otro. Como la
+// unicidad es por organización, solo colisionan usuarios de la misma.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"user-api-restful/internal/application"
+	"user-api-restful/internal/config"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/database"
+)
+
+func main() {
+	apply := flag.Bool("apply", false, "write normalized values for users without collisions")
+	flag.Parse()
+
+	cfg := config.Load()
+
+	db, err := database.Connect(cfg.DSN())
+	if err != nil {
+		log.Fatal("failed to connect to database: ", err)
+	}
+
+	if err := database.Migrate(db); err != nil {
+		log.Fatal("failed to migrate database: ", err)
+	}
+
+	repo := database.NewPostgresRepository(db).WithRowLevelSecurity(cfg.TenantRowLevelSecurity)
+	normalizer := application.NewNormalizer(cfg.NormalizeGmailAddresses)
+
+	// Los usuarios eliminados retienen su identidad salvo con la política "release".
+	releaseDeleted := application.DeletedIdentityPolicy(cfg.DeletedIdentityPolicy) == application.ReleaseDeletedIdentity
+
+	users, err := repo.FindAll(domain.UserFilter{IncludeDeleted: !releaseDeleted})
+	if err != nil {
+		log.Fatal("failed to load users: ", err)
+	}
+
+	// Agrupa los usuarios por organización y forma canónica para detectar colisiones.
+	byUsername := map[identity][]domain.User{}
+	byEmail := map[identity][]domain.User{}
+	for _, user := range *users {
+		username := identity{user.TenantID, normalizer.Username(user.Username)}
+		email := identity{user.TenantID, normalizer.Email(user.Email)}
+		byUsername[username] = append(byUsername[username], user)
+		byEmail[email] = append(byEmail[email], user)
+	}
+
+	colliding := map[string]bool{}
+	collisions := reportCollisions("username", byUsername, colliding)
+	collisions += reportCollisions("email", byEmail, colliding)
+
+	pending, updated := 0, 0
+	for _, user := range *users {
+		usernameNormalized := normalizer.Username(user.Username)
+		emailNormalized := normalizer.Email(user.Email)
+
+		if user.UsernameNormalized == usernameNormalized && user.EmailNormalized == emailNormalized {
+			continue
+		}
+		pending++
+
+		// Los usuarios eliminados solo se reportan: el repositorio no actualiza filas eliminadas.
+		if !*apply || colliding[user.ID] || user.DeletedAt != nil {
+			continue
+		}
+
+		user.UsernameNormalized = usernameNormalized
+		user.EmailNormalized = emailNormalized
+		// Solo escribe las formas normalizadas, sin tocar updated_at ni los
+		// demás campos; si el usuario cambió mientras tanto, lo omite.
+		if err := repo.UpdateNormalized(&user); err != nil {
+			log.Printf("user %s: %v", user.ID, err)
+			continue
+		}
+		updated++
+	}
+
+	fmt.Printf("users: %d, pending normalization: %d, updated: %d, collision groups: %d\n",
+		len(*users), pending, updated, collisions)
+
+	if collisions > 0 {
+		os.Exit(1)
+	}
+}
+
+// identity es una forma canónica dentro de una organización.
+type identity struct {
+	tenantID string
+	value    string
+}
+
+// reportCollisions imprime los grupos de usuarios que comparten la misma forma
+// canónica, marca sus IDs en colliding y retorna la cantidad de grupos.
+func reportCollisions(field string, groups map[identity][]domain.User, colliding map[string]bool) int {
+	keys := make([]identity, 0, len(groups))
+	for key, group := range groups {
+		if len(group) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].tenantID != keys[j].tenantID {
+			return keys[i].tenantID < keys[j].tenantID
+		}
+		return keys[i].value < keys[j].value
+	})
+
+	for _, key := range keys {
+		fmt.Printf("collision on %s %q in tenant %q:\n", field, key.value, key.tenantID)
+		for _, user := range groups[key] {
+			colliding[user.ID] = true
+			fmt.Printf("  id=%s username=%q email=%q\n", user.ID, user.Username, user.Email)
+		}
+	}
+
+	return len(keys)
+}
@@ -0,0 +1,210 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+const (
+	// apiKeyPrefix distingue las claves de API de otras credenciales.
+	apiKeyPrefix = "uak_"
+	// apiKeyVisiblePrefix es la cantidad de caracteres del secreto que se
+	// conservan para reconocer la clave en los listados.
+	apiKeyVisiblePrefix = len(apiKeyPrefix) + 8
+)
+
+// assignableRoles son los roles que pueden otorgarse a una clave de API.
+var assignableRoles = map[string]bool{
+	domain.RoleAdmin:   true,
+	domain.RoleAuditor: true,
+}
+
+// IssuedAPIKey es una clave recién emitida junto con su secreto, que solo
+// se conoce en este momento.
+type IssuedAPIKey struct {
+	domain.APIKey
+	Secret string `json:"secret"`
+}
+
+// APIKeyService emite, rota, revoca y verifica claves de API.
+type APIKeyService struct {
+	repo domain.APIKeyRepository
+	// users verifica el estado de la cuenta de las claves vinculadas a un usuario.
+	users domain.UserRepository
+}
+
+// NewAPIKeyService crea un APIKeyService sobre los repositorios de claves y
+// de usuarios.
+func NewAPIKeyService(repo domain.APIKeyRepository, users domain.UserRepository) *APIKeyService {
+	return &APIKeyService{repo: repo, users: users}
+}
+
+// Issue emite una nueva clave con el nombre y los roles indicados. tenantID
+// restringe la clave a una organización (vacío = clave de operador, que
+// puede elegir la organización en cada petición). userID vincula la clave a
+// un usuario existente, que siempre pertenece a una organización (tenantID
+// vacío = la organización por defecto). ttl acota su validez; 0 = sin
+// vencimiento.
+func (s *APIKeyService) Issue(ctx context.Context, name, tenantID, userID string, roles []string, ttl time.Duration) (*IssuedAPIKey, error) {
+	issued, err := s.newKey(ctx, name, tenantID, userID, roles, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(&issued.APIKey); err != nil {
+		return nil, err
+	}
+
+	return issued, nil
+}
+
+// Rotate emite una nueva clave con el nombre, la organización, el usuario,
+// los roles y la validez de la clave id, y hace vencer las claves vigentes
+// con ese nombre, organización y usuario al terminar el período de gracia
+// (0 = inmediatamente), para que la integración pueda reemplazar el secreto
+// sin interrupciones. La emisión y el vencimiento se aplican en una única
+// transacción. Retorna ErrAPIKeyNotFound si la clave no existe, está
+// revocada o venció.
+func (s *APIKeyService) Rotate(ctx context.Context, id string, grace time.Duration) (*IssuedAPIKey, error) {
+	// 1. Lectura de la clave vigente
+	current, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !current.ValidAt(now) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	// 2. Emisión con la misma validez que la original
+	var ttl time.Duration
+	if current.ExpiresAt != nil {
+		ttl = current.ExpiresAt.Sub(current.CreatedAt)
+	}
+	issued, err := s.newKey(ctx, current.Name, current.TenantID, current.UserID, current.Roles, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Persistencia y vencimiento de las anteriores
+	if _, err := s.repo.Rotate(&issued.APIKey, now.Add(grace).UTC()); err != nil {
+		return nil, err
+	}
+
+	return issued, nil
+}
+
+// newKey valida los datos de una clave y genera su secreto, sin persistirla.
+func (s *APIKeyService) newKey(ctx context.Context, name, tenantID, userID string, roles []string, ttl time.Duration) (*IssuedAPIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.ErrValueNotNullable{Value: "name"}
+	}
+	if userID != "" && tenantID == "" {
+		tenantID = domain.DefaultTenantID
+	}
+	if tenantID != "" && !domain.ValidTenantID(tenantID) {
+		return nil, domain.ErrInvalidTenantID
+	}
+	// Una clave sin organización es de operador: sin el rol admin no podría
+	// elegir organización y operaría siempre sobre la organización por defecto.
+	if tenantID == "" && !slices.Contains(roles, domain.RoleAdmin) {
+		return nil, fmt.Errorf("%w: a key without a tenant must have the %s role", domain.ErrInvalidTenantID, domain.RoleAdmin)
+	}
+	if userID != "" {
+		if _, err := s.users.ForTenant(tenantID).FindById(userID, "id"); err != nil {
+			return nil, err
+		}
+	}
+	for _, role := range roles {
+		if !assignableRoles[role] {
+			return nil, fmt.Errorf("%w %q; assignable roles: %s, %s", domain.ErrInvalidRole, role, domain.RoleAdmin, domain.RoleAuditor)
+		}
+	}
+
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+	secret := apiKeyPrefix + hex.EncodeToString(buffer)
+
+	now := time.Now().UTC()
+	key := domain.APIKey{
+		ID:        newULID(now),
+		Name:      name,
+		Prefix:    secret[:apiKeyVisiblePrefix],
+		Hash:      hashAPIKey(secret),
+		Roles:     append([]string{}, roles...),
+		TenantID:  tenantID,
+		UserID:    userID,
+		CreatedAt: now,
+		CreatedBy: domain.ActorFromContext(ctx),
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		key.ExpiresAt = &expiresAt
+	}
+
+	return &IssuedAPIKey{APIKey: key, Secret: secret}, nil
+}
+
+// Revoke revoca la clave de inmediato.
+func (s *APIKeyService) Revoke(ctx context.Context, id string) error {
+	return s.repo.Revoke(id, time.Now().UTC())
+}
+
+// FindAll retorna las claves; con includeRevoked incluye las revocadas.
+func (s *APIKeyService) FindAll(ctx context.Context, includeRevoked bool) ([]domain.APIKey, error) {
+	return s.repo.FindAll(includeRevoked)
+}
+
+// Authenticate retorna el principal de la clave con el secreto indicado.
+// Retorna ErrUnauthenticated si no existe, está revocada o venció, o si está
+// vinculada a un usuario eliminado o cuya cuenta no está activa (e.g.,
+// suspendida o bloqueada).
+func (s *APIKeyService) Authenticate(ctx context.Context, secret string) (domain.Principal, error) {
+	if !strings.HasPrefix(secret, apiKeyPrefix) {
+		return domain.Principal{}, ErrUnauthenticated
+	}
+
+	key, err := s.repo.FindByHash(hashAPIKey(secret))
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return domain.Principal{}, ErrUnauthenticated
+	}
+	if err != nil {
+		return domain.Principal{}, err
+	}
+	if !key.ValidAt(time.Now()) {
+		return domain.Principal{}, ErrUnauthenticated
+	}
+
+	if key.UserID != "" {
+		user, err := s.users.ForTenant(key.TenantID).FindById(key.UserID, "id", "status")
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.Principal{}, ErrUnauthenticated
+		}
+		if err != nil {
+			return domain.Principal{}, err
+		}
+		if !user.Status.CanAuthenticate() {
+			return domain.Principal{}, fmt.Errorf("%w: user account is %s", ErrUnauthenticated, user.Status)
+		}
+	}
+
+	return domain.Principal{Subject: "apikey:" + key.Name, Roles: key.Roles, TenantID: key.TenantID}, nil
+}
+
+// hashAPIKey retorna el hash SHA-256 (hex) del secreto. Al ser secretos
+// aleatorios de 256 bits, no requieren un hash lento.
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,207 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// newTestAPIKeyService crea un APIKeyService en memoria con el usuario u1
+// de la organización acme.
+func newTestAPIKeyService() (*APIKeyService, *memoryAPIKeys, *memoryStore) {
+	store := newMemoryStore()
+	store.users["u1"] = domain.User{ID: "u1", TenantID: "acme", Status: domain.UserActive}
+	keys := &memoryAPIKeys{}
+	return NewAPIKeyService(keys, store.Users()), keys, store
+}
+
+// keyByID retorna la clave con el ID indicado del repositorio.
+func keyByID(t *testing.T, keys *memoryAPIKeys, id string) domain.APIKey {
+	t.Helper()
+	key, err := keys.FindById(id)
+	if err != nil {
+		t.Fatalf("FindById(%s): %v", id, err)
+	}
+	return *key
+}
+
+func TestIssueAndAuthenticate(t *testing.T) {
+	service, _, _ := newTestAPIKeyService()
+	ctx := context.Background()
+
+	issued, err := service.Issue(ctx, "crm", "acme", "", []string{domain.RoleAuditor}, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if issued.Prefix != issued.Secret[:apiKeyVisiblePrefix] || issued.Hash == issued.Secret || issued.ExpiresAt == nil {
+		t.Errorf("issued = %+v", issued.APIKey)
+	}
+
+	principal, err := service.Authenticate(ctx, issued.Secret)
+	if err != nil || principal.Subject != "apikey:crm" || principal.TenantID != "acme" || !principal.HasRole(domain.RoleAuditor) {
+		t.Errorf("principal = %+v, err = %v", principal, err)
+	}
+
+	for _, secret := range []string{"", "uak_unknown", "other_" + issued.Secret} {
+		if _, err := service.Authenticate(ctx, secret); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("Authenticate(%q) err = %v, want ErrUnauthenticated", secret, err)
+		}
+	}
+}
+
+func TestIssueValidatesTheKey(t *testing.T) {
+	service, _, _ := newTestAPIKeyService()
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		keyName  string
+		tenantID string
+		userID   string
+		roles    []string
+		want     error
+	}{
+		{name: "blank name", keyName: " ", want: domain.ErrValueNotNullable{Value: "name"}},
+		{name: "invalid tenant", keyName: "crm", tenantID: "Not Valid", want: domain.ErrInvalidTenantID},
+		{name: "unknown role", keyName: "crm", tenantID: "acme", roles: []string{"root"}, want: domain.ErrInvalidRole},
+		{name: "operator key without admin", keyName: "crm", roles: []string{domain.RoleAuditor}, want: domain.ErrInvalidTenantID},
+		{name: "user of another tenant", keyName: "crm", tenantID: "other", userID: "u1", want: domain.ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Issue(ctx, tt.keyName, tt.tenantID, tt.userID, tt.roles, 0); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRejectsKeysOfInactiveUsers(t *testing.T) {
+	service, _, store := newTestAPIKeyService()
+	ctx := context.Background()
+
+	issued, err := service.Issue(ctx, "personal", "acme", "u1", nil, 0)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if _, err := service.Authenticate(ctx, issued.Secret); err != nil {
+		t.Fatalf("active user: %v", err)
+	}
+
+	user := store.users["u1"]
+	user.Status = domain.UserSuspended
+	store.users["u1"] = user
+	if _, err := service.Authenticate(ctx, issued.Secret); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("suspended user: err = %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestRotateReplacesTheKeyKeepingItsAttributes(t *testing.T) {
+	service, keys, _ := newTestAPIKeyService()
+	ctx := context.Background()
+
+	current, err := service.Issue(ctx, "crm", "acme", "u1", []string{domain.RoleAdmin}, 90*24*time.Hour)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	rotated, err := service.Rotate(ctx, current.ID, time.Hour)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	if rotated.ID == current.ID || rotated.Secret == current.Secret {
+		t.Fatal("Rotate did not issue a new key")
+	}
+	if rotated.Name != "crm" || rotated.TenantID != "acme" || rotated.UserID != "u1" || len(rotated.Roles) != 1 || rotated.Roles[0] != domain.RoleAdmin {
+		t.Errorf("rotated = %+v, want the attributes of the original key", rotated.APIKey)
+	}
+	if rotated.ExpiresAt == nil || rotated.ExpiresAt.Sub(rotated.CreatedAt) != 90*24*time.Hour {
+		t.Errorf("rotated expires at %v, want the original TTL of 90 days", rotated.ExpiresAt)
+	}
+
+	previous := keyByID(t, keys, current.ID)
+	if previous.ExpiresAt == nil || time.Until(*previous.ExpiresAt) > time.Hour || !previous.ValidAt(time.Now()) {
+		t.Errorf("previous key expires at %v, want the end of the grace period", previous.ExpiresAt)
+	}
+	for _, secret := range []string{current.Secret, rotated.Secret} {
+		if _, err := service.Authenticate(ctx, secret); err != nil {
+			t.Errorf("during the grace period: %v", err)
+		}
+	}
+}
+
+func TestRotateWithoutExpirationIssuesAKeyWithoutExpiration(t *testing.T) {
+	service, keys, _ := newTestAPIKeyService()
+	ctx := context.Background()
+
+	current, _ := service.Issue(ctx, "crm", "", "", []string{domain.RoleAdmin}, 0)
+	rotated, err := service.Rotate(ctx, current.ID, 0)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	if rotated.ExpiresAt != nil {
+		t.Errorf("rotated expires at %v, want no expiration", rotated.ExpiresAt)
+	}
+	if previous := keyByID(t, keys, current.ID); previous.ValidAt(time.Now().Add(time.Second)) {
+		t.Error("with no grace period the previous key must expire immediately")
+	}
+}
+
+func TestRotateOnlyExpiresKeysOfTheSameIntegration(t *testing.T) {
+	service, keys, _ := newTestAPIKeyService()
+	ctx := context.Background()
+
+	current, _ := service.Issue(ctx, "crm", "acme", "", nil, 0)
+	sibling, _ := service.Issue(ctx, "crm", "acme", "", nil, 0)
+	otherTenant, _ := service.Issue(ctx, "crm", "globex", "", nil, 0)
+	otherUser, _ := service.Issue(ctx, "crm", "acme", "u1", nil, 0)
+	operator, _ := service.Issue(ctx, "crm", "", "", []string{domain.RoleAdmin}, 0)
+
+	if _, err := service.Rotate(ctx, current.ID, time.Hour); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	if key := keyByID(t, keys, sibling.ID); key.ExpiresAt == nil {
+		t.Error("the other key of the same integration must expire with the grace period")
+	}
+	for _, id := range []string{otherTenant.ID, otherUser.ID, operator.ID} {
+		if key := keyByID(t, keys, id); key.ExpiresAt != nil {
+			t.Errorf("key %+v of another tenant or user was expired", key)
+		}
+	}
+}
+
+func TestRotateRejectsUnknownAndInvalidKeys(t *testing.T) {
+	service, _, _ := newTestAPIKeyService()
+	ctx := context.Background()
+
+	revoked, _ := service.Issue(ctx, "crm", "", "", []string{domain.RoleAdmin}, 0)
+	if err := service.Revoke(ctx, revoked.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+
+	for _, id := range []string{"missing", revoked.ID} {
+		if _, err := service.Rotate(ctx, id, time.Hour); !errors.Is(err, domain.ErrAPIKeyNotFound) {
+			t.Errorf("Rotate(%s) err = %v, want ErrAPIKeyNotFound", id, err)
+		}
+	}
+}
+
+func TestRotateFailureLeavesTheKeysUnchanged(t *testing.T) {
+	service, keys, _ := newTestAPIKeyService()
+	ctx := context.Background()
+
+	current, _ := service.Issue(ctx, "crm", "", "", []string{domain.RoleAdmin}, 0)
+	keys.failRotate = true
+
+	if _, err := service.Rotate(ctx, current.ID, 0); err == nil {
+		t.Fatal("Rotate succeeded, want the repository error")
+	}
+	if len(keys.keys) != 1 || keys.keys[0].ExpiresAt != nil {
+		t.Errorf("keys = %+v, want only the original key, unchanged", keys.keys)
+	}
+}
@@ -0,0 +1,106 @@
+package application
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// sensitiveFields son los campos cuyo valor se enmascara en la auditoría.
+var sensitiveFields = map[string]bool{
+	"email": true,
+}
+
+// auditedFields son, en orden estable, los campos auditables de un usuario.
+// Las formas normalizadas y las marcas de auditoría se omiten por ser derivadas.
+var auditedFields = []string{"name", "username", "email", "external_id", "deleted_at", "status", "status_reason", "email_verified"}
+
+// auditSnapshot retorna los valores de los campos auditables de un usuario,
+// o nil si el usuario no existe (antes de crearlo o después de purgarlo).
+func auditSnapshot(user *domain.User) map[string]any {
+	if user == nil {
+		return nil
+	}
+
+	var deletedAt any
+	if user.DeletedAt != nil {
+		deletedAt = user.DeletedAt.UTC()
+	}
+
+	return map[string]any{
+		"name":        user.Name,
+		"username":    user.Username,
+		"email":       user.Email,
+		"external_id": user.ExternalID,
+		"deleted_at":  deletedAt,
+
+		"status":        string(user.Status),
+		"status_reason": user.StatusReason,
+
+		"email_verified": user.EmailVerified,
+	}
+}
+
+// diffUsers retorna los cambios por campo entre before y after. Cualquiera
+// puede ser nil (creación o purga); en ese caso se registran todos los
+// campos con valor del lado presente.
+func diffUsers(before, after *domain.User) []domain.FieldChange {
+	beforeValues, afterValues := auditSnapshot(before), auditSnapshot(after)
+
+	changes := make([]domain.FieldChange, 0)
+	for _, field := range auditedFields {
+		change := domain.FieldChange{Field: field, Before: beforeValues[field], After: afterValues[field]}
+		if change.Before == change.After || isEmptyValue(change.Before) && isEmptyValue(change.After) {
+			continue
+		}
+
+		if sensitiveFields[field] {
+			change.Before, change.After = maskValue(change.Before), maskValue(change.After)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// isEmptyValue indica si un valor auditado está ausente o es el valor cero
+// de su tipo; un cambio entre valores vacíos no se registra.
+func isEmptyValue(value any) bool {
+	return value == nil || reflect.ValueOf(value).IsZero()
+}
+
+// maskValue oculta un valor sensible conservando lo mínimo para reconocerlo:
+// la primera letra y, en emails, el dominio (e.g., "j***@example.com").
+func maskValue(value any) any {
+	text, ok := value.(string)
+	if !ok || text == "" {
+		return value
+	}
+
+	local, domainPart, isEmail := strings.Cut(text, "@")
+	masked := string([]rune(local)[:1]) + "***"
+	if isEmail {
+		masked += "@" + domainPart
+	}
+
+	return masked
+}
+
+// recordAudit agrega, dentro de la UnitOfWork, el registro de auditoría de
+// una mutación sobre el usuario userID con los cambios ya calculados.
+func recordAudit(ctx context.Context, uow domain.UnitOfWork, action domain.AuditAction, userID string, changes []domain.FieldChange) error {
+	t := time.Now()
+
+	return uow.Audit().Append(&domain.AuditRecord{
+		ID:        newULID(t),
+		TenantID:  domain.TenantFromContext(ctx),
+		UserID:    userID,
+		Action:    action,
+		Actor:     domain.ActorFromContext(ctx),
+		RequestID: domain.RequestIDFromContext(ctx),
+		Timestamp: t.UTC(),
+		Changes:   changes,
+	})
+}
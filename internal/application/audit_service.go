@@ -0,0 +1,54 @@
+package application
+
+import (
+	"context"
+	"user-api-restful/internal/domain"
+)
+
+const (
+	// DefaultAuditLimit es la cantidad de registros retornados si no se indica un límite.
+	DefaultAuditLimit = 100
+	// MaxAuditLimit es la cantidad máxima de registros por consulta.
+	MaxAuditLimit = 1000
+)
+
+// AuditService define el contract para consultar el registro de auditoría
+// de las mutaciones de usuarios.
+type AuditService interface {
+	// History retorna los registros de auditoría de un usuario, del más reciente al más antiguo.
+	History(ctx context.Context, userID string, limit int) ([]domain.AuditRecord, error)
+	// Find retorna los registros de auditoría que cumplen el filtro.
+	Find(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditRecord, error)
+}
+
+// AuditServiceImpl es la implementación concreta de AuditService.
+type AuditServiceImpl struct {
+	repo domain.AuditRepository
+}
+
+// NewAuditServiceImpl crea un AuditServiceImpl sobre el repositorio de auditoría indicado.
+func NewAuditServiceImpl(repo domain.AuditRepository) *AuditServiceImpl {
+	return &AuditServiceImpl{repo: repo}
+}
+
+// Asegura que AuditServiceImpl implemente la interfaz AuditService en tiempo de compilación.
+var _ AuditService = (*AuditServiceImpl)(nil)
+
+// History retorna los registros de auditoría de un usuario.
+func (a *AuditServiceImpl) History(ctx context.Context, userID string, limit int) ([]domain.AuditRecord, error) {
+	return a.Find(ctx, domain.AuditFilter{UserID: userID, Limit: limit})
+}
+
+// Find aplica los límites por defecto y consulta el registro de auditoría
+// de la organización del contexto.
+func (a *AuditServiceImpl) Find(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditRecord, error) {
+	filter.TenantID = domain.TenantFromContext(ctx)
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultAuditLimit
+	}
+	if filter.Limit > MaxAuditLimit {
+		filter.Limit = MaxAuditLimit
+	}
+
+	return a.repo.Find(filter)
+}
@@ -0,0 +1,137 @@
+package application
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+func TestDiffUsersMasksSensitiveFields(t *testing.T) {
+	before := &domain.User{Name: "Jane", Username: "jane", Email: "jane@example.com", Status: domain.UserActive}
+	after := &domain.User{Name: "Jane Doe", Username: "jane", Email: "doe@example.org", Status: domain.UserActive}
+
+	got := diffUsers(before, after)
+	want := []domain.FieldChange{
+		{Field: "name", Before: "Jane", After: "Jane Doe"},
+		{Field: "email", Before: "j***@example.com", After: "d***@example.org"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffUsers = %#v, want %#v", got, want)
+	}
+}
+
+func TestDiffUsersOnCreateAndPurge(t *testing.T) {
+	user := &domain.User{Name: "Jane", Username: "jane", Email: "jane@example.com", Status: domain.UserActive}
+
+	created := diffUsers(nil, user)
+	purged := diffUsers(user, nil)
+
+	fields := func(changes []domain.FieldChange) []string {
+		names := make([]string, len(changes))
+		for i, change := range changes {
+			names[i] = change.Field
+		}
+		return names
+	}
+	want := []string{"name", "username", "email", "status"}
+	if got := fields(created); !reflect.DeepEqual(got, want) {
+		t.Errorf("create fields = %v, want %v", got, want)
+	}
+	if got := fields(purged); !reflect.DeepEqual(got, want) {
+		t.Errorf("purge fields = %v, want %v", got, want)
+	}
+	if created[2].After != "j***@example.com" || created[2].Before != nil {
+		t.Errorf("created email change = %#v", created[2])
+	}
+}
+
+func TestMaskValue(t *testing.T) {
+	tests := []struct {
+		value any
+		want  any
+	}{
+		{"jane@example.com", "j***@example.com"},
+		{"émile@example.com", "é***@example.com"},
+		{"secret", "s***"},
+		{"", ""},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := maskValue(tt.value); got != tt.want {
+			t.Errorf("maskValue(%#v) = %#v, want %#v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMutationsAreAuditedInTheSameTransaction(t *testing.T) {
+	ctx := domain.WithRequestID(asActor("alice"), "req-1")
+	service, store := newTestUserService(HoldDeletedIdentity)
+	user := mustCreate(t, ctx, service, "jane", "jane@example.com")
+
+	// Una mutación rechazada no deja registro de auditoría ni evento.
+	if _, err := service.Create(ctx, &domain.UserCreateRequest{Name: "Other", Username: "jane", Email: "other@example.com"}); err == nil {
+		t.Fatal("duplicate Create succeeded")
+	}
+	if len(store.audit) != 1 || len(store.outbox) != 1 {
+		t.Fatalf("after rejected create: %d audit records, %d events; want 1 and 1", len(store.audit), len(store.outbox))
+	}
+
+	if _, err := service.Update(ctx, &domain.User{ID: user.ID, Name: "Jane Doe"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := service.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	actions := make([]domain.AuditAction, len(store.audit))
+	for i, record := range store.audit {
+		actions[i] = record.Action
+		if record.Actor != "alice" || record.RequestID != "req-1" || record.UserID != user.ID || record.Timestamp.IsZero() {
+			t.Errorf("record %d = %+v, want actor alice, request req-1 and user %s", i, record, user.ID)
+		}
+	}
+	want := []domain.AuditAction{domain.AuditActionCreate, domain.AuditActionUpdate, domain.AuditActionDelete}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("actions = %v, want %v", actions, want)
+	}
+
+	update := store.audit[1].Changes
+	if len(update) != 1 || update[0].Field != "name" || update[0].Before != "Test User" || update[0].After != "Jane Doe" {
+		t.Errorf("update changes = %#v", update)
+	}
+}
+
+func TestAuditServiceFind(t *testing.T) {
+	store := newMemoryStore()
+	service := NewAuditServiceImpl(store.Audit())
+	now := time.Now().UTC()
+	for i := range 3 {
+		store.audit = append(store.audit, domain.AuditRecord{ID: string(rune('a' + i)), TenantID: "acme", UserID: "u1", Actor: "alice", Timestamp: now.Add(time.Duration(i) * time.Hour)})
+	}
+	store.audit = append(store.audit, domain.AuditRecord{ID: "other", TenantID: "globex", UserID: "u1", Actor: "alice", Timestamp: now})
+
+	ctx := domain.WithTenant(context.Background(), "acme")
+
+	history, err := service.History(ctx, "u1", 0)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 3 || history[0].ID != "c" {
+		t.Errorf("History = %+v, want the 3 acme records newest first", history)
+	}
+
+	since := now.Add(90 * time.Minute)
+	records, err := service.Find(ctx, domain.AuditFilter{Actor: "alice", Since: &since, TenantID: "globex"})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(records) != 1 || records[0].ID != "c" {
+		t.Errorf("Find since = %+v, want only record c of the context tenant", records)
+	}
+
+	if records, _ := service.Find(ctx, domain.AuditFilter{Limit: 2}); len(records) != 2 {
+		t.Errorf("Find limit 2 returned %d records", len(records))
+	}
+}
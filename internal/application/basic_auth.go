@@ -0,0 +1,74 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"user-api-restful/internal/domain"
+)
+
+// ErrUnauthenticated indica que las credenciales faltan o no son válidas.
+var ErrUnauthenticated = errors.New("invalid or missing credentials")
+
+// AuthenticateBasic verifica credenciales Basic Auth contra las variables de
+// entorno BASIC_AUTH_USER y BASIC_AUTH_PASS, que corresponden al
+// administrador de la API. ok indica si la petición incluyó credenciales.
+// Si las variables no están seteadas, Basic Auth queda deshabilitado (solo se
+// aceptan claves de API), salvo que AUTH_DISABLED=true habilite de forma
+// explícita el acceso anónimo de administrador (solo para desarrollo). Lo
+// comparten los adaptadores HTTP y gRPC.
+func AuthenticateBasic(user, pass string, ok bool) (domain.Principal, error) {
+	userEnv := os.Getenv("BASIC_AUTH_USER")
+	passEnv := os.Getenv("BASIC_AUTH_PASS")
+
+	principal := domain.Principal{Subject: "anonymous", Roles: []string{domain.RoleAdmin}}
+
+	if userEnv == "" || passEnv == "" {
+		if disabled, _ := strconv.ParseBool(os.Getenv("AUTH_DISABLED")); disabled {
+			return principal, nil
+		}
+		return domain.Principal{}, ErrUnauthenticated
+	}
+
+	// Verifica que las credenciales coincidan con las variables de entorno.
+	if !ok || user != userEnv || pass != passEnv {
+		return domain.Principal{}, ErrUnauthenticated
+	}
+	principal.Subject = user
+
+	return principal, nil
+}
+
+// AuthenticateRequest verifica el valor de la cabecera Authorization de una
+// petición: "Bearer <clave de API>" (ver APIKeyService) o Basic Auth (ver
+// AuthenticateBasic). apiKeys puede ser nil, en cuyo caso solo se acepta
+// Basic Auth. Lo comparten los adaptadores HTTP y gRPC.
+func AuthenticateRequest(ctx context.Context, apiKeys *APIKeyService, authorization string) (domain.Principal, error) {
+	scheme, credentials, _ := strings.Cut(authorization, " ")
+	if strings.EqualFold(scheme, "Bearer") {
+		if apiKeys == nil {
+			return domain.Principal{}, ErrUnauthenticated
+		}
+		return apiKeys.Authenticate(ctx, strings.TrimSpace(credentials))
+	}
+
+	user, pass, ok := parseBasicAuth(scheme, credentials)
+	return AuthenticateBasic(user, pass, ok)
+}
+
+// parseBasicAuth extrae usuario y contraseña de las credenciales Basic (base64).
+func parseBasicAuth(scheme, encoded string) (user, pass string, ok bool) {
+	if !strings.EqualFold(scheme, "Basic") {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", false
+	}
+
+	return strings.Cut(string(decoded), ":")
+}
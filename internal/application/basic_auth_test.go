@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"user-api-restful/internal/domain"
+)
+
+func TestAuthenticateBasic(t *testing.T) {
+	t.Setenv("BASIC_AUTH_USER", "admin")
+	t.Setenv("BASIC_AUTH_PASS", "secret")
+
+	principal, err := AuthenticateBasic("admin", "secret", true)
+	if err != nil || principal.Subject != "admin" || !principal.HasRole(domain.RoleAdmin) {
+		t.Errorf("valid credentials: principal = %+v, err = %v", principal, err)
+	}
+
+	for _, test := range []struct {
+		name, user, pass string
+		ok               bool
+	}{
+		{"wrong password", "admin", "nope", true},
+		{"missing credentials", "", "", false},
+	} {
+		if _, err := AuthenticateBasic(test.user, test.pass, test.ok); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("%s: err = %v, want ErrUnauthenticated", test.name, err)
+		}
+	}
+}
+
+func TestAuthenticateBasicWithoutCredentialsConfigured(t *testing.T) {
+	t.Setenv("BASIC_AUTH_USER", "")
+	t.Setenv("BASIC_AUTH_PASS", "")
+	t.Setenv("AUTH_DISABLED", "")
+
+	if principal, err := AuthenticateBasic("", "", false); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("principal = %+v, err = %v; anonymous access must be refused", principal, err)
+	}
+
+	t.Setenv("AUTH_DISABLED", "true")
+	principal, err := AuthenticateBasic("", "", false)
+	if err != nil || principal.Subject != "anonymous" || !principal.HasRole(domain.RoleAdmin) {
+		t.Errorf("AUTH_DISABLED: principal = %+v, err = %v", principal, err)
+	}
+}
+
+func TestAuthenticateRequestParsesBasicHeader(t *testing.T) {
+	t.Setenv("BASIC_AUTH_USER", "admin")
+	t.Setenv("BASIC_AUTH_PASS", "s3:cret")
+
+	header := "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:s3:cret"))
+	if _, err := AuthenticateRequest(context.Background(), nil, header); err != nil {
+		t.Errorf("password with a colon: %v", err)
+	}
+
+	if _, err := AuthenticateRequest(context.Background(), nil, "Bearer uak_whatever"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("bearer without API keys: err = %v, want ErrUnauthenticated", err)
+	}
+}
@@ -0,0 +1,89 @@
+package application
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// defaultEmailTemplates son las plantillas incluidas en el binario.
+//
+//go:embed templates/*.tmpl
+var defaultEmailTemplates embed.FS
+
+// EmailTemplates son las plantillas de los mensajes de correo: para cada
+// mensaje, el asunto y el cuerpo en texto (text/template) y en HTML
+// (html/template, que escapa los datos del usuario).
+type EmailTemplates struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// VerificationEmailData son los datos disponibles en las plantillas
+// verify_email.*.tmpl.
+type VerificationEmailData struct {
+	Name     string
+	Username string
+	Email    string
+	// Link es la URL de confirmación (vacía si no se configuró
+	// EMAIL_VERIFICATION_URL); Token es el valor a enviar a
+	// POST /users/{id}/verify-email.
+	Link      string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// NewEmailTemplates carga las plantillas verify_email.subject.tmpl,
+// verify_email.txt.tmpl y verify_email.html.tmpl del directorio indicado o,
+// si es vacío, las incluidas en el binario.
+func NewEmailTemplates(dir string) (*EmailTemplates, error) {
+	var fsys fs.FS = defaultEmailTemplates
+	prefix := "templates/"
+	if dir != "" {
+		fsys, prefix = os.DirFS(dir), ""
+	}
+
+	subject, err := texttemplate.ParseFS(fsys, prefix+"verify_email.subject.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	text, err := texttemplate.ParseFS(fsys, prefix+"verify_email.txt.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.ParseFS(fsys, prefix+"verify_email.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailTemplates{subject: subject, text: text, html: html}, nil
+}
+
+// verificationEmail compone el mensaje de verificación dirigido a to.
+func (t *EmailTemplates) verificationEmail(to string, data VerificationEmailData) (*domain.EmailMessage, error) {
+	var subject, text, html bytes.Buffer
+
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return nil, err
+	}
+	if err := t.text.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := t.html.Execute(&html, data); err != nil {
+		return nil, err
+	}
+
+	return &domain.EmailMessage{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,253 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// verificationTokenPrefix distingue los tokens de verificación de email de
+// otras credenciales.
+const verificationTokenPrefix = "uev_"
+
+// EmailVerificationService emite los tokens de verificación de email, envía
+// los mensajes de verificación y confirma los tokens recibidos.
+//
+// Es un domain.EventPublisher: por cada usuario creado o cuyo email cambió
+// (según los eventos del outbox, de modo que cubre la API, los lotes, las
+// importaciones, SCIM y userctl) emite un token y envía el mensaje.
+type EmailVerificationService struct {
+	txPort    domain.UserTransactionPort
+	mailer    domain.Mailer
+	templates *EmailTemplates
+	tokenTTL  time.Duration
+	// confirmURL es la página que recibe el token (e.g., del frontend); el
+	// enlace agrega user_id, tenant_id y token. Vacío = el mensaje incluye
+	// solo el token.
+	confirmURL string
+}
+
+// NewEmailVerificationService crea un EmailVerificationService cuyos tokens
+// vencen tras tokenTTL.
+func NewEmailVerificationService(tx domain.UserTransactionPort, mailer domain.Mailer, templates *EmailTemplates, tokenTTL time.Duration, confirmURL string) *EmailVerificationService {
+	return &EmailVerificationService{txPort: tx, mailer: mailer, templates: templates, tokenTTL: tokenTTL, confirmURL: confirmURL}
+}
+
+// Asegura que EmailVerificationService implemente domain.EventPublisher.
+var _ domain.EventPublisher = (*EmailVerificationService)(nil)
+
+// Publish emite un token y envía el mensaje de verificación si el evento
+// creó un usuario o cambió su email. Los eventos ya atendidos (el relay
+// puede reintentarlos), los de usuarios eliminados o ya verificados y los
+// que quedaron desactualizados por un cambio de email posterior se omiten.
+// Un fallo del envío solo se registra en el log, para no demorar la
+// publicación de los demás eventos: el usuario puede pedir el reenvío.
+func (s *EmailVerificationService) Publish(ctx context.Context, event domain.UserEvent) error {
+	if !requiresVerification(event) {
+		return nil
+	}
+
+	ctx = domain.WithTenant(ctx, event.Tenant())
+	message, err := s.issue(ctx, event.UserID, event.ID, event.User.Email)
+	if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrEmailAlreadyVerified) {
+		return nil
+	}
+	if err != nil || message == nil {
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, *message); err != nil {
+		log.Printf("[EmailVerification] sending the verification email of user %s failed: %v", event.UserID, err)
+	}
+
+	return nil
+}
+
+// requiresVerification indica si el evento creó un usuario o cambió su email.
+func requiresVerification(event domain.UserEvent) bool {
+	switch event.Type {
+	case domain.EventUserCreated:
+		return event.User != nil
+	case domain.EventUserUpdated:
+		return event.User != nil && slices.Contains(event.ChangedFields, "email")
+	default:
+		return false
+	}
+}
+
+// Resend emite un nuevo token para el email actual del usuario (los
+// anteriores dejan de ser válidos) y envía el mensaje de verificación.
+// Retorna ErrUserNotFound, ErrEmailAlreadyVerified o ErrEmailDeliveryFailed.
+func (s *EmailVerificationService) Resend(ctx context.Context, id string) error {
+	message, err := s.issue(ctx, id, "", "")
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, *message); err != nil {
+		log.Printf("[EmailVerification] sending the verification email of user %s failed: %v", id, err)
+		return domain.ErrEmailDeliveryFailed
+	}
+
+	return nil
+}
+
+// issue emite, dentro de una transacción, un token para el email actual del
+// usuario y retorna el mensaje a enviar. Los tokens vigentes anteriores
+// vencen. Si se indica eventID y ya se emitió un token para ese evento, o si
+// se indica email y el usuario ya no lo tiene, retorna nil sin emitir.
+func (s *EmailVerificationService) issue(ctx context.Context, userID, eventID, email string) (*domain.EmailMessage, error) {
+	var message *domain.EmailMessage
+
+	err := s.txPort.Execute(func(uow domain.UnitOfWork) error {
+		// 1. Verificación del usuario y del evento
+		user, err := usersOf(ctx, uow).FindById(userID)
+		if err != nil {
+			return err
+		}
+		if user.EmailVerified {
+			return domain.ErrEmailAlreadyVerified
+		}
+		if email != "" && user.Email != email {
+			return nil
+		}
+		if eventID != "" {
+			issued, err := uow.EmailVerifications().ExistsForEvent(eventID)
+			if err != nil || issued {
+				return err
+			}
+		}
+
+		// 2. Generación del token; solo se persiste su hash
+		buffer := make([]byte, 32)
+		if _, err := rand.Read(buffer); err != nil {
+			return domain.ErrInternalServer{Value: err.Error()}
+		}
+		secret := verificationTokenPrefix + hex.EncodeToString(buffer)
+
+		now := time.Now().UTC()
+		token := &domain.EmailVerificationToken{
+			ID:        newULID(now),
+			TenantID:  domain.TenantFromContext(ctx),
+			UserID:    user.ID,
+			Email:     user.Email,
+			Hash:      hashVerificationToken(secret),
+			EventID:   eventID,
+			CreatedAt: now,
+			ExpiresAt: now.Add(s.tokenTTL),
+		}
+		if err := uow.EmailVerifications().ExpirePending(user.ID, now); err != nil {
+			return err
+		}
+		if err := uow.EmailVerifications().Create(token); err != nil {
+			return err
+		}
+
+		// 3. Composición del mensaje
+		message, err = s.templates.verificationEmail(user.Email, VerificationEmailData{
+			Name:      user.Name,
+			Username:  user.Username,
+			Email:     user.Email,
+			Link:      s.confirmLink(token, secret),
+			Token:     secret,
+			ExpiresAt: token.ExpiresAt,
+		})
+		if err != nil {
+			return domain.ErrInternalServer{Value: err.Error()}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// confirmLink retorna la URL de confirmación del token, o vacío si no se
+// configuró la página de confirmación.
+func (s *EmailVerificationService) confirmLink(token *domain.EmailVerificationToken, secret string) string {
+	if s.confirmURL == "" {
+		return ""
+	}
+
+	query := url.Values{}
+	query.Set("user_id", token.UserID)
+	query.Set("tenant_id", token.TenantID)
+	query.Set("token", secret)
+
+	separator := "?"
+	if strings.Contains(s.confirmURL, "?") {
+		separator = "&"
+	}
+	return s.confirmURL + separator + query.Encode()
+}
+
+// Verify confirma el email del usuario con un token recibido por correo: lo
+// marca como usado y registra la verificación en el usuario, la auditoría y
+// el evento UserUpdated, en una transacción. Retorna ErrUserNotFound,
+// ErrEmailAlreadyVerified o ErrInvalidVerificationToken (también si el token
+// es de otro usuario o de una dirección que el usuario ya no tiene).
+func (s *EmailVerificationService) Verify(ctx context.Context, id, token string) (*domain.User, error) {
+	var verifiedUser *domain.User
+	now := time.Now().UTC()
+
+	err := s.txPort.Execute(func(uow domain.UnitOfWork) error {
+		// 1. Lectura y validación del token y del usuario
+		stored, err := uow.EmailVerifications().FindByHash(hashVerificationToken(token))
+		if err != nil {
+			return err
+		}
+		if stored.UserID != id || stored.TenantID != domain.TenantFromContext(ctx) || !stored.ValidAt(now) {
+			return domain.ErrInvalidVerificationToken
+		}
+
+		before, err := usersOf(ctx, uow).FindById(id)
+		if err != nil {
+			return err
+		}
+		if before.EmailVerified {
+			return domain.ErrEmailAlreadyVerified
+		}
+		if before.Email != stored.Email {
+			return domain.ErrInvalidVerificationToken
+		}
+
+		// 2. Uso del token (falla si otra petición lo usó antes)
+		if err := uow.EmailVerifications().Consume(stored.ID, now); err != nil {
+			return err
+		}
+
+		// 3. Registro de la verificación
+		after := *before
+		after.EmailVerified, after.EmailVerifiedAt = true, &now
+		touch(ctx, &after)
+		if err := usersOf(ctx, uow).UpdateEmailVerification(&after); err != nil {
+			return err
+		}
+
+		verifiedUser = &after
+		return recordChange(ctx, uow, domain.AuditActionEmailVerify, id, before, &after)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return verifiedUser, nil
+}
+
+// hashVerificationToken retorna el hash SHA-256 (hex) del token.
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,393 @@
+package application
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/messaging"
+)
+
+// verificationToken extrae el token de verificación de un mensaje.
+var verificationToken = regexp.MustCompile(`uev_[0-9a-f]{64}`)
+
+// receivedMail es un mensaje recibido por el servidor SMTP de las pruebas.
+type receivedMail struct {
+	from, to string
+	data     []byte
+}
+
+// startSMTPServer levanta un servidor SMTP mínimo (sin TLS ni autenticación)
+// en 127.0.0.1 y retorna su host, su puerto y los mensajes aceptados. Con
+// rejectRecipients responde 550 a cada RCPT TO.
+func startSMTPServer(t *testing.T, rejectRecipients bool) (string, string, <-chan receivedMail) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan receivedMail, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, rejectRecipients, messages)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return host, port, messages
+}
+
+// serveSMTP atiende una sesión SMTP y entrega el mensaje antes de confirmar DATA.
+func serveSMTP(conn net.Conn, rejectRecipients bool, messages chan<- receivedMail) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost test SMTP")
+
+	var received receivedMail
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command, argument, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 localhost")
+		case "MAIL":
+			received.from = strings.Trim(strings.TrimPrefix(argument, "FROM:"), "<>")
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			if rejectRecipients {
+				text.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			received.to = strings.Trim(strings.TrimPrefix(argument, "TO:"), "<>")
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+			if received.data, err = io.ReadAll(text.DotReader()); err != nil {
+				return
+			}
+			messages <- received
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+// parsedMail es un mensaje recibido con su asunto decodificado y sus partes
+// de texto y HTML.
+type parsedMail struct {
+	subject, text, html string
+}
+
+// parseMail decodifica un mensaje multipart/alternative.
+func parseMail(t *testing.T, data []byte) parsedMail {
+	t.Helper()
+	message, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+
+	var parsed parsedMail
+	if parsed.subject, err = new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject")); err != nil {
+		t.Fatalf("subject: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", message.Header.Get("Content-Type"))
+	}
+
+	// NextPart decodifica el quoted-printable de cada parte.
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+		body, _ := io.ReadAll(part)
+		switch {
+		case strings.HasPrefix(part.Header.Get("Content-Type"), "text/plain"):
+			parsed.text = string(body)
+		case strings.HasPrefix(part.Header.Get("Content-Type"), "text/html"):
+			parsed.html = string(body)
+		}
+	}
+	return parsed
+}
+
+// newTestVerificationService crea un EmailVerificationService con las
+// plantillas incluidas en el binario sobre el almacenamiento de users.
+func newTestVerificationService(t *testing.T, store *memoryStore, mailer domain.Mailer, ttl time.Duration, confirmURL string) *EmailVerificationService {
+	t.Helper()
+	templates, err := NewEmailTemplates("")
+	if err != nil {
+		t.Fatalf("NewEmailTemplates: %v", err)
+	}
+	return NewEmailVerificationService(store, mailer, templates, ttl, confirmURL)
+}
+
+// recordingMailer registra los mensajes enviados.
+type recordingMailer struct {
+	messages []domain.EmailMessage
+}
+
+func (m *recordingMailer) Send(_ context.Context, message domain.EmailMessage) error {
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+// lastToken retorna el token del último mensaje enviado.
+func (m *recordingMailer) lastToken(t *testing.T) string {
+	t.Helper()
+	if len(m.messages) == 0 {
+		t.Fatal("no message was sent")
+	}
+	token := verificationToken.FindString(m.messages[len(m.messages)-1].Text)
+	if token == "" {
+		t.Fatalf("no token in %q", m.messages[len(m.messages)-1].Text)
+	}
+	return token
+}
+
+func TestVerificationEmailOverSMTPHasASingleUseToken(t *testing.T) {
+	ctx := context.Background()
+	host, port, messages := startSMTPServer(t, false)
+	users, store := newTestUserService(HoldDeletedIdentity)
+	mailer := messaging.NewSMTPMailer(host, port, "", "", "accounts@example.com", 5*time.Second)
+	service := newTestVerificationService(t, store, mailer, time.Hour, "https://app.example.com/verify")
+
+	user, err := users.Create(ctx, &domain.UserCreateRequest{Name: "Jane Doe", Username: "jane", Email: "jane@example.com"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	created := store.events()[0]
+	if err := service.Publish(ctx, created); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	// 1. Mensaje entregado con las plantillas
+	var received receivedMail
+	select {
+	case received = <-messages:
+	default:
+		t.Fatal("no message reached the SMTP server")
+	}
+	if received.from != "accounts@example.com" || received.to != "jane@example.com" {
+		t.Errorf("envelope = %s → %s", received.from, received.to)
+	}
+	message := parseMail(t, received.data)
+	if message.subject != "Verify your email address" {
+		t.Errorf("subject = %q", message.subject)
+	}
+	token := verificationToken.FindString(message.text)
+	if token == "" {
+		t.Fatalf("no token in the text part: %q", message.text)
+	}
+	for _, want := range []string{"Hi Jane Doe,", "jane@example.com is the email address of your account (jane)", "https://app.example.com/verify?", "user_id=" + user.ID, "tenant_id=default", "can be used once"} {
+		if !strings.Contains(message.text, want) {
+			t.Errorf("text part does not contain %q:\n%s", want, message.text)
+		}
+	}
+	if !strings.Contains(message.html, "<strong>jane@example.com</strong>") || !strings.Contains(message.html, "token="+token) {
+		t.Errorf("html part = %s, want the email and the link with the token", message.html)
+	}
+
+	// 2. Solo se guarda el hash del token
+	for _, stored := range store.tokens {
+		if stored.Hash == token || stored.Hash != hashVerificationToken(token) || stored.EventID != created.ID {
+			t.Errorf("stored token = %+v, want only the hash of the sent token", stored)
+		}
+	}
+
+	// 3. El token se usa una sola vez
+	verified, err := service.Verify(ctx, user.ID, token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !verified.EmailVerified || verified.EmailVerifiedAt == nil || !store.users[user.ID].EmailVerified {
+		t.Errorf("verified = %+v, want the email verified", verified)
+	}
+	if _, err := service.Verify(ctx, user.ID, token); !errors.Is(err, domain.ErrInvalidVerificationToken) {
+		t.Errorf("second Verify: err = %v, want ErrInvalidVerificationToken", err)
+	}
+
+	// 4. Un evento reintentado no envía otro mensaje
+	if err := service.Publish(ctx, created); err != nil {
+		t.Fatalf("second Publish: %v", err)
+	}
+	if len(messages) != 0 {
+		t.Errorf("a replayed event sent %d more messages", len(messages))
+	}
+}
+
+func TestResendReportsSMTPRejections(t *testing.T) {
+	ctx := context.Background()
+	host, port, _ := startSMTPServer(t, true)
+	users, store := newTestUserService(HoldDeletedIdentity)
+	mailer := messaging.NewSMTPMailer(host, port, "", "", "accounts@example.com", 5*time.Second)
+	service := newTestVerificationService(t, store, mailer, time.Hour, "")
+	user := mustCreate(t, ctx, users, "jane", "jane@example.com")
+
+	if err := service.Resend(ctx, user.ID); !errors.Is(err, domain.ErrEmailDeliveryFailed) {
+		t.Errorf("Resend: err = %v, want ErrEmailDeliveryFailed", err)
+	}
+	// El relay no reintenta un envío fallido
+	if err := service.Publish(ctx, store.events()[0]); err != nil {
+		t.Errorf("Publish: err = %v, want nil", err)
+	}
+}
+
+func TestVerifyRejectsInvalidTokens(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// prepare retorna el usuario y el token a verificar.
+		prepare func(t *testing.T, users *UserServiceImpl, service *EmailVerificationService, mailer *recordingMailer) (string, string)
+	}{
+		{name: "unknown token", prepare: func(t *testing.T, users *UserServiceImpl, service *EmailVerificationService, mailer *recordingMailer) (string, string) {
+			user := mustCreate(t, ctx, users, "jane", "jane@example.com")
+			return user.ID, "uev_" + strings.Repeat("0", 64)
+		}},
+		{name: "token of another user", prepare: func(t *testing.T, users *UserServiceImpl, service *EmailVerificationService, mailer *recordingMailer) (string, string) {
+			jane := mustCreate(t, ctx, users, "jane", "jane@example.com")
+			john := mustCreate(t, ctx, users, "john", "john@example.com")
+			if err := service.Resend(ctx, jane.ID); err != nil {
+				t.Fatalf("Resend: %v", err)
+			}
+			return john.ID, mailer.lastToken(t)
+		}},
+		{name: "superseded by a newer token", prepare: func(t *testing.T, users *UserServiceImpl, service *EmailVerificationService, mailer *recordingMailer) (string, string) {
+			user := mustCreate(t, ctx, users, "jane", "jane@example.com")
+			if err := service.Resend(ctx, user.ID); err != nil {
+				t.Fatalf("Resend: %v", err)
+			}
+			first := mailer.lastToken(t)
+			if err := service.Resend(ctx, user.ID); err != nil {
+				t.Fatalf("Resend: %v", err)
+			}
+			return user.ID, first
+		}},
+		{name: "issued for a previous email", prepare: func(t *testing.T, users *UserServiceImpl, service *EmailVerificationService, mailer *recordingMailer) (string, string) {
+			user := mustCreate(t, ctx, users, "jane", "jane@example.com")
+			if err := service.Resend(ctx, user.ID); err != nil {
+				t.Fatalf("Resend: %v", err)
+			}
+			update := *user
+			update.Email = "jane@new.example.com"
+			if _, err := users.Update(ctx, &update); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			return user.ID, mailer.lastToken(t)
+		}},
+		{name: "of another tenant", prepare: func(t *testing.T, users *UserServiceImpl, service *EmailVerificationService, mailer *recordingMailer) (string, string) {
+			acme := domain.WithTenant(ctx, "acme")
+			user := mustCreate(t, acme, users, "jane", "jane@example.com")
+			if err := service.Resend(acme, user.ID); err != nil {
+				t.Fatalf("Resend: %v", err)
+			}
+			return user.ID, mailer.lastToken(t)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, store := newTestUserService(HoldDeletedIdentity)
+			mailer := &recordingMailer{}
+			service := newTestVerificationService(t, store, mailer, time.Hour, "")
+
+			userID, token := tt.prepare(t, users, service, mailer)
+
+			if _, err := service.Verify(ctx, userID, token); !errors.Is(err, domain.ErrInvalidVerificationToken) {
+				t.Errorf("Verify: err = %v, want ErrInvalidVerificationToken", err)
+			}
+			if store.users[userID].EmailVerified {
+				t.Error("the email was verified")
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsExpiredTokens(t *testing.T) {
+	ctx := context.Background()
+	users, store := newTestUserService(HoldDeletedIdentity)
+	mailer := &recordingMailer{}
+	service := newTestVerificationService(t, store, mailer, -time.Minute, "")
+	user := mustCreate(t, ctx, users, "jane", "jane@example.com")
+
+	if err := service.Resend(ctx, user.ID); err != nil {
+		t.Fatalf("Resend: %v", err)
+	}
+	if _, err := service.Verify(ctx, user.ID, mailer.lastToken(t)); !errors.Is(err, domain.ErrInvalidVerificationToken) {
+		t.Errorf("Verify: err = %v, want ErrInvalidVerificationToken", err)
+	}
+}
+
+func TestVerificationIsIssuedOnlyWhenNeeded(t *testing.T) {
+	ctx := context.Background()
+	users, store := newTestUserService(HoldDeletedIdentity)
+	mailer := &recordingMailer{}
+	service := newTestVerificationService(t, store, mailer, time.Hour, "")
+	user := mustCreate(t, ctx, users, "jane", "jane@example.com")
+
+	// Un cambio que no afecta al email no envía el mensaje
+	update := *user
+	update.Name = "Jane Doe"
+	if _, err := users.Update(ctx, &update); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	events := store.events()
+	if err := service.Publish(ctx, events[len(events)-1]); err != nil || len(mailer.messages) != 0 {
+		t.Fatalf("Publish of a name change = %v, sent %d messages; want none", err, len(mailer.messages))
+	}
+
+	// Verificado el email, no se reenvía
+	if err := service.Publish(ctx, events[0]); err != nil || len(mailer.messages) != 1 {
+		t.Fatalf("Publish of the creation = %v, sent %d messages; want one", err, len(mailer.messages))
+	}
+	if _, err := service.Verify(ctx, user.ID, mailer.lastToken(t)); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if err := service.Resend(ctx, user.ID); !errors.Is(err, domain.ErrEmailAlreadyVerified) {
+		t.Errorf("Resend of a verified email: err = %v, want ErrEmailAlreadyVerified", err)
+	}
+
+	// Cambiar el email lo vuelve a marcar como no verificado y envía otro mensaje
+	update = store.users[user.ID]
+	update.Email = "jane@new.example.com"
+	if _, err := users.Update(ctx, &update); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	events = store.events()
+	if err := service.Publish(ctx, events[len(events)-1]); err != nil || len(mailer.messages) != 2 || mailer.messages[1].To != "jane@new.example.com" {
+		t.Fatalf("Publish of the email change = %v, messages = %+v; want one to the new address", err, mailer.messages)
+	}
+	if store.users[user.ID].EmailVerified {
+		t.Error("the new email is verified before confirming it")
+	}
+}
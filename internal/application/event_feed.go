@@ -0,0 +1,113 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// eventReplayPageSize es la cantidad de eventos leídos del registro por consulta.
+const eventReplayPageSize = 500
+
+// ErrEventStreamLagged indica que el suscriptor no consumió los eventos a
+// tiempo y fue desconectado; debe reanudar desde el último evento recibido.
+var ErrEventStreamLagged = errors.New("event stream lagged behind")
+
+// EventFeed combina el registro de eventos publicados (outbox) con el stream
+// en vivo para ofrecer suscripciones reanudables a los cambios de usuarios.
+type EventFeed struct {
+	outbox domain.OutboxRepository
+	stream domain.EventStream
+}
+
+// NewEventFeed crea un EventFeed sobre el registro y el stream indicados.
+func NewEventFeed(outbox domain.OutboxRepository, stream domain.EventStream) *EventFeed {
+	return &EventFeed{outbox: outbox, stream: stream}
+}
+
+// Follow entrega a onEvent, en orden y sin duplicados, los eventos de la
+// organización del contexto con PublishedSequence mayor a afterPublished que
+// coincidan con types (vacío = todos):
+// primero los retenidos en el registro y luego los publicados en vivo. Cada
+// heartbeat sin eventos invoca onHeartbeat. Retorna cuando el contexto se
+// cancela, cuando un callback falla o con ErrEventStreamLagged.
+func (f *EventFeed) Follow(ctx context.Context, afterPublished int64, types []domain.EventType, heartbeat time.Duration, onEvent func(domain.UserEvent) error, onHeartbeat func() error) error {
+	// Se suscribe antes de leer el registro para no perder eventos publicados
+	// durante la lectura; los duplicados se descartan por PublishedSequence.
+	// No se usa Sequence: los eventos se publican en el orden en que sus
+	// transacciones confirman, que puede diferir del de Sequence.
+	tenantID := domain.TenantFromContext(ctx)
+	live, cancel := f.stream.Subscribe(tenantID)
+	defer cancel()
+
+	last := afterPublished
+	for {
+		events, err := f.outbox.FindPublished(tenantID, last, types, eventReplayPageSize)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			last = event.PublishedSequence
+			if err := onEvent(event); err != nil {
+				return err
+			}
+		}
+		if len(events) < eventReplayPageSize {
+			break
+		}
+	}
+
+	accepts := eventTypeFilter(types)
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-live:
+			if !ok {
+				return ErrEventStreamLagged
+			}
+			if event.PublishedSequence <= last {
+				continue
+			}
+			last = event.PublishedSequence
+			if !accepts(event.Type) {
+				continue
+			}
+			if err := onEvent(event); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := onHeartbeat(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ParseEventTypes valida una lista de tipos de evento.
+func ParseEventTypes(names []string) ([]domain.EventType, error) {
+	types := make([]domain.EventType, 0, len(names))
+	for _, name := range names {
+		types = append(types, domain.EventType(name))
+	}
+	if err := validateEventTypes(types); err != nil {
+		return nil, err
+	}
+	return normalizeEventTypes(types), nil
+}
+
+// eventTypeFilter retorna un predicado que acepta los tipos indicados (vacío = todos).
+func eventTypeFilter(types []domain.EventType) func(domain.EventType) bool {
+	if len(types) == 0 {
+		return func(domain.EventType) bool { return true }
+	}
+	accepted := map[domain.EventType]bool{}
+	for _, eventType := range types {
+		accepted[eventType] = true
+	}
+	return func(eventType domain.EventType) bool { return accepted[eventType] }
+}
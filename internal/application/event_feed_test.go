@@ -0,0 +1,138 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// memoryStream implementa domain.EventStream con un único canal, que
+// registra la organización de la suscripción.
+type memoryStream struct {
+	events   chan domain.UserEvent
+	tenantID string
+}
+
+func (s *memoryStream) Subscribe(tenantID string) (<-chan domain.UserEvent, func()) {
+	s.tenantID = tenantID
+	return s.events, func() {}
+}
+
+// publishAll agrega los eventos al outbox y los publica como el relay.
+func publishAll(store *memoryStore, events ...domain.UserEvent) {
+	for _, event := range events {
+		_ = store.Outbox().Append(&event)
+		_, _ = store.Outbox().AssignPublishedSequence(event.Sequence)
+		_ = store.Outbox().MarkPublished(event.Sequence)
+	}
+}
+
+func TestEventFeedReplaysTheTenantLogThenFollowsLiveEvents(t *testing.T) {
+	store := newMemoryStore()
+	publishAll(store,
+		domain.UserEvent{ID: "1", Type: domain.EventUserCreated, TenantID: "acme"},
+		domain.UserEvent{ID: "2", Type: domain.EventUserCreated, TenantID: "globex"},
+		domain.UserEvent{ID: "3", Type: domain.EventUserUpdated, TenantID: "acme"},
+		domain.UserEvent{ID: "4", Type: domain.EventUserDeleted, TenantID: "acme"},
+	)
+	// El evento pendiente no forma parte del registro.
+	_ = store.Outbox().Append(&domain.UserEvent{ID: "5", Type: domain.EventUserCreated, TenantID: "acme"})
+
+	stream := &memoryStream{events: make(chan domain.UserEvent, 4)}
+	// El stream en vivo repite el último evento del registro (ya entregado).
+	stream.events <- domain.UserEvent{ID: "4", PublishedSequence: 4, Type: domain.EventUserDeleted, TenantID: "acme"}
+	stream.events <- domain.UserEvent{ID: "6", PublishedSequence: 6, Type: domain.EventUserUpdated, TenantID: "acme"}
+	stream.events <- domain.UserEvent{ID: "7", PublishedSequence: 7, Type: domain.EventUserCreated, TenantID: "acme"}
+
+	ctx, cancel := context.WithCancel(domain.WithTenant(context.Background(), "acme"))
+	defer cancel()
+
+	var received []string
+	onEvent := func(event domain.UserEvent) error {
+		received = append(received, event.ID)
+		if event.ID == "7" {
+			cancel()
+		}
+		return nil
+	}
+
+	feed := NewEventFeed(store.Outbox(), stream)
+	err := feed.Follow(ctx, 1, []domain.EventType{domain.EventUserUpdated, domain.EventUserCreated}, time.Hour, onEvent, func() error { return nil })
+	if err != nil {
+		t.Fatalf("Follow: %v", err)
+	}
+
+	if stream.tenantID != "acme" {
+		t.Errorf("subscribed to tenant %q, want acme", stream.tenantID)
+	}
+	if want := []string{"3", "6", "7"}; !reflect.DeepEqual(received, want) {
+		t.Errorf("received %v, want %v", received, want)
+	}
+}
+
+func TestEventFeedResumesAfterEventsCommittedOutOfOrder(t *testing.T) {
+	store := newMemoryStore()
+	_ = store.Outbox().Append(&domain.UserEvent{ID: "early", Type: domain.EventUserCreated})
+	_ = store.Outbox().Append(&domain.UserEvent{ID: "late", Type: domain.EventUserCreated})
+
+	// La transacción del evento con Sequence 1 confirma después que la del 2.
+	uncommitted := store.outbox[0]
+	store.outbox = slices.Clone(store.outbox[1:])
+	relay := NewOutboxRelay(store.Outbox(), &recordingPublisher{}, 10, time.Hour, time.Minute)
+	relay.relayBatch(context.Background())
+
+	// follow reproduce el registro posterior a after y retorna los eventos y
+	// el último id recibido, como un cliente que luego se desconecta.
+	feed := NewEventFeed(store.Outbox(), &memoryStream{events: make(chan domain.UserEvent)})
+	follow := func(after int64) ([]string, int64) {
+		ctx, cancel := context.WithCancel(domain.WithTenant(context.Background(), domain.DefaultTenantID))
+		cancel()
+		var received []string
+		onEvent := func(event domain.UserEvent) error {
+			received = append(received, event.ID)
+			after = event.PublishedSequence
+			return nil
+		}
+		if err := feed.Follow(ctx, after, nil, time.Hour, onEvent, func() error { return nil }); err != nil {
+			t.Fatalf("Follow: %v", err)
+		}
+		return received, after
+	}
+
+	received, last := follow(0)
+	if !reflect.DeepEqual(received, []string{"late"}) {
+		t.Fatalf("first connection received %v, want [late]", received)
+	}
+
+	store.outbox = append([]outboxEntry{uncommitted}, store.outbox...)
+	relay.relayBatch(context.Background())
+
+	if received, _ := follow(last); !reflect.DeepEqual(received, []string{"early"}) {
+		t.Errorf("resumed after %d and received %v, want [early]", last, received)
+	}
+}
+
+func TestEventFeedReportsLaggedSubscribers(t *testing.T) {
+	stream := &memoryStream{events: make(chan domain.UserEvent)}
+	close(stream.events)
+
+	feed := NewEventFeed(newMemoryStore().Outbox(), stream)
+	err := feed.Follow(context.Background(), 0, nil, time.Hour, func(domain.UserEvent) error { return nil }, func() error { return nil })
+	if !errors.Is(err, ErrEventStreamLagged) {
+		t.Errorf("err = %v, want ErrEventStreamLagged", err)
+	}
+}
+
+func TestParseEventTypes(t *testing.T) {
+	types, err := ParseEventTypes([]string{"user.created", "user.deleted", "user.created"})
+	if err != nil || !reflect.DeepEqual(types, []domain.EventType{domain.EventUserCreated, domain.EventUserDeleted}) {
+		t.Errorf("ParseEventTypes = %v, %v", types, err)
+	}
+	if _, err := ParseEventTypes([]string{"user.renamed"}); !errors.Is(err, domain.ErrInvalidEventType) {
+		t.Errorf("unknown type: err = %v, want ErrInvalidEventType", err)
+	}
+}
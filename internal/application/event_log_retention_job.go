@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// EventLogRetentionJob elimina periódicamente del outbox los eventos
+// publicados que superaron el período de retención del registro de eventos.
+type EventLogRetentionJob struct {
+	outbox    domain.OutboxRepository
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewEventLogRetentionJob crea un EventLogRetentionJob que, cada interval,
+// elimina los eventos publicados hace más de retention.
+func NewEventLogRetentionJob(outbox domain.OutboxRepository, retention, interval time.Duration) *EventLogRetentionJob {
+	return &EventLogRetentionJob{outbox: outbox, retention: retention, interval: interval}
+}
+
+// Run ejecuta la limpieza inmediatamente y luego en cada intervalo, hasta que
+// el contexto sea cancelado.
+func (j *EventLogRetentionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.prune()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prune ejecuta una pasada de limpieza y registra el resultado.
+func (j *EventLogRetentionJob) prune() {
+	pruned, err := j.outbox.PurgePublished(time.Now().Add(-j.retention))
+	if err != nil {
+		log.Printf("[EventLog] prune failed: %v", err)
+		return
+	}
+	if pruned > 0 {
+		log.Printf("[EventLog] pruned %d published events", pruned)
+	}
+}
@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// eventTypes asocia cada acción auditada con el evento de dominio que emite.
+// Las acciones sin evento (e.g., la purga de usuarios ya eliminados) se omiten.
+var eventTypes = map[domain.AuditAction]domain.EventType{
+	domain.AuditActionCreate:  domain.EventUserCreated,
+	domain.AuditActionUpdate:  domain.EventUserUpdated,
+	domain.AuditActionRestore: domain.EventUserUpdated,
+	domain.AuditActionDelete:  domain.EventUserDeleted,
+
+	domain.AuditActionStatusChange: domain.EventUserUpdated,
+	domain.AuditActionEmailVerify:  domain.EventUserUpdated,
+}
+
+// recordChange registra, dentro de la UnitOfWork, la auditoría de una mutación
+// y el evento de dominio correspondiente en el outbox. Ambos se confirman o
+// revierten junto con la mutación.
+func recordChange(ctx context.Context, uow domain.UnitOfWork, action domain.AuditAction, userID string, before, after *domain.User) error {
+	changes := diffUsers(before, after)
+
+	if err := recordAudit(ctx, uow, action, userID, changes); err != nil {
+		return err
+	}
+
+	eventType, ok := eventTypes[action]
+	if !ok {
+		return nil
+	}
+
+	event := newUserEvent(ctx, eventType, userID)
+	event.User = after
+	if eventType == domain.EventUserDeleted {
+		event.User = before
+	}
+	if eventType == domain.EventUserUpdated {
+		for _, change := range changes {
+			event.ChangedFields = append(event.ChangedFields, change.Field)
+		}
+	}
+
+	return uow.Outbox().Append(event)
+}
+
+// newUserEvent crea un evento con ID (ULID), instante, organización y
+// atribución del contexto.
+func newUserEvent(ctx context.Context, eventType domain.EventType, userID string) *domain.UserEvent {
+	t := time.Now()
+
+	return &domain.UserEvent{
+		ID:         newULID(t),
+		Type:       eventType,
+		TenantID:   domain.TenantFromContext(ctx),
+		UserID:     userID,
+		OccurredAt: t.UTC(),
+		Actor:      domain.ActorFromContext(ctx),
+		RequestID:  domain.RequestIDFromContext(ctx),
+	}
+}
@@ -0,0 +1,717 @@
+package application
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// memoryStore es un almacenamiento en memoria para las pruebas. Implementa
+// domain.UserTransactionPort y domain.UnitOfWork: Execute descarta los
+// cambios de fn si retorna un error, como una transacción.
+type memoryStore struct {
+	users    map[string]domain.User
+	audit    []domain.AuditRecord
+	outbox   []outboxEntry
+	tokens   map[string]domain.EmailVerificationToken
+	sequence int64
+	// publishedSequence es el contador de posiciones de publicación del outbox.
+	publishedSequence int64
+}
+
+// outboxEntry es un evento del outbox en memoria con su estado de publicación.
+type outboxEntry struct {
+	event         domain.UserEvent
+	attempts      int
+	lastError     string
+	nextAttemptAt time.Time
+	publishedAt   *time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{users: map[string]domain.User{}, tokens: map[string]domain.EmailVerificationToken{}}
+}
+
+func (s *memoryStore) Execute(fn func(uow domain.UnitOfWork) error) error {
+	snapshot := *s
+	snapshot.users = maps.Clone(s.users)
+	snapshot.audit = slices.Clone(s.audit)
+	snapshot.outbox = slices.Clone(s.outbox)
+	snapshot.tokens = maps.Clone(s.tokens)
+
+	if err := fn(s); err != nil {
+		*s = snapshot
+		return err
+	}
+	return nil
+}
+
+func (s *memoryStore) Users() domain.UserRepository { return &memoryUsers{store: s} }
+
+func (s *memoryStore) Audit() domain.AuditRepository { return memoryAudit{store: s} }
+
+func (s *memoryStore) Outbox() domain.OutboxRepository { return memoryOutbox{store: s} }
+
+func (s *memoryStore) EmailVerifications() domain.EmailVerificationRepository {
+	return memoryEmailVerifications{store: s}
+}
+
+// events retorna los eventos agregados al outbox, en orden.
+func (s *memoryStore) events() []domain.UserEvent {
+	events := make([]domain.UserEvent, len(s.outbox))
+	for i, entry := range s.outbox {
+		events[i] = entry.event
+	}
+	return events
+}
+
+// memoryUsers implementa domain.UserRepository sobre un memoryStore, con
+// las reglas de unicidad por organización de los índices de PostgreSQL.
+type memoryUsers struct {
+	store    *memoryStore
+	tenantID string
+}
+
+func (r *memoryUsers) ForTenant(tenantID string) domain.UserRepository {
+	return &memoryUsers{store: r.store, tenantID: tenantID}
+}
+
+func (r *memoryUsers) inScope(user domain.User) bool {
+	return r.tenantID == "" || user.TenantID == r.tenantID
+}
+
+// find retorna el usuario de la organización, opcionalmente aunque esté eliminado.
+func (r *memoryUsers) find(id string, includeDeleted bool) (domain.User, bool) {
+	user, ok := r.store.users[id]
+	if !ok || !r.inScope(user) || (!includeDeleted && user.DeletedAt != nil) {
+		return domain.User{}, false
+	}
+	return user, true
+}
+
+// checkUnique emula los índices únicos parciales sobre las formas canónicas.
+func (r *memoryUsers) checkUnique(user domain.User) error {
+	for _, other := range r.store.users {
+		if other.ID == user.ID || other.TenantID != user.TenantID {
+			continue
+		}
+		if user.UsernameNormalized != "" && other.UsernameNormalized == user.UsernameNormalized {
+			return domain.ErrUsernameInUse
+		}
+		if user.EmailNormalized != "" && other.EmailNormalized == user.EmailNormalized {
+			return domain.ErrEmailInUse
+		}
+	}
+	return nil
+}
+
+func (r *memoryUsers) Create(user *domain.User) error {
+	if r.tenantID != "" {
+		user.TenantID = r.tenantID
+	}
+	if user.TenantID == "" {
+		user.TenantID = domain.DefaultTenantID
+	}
+	if _, exists := r.store.users[user.ID]; exists {
+		return domain.ErrIdInUse
+	}
+	if err := r.checkUnique(*user); err != nil {
+		return err
+	}
+	r.store.users[user.ID] = *user
+	return nil
+}
+
+func (r *memoryUsers) FindAll(filter domain.UserFilter) (*[]domain.User, error) {
+	users := make([]domain.User, 0)
+	err := r.Each(filter, func(user *domain.User) error {
+		users = append(users, *user)
+		return nil
+	})
+	return &users, err
+}
+
+func (r *memoryUsers) Each(filter domain.UserFilter, fn func(user *domain.User) error) error {
+	ids := slices.Sorted(maps.Keys(r.store.users))
+
+	count := 0
+	for _, id := range ids {
+		user := r.store.users[id]
+		if !r.inScope(user) || !matches(user, filter) {
+			continue
+		}
+		if filter.Limit > 0 && count == filter.Limit {
+			break
+		}
+		count++
+		if err := fn(&user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matches aplica un domain.UserFilter (salvo Fields y Limit) a un usuario.
+func matches(user domain.User, filter domain.UserFilter) bool {
+	switch {
+	case !filter.IncludeDeleted && user.DeletedAt != nil:
+		return false
+	case filter.CreatedAfter != nil && user.CreatedAt.Before(*filter.CreatedAfter):
+		return false
+	case filter.CreatedBefore != nil && user.CreatedAt.After(*filter.CreatedBefore):
+		return false
+	case filter.UpdatedAfter != nil && user.UpdatedAt.Before(*filter.UpdatedAfter):
+		return false
+	case filter.UpdatedBefore != nil && user.UpdatedAt.After(*filter.UpdatedBefore):
+		return false
+	case filter.IDs != nil && !slices.Contains(filter.IDs, user.ID):
+		return false
+	case filter.Statuses != nil && !slices.Contains(filter.Statuses, user.Status):
+		return false
+	case filter.AfterID != "" && user.ID <= filter.AfterID:
+		return false
+	}
+	return true
+}
+
+func (r *memoryUsers) FindById(id string, fields ...string) (*domain.User, error) {
+	user, ok := r.find(id, false)
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+// Update emula el Updates de GORM: solo escribe los campos con valor.
+func (r *memoryUsers) Update(user *domain.User) error {
+	stored, ok := r.find(user.ID, false)
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+
+	set := func(target *string, value string) {
+		if value != "" {
+			*target = value
+		}
+	}
+	set(&stored.Name, user.Name)
+	set(&stored.Username, user.Username)
+	set(&stored.Email, user.Email)
+	set(&stored.UsernameNormalized, user.UsernameNormalized)
+	set(&stored.EmailNormalized, user.EmailNormalized)
+	set(&stored.ExternalID, user.ExternalID)
+	set(&stored.CreatedBy, user.CreatedBy)
+	set(&stored.UpdatedBy, user.UpdatedBy)
+	set((*string)(&stored.Status), string(user.Status))
+	set(&stored.StatusReason, user.StatusReason)
+	if !user.CreatedAt.IsZero() {
+		stored.CreatedAt = user.CreatedAt
+	}
+	if !user.UpdatedAt.IsZero() {
+		stored.UpdatedAt = user.UpdatedAt
+	}
+	if user.StatusChangedAt != nil {
+		stored.StatusChangedAt = user.StatusChangedAt
+	}
+	if user.EmailVerified {
+		stored.EmailVerified, stored.EmailVerifiedAt = true, user.EmailVerifiedAt
+	}
+
+	if err := r.checkUnique(stored); err != nil {
+		return err
+	}
+	r.store.users[stored.ID] = stored
+	return nil
+}
+
+func (r *memoryUsers) UpdateStatus(user *domain.User) error {
+	stored, ok := r.find(user.ID, false)
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	stored.Status, stored.StatusReason, stored.StatusChangedAt = user.Status, user.StatusReason, user.StatusChangedAt
+	stored.UpdatedAt, stored.UpdatedBy = user.UpdatedAt, user.UpdatedBy
+	r.store.users[stored.ID] = stored
+	return nil
+}
+
+func (r *memoryUsers) UpdateEmailVerification(user *domain.User) error {
+	stored, ok := r.find(user.ID, false)
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	stored.EmailVerified, stored.EmailVerifiedAt = user.EmailVerified, user.EmailVerifiedAt
+	stored.UpdatedAt, stored.UpdatedBy = user.UpdatedAt, user.UpdatedBy
+	r.store.users[stored.ID] = stored
+	return nil
+}
+
+func (r *memoryUsers) Delete(id string) error {
+	stored, ok := r.find(id, false)
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	deletedAt := time.Now().UTC()
+	stored.DeletedAt = &deletedAt
+	r.store.users[id] = stored
+	return nil
+}
+
+func (r *memoryUsers) ReleaseIdentity(id string) error {
+	stored, ok := r.find(id, true)
+	if !ok || stored.DeletedAt == nil {
+		return domain.ErrUserNotFound
+	}
+	stored.UsernameNormalized, stored.EmailNormalized = "", ""
+	r.store.users[id] = stored
+	return nil
+}
+
+func (r *memoryUsers) Restore(id string) (*domain.User, error) {
+	stored, ok := r.find(id, true)
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	if stored.DeletedAt == nil {
+		return nil, domain.ErrUserNotDeleted
+	}
+	before := stored
+	stored.DeletedAt = nil
+	r.store.users[id] = stored
+	return &before, nil
+}
+
+func (r *memoryUsers) Purge(deletedBefore time.Time) ([]domain.User, error) {
+	purged := make([]domain.User, 0)
+	for _, id := range slices.Sorted(maps.Keys(r.store.users)) {
+		user := r.store.users[id]
+		if r.inScope(user) && user.DeletedAt != nil && user.DeletedAt.Before(deletedBefore) {
+			purged = append(purged, domain.User{ID: user.ID, TenantID: user.TenantID})
+			delete(r.store.users, id)
+		}
+	}
+	return purged, nil
+}
+
+func (r *memoryUsers) TakenIdentities(usernamesNormalized, emailsNormalized []string) ([]string, []string, error) {
+	usernames, emails := make([]string, 0), make([]string, 0)
+	for _, user := range r.store.users {
+		if !r.inScope(user) {
+			continue
+		}
+		if slices.Contains(usernamesNormalized, user.UsernameNormalized) {
+			usernames = append(usernames, user.UsernameNormalized)
+		}
+		if slices.Contains(emailsNormalized, user.EmailNormalized) {
+			emails = append(emails, user.EmailNormalized)
+		}
+	}
+	return usernames, emails, nil
+}
+
+// memoryAudit implementa domain.AuditRepository sobre un memoryStore.
+type memoryAudit struct {
+	store *memoryStore
+}
+
+func (r memoryAudit) Append(record *domain.AuditRecord) error {
+	r.store.audit = append(r.store.audit, *record)
+	return nil
+}
+
+func (r memoryAudit) Find(filter domain.AuditFilter) ([]domain.AuditRecord, error) {
+	records := make([]domain.AuditRecord, 0)
+	for i := len(r.store.audit) - 1; i >= 0; i-- {
+		record := r.store.audit[i]
+		switch {
+		case filter.TenantID != "" && record.TenantID != filter.TenantID,
+			filter.UserID != "" && record.UserID != filter.UserID,
+			filter.Actor != "" && record.Actor != filter.Actor,
+			filter.Since != nil && record.Timestamp.Before(*filter.Since):
+			continue
+		}
+		if filter.Limit > 0 && len(records) == filter.Limit {
+			break
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// memoryOutbox implementa domain.OutboxRepository sobre un memoryStore.
+type memoryOutbox struct {
+	store *memoryStore
+}
+
+func (r memoryOutbox) Append(event *domain.UserEvent) error {
+	r.store.sequence++
+	event.Sequence = r.store.sequence
+	r.store.outbox = append(r.store.outbox, outboxEntry{event: *event})
+	return nil
+}
+
+func (r memoryOutbox) FetchPending(limit int) ([]domain.PendingEvent, error) {
+	pending := make([]domain.PendingEvent, 0)
+	for _, entry := range r.store.outbox {
+		if entry.publishedAt == nil {
+			pending = append(pending, domain.PendingEvent{Event: entry.event, Attempts: entry.attempts, NextAttemptAt: entry.nextAttemptAt})
+		}
+	}
+	// Como "ORDER BY published_sequence NULLS LAST, sequence"
+	slices.SortStableFunc(pending, func(a, b domain.PendingEvent) int {
+		if a.Event.PublishedSequence == 0 || b.Event.PublishedSequence == 0 {
+			return cmp.Compare(b.Event.PublishedSequence, a.Event.PublishedSequence)
+		}
+		return cmp.Compare(a.Event.PublishedSequence, b.Event.PublishedSequence)
+	})
+	return pending[:min(limit, len(pending))], nil
+}
+
+func (r memoryOutbox) AssignPublishedSequence(sequence int64) (int64, error) {
+	entry := r.entry(sequence)
+	if entry == nil {
+		return 0, nil
+	}
+	if entry.event.PublishedSequence == 0 {
+		r.store.publishedSequence++
+		entry.event.PublishedSequence = r.store.publishedSequence
+	}
+	return entry.event.PublishedSequence, nil
+}
+
+func (r memoryOutbox) entry(sequence int64) *outboxEntry {
+	for i := range r.store.outbox {
+		if r.store.outbox[i].event.Sequence == sequence {
+			return &r.store.outbox[i]
+		}
+	}
+	return nil
+}
+
+func (r memoryOutbox) MarkPublished(sequence int64) error {
+	if entry := r.entry(sequence); entry != nil {
+		publishedAt := time.Now().UTC()
+		entry.publishedAt, entry.lastError = &publishedAt, ""
+		entry.attempts++
+	}
+	return nil
+}
+
+func (r memoryOutbox) MarkFailed(sequence int64, cause string, nextAttemptAt time.Time) error {
+	if entry := r.entry(sequence); entry != nil {
+		entry.attempts++
+		entry.lastError, entry.nextAttemptAt = cause, nextAttemptAt
+	}
+	return nil
+}
+
+func (r memoryOutbox) FindPublished(tenantID string, afterPublished int64, types []domain.EventType, limit int) ([]domain.UserEvent, error) {
+	events := make([]domain.UserEvent, 0)
+	for _, entry := range r.store.outbox {
+		if entry.event.Tenant() != tenantID || entry.publishedAt == nil || entry.event.PublishedSequence <= afterPublished {
+			continue
+		}
+		if len(types) > 0 && !slices.Contains(types, entry.event.Type) {
+			continue
+		}
+		events = append(events, entry.event)
+	}
+	slices.SortFunc(events, func(a, b domain.UserEvent) int {
+		return cmp.Compare(a.PublishedSequence, b.PublishedSequence)
+	})
+	return events[:min(limit, len(events))], nil
+}
+
+func (r memoryOutbox) PurgePublished(publishedBefore time.Time) (int64, error) {
+	kept := r.store.outbox[:0]
+	var purged int64
+	for _, entry := range r.store.outbox {
+		if entry.publishedAt != nil && entry.publishedAt.Before(publishedBefore) {
+			purged++
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	r.store.outbox = kept
+	return purged, nil
+}
+
+// memoryEmailVerifications implementa domain.EmailVerificationRepository sobre un memoryStore.
+type memoryEmailVerifications struct {
+	store *memoryStore
+}
+
+func (r memoryEmailVerifications) Create(token *domain.EmailVerificationToken) error {
+	r.store.tokens[token.ID] = *token
+	return nil
+}
+
+func (r memoryEmailVerifications) FindByHash(hash string) (*domain.EmailVerificationToken, error) {
+	for _, token := range r.store.tokens {
+		if token.Hash == hash {
+			return &token, nil
+		}
+	}
+	return nil, domain.ErrInvalidVerificationToken
+}
+
+func (r memoryEmailVerifications) ExistsForEvent(eventID string) (bool, error) {
+	for _, token := range r.store.tokens {
+		if token.EventID == eventID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryEmailVerifications) Consume(id string, usedAt time.Time) error {
+	token, ok := r.store.tokens[id]
+	if !ok || !token.ValidAt(usedAt) {
+		return domain.ErrInvalidVerificationToken
+	}
+	token.UsedAt = &usedAt
+	r.store.tokens[id] = token
+	return nil
+}
+
+func (r memoryEmailVerifications) ExpirePending(userID string, at time.Time) error {
+	for id, token := range r.store.tokens {
+		if token.UserID == userID && token.ValidAt(at) {
+			token.ExpiresAt = at
+			r.store.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+// memoryWebhooks implementa domain.WebhookRepository en memoria.
+type memoryWebhooks struct {
+	webhooks   map[string]domain.Webhook
+	deliveries []domain.WebhookDelivery
+}
+
+func newMemoryWebhooks() *memoryWebhooks {
+	return &memoryWebhooks{webhooks: map[string]domain.Webhook{}}
+}
+
+func (r *memoryWebhooks) Create(webhook *domain.Webhook) error {
+	r.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (r *memoryWebhooks) FindAll() ([]domain.Webhook, error) {
+	return slices.SortedFunc(maps.Values(r.webhooks), func(a, b domain.Webhook) int {
+		return strings.Compare(a.ID, b.ID)
+	}), nil
+}
+
+func (r *memoryWebhooks) FindById(id string) (*domain.Webhook, error) {
+	webhook, ok := r.webhooks[id]
+	if !ok {
+		return nil, domain.ErrWebhookNotFound
+	}
+	return &webhook, nil
+}
+
+func (r *memoryWebhooks) Update(webhook *domain.Webhook) error {
+	if _, ok := r.webhooks[webhook.ID]; !ok {
+		return domain.ErrWebhookNotFound
+	}
+	r.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (r *memoryWebhooks) Delete(id string) error {
+	delete(r.webhooks, id)
+	r.deliveries = slices.DeleteFunc(r.deliveries, func(delivery domain.WebhookDelivery) bool {
+		return delivery.WebhookID == id
+	})
+	return nil
+}
+
+func (r *memoryWebhooks) CreateDelivery(delivery *domain.WebhookDelivery) error {
+	r.deliveries = append(r.deliveries, *delivery)
+	return nil
+}
+
+func (r *memoryWebhooks) UpdateDelivery(delivery *domain.WebhookDelivery) error {
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == delivery.ID {
+			r.deliveries[i] = *delivery
+		}
+	}
+	return nil
+}
+
+func (r *memoryWebhooks) FindDelivery(webhookID, deliveryID string) (*domain.WebhookDelivery, error) {
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID && delivery.ID == deliveryID {
+			return &delivery, nil
+		}
+	}
+	return nil, domain.ErrWebhookDeliveryNotFound
+}
+
+func (r *memoryWebhooks) FindDeliveries(webhookID string, limit int) ([]domain.WebhookDelivery, error) {
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for i := len(r.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if r.deliveries[i].WebhookID == webhookID {
+			deliveries = append(deliveries, r.deliveries[i])
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *memoryWebhooks) FindDueDeliveries(now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for _, delivery := range r.deliveries {
+		if delivery.State == domain.DeliveryPending && !delivery.NextAttemptAt.After(now) && len(deliveries) < limit {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+// memoryImports implementa domain.UserImportRepository en memoria, con un
+// único lease por importación.
+type memoryImports struct {
+	imports  map[string]domain.UserImport
+	payloads map[string][]byte
+	errors   map[string][]domain.ImportRowError
+	owners   map[string]string
+}
+
+func newMemoryImports() *memoryImports {
+	return &memoryImports{
+		imports:  map[string]domain.UserImport{},
+		payloads: map[string][]byte{},
+		errors:   map[string][]domain.ImportRowError{},
+		owners:   map[string]string{},
+	}
+}
+
+func (r *memoryImports) Create(userImport *domain.UserImport, payload []byte) error {
+	r.imports[userImport.ID], r.payloads[userImport.ID] = *userImport, payload
+	return nil
+}
+
+func (r *memoryImports) FindById(id string) (*domain.UserImport, error) {
+	userImport, ok := r.imports[id]
+	if !ok {
+		return nil, domain.ErrImportNotFound
+	}
+	return &userImport, nil
+}
+
+func (r *memoryImports) FindRunnable(limit int) ([]domain.UserImport, error) {
+	runnable := make([]domain.UserImport, 0)
+	for _, userImport := range r.imports {
+		if (userImport.Status == domain.ImportPending || userImport.Status == domain.ImportRunning) && len(runnable) < limit {
+			runnable = append(runnable, userImport)
+		}
+	}
+	return runnable, nil
+}
+
+func (r *memoryImports) Claim(id, owner string, _ time.Time) (bool, error) {
+	userImport, ok := r.imports[id]
+	if !ok || userImport.Status == domain.ImportCompleted || userImport.Status == domain.ImportFailed {
+		return false, nil
+	}
+	if current, taken := r.owners[id]; taken && current != owner {
+		return false, nil
+	}
+	r.owners[id] = owner
+	return true, nil
+}
+
+func (r *memoryImports) Payload(id string) ([]byte, error) {
+	return r.payloads[id], nil
+}
+
+func (r *memoryImports) Update(userImport *domain.UserImport) error {
+	r.imports[userImport.ID] = *userImport
+	if userImport.Status == domain.ImportCompleted || userImport.Status == domain.ImportFailed {
+		delete(r.owners, userImport.ID)
+	}
+	return nil
+}
+
+func (r *memoryImports) AppendErrors(importID string, rowErrors []domain.ImportRowError) error {
+	r.errors[importID] = append(r.errors[importID], rowErrors...)
+	return nil
+}
+
+func (r *memoryImports) Errors(importID string) ([]domain.ImportRowError, error) {
+	return slices.Clone(r.errors[importID]), nil
+}
+
+// memoryAPIKeys implementa domain.APIKeyRepository en memoria. failRotate
+// simula un fallo de la transacción de Rotate, que no debe dejar cambios.
+type memoryAPIKeys struct {
+	keys       []domain.APIKey
+	failRotate bool
+}
+
+func (r *memoryAPIKeys) Create(key *domain.APIKey) error {
+	r.keys = append(r.keys, *key)
+	return nil
+}
+
+func (r *memoryAPIKeys) FindById(id string) (*domain.APIKey, error) {
+	for _, key := range r.keys {
+		if key.ID == id {
+			return &key, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *memoryAPIKeys) FindByHash(hash string) (*domain.APIKey, error) {
+	for _, key := range r.keys {
+		if key.Hash == hash {
+			return &key, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *memoryAPIKeys) FindAll(includeRevoked bool) ([]domain.APIKey, error) {
+	keys := make([]domain.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		if includeRevoked || key.RevokedAt == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *memoryAPIKeys) Rotate(key *domain.APIKey, expiresAt time.Time) (int64, error) {
+	if r.failRotate {
+		return 0, domain.ErrInternalServer{Value: "rotate failed"}
+	}
+
+	var expired int64
+	for i, other := range r.keys {
+		if other.Name == key.Name && other.TenantID == key.TenantID && other.UserID == key.UserID &&
+			other.RevokedAt == nil && (other.ExpiresAt == nil || other.ExpiresAt.After(expiresAt)) {
+			r.keys[i].ExpiresAt = &expiresAt
+			expired++
+		}
+	}
+	r.keys = append(r.keys, *key)
+	return expired, nil
+}
+
+func (r *memoryAPIKeys) Revoke(id string, revokedAt time.Time) error {
+	for i, key := range r.keys {
+		if key.ID == id && key.RevokedAt == nil {
+			r.keys[i].RevokedAt = &revokedAt
+			return nil
+		}
+	}
+	return domain.ErrAPIKeyNotFound
+}
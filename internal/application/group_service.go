@@ -0,0 +1,241 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// GroupService define el contract para administrar los grupos de la
+// organización del contexto y sus membresías.
+type GroupService interface {
+	// Create crea un grupo. Retorna ErrGroupNameInUse si el nombre ya existe.
+	Create(ctx context.Context, request *domain.GroupCreateRequest) (*domain.Group, error)
+	FindAll(ctx context.Context) ([]domain.Group, error)
+	// FindByIds retorna los grupos existentes de entre los IDs indicados.
+	FindByIds(ctx context.Context, ids []string) ([]domain.Group, error)
+	// FindById retorna ErrGroupNotFound si no existe.
+	FindById(ctx context.Context, id string) (*domain.Group, error)
+	// Update aplica los campos presentes en el request.
+	Update(ctx context.Context, id string, request *domain.GroupUpdateRequest) (*domain.Group, error)
+	// Delete elimina el grupo y sus membresías.
+	Delete(ctx context.Context, id string) error
+	// Members retorna los miembros directos del grupo o, con transitive, los
+	// usuarios miembros directos o a través de grupos anidados. Omite los
+	// usuarios eliminados lógicamente.
+	Members(ctx context.Context, id string, transitive bool) ([]domain.GroupMember, error)
+	// ChangeMembers agrega y quita miembros en una sola transacción y retorna
+	// los miembros directos resultantes.
+	ChangeMembers(ctx context.Context, id string, change *domain.GroupMembershipChange) ([]domain.GroupMember, error)
+	// GroupsOfUser retorna los grupos del usuario; con transitive incluye los
+	// que lo contienen a través de grupos anidados.
+	GroupsOfUser(ctx context.Context, userID string, transitive bool) ([]domain.Group, error)
+}
+
+// GroupServiceImpl es la implementación concreta de GroupService.
+type GroupServiceImpl struct {
+	repo  domain.GroupRepository
+	users domain.UserRepository
+}
+
+// NewGroupServiceImpl crea un GroupServiceImpl sobre los repositorios de
+// grupos y de usuarios (para validar y describir los usuarios miembros).
+func NewGroupServiceImpl(repo domain.GroupRepository, users domain.UserRepository) *GroupServiceImpl {
+	return &GroupServiceImpl{repo: repo, users: users}
+}
+
+// Asegura que GroupServiceImpl implemente la interfaz GroupService en tiempo de compilación.
+var _ GroupService = (*GroupServiceImpl)(nil)
+
+// Create valida el nombre y persiste el grupo en la organización del contexto.
+func (s *GroupServiceImpl) Create(ctx context.Context, request *domain.GroupCreateRequest) (*domain.Group, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return nil, domain.ErrValueNotNullable{Value: "name"}
+	}
+
+	now := time.Now().UTC()
+	actor := domain.ActorFromContext(ctx)
+	group := &domain.Group{
+		ID:          newULID(now),
+		Name:        name,
+		Description: strings.TrimSpace(request.Description),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		CreatedBy:   actor,
+		UpdatedBy:   actor,
+	}
+
+	if err := s.groups(ctx).Create(group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// FindAll retorna los grupos de la organización en orden de nombre.
+func (s *GroupServiceImpl) FindAll(ctx context.Context) ([]domain.Group, error) {
+	return s.groups(ctx).FindAll()
+}
+
+// FindByIds retorna los grupos de la organización de entre los IDs indicados.
+func (s *GroupServiceImpl) FindByIds(ctx context.Context, ids []string) ([]domain.Group, error) {
+	return s.groups(ctx).FindByIds(ids)
+}
+
+// FindById retorna el grupo o ErrGroupNotFound.
+func (s *GroupServiceImpl) FindById(ctx context.Context, id string) (*domain.Group, error) {
+	return s.groups(ctx).FindById(id)
+}
+
+// Update aplica el nombre y la descripción presentes en el request.
+func (s *GroupServiceImpl) Update(ctx context.Context, id string, request *domain.GroupUpdateRequest) (*domain.Group, error) {
+	repo := s.groups(ctx)
+
+	group, err := repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != nil {
+		name := strings.TrimSpace(*request.Name)
+		if name == "" {
+			return nil, domain.ErrValueNotNullable{Value: "name"}
+		}
+		group.Name = name
+	}
+	if request.Description != nil {
+		group.Description = strings.TrimSpace(*request.Description)
+	}
+	group.UpdatedAt = time.Now().UTC()
+	group.UpdatedBy = domain.ActorFromContext(ctx)
+
+	if err := repo.Update(group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// Delete elimina el grupo y sus membresías.
+func (s *GroupServiceImpl) Delete(ctx context.Context, id string) error {
+	return s.groups(ctx).Delete(id)
+}
+
+// Members retorna los miembros del grupo con su username o nombre.
+func (s *GroupServiceImpl) Members(ctx context.Context, id string, transitive bool) ([]domain.GroupMember, error) {
+	repo := s.groups(ctx)
+
+	// 1. Miembros efectivos: solo usuarios
+	if transitive {
+		userIDs, err := repo.MemberUserIDs(id, true)
+		if err != nil {
+			return nil, err
+		}
+		members := make([]domain.GroupMember, len(userIDs))
+		for i, userID := range userIDs {
+			members[i] = domain.GroupMember{Type: domain.MemberUser, ID: userID}
+		}
+		return s.describeUsers(ctx, members)
+	}
+
+	// 2. Miembros directos
+	members, err := repo.Members(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.describeUsers(ctx, members)
+}
+
+// ChangeMembers valida los miembros a agregar y aplica el cambio en una
+// transacción. Los usuarios deben existir en la organización y no estar
+// eliminados; los grupos se validan (existencia y ciclos) en la transacción.
+func (s *GroupServiceImpl) ChangeMembers(ctx context.Context, id string, change *domain.GroupMembershipChange) ([]domain.GroupMember, error) {
+	// 1. Tipos de miembro
+	for _, member := range append(append([]domain.MemberRef{}, change.Add...), change.Remove...) {
+		if member.Type != domain.MemberUser && member.Type != domain.MemberGroup {
+			return nil, fmt.Errorf("%w: %q", domain.ErrInvalidMemberType, member.Type)
+		}
+	}
+	var userIDs []string
+	for _, member := range change.Add {
+		if member.Type == domain.MemberUser {
+			userIDs = append(userIDs, member.ID)
+		}
+	}
+
+	// 2. Los usuarios a agregar deben estar activos en la organización
+	if len(userIDs) > 0 {
+		users, err := s.users.ForTenant(domain.TenantFromContext(ctx)).FindAll(domain.UserFilter{IDs: userIDs})
+		if err != nil {
+			return nil, err
+		}
+		found := make(map[string]bool, len(*users))
+		for _, user := range *users {
+			found[user.ID] = true
+		}
+		for _, userID := range userIDs {
+			if !found[userID] {
+				return nil, fmt.Errorf("%w: user %s", domain.ErrMemberNotFound, userID)
+			}
+		}
+	}
+
+	// 3. Cambio atómico
+	if err := s.groups(ctx).ChangeMembers(id, *change, domain.ActorFromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	return s.Members(ctx, id, false)
+}
+
+// GroupsOfUser retorna ErrUserNotFound si el usuario no existe en la
+// organización o está eliminado.
+func (s *GroupServiceImpl) GroupsOfUser(ctx context.Context, userID string, transitive bool) ([]domain.Group, error) {
+	if _, err := s.users.ForTenant(domain.TenantFromContext(ctx)).FindById(userID, "id"); err != nil {
+		return nil, err
+	}
+
+	return s.groups(ctx).GroupsOf(domain.MemberRef{Type: domain.MemberUser, ID: userID}, transitive)
+}
+
+// describeUsers completa el username de los usuarios miembros y omite los
+// que no existen o están eliminados lógicamente.
+func (s *GroupServiceImpl) describeUsers(ctx context.Context, members []domain.GroupMember) ([]domain.GroupMember, error) {
+	var userIDs []string
+	for _, member := range members {
+		if member.Type == domain.MemberUser {
+			userIDs = append(userIDs, member.ID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return members, nil
+	}
+
+	users, err := s.users.ForTenant(domain.TenantFromContext(ctx)).FindAll(domain.UserFilter{IDs: userIDs})
+	if err != nil {
+		return nil, err
+	}
+	usernames := make(map[string]string, len(*users))
+	for _, user := range *users {
+		usernames[user.ID] = user.Username
+	}
+
+	described := make([]domain.GroupMember, 0, len(members))
+	for _, member := range members {
+		if member.Type == domain.MemberUser {
+			username, ok := usernames[member.ID]
+			if !ok {
+				continue
+			}
+			member.Display = username
+		}
+		described = append(described, member)
+	}
+	return described, nil
+}
+
+// groups retorna el repositorio restringido a la organización del contexto.
+func (s *GroupServiceImpl) groups(ctx context.Context) domain.GroupRepository {
+	return s.repo.ForTenant(domain.TenantFromContext(ctx))
+}
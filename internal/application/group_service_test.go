@@ -0,0 +1,548 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"user-api-restful/internal/domain"
+)
+
+// groupStore es el almacenamiento en memoria de grupos y membresías.
+type groupStore struct {
+	groups  map[string]domain.Group
+	users   map[[2]string]bool // {grupo, usuario}
+	nesting map[[2]string]bool // {padre, hijo}
+}
+
+// memoryGroups implementa domain.GroupRepository sobre un groupStore, con
+// la unicidad de nombres por organización y ChangeMembers transaccional.
+type memoryGroups struct {
+	store    *groupStore
+	tenantID string
+}
+
+func newMemoryGroups() *memoryGroups {
+	return &memoryGroups{store: &groupStore{
+		groups:  map[string]domain.Group{},
+		users:   map[[2]string]bool{},
+		nesting: map[[2]string]bool{},
+	}}
+}
+
+func (r *memoryGroups) ForTenant(tenantID string) domain.GroupRepository {
+	return &memoryGroups{store: r.store, tenantID: tenantID}
+}
+
+func (r *memoryGroups) find(id string) (domain.Group, bool) {
+	group, ok := r.store.groups[id]
+	if !ok || (r.tenantID != "" && group.TenantID != r.tenantID) {
+		return domain.Group{}, false
+	}
+	return group, true
+}
+
+func (r *memoryGroups) checkName(group domain.Group) error {
+	for _, other := range r.store.groups {
+		if other.ID != group.ID && other.TenantID == group.TenantID && other.Name == group.Name {
+			return domain.ErrGroupNameInUse
+		}
+	}
+	return nil
+}
+
+func (r *memoryGroups) Create(group *domain.Group) error {
+	group.TenantID = r.tenantID
+	if err := r.checkName(*group); err != nil {
+		return err
+	}
+	r.store.groups[group.ID] = *group
+	return nil
+}
+
+func (r *memoryGroups) FindAll() ([]domain.Group, error) {
+	groups := make([]domain.Group, 0)
+	for _, group := range r.store.groups {
+		if _, ok := r.find(group.ID); ok {
+			groups = append(groups, group)
+		}
+	}
+	slices.SortFunc(groups, func(a, b domain.Group) int { return strings.Compare(a.Name, b.Name) })
+	return groups, nil
+}
+
+func (r *memoryGroups) FindByIds(ids []string) ([]domain.Group, error) {
+	groups := make([]domain.Group, 0)
+	for _, id := range ids {
+		if group, ok := r.find(id); ok {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+func (r *memoryGroups) FindById(id string) (*domain.Group, error) {
+	group, ok := r.find(id)
+	if !ok {
+		return nil, domain.ErrGroupNotFound
+	}
+	return &group, nil
+}
+
+func (r *memoryGroups) Update(group *domain.Group) error {
+	if _, ok := r.find(group.ID); !ok {
+		return domain.ErrGroupNotFound
+	}
+	if err := r.checkName(*group); err != nil {
+		return err
+	}
+	r.store.groups[group.ID] = *group
+	return nil
+}
+
+func (r *memoryGroups) Delete(id string) error {
+	if _, ok := r.find(id); !ok {
+		return domain.ErrGroupNotFound
+	}
+	delete(r.store.groups, id)
+	for key := range r.store.users {
+		if key[0] == id {
+			delete(r.store.users, key)
+		}
+	}
+	for key := range r.store.nesting {
+		if key[0] == id || key[1] == id {
+			delete(r.store.nesting, key)
+		}
+	}
+	return nil
+}
+
+func (r *memoryGroups) Members(groupID string) ([]domain.GroupMember, error) {
+	if _, ok := r.find(groupID); !ok {
+		return nil, domain.ErrGroupNotFound
+	}
+	members := make([]domain.GroupMember, 0)
+	for _, key := range slices.SortedFunc(maps.Keys(r.store.nesting), compareKeys) {
+		if key[0] == groupID {
+			members = append(members, domain.GroupMember{Type: domain.MemberGroup, ID: key[1], Display: r.store.groups[key[1]].Name})
+		}
+	}
+	for _, key := range slices.SortedFunc(maps.Keys(r.store.users), compareKeys) {
+		if key[0] == groupID {
+			members = append(members, domain.GroupMember{Type: domain.MemberUser, ID: key[1]})
+		}
+	}
+	return members, nil
+}
+
+func (r *memoryGroups) MemberUserIDs(groupID string, transitive bool) ([]string, error) {
+	if _, ok := r.find(groupID); !ok {
+		return nil, domain.ErrGroupNotFound
+	}
+	groups := []string{groupID}
+	if transitive {
+		groups = append(groups, r.descendants(groupID)...)
+	}
+	found := map[string]bool{}
+	for key := range r.store.users {
+		if slices.Contains(groups, key[0]) {
+			found[key[1]] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(found)), nil
+}
+
+// descendants retorna los grupos contenidos, a cualquier profundidad, en groupID.
+func (r *memoryGroups) descendants(groupID string) []string {
+	var result []string
+	pending := []string{groupID}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		for key := range r.store.nesting {
+			if key[0] == current && !slices.Contains(result, key[1]) {
+				result = append(result, key[1])
+				pending = append(pending, key[1])
+			}
+		}
+	}
+	return result
+}
+
+func (r *memoryGroups) GroupsOf(member domain.MemberRef, transitive bool) ([]domain.Group, error) {
+	found := map[string]bool{}
+	for id := range r.store.groups {
+		if _, ok := r.find(id); !ok {
+			continue
+		}
+		if member.Type == domain.MemberUser && r.store.users[[2]string{id, member.ID}] ||
+			member.Type == domain.MemberGroup && r.store.nesting[[2]string{id, member.ID}] {
+			found[id] = true
+		}
+	}
+	if transitive {
+		for id := range r.store.groups {
+			for direct := range maps.Clone(found) {
+				if slices.Contains(r.descendants(id), direct) {
+					found[id] = true
+				}
+			}
+		}
+	}
+	return r.FindByIds(slices.Sorted(maps.Keys(found)))
+}
+
+// ChangeMembers restaura las membresías previas si algún cambio falla.
+func (r *memoryGroups) ChangeMembers(groupID string, change domain.GroupMembershipChange, _ string) error {
+	if _, ok := r.find(groupID); !ok {
+		return domain.ErrGroupNotFound
+	}
+	users, nesting := maps.Clone(r.store.users), maps.Clone(r.store.nesting)
+	err := r.applyChange(groupID, change)
+	if err != nil {
+		r.store.users, r.store.nesting = users, nesting
+	}
+	return err
+}
+
+func (r *memoryGroups) applyChange(groupID string, change domain.GroupMembershipChange) error {
+	for _, member := range change.Remove {
+		delete(r.store.users, [2]string{groupID, member.ID})
+		delete(r.store.nesting, [2]string{groupID, member.ID})
+	}
+	for _, member := range change.Add {
+		if member.Type == domain.MemberUser {
+			r.store.users[[2]string{groupID, member.ID}] = true
+			continue
+		}
+		if _, ok := r.find(member.ID); !ok {
+			return fmt.Errorf("%w: group %s", domain.ErrMemberNotFound, member.ID)
+		}
+		if member.ID == groupID || slices.Contains(r.descendants(member.ID), groupID) {
+			return fmt.Errorf("%w: group %s", domain.ErrGroupCycle, member.ID)
+		}
+		r.store.nesting[[2]string{groupID, member.ID}] = true
+	}
+	return nil
+}
+
+func compareKeys(a, b [2]string) int {
+	if c := strings.Compare(a[0], b[0]); c != 0 {
+		return c
+	}
+	return strings.Compare(a[1], b[1])
+}
+
+// newTestGroupService crea un GroupServiceImpl sobre repositorios en
+// memoria y retorna también el servicio de usuarios que comparte el almacenamiento.
+func newTestGroupService() (*GroupServiceImpl, *UserServiceImpl) {
+	users, store := newTestUserService(HoldDeletedIdentity)
+	return NewGroupServiceImpl(newMemoryGroups(), store.Users()), users
+}
+
+// mustCreateGroup crea un grupo y falla la prueba si no es posible.
+func mustCreateGroup(t *testing.T, ctx context.Context, service *GroupServiceImpl, name string) *domain.Group {
+	t.Helper()
+	group, err := service.Create(ctx, &domain.GroupCreateRequest{Name: name})
+	if err != nil {
+		t.Fatalf("Create(%q): %v", name, err)
+	}
+	return group
+}
+
+// memberIDs retorna los IDs de los miembros, en orden.
+func memberIDs(members []domain.GroupMember) []string {
+	ids := make([]string, len(members))
+	for i, member := range members {
+		ids[i] = member.ID
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// groupIDs retorna los IDs de los grupos, en orden.
+func groupIDs(groups []domain.Group) []string {
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ID
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func sortedIDs(ids ...string) []string {
+	return slices.Sorted(slices.Values(ids))
+}
+
+func TestGroupCRUD(t *testing.T) {
+	ctx := domain.WithPrincipal(context.Background(), domain.Principal{Subject: "admin"})
+	service, _ := newTestGroupService()
+
+	group, err := service.Create(ctx, &domain.GroupCreateRequest{Name: "  Engineering ", Description: " Builders "})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if group.Name != "Engineering" || group.Description != "Builders" || group.CreatedBy != "admin" {
+		t.Errorf("created group = %+v", group)
+	}
+
+	if _, err := service.Create(ctx, &domain.GroupCreateRequest{Name: "Engineering"}); !errors.Is(err, domain.ErrGroupNameInUse) {
+		t.Errorf("duplicate name: err = %v, want ErrGroupNameInUse", err)
+	}
+	var notNullable domain.ErrValueNotNullable
+	if _, err := service.Create(ctx, &domain.GroupCreateRequest{Name: "  "}); !errors.As(err, &notNullable) {
+		t.Errorf("blank name: err = %v, want ErrValueNotNullable", err)
+	}
+
+	other := mustCreateGroup(t, ctx, service, "Design")
+	renamed := "Engineering"
+	if _, err := service.Update(ctx, other.ID, &domain.GroupUpdateRequest{Name: &renamed}); !errors.Is(err, domain.ErrGroupNameInUse) {
+		t.Errorf("rename to a taken name: err = %v, want ErrGroupNameInUse", err)
+	}
+	description := "Makers"
+	updated, err := service.Update(ctx, other.ID, &domain.GroupUpdateRequest{Description: &description})
+	if err != nil || updated.Name != "Design" || updated.Description != "Makers" {
+		t.Errorf("Update description = %+v, %v", updated, err)
+	}
+
+	groups, err := service.FindAll(ctx)
+	if err != nil || len(groups) != 2 || groups[0].Name != "Design" {
+		t.Errorf("FindAll = %+v, %v; want Design, Engineering", groups, err)
+	}
+
+	if err := service.Delete(ctx, group.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := service.FindById(ctx, group.ID); !errors.Is(err, domain.ErrGroupNotFound) {
+		t.Errorf("FindById after Delete: err = %v, want ErrGroupNotFound", err)
+	}
+	if err := service.Delete(ctx, group.ID); !errors.Is(err, domain.ErrGroupNotFound) {
+		t.Errorf("second Delete: err = %v, want ErrGroupNotFound", err)
+	}
+}
+
+func TestChangeMembersRejectsCycles(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestGroupService()
+	a := mustCreateGroup(t, ctx, service, "A")
+	b := mustCreateGroup(t, ctx, service, "B")
+	c := mustCreateGroup(t, ctx, service, "C")
+
+	// A contiene a B y B contiene a C
+	if _, err := service.ChangeMembers(ctx, a.ID, &domain.GroupMembershipChange{Add: []domain.MemberRef{{Type: domain.MemberGroup, ID: b.ID}}}); err != nil {
+		t.Fatalf("nest B in A: %v", err)
+	}
+	if _, err := service.ChangeMembers(ctx, b.ID, &domain.GroupMembershipChange{Add: []domain.MemberRef{{Type: domain.MemberGroup, ID: c.ID}}}); err != nil {
+		t.Fatalf("nest C in B: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		parent string
+		child  string
+	}{
+		{name: "group contains itself", parent: a.ID, child: a.ID},
+		{name: "direct cycle", parent: b.ID, child: a.ID},
+		{name: "indirect cycle", parent: c.ID, child: a.ID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ChangeMembers(ctx, tt.parent, &domain.GroupMembershipChange{Add: []domain.MemberRef{{Type: domain.MemberGroup, ID: tt.child}}})
+			if !errors.Is(err, domain.ErrGroupCycle) {
+				t.Errorf("err = %v, want ErrGroupCycle", err)
+			}
+		})
+	}
+}
+
+func TestChangeMembersIsAllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	service, users := newTestGroupService()
+	parent := mustCreateGroup(t, ctx, service, "Parent")
+	child := mustCreateGroup(t, ctx, service, "Child")
+	jane := mustCreate(t, ctx, users, "jane", "jane@example.com")
+	john := mustCreate(t, ctx, users, "john", "john@example.com")
+
+	if _, err := service.ChangeMembers(ctx, child.ID, &domain.GroupMembershipChange{Add: []domain.MemberRef{{Type: domain.MemberGroup, ID: parent.ID}}}); err != nil {
+		t.Fatalf("nest Parent in Child: %v", err)
+	}
+	if _, err := service.ChangeMembers(ctx, parent.ID, &domain.GroupMembershipChange{Add: []domain.MemberRef{{Type: domain.MemberUser, ID: jane.ID}}}); err != nil {
+		t.Fatalf("add jane: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		change  domain.GroupMembershipChange
+		wantErr error
+	}{
+		{
+			name: "cycle after valid changes",
+			change: domain.GroupMembershipChange{
+				Add:    []domain.MemberRef{{Type: domain.MemberUser, ID: john.ID}, {Type: domain.MemberGroup, ID: child.ID}},
+				Remove: []domain.MemberRef{{Type: domain.MemberUser, ID: jane.ID}},
+			},
+			wantErr: domain.ErrGroupCycle,
+		},
+		{
+			name: "unknown user",
+			change: domain.GroupMembershipChange{
+				Add:    []domain.MemberRef{{Type: domain.MemberUser, ID: john.ID}, {Type: domain.MemberUser, ID: "missing"}},
+				Remove: []domain.MemberRef{{Type: domain.MemberUser, ID: jane.ID}},
+			},
+			wantErr: domain.ErrMemberNotFound,
+		},
+		{
+			name: "unknown group",
+			change: domain.GroupMembershipChange{
+				Add:    []domain.MemberRef{{Type: domain.MemberUser, ID: john.ID}, {Type: domain.MemberGroup, ID: "missing"}},
+				Remove: []domain.MemberRef{{Type: domain.MemberUser, ID: jane.ID}},
+			},
+			wantErr: domain.ErrMemberNotFound,
+		},
+		{
+			name: "invalid member type",
+			change: domain.GroupMembershipChange{
+				Add:    []domain.MemberRef{{Type: domain.MemberUser, ID: john.ID}, {Type: "robot", ID: "r2d2"}},
+				Remove: []domain.MemberRef{{Type: domain.MemberUser, ID: jane.ID}},
+			},
+			wantErr: domain.ErrInvalidMemberType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.ChangeMembers(ctx, parent.ID, &tt.change); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			members, err := service.Members(ctx, parent.ID, false)
+			if err != nil {
+				t.Fatalf("Members: %v", err)
+			}
+			if got := memberIDs(members); !slices.Equal(got, []string{jane.ID}) {
+				t.Errorf("members after failed change = %v, want only jane", got)
+			}
+		})
+	}
+
+	members, err := service.ChangeMembers(ctx, parent.ID, &domain.GroupMembershipChange{
+		Add:    []domain.MemberRef{{Type: domain.MemberUser, ID: john.ID}, {Type: domain.MemberUser, ID: john.ID}},
+		Remove: []domain.MemberRef{{Type: domain.MemberUser, ID: jane.ID}, {Type: domain.MemberUser, ID: "never-a-member"}},
+	})
+	if err != nil {
+		t.Fatalf("valid change: %v", err)
+	}
+	if len(members) != 1 || members[0].ID != john.ID || members[0].Display != "john" {
+		t.Errorf("members = %+v, want john", members)
+	}
+}
+
+func TestTransitiveMembership(t *testing.T) {
+	ctx := context.Background()
+	service, users := newTestGroupService()
+	company := mustCreateGroup(t, ctx, service, "Company")
+	engineering := mustCreateGroup(t, ctx, service, "Engineering")
+	backend := mustCreateGroup(t, ctx, service, "Backend")
+	ceo := mustCreate(t, ctx, users, "ceo", "ceo@example.com")
+	jane := mustCreate(t, ctx, users, "jane", "jane@example.com")
+	gone := mustCreate(t, ctx, users, "gone", "gone@example.com")
+
+	// Company ⊃ Engineering ⊃ Backend
+	changes := map[string]domain.GroupMembershipChange{
+		company.ID:     {Add: []domain.MemberRef{{Type: domain.MemberUser, ID: ceo.ID}, {Type: domain.MemberGroup, ID: engineering.ID}}},
+		engineering.ID: {Add: []domain.MemberRef{{Type: domain.MemberGroup, ID: backend.ID}}},
+		backend.ID:     {Add: []domain.MemberRef{{Type: domain.MemberUser, ID: jane.ID}, {Type: domain.MemberUser, ID: gone.ID}}},
+	}
+	for id, change := range changes {
+		if _, err := service.ChangeMembers(ctx, id, &change); err != nil {
+			t.Fatalf("ChangeMembers(%s): %v", id, err)
+		}
+	}
+	if err := users.Delete(ctx, gone.ID); err != nil {
+		t.Fatalf("Delete gone: %v", err)
+	}
+
+	direct, err := service.Members(ctx, company.ID, false)
+	if err != nil {
+		t.Fatalf("direct Members: %v", err)
+	}
+	if got, want := memberIDs(direct), sortedIDs(ceo.ID, engineering.ID); !slices.Equal(got, want) {
+		t.Errorf("direct members = %v, want %v", got, want)
+	}
+
+	effective, err := service.Members(ctx, company.ID, true)
+	if err != nil {
+		t.Fatalf("transitive Members: %v", err)
+	}
+	if got, want := memberIDs(effective), sortedIDs(ceo.ID, jane.ID); !slices.Equal(got, want) {
+		t.Errorf("transitive members = %v, want %v (deleted users omitted)", got, want)
+	}
+	for _, member := range effective {
+		if member.Type != domain.MemberUser || member.Display == "" {
+			t.Errorf("transitive member = %+v, want a described user", member)
+		}
+	}
+
+	directGroups, err := service.GroupsOfUser(ctx, jane.ID, false)
+	if err != nil {
+		t.Fatalf("direct GroupsOfUser: %v", err)
+	}
+	if got := groupIDs(directGroups); !slices.Equal(got, []string{backend.ID}) {
+		t.Errorf("direct groups of jane = %v, want Backend", got)
+	}
+	allGroups, err := service.GroupsOfUser(ctx, jane.ID, true)
+	if err != nil {
+		t.Fatalf("transitive GroupsOfUser: %v", err)
+	}
+	if got, want := groupIDs(allGroups), sortedIDs(company.ID, engineering.ID, backend.ID); !slices.Equal(got, want) {
+		t.Errorf("transitive groups of jane = %v, want %v", got, want)
+	}
+
+	if _, err := service.GroupsOfUser(ctx, gone.ID, true); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("GroupsOfUser of a deleted user: err = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestGroupsAreScopedToTheTenant(t *testing.T) {
+	acme := domain.WithTenant(context.Background(), "acme")
+	globex := domain.WithTenant(context.Background(), "globex")
+	service, users := newTestGroupService()
+
+	acmeGroup := mustCreateGroup(t, acme, service, "Staff")
+	// El mismo nombre está disponible en otra organización
+	globexGroup := mustCreateGroup(t, globex, service, "Staff")
+	globexUser := mustCreate(t, globex, users, "jane", "jane@example.com")
+
+	if _, err := service.FindById(globex, acmeGroup.ID); !errors.Is(err, domain.ErrGroupNotFound) {
+		t.Errorf("FindById from another tenant: err = %v, want ErrGroupNotFound", err)
+	}
+	if err := service.Delete(globex, acmeGroup.ID); !errors.Is(err, domain.ErrGroupNotFound) {
+		t.Errorf("Delete from another tenant: err = %v, want ErrGroupNotFound", err)
+	}
+	groups, err := service.FindAll(acme)
+	if err != nil || len(groups) != 1 || groups[0].ID != acmeGroup.ID {
+		t.Errorf("FindAll(acme) = %+v, %v; want only the acme group", groups, err)
+	}
+
+	tests := []struct {
+		name   string
+		member domain.MemberRef
+	}{
+		{name: "user of another tenant", member: domain.MemberRef{Type: domain.MemberUser, ID: globexUser.ID}},
+		{name: "group of another tenant", member: domain.MemberRef{Type: domain.MemberGroup, ID: globexGroup.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ChangeMembers(acme, acmeGroup.ID, &domain.GroupMembershipChange{Add: []domain.MemberRef{tt.member}})
+			if !errors.Is(err, domain.ErrMemberNotFound) {
+				t.Errorf("err = %v, want ErrMemberNotFound", err)
+			}
+		})
+	}
+
+	if _, err := service.GroupsOfUser(acme, globexUser.ID, false); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("GroupsOfUser from another tenant: err = %v, want ErrUserNotFound", err)
+	}
+}
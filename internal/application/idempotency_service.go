@@ -0,0 +1,100 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// IdempotencyService administra las claves de idempotencia de las peticiones
+// no idempotentes (e.g., POST /users), para que un reintento con la misma
+// clave reproduzca la respuesta original en lugar de repetir la operación.
+type IdempotencyService struct {
+	repo domain.IdempotencyRepository
+	ttl  time.Duration
+}
+
+// NewIdempotencyService crea un IdempotencyService cuyas claves expiran tras ttl.
+func NewIdempotencyService(repo domain.IdempotencyRepository, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{repo: repo, ttl: ttl}
+}
+
+// Begin reserva la clave para el actor (en su organización) del contexto. Si la clave es nueva
+// retorna (nil, nil) y el llamador debe procesar la petición y luego invocar
+// Complete o Release. Si la petición original ya terminó, retorna su registro
+// para reproducir la respuesta. Falla con ErrIdempotencyKeyReused si la
+// huella no coincide, o con ErrIdempotencyRequestInProgress si la original
+// todavía está en curso.
+func (s *IdempotencyService) Begin(ctx context.Context, key, fingerprint string) (*domain.IdempotencyRecord, error) {
+	scope := idempotencyScope(ctx)
+	now := time.Now().UTC()
+
+	err := s.repo.Create(&domain.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, domain.ErrIdempotencyKeyExists) {
+		return nil, err
+	}
+
+	record, err := s.repo.Find(scope, key)
+	if err != nil {
+		return nil, err
+	}
+	if record.Fingerprint != fingerprint {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	if !record.Completed() {
+		return nil, domain.ErrIdempotencyRequestInProgress
+	}
+
+	return record, nil
+}
+
+// Complete registra la respuesta de la petición reservada con Begin.
+func (s *IdempotencyService) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	return s.repo.Complete(idempotencyScope(ctx), key, statusCode, contentType, body)
+}
+
+// Release libera una clave reservada con Begin cuya petición no debe
+// reproducirse (e.g., falló por un error del servidor), permitiendo reintentarla.
+func (s *IdempotencyService) Release(ctx context.Context, key string) error {
+	return s.repo.Delete(idempotencyScope(ctx), key)
+}
+
+// RunCleanup elimina las claves expiradas inmediatamente y luego en cada
+// intervalo, hasta que el contexto sea cancelado.
+func (s *IdempotencyService) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.repo.PurgeExpired(time.Now())
+		if err != nil {
+			log.Printf("[Idempotency] cleanup failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("[Idempotency] purged %d expired keys", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// idempotencyScope retorna el ámbito de las claves: el actor dentro de su
+// organización, para que la misma clave de otro actor u organización no
+// reproduzca una respuesta ajena.
+func idempotencyScope(ctx context.Context) string {
+	return domain.TenantFromContext(ctx) + "/" + domain.ActorFromContext(ctx)
+}
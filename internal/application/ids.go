@@ -0,0 +1,14 @@
+package application
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// newULID genera un ULID (ID único, ordenable por tiempo) para el instante t.
+func newULID(t time.Time) string {
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
+	return ulid.MustNew(ulid.Timestamp(t), entropy).String()
+}
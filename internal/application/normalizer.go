@@ -0,0 +1,90 @@
+package application
+
+import (
+	"strings"
+	"user-api-restful/internal/domain"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalizer canonicaliza usernames y emails para que las comparaciones de
+// unicidad no dependan de mayúsculas, espacios o representaciones Unicode
+// equivalentes (e.g., "Jane@Example.com" y "jane@example.com").
+//
+// La forma normalizada solo se usa para comparar; la forma de presentación
+// (display) que envió el cliente se conserva tal cual, sin espacios extremos.
+type Normalizer struct {
+	// foldGmail activa el tratamiento estilo Gmail: ignora los puntos y el
+	// sufijo "+tag" de la parte local en dominios gmail.com/googlemail.com.
+	foldGmail bool
+	folder    cases.Caser
+}
+
+// gmailDomains son los dominios a los que se aplica el tratamiento estilo Gmail.
+var gmailDomains = map[string]bool{
+	"gmail.com":      true,
+	"googlemail.com": true,
+}
+
+// NewNormalizer crea un Normalizer. Si foldGmail es true, las direcciones de
+// Gmail se canonicalizan eliminando puntos y sufijos "+tag".
+func NewNormalizer(foldGmail bool) *Normalizer {
+	return &Normalizer{foldGmail: foldGmail, folder: cases.Fold()}
+}
+
+// Username retorna la forma canónica de un nombre de usuario:
+// recorte de espacios, normalización NFKC y case folding.
+func (n *Normalizer) Username(username string) string {
+	return n.canonical(username)
+}
+
+// Email retorna la forma canónica de un correo electrónico. Además de las
+// reglas de Username, aplica (si está activo) el tratamiento estilo Gmail.
+func (n *Normalizer) Email(email string) string {
+	canonical := n.canonical(email)
+
+	at := strings.LastIndex(canonical, "@")
+	if !n.foldGmail || at < 0 {
+		return canonical
+	}
+
+	local, host := canonical[:at], canonical[at+1:]
+	if !gmailDomains[host] {
+		return canonical
+	}
+
+	// Elimina el sufijo "+tag" y los puntos de la parte local.
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	local = strings.ReplaceAll(local, ".", "")
+
+	return local + "@gmail.com"
+}
+
+// Apply recorta la forma de presentación y completa las formas normalizadas
+// del usuario. Los campos vacíos se dejan vacíos para no pisar valores en
+// actualizaciones parciales.
+func (n *Normalizer) Apply(user *domain.User) {
+	user.Username = strings.TrimSpace(user.Username)
+	user.Email = strings.TrimSpace(user.Email)
+
+	user.UsernameNormalized = ""
+	if user.Username != "" {
+		user.UsernameNormalized = n.Username(user.Username)
+	}
+
+	user.EmailNormalized = ""
+	if user.Email != "" {
+		user.EmailNormalized = n.Email(user.Email)
+	}
+}
+
+// canonical aplica NFKC + case folding + NFKC (NFKC_Casefold) sobre el valor recortado.
+func (n *Normalizer) canonical(value string) string {
+	value = strings.TrimSpace(value)
+	value = norm.NFKC.String(value)
+	value = n.folder.String(value)
+	return norm.NFKC.String(value)
+}
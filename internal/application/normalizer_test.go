@@ -0,0 +1,67 @@
+package application
+
+import (
+	"testing"
+	"user-api-restful/internal/domain"
+)
+
+func TestNormalizerUsername(t *testing.T) {
+	normalizer := NewNormalizer(false)
+
+	tests := []struct {
+		name, input, want string
+	}{
+		{"case", "JaneDoe", "janedoe"},
+		{"surrounding spaces", "  jane ", "jane"},
+		{"compatibility form", "ｊａｎｅ", "jane"},
+		{"full case folding", "STRAẞE", "strasse"},
+		{"composed and decomposed", "josé", "josé"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := normalizer.Username(test.input); got != test.want {
+				t.Errorf("Username(%q) = %q, want %q", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalizerEmail(t *testing.T) {
+	tests := []struct {
+		name      string
+		foldGmail bool
+		input     string
+		want      string
+	}{
+		{"case", false, "Jane@Example.COM", "jane@example.com"},
+		{"gmail untouched by default", false, "Jane.Doe+news@gmail.com", "jane.doe+news@gmail.com"},
+		{"gmail dots and tag", true, "Jane.Doe+news@gmail.com", "janedoe@gmail.com"},
+		{"googlemail alias", true, "jane.doe@googlemail.com", "janedoe@gmail.com"},
+		{"other domains keep dots", true, "jane.doe+news@example.com", "jane.doe+news@example.com"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := NewNormalizer(test.foldGmail).Email(test.input); got != test.want {
+				t.Errorf("Email(%q) = %q, want %q", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalizerApplyKeepsDisplayForm(t *testing.T) {
+	user := domain.User{Username: " JaneDoe ", Email: "Jane@Example.com"}
+	NewNormalizer(false).Apply(&user)
+
+	if user.Username != "JaneDoe" || user.Email != "Jane@Example.com" {
+		t.Errorf("display form = %q, %q; want trimmed original", user.Username, user.Email)
+	}
+	if user.UsernameNormalized != "janedoe" || user.EmailNormalized != "jane@example.com" {
+		t.Errorf("normalized form = %q, %q", user.UsernameNormalized, user.EmailNormalized)
+	}
+
+	partial := domain.User{Name: "Only name"}
+	NewNormalizer(false).Apply(&partial)
+	if partial.UsernameNormalized != "" || partial.EmailNormalized != "" {
+		t.Errorf("empty fields must stay empty, got %q, %q", partial.UsernameNormalized, partial.EmailNormalized)
+	}
+}
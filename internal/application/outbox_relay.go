@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// OutboxRelay publica los eventos pendientes del outbox a través de un
+// EventPublisher con semántica "at-least-once": un evento solo se marca como
+// publicado después de que el publisher confirmó su entrega, por lo que un
+// fallo intermedio lo reenvía.
+//
+// Los eventos se entregan en el orden en que sus transacciones quedan
+// visibles, que puede diferir del orden de Sequence: un evento cuya
+// transacción confirma más tarde se entrega después de los ya publicados.
+// Antes de publicar cada evento se le asigna PublishedSequence, que crece en
+// el orden de entrega. Los eventos de un mismo usuario se entregan en orden,
+// porque sus transacciones se serializan sobre la fila del usuario.
+//
+// Se asume una única instancia del relay por base de datos para preservar el orden.
+type OutboxRelay struct {
+	outbox       domain.OutboxRepository
+	publisher    domain.EventPublisher
+	batchSize    int
+	pollInterval time.Duration
+	maxBackoff   time.Duration
+}
+
+// NewOutboxRelay crea un relay que consulta el outbox cada pollInterval y
+// publica hasta batchSize eventos por pasada.
+func NewOutboxRelay(outbox domain.OutboxRepository, publisher domain.EventPublisher, batchSize int, pollInterval, maxBackoff time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:       outbox,
+		publisher:    publisher,
+		batchSize:    batchSize,
+		pollInterval: pollInterval,
+		maxBackoff:   maxBackoff,
+	}
+}
+
+// Run publica eventos hasta que el contexto sea cancelado.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Mientras haya lotes completos publicados, continúa sin esperar.
+		for r.relayBatch(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayBatch publica un lote de eventos pendientes. Se detiene en el primer
+// evento que no puede publicarse (o que aún espera su reintento) para no
+// entregar eventos posteriores antes que él. Retorna true si el lote se
+// publicó completo y podría haber más eventos pendientes.
+func (r *OutboxRelay) relayBatch(ctx context.Context) bool {
+	pending, err := r.outbox.FetchPending(r.batchSize)
+	if err != nil {
+		log.Printf("[Outbox] fetch failed: %v", err)
+		return false
+	}
+
+	for _, item := range pending {
+		if ctx.Err() != nil || time.Now().Before(item.NextAttemptAt) {
+			return false
+		}
+
+		event := item.Event
+		if event.PublishedSequence == 0 {
+			published, err := r.outbox.AssignPublishedSequence(event.Sequence)
+			if err != nil {
+				log.Printf("[Outbox] assign published sequence failed: %v", err)
+				return false
+			}
+			event.PublishedSequence = published
+		}
+
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			next := time.Now().Add(r.backoff(item.Attempts))
+			log.Printf("[Outbox] publish of event %d failed (attempt %d), retrying at %s: %v",
+				item.Event.Sequence, item.Attempts+1, next.Format(time.RFC3339), err)
+			if markErr := r.outbox.MarkFailed(item.Event.Sequence, err.Error(), next); markErr != nil {
+				log.Printf("[Outbox] mark failed: %v", markErr)
+			}
+			return false
+		}
+
+		if err := r.outbox.MarkPublished(item.Event.Sequence); err != nil {
+			// El evento se volverá a publicar: los consumidores deben deduplicar por ID.
+			log.Printf("[Outbox] mark published failed: %v", err)
+			return false
+		}
+	}
+
+	return len(pending) == r.batchSize
+}
+
+// backoff calcula la espera exponencial (1s, 2s, 4s, ...) acotada por maxBackoff.
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := time.Second << min(attempts, 20)
+	return min(delay, r.maxBackoff)
+}
@@ -0,0 +1,166 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// recordingPublisher registra la Sequence y la PublishedSequence de los
+// eventos publicados y falla mientras failures sea mayor que cero.
+type recordingPublisher struct {
+	published []int64
+	positions []int64
+	failures  int
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event domain.UserEvent) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event.Sequence)
+	p.positions = append(p.positions, event.PublishedSequence)
+	return nil
+}
+
+func TestUserMutationsEmitDomainEvents(t *testing.T) {
+	ctx := context.Background()
+	service, store := newTestUserService(HoldDeletedIdentity)
+	user := mustCreate(t, ctx, service, "jane", "jane@example.com")
+
+	if _, err := service.Update(ctx, &domain.User{ID: user.ID, Name: "Jane Doe", Email: "doe@example.com"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := service.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	events := store.events()
+	types := make([]domain.EventType, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+		if event.UserID != user.ID || event.Sequence != int64(i+1) || event.ID == "" {
+			t.Errorf("event %d = %+v", i, event)
+		}
+	}
+	want := []domain.EventType{domain.EventUserCreated, domain.EventUserUpdated, domain.EventUserDeleted}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("types = %v, want %v", types, want)
+	}
+
+	if got := events[1].ChangedFields; !reflect.DeepEqual(got, []string{"name", "email"}) {
+		t.Errorf("changed fields = %v, want [name email]", got)
+	}
+	if events[2].User == nil || events[2].User.Username != "jane" {
+		t.Errorf("deleted event carries %+v, want the user before deletion", events[2].User)
+	}
+}
+
+func TestOutboxRelayPublishesInOrder(t *testing.T) {
+	store := newMemoryStore()
+	for range 5 {
+		_ = store.Outbox().Append(&domain.UserEvent{Type: domain.EventUserCreated})
+	}
+	publisher := &recordingPublisher{}
+	relay := NewOutboxRelay(store.Outbox(), publisher, 2, time.Hour, time.Minute)
+
+	for relay.relayBatch(context.Background()) {
+	}
+
+	if want := []int64{1, 2, 3, 4, 5}; !reflect.DeepEqual(publisher.published, want) {
+		t.Errorf("published = %v, want %v", publisher.published, want)
+	}
+	if pending, _ := store.Outbox().FetchPending(10); len(pending) != 0 {
+		t.Errorf("%d events still pending", len(pending))
+	}
+}
+
+func TestOutboxRelayRetriesFailedEventsBeforeLaterOnes(t *testing.T) {
+	store := newMemoryStore()
+	for range 3 {
+		_ = store.Outbox().Append(&domain.UserEvent{Type: domain.EventUserUpdated})
+	}
+	publisher := &recordingPublisher{failures: 1}
+	relay := NewOutboxRelay(store.Outbox(), publisher, 10, time.Hour, time.Minute)
+
+	// El primer evento falla: no se publica ninguno posterior.
+	if relay.relayBatch(context.Background()) {
+		t.Error("relayBatch reported a complete batch after a failure")
+	}
+	if len(publisher.published) != 0 {
+		t.Fatalf("published = %v after a failure, want none", publisher.published)
+	}
+	entry := store.outbox[0]
+	if entry.attempts != 1 || entry.lastError != "broker unavailable" || !entry.nextAttemptAt.After(time.Now()) {
+		t.Errorf("failed entry = %+v", entry)
+	}
+
+	// Mientras no llegue el instante del reintento, el relay espera.
+	relay.relayBatch(context.Background())
+	if len(publisher.published) != 0 {
+		t.Fatalf("published = %v before the retry time", publisher.published)
+	}
+
+	store.outbox[0].nextAttemptAt = time.Now().Add(-time.Second)
+	relay.relayBatch(context.Background())
+	if want := []int64{1, 2, 3}; !reflect.DeepEqual(publisher.published, want) {
+		t.Errorf("published = %v, want %v", publisher.published, want)
+	}
+}
+
+func TestOutboxRelayAssignsPublishedSequenceInDeliveryOrder(t *testing.T) {
+	store := newMemoryStore()
+	for range 2 {
+		_ = store.Outbox().Append(&domain.UserEvent{Type: domain.EventUserUpdated})
+	}
+	// La transacción del evento 1 aún no confirmó: el relay solo ve el 2, que
+	// toma la primera posición y falla.
+	late := store.outbox[0]
+	store.outbox = slices.Clone(store.outbox[1:])
+	publisher := &recordingPublisher{failures: 1}
+	relay := NewOutboxRelay(store.Outbox(), publisher, 10, time.Hour, time.Minute)
+	relay.relayBatch(context.Background())
+
+	// Al confirmar el evento 1, el reintento del 2 conserva su posición y se
+	// entrega antes.
+	store.outbox = append([]outboxEntry{late}, store.outbox...)
+	store.outbox[1].nextAttemptAt = time.Now().Add(-time.Second)
+	relay.relayBatch(context.Background())
+
+	if want := []int64{2, 1}; !reflect.DeepEqual(publisher.published, want) {
+		t.Errorf("published = %v, want %v", publisher.published, want)
+	}
+	if want := []int64{1, 2}; !reflect.DeepEqual(publisher.positions, want) {
+		t.Errorf("published sequences = %v, want %v", publisher.positions, want)
+	}
+	for _, entry := range store.outbox {
+		if entry.publishedAt == nil {
+			t.Errorf("event %d still pending", entry.event.Sequence)
+		}
+	}
+}
+
+func TestOutboxRelayBackoff(t *testing.T) {
+	relay := NewOutboxRelay(nil, nil, 1, time.Second, 30*time.Second)
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{4, 16 * time.Second},
+		{5, 30 * time.Second},
+		{100, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := relay.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RetentionJob purga periódicamente los usuarios que llevan eliminados
+// lógicamente más tiempo que el período de retención configurado.
+type RetentionJob struct {
+	service   UserService
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewRetentionJob crea un RetentionJob que, cada interval, purga los usuarios
+// eliminados hace más de retention.
+func NewRetentionJob(service UserService, retention, interval time.Duration) *RetentionJob {
+	return &RetentionJob{service: service, retention: retention, interval: interval}
+}
+
+// Run ejecuta la purga inmediatamente y luego en cada intervalo, hasta que
+// el contexto sea cancelado.
+func (j *RetentionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge ejecuta una pasada de purga y registra el resultado.
+func (j *RetentionJob) purge(ctx context.Context) {
+	purged, err := j.service.PurgeDeleted(ctx, time.Now().Add(-j.retention))
+	if err != nil {
+		log.Printf("[Retention] purge failed: %v", err)
+		return
+	}
+	if purged > 0 {
+		log.Printf("[Retention] purged %d deleted users", purged)
+	}
+}
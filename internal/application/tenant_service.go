@@ -0,0 +1,141 @@
+package application
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// TenantService administra las organizaciones y resuelve sobre cuál opera
+// cada petición.
+type TenantService struct {
+	repo domain.TenantRepository
+	// baseDomain es el dominio bajo el cual cada organización tiene su
+	// subdominio (<id>.<baseDomain>); vacío = sin resolución por subdominio.
+	baseDomain string
+}
+
+// NewTenantService crea un TenantService sobre el repositorio indicado.
+func NewTenantService(repo domain.TenantRepository, baseDomain string) *TenantService {
+	return &TenantService{repo: repo, baseDomain: strings.ToLower(strings.Trim(baseDomain, "."))}
+}
+
+// Create valida el ID y persiste la organización.
+func (s *TenantService) Create(ctx context.Context, request *domain.TenantCreateRequest) (*domain.Tenant, error) {
+	if !domain.ValidTenantID(request.ID) {
+		return nil, domain.ErrInvalidTenantID
+	}
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return nil, domain.ErrValueNotNullable{Value: "name"}
+	}
+
+	now := time.Now().UTC()
+	actor := domain.ActorFromContext(ctx)
+	tenant := &domain.Tenant{
+		ID:        request.ID,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+		CreatedBy: actor,
+		UpdatedBy: actor,
+	}
+
+	if err := s.repo.Create(tenant); err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// FindAll retorna las organizaciones en orden de ID.
+func (s *TenantService) FindAll(ctx context.Context) ([]domain.Tenant, error) {
+	return s.repo.FindAll()
+}
+
+// FindById retorna la organización o ErrTenantNotFound.
+func (s *TenantService) FindById(ctx context.Context, id string) (*domain.Tenant, error) {
+	return s.repo.FindById(id)
+}
+
+// Update cambia el nombre de la organización; el ID es inmutable.
+func (s *TenantService) Update(ctx context.Context, id string, request *domain.TenantUpdateRequest) (*domain.Tenant, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return nil, domain.ErrValueNotNullable{Value: "name"}
+	}
+
+	tenant, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant.Name = name
+	tenant.UpdatedAt = time.Now().UTC()
+	tenant.UpdatedBy = domain.ActorFromContext(ctx)
+
+	if err := s.repo.Update(tenant); err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// Delete elimina una organización sin usuarios. La organización por defecto
+// no puede eliminarse.
+func (s *TenantService) Delete(ctx context.Context, id string) error {
+	if id == domain.DefaultTenantID {
+		return domain.ErrDefaultTenant
+	}
+	return s.repo.Delete(id)
+}
+
+// Resolve determina la organización sobre la que opera el principal.
+// requested es la organización pedida explícitamente (cabecera o
+// subdominio; vacío = ninguna). Un principal restringido a una organización
+// solo puede operar sobre ella y uno no restringido que no es operador, solo
+// sobre DefaultTenantID (ErrTenantForbidden si pide otra); los operadores
+// operan sobre la pedida o, en su defecto, sobre DefaultTenantID. Retorna
+// ErrTenantNotFound si la organización no existe.
+func (s *TenantService) Resolve(ctx context.Context, principal domain.Principal, requested string) (string, error) {
+	// 1. Un principal restringido no puede elegir otra organización, y uno
+	// que no es operador solo puede pedir la organización por defecto.
+	if principal.TenantID != "" {
+		if requested != "" && requested != principal.TenantID {
+			return "", domain.ErrTenantForbidden
+		}
+		requested = principal.TenantID
+	} else if !principal.IsOperator() && requested != "" && requested != domain.DefaultTenantID {
+		return "", domain.ErrTenantForbidden
+	}
+
+	// 2. Sin organización explícita se opera sobre la organización por defecto,
+	// que la migración 0005_tenants garantiza.
+	if requested == "" {
+		return domain.DefaultTenantID, nil
+	}
+
+	// 3. La organización debe existir.
+	if _, err := s.repo.FindById(requested); err != nil {
+		return "", err
+	}
+	return requested, nil
+}
+
+// TenantFromHost retorna la organización indicada por el subdominio de host
+// (e.g., "acme" para "acme.example.com" con dominio base "example.com"), o
+// "" si no hay dominio base configurado o host no es un subdominio directo.
+func (s *TenantService) TenantFromHost(host string) string {
+	if s.baseDomain == "" {
+		return ""
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	subdomain, found := strings.CutSuffix(strings.ToLower(strings.TrimSuffix(host, ".")), "."+s.baseDomain)
+	if !found || strings.Contains(subdomain, ".") {
+		return ""
+	}
+	return subdomain
+}
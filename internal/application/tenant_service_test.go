@@ -0,0 +1,172 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-api-restful/internal/domain"
+)
+
+// memoryTenants implementa domain.TenantRepository en memoria.
+type memoryTenants map[string]domain.Tenant
+
+func (r memoryTenants) Create(tenant *domain.Tenant) error {
+	if _, ok := r[tenant.ID]; ok {
+		return domain.ErrTenantIDInUse
+	}
+	r[tenant.ID] = *tenant
+	return nil
+}
+
+func (r memoryTenants) FindAll() ([]domain.Tenant, error) {
+	tenants := make([]domain.Tenant, 0, len(r))
+	for _, tenant := range r {
+		tenants = append(tenants, tenant)
+	}
+	return tenants, nil
+}
+
+func (r memoryTenants) FindById(id string) (*domain.Tenant, error) {
+	tenant, ok := r[id]
+	if !ok {
+		return nil, domain.ErrTenantNotFound
+	}
+	return &tenant, nil
+}
+
+func (r memoryTenants) Update(tenant *domain.Tenant) error {
+	if _, ok := r[tenant.ID]; !ok {
+		return domain.ErrTenantNotFound
+	}
+	r[tenant.ID] = *tenant
+	return nil
+}
+
+func (r memoryTenants) Delete(id string) error {
+	if _, ok := r[id]; !ok {
+		return domain.ErrTenantNotFound
+	}
+	delete(r, id)
+	return nil
+}
+
+// newTestTenantService crea un TenantService con las organizaciones default y acme.
+func newTestTenantService() *TenantService {
+	repo := memoryTenants{}
+	repo[domain.DefaultTenantID] = domain.Tenant{ID: domain.DefaultTenantID}
+	repo["acme"] = domain.Tenant{ID: "acme"}
+	return NewTenantService(repo, "Users.Example.com.")
+}
+
+func TestResolve(t *testing.T) {
+	service := newTestTenantService()
+	operator := domain.Principal{Subject: "ops", Roles: []string{domain.RoleAdmin}}
+	restricted := domain.Principal{Subject: "apikey:crm", TenantID: "acme"}
+	unrestricted := domain.Principal{Subject: "apikey:reports", Roles: []string{domain.RoleAuditor}}
+
+	tests := []struct {
+		name      string
+		principal domain.Principal
+		requested string
+		want      string
+		wantErr   error
+	}{
+		{name: "operator without request", principal: operator, want: domain.DefaultTenantID},
+		{name: "operator chooses a tenant", principal: operator, requested: "acme", want: "acme"},
+		{name: "operator requests an unknown tenant", principal: operator, requested: "globex", wantErr: domain.ErrTenantNotFound},
+		{name: "restricted principal without request", principal: restricted, want: "acme"},
+		{name: "restricted principal requests its tenant", principal: restricted, requested: "acme", want: "acme"},
+		{name: "restricted principal requests another tenant", principal: restricted, requested: domain.DefaultTenantID, wantErr: domain.ErrTenantForbidden},
+		{name: "non-operator without request", principal: unrestricted, want: domain.DefaultTenantID},
+		{name: "non-operator requests the default tenant", principal: unrestricted, requested: domain.DefaultTenantID, want: domain.DefaultTenantID},
+		{name: "non-operator chooses a tenant", principal: unrestricted, requested: "acme", wantErr: domain.ErrTenantForbidden},
+		{name: "restricted admin requests another tenant", principal: domain.Principal{Roles: []string{domain.RoleAdmin}, TenantID: "acme"}, requested: domain.DefaultTenantID, wantErr: domain.ErrTenantForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.Resolve(context.Background(), tt.principal, tt.requested)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("Resolve = %q, %v; want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTenantFromHost(t *testing.T) {
+	service := newTestTenantService()
+
+	tests := map[string]string{
+		"acme.users.example.com":      "acme",
+		"ACME.users.example.com:8443": "acme",
+		"acme.users.example.com.":     "acme",
+		"users.example.com":           "",
+		"a.b.users.example.com":       "",
+		"acme.other.com":              "",
+	}
+	for host, want := range tests {
+		if got := service.TenantFromHost(host); got != want {
+			t.Errorf("TenantFromHost(%q) = %q, want %q", host, got, want)
+		}
+	}
+
+	if got := NewTenantService(memoryTenants{}, "").TenantFromHost("acme.users.example.com"); got != "" {
+		t.Errorf("without base domain = %q, want none", got)
+	}
+}
+
+func TestTenantLifecycle(t *testing.T) {
+	service := newTestTenantService()
+	ctx := domain.WithPrincipal(context.Background(), domain.Principal{Subject: "ops"})
+
+	if _, err := service.Create(ctx, &domain.TenantCreateRequest{ID: "Not Valid", Name: "x"}); !errors.Is(err, domain.ErrInvalidTenantID) {
+		t.Errorf("invalid ID: err = %v", err)
+	}
+	if _, err := service.Create(ctx, &domain.TenantCreateRequest{ID: "globex", Name: " "}); !errors.Is(err, domain.ErrValueNotNullable{Value: "name"}) {
+		t.Errorf("blank name: err = %v", err)
+	}
+
+	created, err := service.Create(ctx, &domain.TenantCreateRequest{ID: "globex", Name: " Globex "})
+	if err != nil || created.Name != "Globex" || created.CreatedBy != "ops" {
+		t.Fatalf("Create = %+v, %v", created, err)
+	}
+	if _, err := service.Create(ctx, &domain.TenantCreateRequest{ID: "globex", Name: "Again"}); !errors.Is(err, domain.ErrTenantIDInUse) {
+		t.Errorf("duplicate ID: err = %v", err)
+	}
+
+	updated, err := service.Update(ctx, "globex", &domain.TenantUpdateRequest{Name: "Globex Corp"})
+	if err != nil || updated.Name != "Globex Corp" || updated.ID != "globex" {
+		t.Errorf("Update = %+v, %v", updated, err)
+	}
+
+	if err := service.Delete(ctx, domain.DefaultTenantID); !errors.Is(err, domain.ErrDefaultTenant) {
+		t.Errorf("delete default tenant: err = %v", err)
+	}
+	if err := service.Delete(ctx, "globex"); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+}
+
+func TestUsersAreIsolatedByTenant(t *testing.T) {
+	service, _ := newTestUserService(HoldDeletedIdentity)
+	acme := domain.WithTenant(context.Background(), "acme")
+	globex := domain.WithTenant(context.Background(), "globex")
+
+	// El mismo username y email pueden existir en organizaciones distintas.
+	jane := mustCreate(t, acme, service, "jane", "jane@example.com")
+	mustCreate(t, globex, service, "jane", "jane@example.com")
+
+	if jane.TenantID != "acme" {
+		t.Errorf("TenantID = %q, want acme", jane.TenantID)
+	}
+	if _, err := service.FindById(globex, jane.ID); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("FindById from another tenant: err = %v, want ErrUserNotFound", err)
+	}
+	if err := service.Delete(globex, jane.ID); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("Delete from another tenant: err = %v, want ErrUserNotFound", err)
+	}
+
+	users, err := service.FindAll(acme, domain.UserFilter{})
+	if err != nil || len(*users) != 1 || (*users)[0].ID != jane.ID {
+		t.Errorf("FindAll(acme) = %v, %v; want only jane of acme", users, err)
+	}
+}
@@ -0,0 +1,70 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-api-restful/internal/domain"
+)
+
+// batchOperations retorna un lote que crea dos usuarios y elimina existingID.
+func batchOperations(existingID string, secondUsername string) []domain.UserBatchOperation {
+	return []domain.UserBatchOperation{
+		{Index: 0, Op: domain.BatchCreate, Create: &domain.UserCreateRequest{Name: "Ann", Username: "ann", Email: "ann@example.com"}},
+		{Index: 1, Op: domain.BatchCreate, Create: &domain.UserCreateRequest{Name: "Bob", Username: secondUsername, Email: "bob@example.com"}},
+		{Index: 2, Op: domain.BatchDelete, ID: existingID},
+	}
+}
+
+func TestAtomicBatchAppliesAllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	service, store := newTestUserService(HoldDeletedIdentity)
+	jane := mustCreate(t, ctx, service, "jane", "jane@example.com")
+
+	results := service.Batch(ctx, batchOperations(jane.ID, "jane"), true)
+
+	if !errors.Is(results[1].Err, domain.ErrUsernameInUse) {
+		t.Errorf("failing operation: err = %v, want ErrUsernameInUse", results[1].Err)
+	}
+	for _, i := range []int{0, 2} {
+		if !errors.Is(results[i].Err, domain.ErrBatchNotApplied) || results[i].User != nil {
+			t.Errorf("operation %d = %+v, want ErrBatchNotApplied", i, results[i])
+		}
+	}
+	if len(store.users) != 1 || store.users[jane.ID].DeletedAt != nil || len(store.audit) != 1 {
+		t.Errorf("store changed by a rolled back batch: %d users, %d audit records", len(store.users), len(store.audit))
+	}
+
+	results = service.Batch(ctx, batchOperations(jane.ID, "bob"), true)
+	for i, result := range results {
+		if result.Err != nil {
+			t.Errorf("operation %d: %v", i, result.Err)
+		}
+	}
+	if results[0].User == nil || results[0].User.Username != "ann" || len(store.users) != 3 {
+		t.Errorf("results = %+v, %d users", results, len(store.users))
+	}
+}
+
+func TestBestEffortBatchAppliesEachOperationIndependently(t *testing.T) {
+	ctx := context.Background()
+	service, store := newTestUserService(HoldDeletedIdentity)
+	jane := mustCreate(t, ctx, service, "jane", "jane@example.com")
+
+	operations := append(batchOperations(jane.ID, "jane"),
+		domain.UserBatchOperation{Index: 3, Op: domain.BatchUpdate, Update: &domain.User{ID: "missing", Name: "Nobody"}})
+	results := service.Batch(ctx, operations, false)
+
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("independent operations failed: %v, %v", results[0].Err, results[2].Err)
+	}
+	if !errors.Is(results[1].Err, domain.ErrUsernameInUse) {
+		t.Errorf("duplicate: err = %v, want ErrUsernameInUse", results[1].Err)
+	}
+	if !errors.Is(results[3].Err, domain.ErrUserNotFound) || results[3].Index != 3 {
+		t.Errorf("missing user: result = %+v, want ErrUserNotFound", results[3])
+	}
+	if store.users[jane.ID].DeletedAt == nil || len(store.users) != 2 {
+		t.Errorf("store = %d users, jane deleted = %v", len(store.users), store.users[jane.ID].DeletedAt != nil)
+	}
+}
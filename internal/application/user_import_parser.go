@@ -0,0 +1,153 @@
+package application
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"user-api-restful/internal/domain"
+)
+
+// importRow es una fila de datos de un archivo de importación con los
+// errores detectados al leerla y validarla.
+type importRow struct {
+	// Row es el número de línea de la fila en el archivo.
+	Row     int
+	Request domain.UserCreateRequest
+	Errors  []domain.ImportRowError
+}
+
+// valid indica si la fila puede importarse.
+func (r *importRow) valid() bool {
+	return len(r.Errors) == 0
+}
+
+// reject agrega un error a la fila.
+func (r *importRow) reject(field, message string) {
+	r.Errors = append(r.Errors, domain.ImportRowError{Row: r.Row, Field: field, Message: message})
+}
+
+// parseImport lee las filas de un archivo CSV o NDJSON. Un archivo ilegible
+// (e.g., CSV sin las columnas requeridas) retorna ErrInvalidImportFile; los
+// problemas de filas individuales quedan registrados en cada fila.
+func parseImport(format domain.ImportFormat, mapping domain.ImportColumnMapping, payload []byte) ([]importRow, error) {
+	switch format {
+	case domain.ImportCSV:
+		return parseCSVImport(mapping, payload)
+	case domain.ImportNDJSON:
+		return parseNDJSONImport(payload)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", domain.ErrInvalidImportFile, format)
+	}
+}
+
+// parseCSVImport lee un CSV cuyo encabezado se asocia a los campos del
+// usuario según mapping (sin distinguir mayúsculas ni espacios alrededor).
+func parseCSVImport(mapping domain.ImportColumnMapping, payload []byte) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(payload, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: the file is empty", domain.ErrInvalidImportFile)
+		}
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImportFile, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	indexes := map[string]int{}
+	for field, column := range map[string]string{"name": mapping.Name, "username": mapping.Username, "email": mapping.Email} {
+		if column == "" {
+			column = field
+		}
+		index, ok := columns[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing column %q for field %s", domain.ErrInvalidImportFile, column, field)
+		}
+		indexes[field] = index
+	}
+
+	rows := make([]importRow, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImportFile, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		value := func(field string) string {
+			if indexes[field] < len(record) {
+				return strings.TrimSpace(record[indexes[field]])
+			}
+			return ""
+		}
+
+		rows = append(rows, importRow{
+			Row: line,
+			Request: domain.UserCreateRequest{
+				Name:     value("name"),
+				Username: value("username"),
+				Email:    value("email"),
+			},
+		})
+	}
+
+	return rows, nil
+}
+
+// parseNDJSONImport lee un objeto UserCreateRequest por línea, ignorando las líneas vacías.
+func parseNDJSONImport(payload []byte) ([]importRow, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(payload))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	rows := make([]importRow, 0)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		row := importRow{Row: line}
+		if err := json.Unmarshal(text, &row.Request); err != nil {
+			row.reject("", "invalid JSON object")
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImportFile, err)
+	}
+
+	return rows, nil
+}
+
+// WriteImportErrorsCSV escribe el reporte de errores de una importación como
+// CSV con las columnas row, field y message.
+func WriteImportErrorsCSV(w io.Writer, rowErrors []domain.ImportRowError) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"row", "field", "message"}); err != nil {
+		return err
+	}
+	for _, rowError := range rowErrors {
+		if err := writer.Write([]string{strconv.Itoa(rowError.Row), rowError.Field, rowError.Message}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,409 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// importLease es cuánto dura el derecho exclusivo de procesar una importación
+// sin renovarlo; se renueva en cada bloque.
+const importLease = 5 * time.Minute
+
+// UserImportService valida e importa archivos de usuarios (CSV o NDJSON).
+// Las importaciones se aplican por bloques, cada uno en su propia
+// transacción, guardando el progreso para poder reanudarlas.
+type UserImportService struct {
+	users      UserService
+	userRepo   domain.UserRepository
+	repo       domain.UserImportRepository
+	normalizer *Normalizer
+	validator  *validator.Validate
+	chunkSize  int
+	// owner identifica a este proceso al tomar importaciones.
+	owner string
+}
+
+// NewUserImportService crea un UserImportService que aplica las
+// importaciones en bloques de chunkSize filas.
+func NewUserImportService(users UserService, userRepo domain.UserRepository, repo domain.UserImportRepository, normalizer *Normalizer, chunkSize int) *UserImportService {
+	return &UserImportService{
+		users:      users,
+		userRepo:   userRepo,
+		repo:       repo,
+		normalizer: normalizer,
+		validator:  validator.New(),
+		chunkSize:  chunkSize,
+		owner:      newULID(time.Now()),
+	}
+}
+
+// DryRun valida el archivo completo, incluyendo los duplicados dentro del
+// archivo y contra la base de datos, sin crear ningún usuario.
+func (s *UserImportService) DryRun(ctx context.Context, format domain.ImportFormat, mapping domain.ImportColumnMapping, payload []byte) (*domain.ImportReport, error) {
+	rows, err := parseImport(format, mapping, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	s.validateRows(rows)
+	for start := 0; start < len(rows); start += s.chunkSize {
+		if err := s.checkTaken(ctx, rows[start:min(start+s.chunkSize, len(rows))]); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &domain.ImportReport{TotalRows: len(rows), Errors: make([]domain.ImportRowError, 0)}
+	for i := range rows {
+		if rows[i].valid() {
+			report.ValidRows++
+			continue
+		}
+		report.InvalidRows++
+		report.Errors = append(report.Errors, rows[i].Errors...)
+	}
+
+	return report, nil
+}
+
+// Start verifica que el archivo sea legible y registra una importación
+// pendiente, que se procesa en segundo plano (ver Process).
+func (s *UserImportService) Start(ctx context.Context, format domain.ImportFormat, mapping domain.ImportColumnMapping, payload []byte) (*domain.UserImport, error) {
+	rows, err := parseImport(format, mapping, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	t := time.Now()
+	userImport := &domain.UserImport{
+		ID:        newULID(t),
+		TenantID:  domain.TenantFromContext(ctx),
+		Format:    format,
+		Mapping:   mapping,
+		Status:    domain.ImportPending,
+		TotalRows: len(rows),
+		CreatedBy: domain.ActorFromContext(ctx),
+		CreatedAt: t.UTC(),
+		UpdatedAt: t.UTC(),
+	}
+
+	if err := s.repo.Create(userImport, payload); err != nil {
+		return nil, err
+	}
+
+	return userImport, nil
+}
+
+// FindById retorna una importación de la organización del contexto con su
+// progreso. Las de otras organizaciones se reportan como inexistentes.
+func (s *UserImportService) FindById(ctx context.Context, id string) (*domain.UserImport, error) {
+	userImport, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if userImport.TenantID != domain.TenantFromContext(ctx) {
+		return nil, domain.ErrImportNotFound
+	}
+	return userImport, nil
+}
+
+// Errors retorna el reporte de errores de una importación.
+func (s *UserImportService) Errors(ctx context.Context, id string) ([]domain.ImportRowError, error) {
+	if _, err := s.FindById(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.Errors(id)
+}
+
+// Resume vuelve a poner en cola una importación fallida; continúa desde la
+// última fila procesada. Retorna ErrImportNotResumable si ya se completó.
+func (s *UserImportService) Resume(ctx context.Context, id string) (*domain.UserImport, error) {
+	userImport, err := s.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch userImport.Status {
+	case domain.ImportCompleted:
+		return nil, domain.ErrImportNotResumable
+	case domain.ImportFailed:
+		userImport.Status, userImport.Error = domain.ImportPending, ""
+		userImport.UpdatedAt = time.Now().UTC()
+		if err := s.repo.Update(userImport); err != nil {
+			return nil, err
+		}
+	}
+
+	return userImport, nil
+}
+
+// Process aplica las filas pendientes de una importación, bloque a bloque,
+// si puede tomarla (otro proceso podría estar haciéndolo). Los usuarios se
+// atribuyen al actor que inició la importación y se crean en su
+// organización. Si el proceso se interrumpe, la importación se reanuda desde
+// el último bloque confirmado.
+func (s *UserImportService) Process(ctx context.Context, id string) (*domain.UserImport, error) {
+	claimed, err := s.repo.Claim(id, s.owner, time.Now().Add(importLease))
+	if err != nil || !claimed {
+		return nil, err
+	}
+
+	userImport, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.process(ctx, userImport)
+	if err != nil {
+		userImport.Status, userImport.Error = domain.ImportFailed, err.Error()
+	}
+
+	userImport.UpdatedAt = time.Now().UTC()
+	if updateErr := s.repo.Update(userImport); updateErr != nil {
+		return nil, updateErr
+	}
+
+	return userImport, err
+}
+
+// process aplica los bloques restantes de la importación.
+func (s *UserImportService) process(ctx context.Context, userImport *domain.UserImport) error {
+	payload, err := s.repo.Payload(userImport.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := parseImport(userImport.Format, userImport.Mapping, payload)
+	if err != nil {
+		return err
+	}
+
+	// Los duplicados dentro del archivo se evalúan sobre el archivo completo,
+	// de modo que el resultado no depende de dónde se reanude.
+	s.validateRows(rows)
+
+	ctx = domain.WithPrincipal(ctx, domain.Principal{Subject: userImport.CreatedBy})
+	ctx = domain.WithTenant(ctx, userImport.TenantID)
+	ctx = domain.WithRequestID(ctx, "import-"+userImport.ID)
+
+	userImport.Status, userImport.TotalRows = domain.ImportRunning, len(rows)
+
+	for userImport.ProcessedRows < len(rows) {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		chunk := rows[userImport.ProcessedRows:min(userImport.ProcessedRows+s.chunkSize, len(rows))]
+		created, err := s.applyChunk(ctx, chunk)
+		if err != nil {
+			return err
+		}
+
+		rowErrors := make([]domain.ImportRowError, 0)
+		for i := range chunk {
+			rowErrors = append(rowErrors, chunk[i].Errors...)
+			if !chunk[i].valid() {
+				userImport.FailedRows++
+			}
+		}
+		if err := s.repo.AppendErrors(userImport.ID, rowErrors); err != nil {
+			return err
+		}
+
+		userImport.ProcessedRows += len(chunk)
+		userImport.CreatedRows += created
+		userImport.UpdatedAt = time.Now().UTC()
+		if err := s.repo.Update(userImport); err != nil {
+			return err
+		}
+
+		// Renueva el lease para el próximo bloque.
+		if claimed, err := s.repo.Claim(userImport.ID, s.owner, time.Now().Add(importLease)); err != nil || !claimed {
+			return errors.Join(errors.New("lost the import lease"), err)
+		}
+	}
+
+	completedAt := time.Now().UTC()
+	userImport.Status, userImport.CompletedAt = domain.ImportCompleted, &completedAt
+
+	return nil
+}
+
+// applyChunk crea los usuarios válidos de un bloque en una única transacción.
+// Si alguno falla (e.g., otro cliente tomó el username mientras tanto), el
+// bloque se reintenta fila por fila para aplicar las demás. Retorna la
+// cantidad de usuarios creados; los rechazos quedan en los errores de cada fila.
+func (s *UserImportService) applyChunk(ctx context.Context, chunk []importRow) (int, error) {
+	if err := s.checkTaken(ctx, chunk); err != nil {
+		return 0, err
+	}
+
+	operations := make([]domain.UserBatchOperation, 0, len(chunk))
+	for i := range chunk {
+		if chunk[i].valid() {
+			operations = append(operations, domain.UserBatchOperation{Index: i, Op: domain.BatchCreate, Create: &chunk[i].Request})
+		}
+	}
+	if len(operations) == 0 {
+		return 0, nil
+	}
+
+	results := s.users.Batch(ctx, operations, true)
+	if results[0].Err == nil {
+		return len(results), nil
+	}
+
+	created := 0
+	for _, result := range s.users.Batch(ctx, operations, false) {
+		if result.Err == nil {
+			created++
+			continue
+		}
+		var errValue domain.ErrValueNotNullable
+		if !errors.Is(result.Err, domain.ErrUsernameInUse) && !errors.Is(result.Err, domain.ErrEmailInUse) && !errors.As(result.Err, &errValue) {
+			log.Printf("[Import] row %d: %v", chunk[result.Index].Row, result.Err)
+		}
+		chunk[result.Index].reject(importErrorField(result.Err), result.Err.Error())
+	}
+
+	return created, nil
+}
+
+// validateRows aplica a cada fila las reglas de UserCreateRequest y rechaza
+// las que repiten un username o email (normalizados) de una fila anterior.
+func (s *UserImportService) validateRows(rows []importRow) {
+	firstUsername, firstEmail := map[string]int{}, map[string]int{}
+
+	for i := range rows {
+		row := &rows[i]
+		if !row.valid() {
+			continue
+		}
+
+		if err := s.validator.Struct(row.Request); err != nil {
+			var validationErrors validator.ValidationErrors
+			if errors.As(err, &validationErrors) {
+				for _, fieldError := range validationErrors {
+					field := strings.ToLower(fieldError.Field())
+					if fieldError.Tag() == "email" {
+						row.reject(field, "email format is invalid")
+					} else {
+						row.reject(field, field+" is required and cannot be blank")
+					}
+				}
+			}
+			continue
+		}
+
+		username := s.normalizer.Username(row.Request.Username)
+		if first, ok := firstUsername[username]; ok {
+			row.reject("username", fmt.Sprintf("duplicate username in file (first seen at row %d)", first))
+		} else {
+			firstUsername[username] = row.Row
+		}
+
+		email := s.normalizer.Email(row.Request.Email)
+		if first, ok := firstEmail[email]; ok {
+			row.reject("email", fmt.Sprintf("duplicate email in file (first seen at row %d)", first))
+		} else {
+			firstEmail[email] = row.Row
+		}
+	}
+}
+
+// checkTaken rechaza las filas válidas cuyo username o email ya existen en
+// la organización del contexto.
+func (s *UserImportService) checkTaken(ctx context.Context, rows []importRow) error {
+	usernames, emails := make([]string, 0, len(rows)), make([]string, 0, len(rows))
+	for i := range rows {
+		if rows[i].valid() {
+			usernames = append(usernames, s.normalizer.Username(rows[i].Request.Username))
+			emails = append(emails, s.normalizer.Email(rows[i].Request.Email))
+		}
+	}
+
+	takenUsernames, takenEmails, err := s.userRepo.ForTenant(domain.TenantFromContext(ctx)).TakenIdentities(usernames, emails)
+	if err != nil {
+		return err
+	}
+
+	usernameTaken, emailTaken := map[string]bool{}, map[string]bool{}
+	for _, username := range takenUsernames {
+		usernameTaken[username] = true
+	}
+	for _, email := range takenEmails {
+		emailTaken[email] = true
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		if !row.valid() {
+			continue
+		}
+		if usernameTaken[s.normalizer.Username(row.Request.Username)] {
+			row.reject("username", domain.ErrUsernameInUse.Error())
+		}
+		if emailTaken[s.normalizer.Email(row.Request.Email)] {
+			row.reject("email", domain.ErrEmailInUse.Error())
+		}
+	}
+
+	return nil
+}
+
+// importErrorField retorna el campo asociado a un error de creación, si lo hay.
+func importErrorField(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrUsernameInUse):
+		return "username"
+	case errors.Is(err, domain.ErrEmailInUse):
+		return "email"
+	default:
+		return ""
+	}
+}
+
+// ImportWorker procesa en segundo plano las importaciones pendientes o
+// interrumpidas (e.g., por un reinicio del servidor).
+type ImportWorker struct {
+	service      *UserImportService
+	pollInterval time.Duration
+}
+
+// NewImportWorker crea un ImportWorker que busca importaciones cada pollInterval.
+func NewImportWorker(service *UserImportService, pollInterval time.Duration) *ImportWorker {
+	return &ImportWorker{service: service, pollInterval: pollInterval}
+}
+
+// Run procesa importaciones hasta que el contexto sea cancelado.
+func (w *ImportWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		imports, err := w.service.repo.FindRunnable(10)
+		if err != nil {
+			log.Printf("[Import] failed to load imports: %v", err)
+		}
+		for _, userImport := range imports {
+			processed, err := w.service.Process(ctx, userImport.ID)
+			if err != nil {
+				log.Printf("[Import] import %s failed: %v", userImport.ID, err)
+			} else if processed != nil && processed.Status == domain.ImportCompleted {
+				log.Printf("[Import] import %s completed: %d created, %d failed", processed.ID, processed.CreatedRows, processed.FailedRows)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"user-api-restful/internal/domain"
+)
+
+// newTestImportService crea un UserImportService con bloques de chunkSize
+// filas sobre un UserServiceImpl en memoria.
+func newTestImportService(chunkSize int) (*UserImportService, *UserServiceImpl, *memoryStore, *memoryImports) {
+	users, store := newTestUserService(HoldDeletedIdentity)
+	imports := newMemoryImports()
+	return NewUserImportService(users, store.Users(), imports, NewNormalizer(true), chunkSize), users, store, imports
+}
+
+// rowErrorSummary resume los errores como "fila:campo".
+func rowErrorSummary(rowErrors []domain.ImportRowError) []string {
+	summary := make([]string, len(rowErrors))
+	for i, rowError := range rowErrors {
+		summary[i] = fmt.Sprintf("%d:%s", rowError.Row, rowError.Field)
+	}
+	return summary
+}
+
+func TestParseCSVImportMapsColumns(t *testing.T) {
+	payload := "\ufeffFull Name, Login ,E-Mail\nJane Doe, jane ,jane@example.com\nJohn\n"
+	rows, err := parseImport(domain.ImportCSV, domain.ImportColumnMapping{Name: "full name", Username: "LOGIN", Email: "e-mail"}, []byte(payload))
+	if err != nil {
+		t.Fatalf("parseImport: %v", err)
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("%d rows, want 2", len(rows))
+	}
+	want := domain.UserCreateRequest{Name: "Jane Doe", Username: "jane", Email: "jane@example.com"}
+	if rows[0].Row != 2 || rows[0].Request != want {
+		t.Errorf("row = %+v, want line 2 with %+v", rows[0], want)
+	}
+	if rows[1].Row != 3 || rows[1].Request.Name != "John" || rows[1].Request.Email != "" {
+		t.Errorf("short row = %+v", rows[1])
+	}
+
+	_, err = parseImport(domain.ImportCSV, domain.ImportColumnMapping{}, []byte("name,username\nJane,jane\n"))
+	if !errors.Is(err, domain.ErrInvalidImportFile) {
+		t.Errorf("missing column: err = %v, want ErrInvalidImportFile", err)
+	}
+	if _, err := parseImport("xlsx", domain.ImportColumnMapping{}, nil); !errors.Is(err, domain.ErrInvalidImportFile) {
+		t.Errorf("unknown format: err = %v, want ErrInvalidImportFile", err)
+	}
+}
+
+func TestParseNDJSONImportKeepsLineNumbers(t *testing.T) {
+	payload := `{"name":"Jane","username":"jane","email":"jane@example.com"}
+
+not json
+{"name":"John","username":"john","email":"john@example.com"}`
+	rows, err := parseImport(domain.ImportNDJSON, domain.ImportColumnMapping{}, []byte(payload))
+	if err != nil {
+		t.Fatalf("parseImport: %v", err)
+	}
+
+	lines := []int{rows[0].Row, rows[1].Row, rows[2].Row}
+	if !reflect.DeepEqual(lines, []int{1, 3, 4}) {
+		t.Errorf("lines = %v, want [1 3 4]", lines)
+	}
+	if rows[1].valid() || rows[1].Errors[0].Message != "invalid JSON object" {
+		t.Errorf("invalid line = %+v", rows[1])
+	}
+}
+
+func TestImportDryRunReportsInvalidAndDuplicateRows(t *testing.T) {
+	service, users, store, _ := newTestImportService(2)
+	mustCreate(t, context.Background(), users, "taken", "taken@example.com")
+
+	payload := strings.Join([]string{
+		"name,username,email",
+		"Ann,ann,ann@example.com",
+		"Bob,bob,not-an-email",
+		"Annie,ANN,ann2@example.com",
+		"Taken,other,Taken@Example.com",
+		"Cid,cid,cid@example.com",
+	}, "\n")
+	report, err := service.DryRun(context.Background(), domain.ImportCSV, domain.ImportColumnMapping{}, []byte(payload))
+	if err != nil {
+		t.Fatalf("DryRun: %v", err)
+	}
+
+	if report.TotalRows != 5 || report.ValidRows != 2 || report.InvalidRows != 3 {
+		t.Errorf("report totals = %d/%d/%d, want 5/2/3", report.TotalRows, report.ValidRows, report.InvalidRows)
+	}
+	if got, want := rowErrorSummary(report.Errors), []string{"3:email", "4:username", "5:email"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("errors = %v, want %v", got, want)
+	}
+	if len(store.users) != 1 {
+		t.Errorf("dry run created %d users", len(store.users)-1)
+	}
+}
+
+func TestImportProcessesInChunksAndResumes(t *testing.T) {
+	service, _, store, imports := newTestImportService(2)
+	ctx := domain.WithTenant(asActor("importer"), "acme")
+
+	payload := strings.Join([]string{
+		`{"name":"Ann","username":"ann","email":"ann@example.com"}`,
+		`{"name":"Bob","username":"bob","email":"bob@example.com"}`,
+		`{"name":"","username":"cid","email":"cid@example.com"}`,
+		`{"name":"Dee","username":"dee","email":"dee@example.com"}`,
+		`{"name":"Eve","username":"eve","email":"eve@example.com"}`,
+	}, "\n")
+	started, err := service.Start(ctx, domain.ImportNDJSON, domain.ImportColumnMapping{}, []byte(payload))
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if started.Status != domain.ImportPending || started.TotalRows != 5 || started.CreatedBy != "importer" {
+		t.Errorf("started = %+v", started)
+	}
+
+	// Simula un proceso interrumpido tras confirmar el primer bloque.
+	interrupted := imports.imports[started.ID]
+	interrupted.Status, interrupted.ProcessedRows, interrupted.CreatedRows = domain.ImportRunning, 2, 2
+	imports.imports[started.ID] = interrupted
+
+	done, err := service.Process(context.Background(), started.ID)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if done.Status != domain.ImportCompleted || done.ProcessedRows != 5 || done.CreatedRows != 4 || done.FailedRows != 1 || done.CompletedAt == nil {
+		t.Errorf("done = %+v", done)
+	}
+
+	var usernames []string
+	for _, user := range store.users {
+		usernames = append(usernames, user.Username)
+		if user.TenantID != "acme" || user.CreatedBy != "importer" {
+			t.Errorf("user %s created in %q by %q", user.Username, user.TenantID, user.CreatedBy)
+		}
+	}
+	if len(usernames) != 2 {
+		t.Errorf("created %v, want only the rows after the resumed position (dee, eve)", usernames)
+	}
+
+	rowErrors, err := service.Errors(ctx, started.ID)
+	if err != nil || !reflect.DeepEqual(rowErrorSummary(rowErrors), []string{"3:name"}) {
+		t.Errorf("Errors = %+v, %v", rowErrors, err)
+	}
+
+	if _, err := service.Resume(ctx, started.ID); !errors.Is(err, domain.ErrImportNotResumable) {
+		t.Errorf("Resume completed import: err = %v, want ErrImportNotResumable", err)
+	}
+	if _, err := service.FindById(domain.WithTenant(context.Background(), "globex"), started.ID); !errors.Is(err, domain.ErrImportNotFound) {
+		t.Errorf("other tenant: err = %v, want ErrImportNotFound", err)
+	}
+}
+
+func TestImportRejectsRowsTakenWhileProcessing(t *testing.T) {
+	service, users, store, _ := newTestImportService(10)
+	ctx := context.Background()
+
+	started, err := service.Start(ctx, domain.ImportCSV, domain.ImportColumnMapping{}, []byte("name,username,email\nAnn,ann,ann@example.com\nBob,bob,bob@example.com\n"))
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	// Otro cliente crea "bob" después de iniciar la importación.
+	mustCreate(t, ctx, users, "bob", "robert@example.com")
+
+	done, err := service.Process(ctx, started.ID)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if done.CreatedRows != 1 || done.FailedRows != 1 || len(store.users) != 2 {
+		t.Errorf("done = %+v with %d users", done, len(store.users))
+	}
+}
+
+func TestWriteImportErrorsCSV(t *testing.T) {
+	var buffer bytes.Buffer
+	err := WriteImportErrorsCSV(&buffer, []domain.ImportRowError{{Row: 3, Field: "email", Message: "email format is invalid"}, {Row: 7, Message: "invalid, \"quoted\""}})
+	if err != nil {
+		t.Fatalf("WriteImportErrorsCSV: %v", err)
+	}
+
+	want := "row,field,message\n3,email,email format is invalid\n7,,\"invalid, \"\"quoted\"\"\"\n"
+	if buffer.String() != want {
+		t.Errorf("csv = %q, want %q", buffer.String(), want)
+	}
+}
@@ -0,0 +1,209 @@
+package application
+
+import (
+	"context"
+	"html"
+	"sort"
+	"strings"
+	"unicode/utf8"
+	"user-api-restful/internal/domain"
+)
+
+const (
+	// MinSearchQueryLength es la cantidad mínima de caracteres de una búsqueda.
+	MinSearchQueryLength = 3
+	// DefaultSearchLimit es el tamaño de página cuando el cliente no lo indica.
+	DefaultSearchLimit = 20
+	// MaxSearchLimit es el tamaño de página máximo permitido.
+	MaxSearchLimit = 100
+	// searchSimilarityThreshold replica el umbral por defecto de pg_trgm (%).
+	searchSimilarityThreshold = 0.3
+)
+
+// Search valida la consulta, la resuelve con el repositorio (de forma nativa
+// si implementa domain.UserSearcher, o con la búsqueda genérica en otro caso)
+// y marca los fragmentos coincidentes de cada resultado.
+func (u *UserServiceImpl) Search(ctx context.Context, query domain.UserSearchQuery) (*domain.UserSearchResult, error) {
+	query.Query = strings.TrimSpace(query.Query)
+	if utf8.RuneCountInString(query.Query) < MinSearchQueryLength {
+		return nil, domain.ErrSearchQueryTooShort
+	}
+	if query.Limit <= 0 {
+		query.Limit = DefaultSearchLimit
+	}
+	if query.Limit > MaxSearchLimit {
+		query.Limit = MaxSearchLimit
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	var result *domain.UserSearchResult
+	var err error
+
+	users := u.users(ctx)
+	if searcher, ok := users.(domain.UserSearcher); ok {
+		result, err = searcher.Search(query)
+	} else {
+		result, err = searchAll(users, query)
+	}
+
+	if err != nil {
+		return nil, u.mapRepositoryError(err)
+	}
+
+	terms := searchTerms(query.Query)
+	for i := range result.Hits {
+		result.Hits[i].Highlights = highlightUser(&result.Hits[i].User, terms)
+	}
+
+	result.Limit = query.Limit
+	result.Offset = query.Offset
+
+	return result, nil
+}
+
+// searchAll es la implementación portable de la búsqueda: recorre todos los
+// usuarios y combina coincidencia por subcadena con similitud de trigramas.
+func searchAll(repo domain.UserRepository, query domain.UserSearchQuery) (*domain.UserSearchResult, error) {
+	users, err := repo.FindAll(domain.UserFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	needle := strings.ToLower(query.Query)
+	hits := make([]domain.UserSearchHit, 0)
+
+	for _, user := range *users {
+		rank := 0.0
+		for _, field := range []string{user.Name, user.Username, user.Email} {
+			value := strings.ToLower(field)
+			score := trigramSimilarity(value, needle)
+			if strings.Contains(value, needle) {
+				score += 1
+			}
+			// Acumula por campo: coincidir en varios campos aumenta la relevancia.
+			if score >= searchSimilarityThreshold {
+				rank += score
+			}
+		}
+
+		if rank >= searchSimilarityThreshold {
+			hits = append(hits, domain.UserSearchHit{User: user, Rank: rank})
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hits[i].User.ID < hits[j].User.ID
+	})
+
+	total := int64(len(hits))
+	start := min(query.Offset, len(hits))
+	end := min(start+query.Limit, len(hits))
+
+	return &domain.UserSearchResult{Hits: hits[start:end], Total: total}, nil
+}
+
+// trigramSimilarity calcula la similitud de Jaccard entre los conjuntos de
+// trigramas de a y b, con el mismo relleno de espacios que usa pg_trgm.
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for trigram := range ta {
+		if tb[trigram] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// trigrams retorna el conjunto de trigramas de cada palabra de s.
+func trigrams(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.FieldsFunc(s, isSearchSeparator) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			set[string(runes[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// searchTerms divide la consulta en términos en minúsculas para el resaltado.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), isSearchSeparator)
+}
+
+// isSearchSeparator define los separadores de palabras de la búsqueda.
+func isSearchSeparator(r rune) bool {
+	return r == ' ' || r == '\t' || r == '@' || r == '.' || r == '_' || r == '-' || r == '+'
+}
+
+// highlightUser retorna, por campo, el valor con los términos marcados.
+// Omite los campos en los que no aparece ningún término.
+func highlightUser(user *domain.User, terms []string) map[string]string {
+	highlights := map[string]string{}
+	for field, value := range map[string]string{"name": user.Name, "username": user.Username, "email": user.Email} {
+		if marked, ok := highlight(value, terms); ok {
+			highlights[field] = marked
+		}
+	}
+	if len(highlights) == 0 {
+		return nil
+	}
+	return highlights
+}
+
+// highlight envuelve en <mark></mark> cada aparición (sin distinguir
+// mayúsculas) de los términos en value. El resto del texto se escapa como HTML.
+func highlight(value string, terms []string) (string, bool) {
+	lower := strings.ToLower(value)
+	if len(lower) != len(value) {
+		// El cambio de mayúsculas alteró la longitud en bytes; no es posible
+		// mapear posiciones de forma segura.
+		return "", false
+	}
+
+	marked := make([]bool, len(value))
+	found := false
+	for _, term := range terms {
+		for from := 0; from < len(lower); {
+			index := strings.Index(lower[from:], term)
+			if index < 0 {
+				break
+			}
+			for i := from + index; i < from+index+len(term); i++ {
+				marked[i] = true
+			}
+			found = true
+			from += index + len(term)
+		}
+	}
+	if !found {
+		return "", false
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(value); {
+		j := i
+		for j < len(value) && marked[j] == marked[i] {
+			j++
+		}
+		if marked[i] {
+			builder.WriteString("<mark>" + html.EscapeString(value[i:j]) + "</mark>")
+		} else {
+			builder.WriteString(html.EscapeString(value[i:j]))
+		}
+		i = j
+	}
+
+	return builder.String(), true
+}
@@ -0,0 +1,77 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-api-restful/internal/domain"
+)
+
+func TestSearchRanksAndHighlights(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestUserService(HoldDeletedIdentity)
+	exact := mustCreate(t, ctx, service, "jonathan", "jonathan@example.com")
+	fuzzy := mustCreate(t, ctx, service, "jonatan", "jonatan@example.org")
+	mustCreate(t, ctx, service, "mary", "mary@example.net")
+
+	result, err := service.Search(ctx, domain.UserSearchQuery{Query: "Jonathan"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if result.Total != 2 || len(result.Hits) != 2 {
+		t.Fatalf("total = %d, hits = %d; want 2 and 2", result.Total, len(result.Hits))
+	}
+	if result.Hits[0].User.ID != exact.ID || result.Hits[1].User.ID != fuzzy.ID {
+		t.Errorf("order = %s, %s; want the exact match first", result.Hits[0].User.Username, result.Hits[1].User.Username)
+	}
+	if got := result.Hits[0].Highlights["username"]; got != "<mark>jonathan</mark>" {
+		t.Errorf("username highlight = %q", got)
+	}
+	if _, ok := result.Hits[1].Highlights["username"]; ok {
+		t.Errorf("fuzzy match must not be highlighted: %v", result.Hits[1].Highlights)
+	}
+	if result.Limit != DefaultSearchLimit || result.Offset != 0 {
+		t.Errorf("page = %d/%d; want the defaults", result.Limit, result.Offset)
+	}
+}
+
+func TestSearchPaginatesAndClampsLimit(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestUserService(HoldDeletedIdentity)
+	for _, username := range []string{"anna1", "anna2", "anna3"} {
+		mustCreate(t, ctx, service, username, username+"@example.com")
+	}
+
+	result, err := service.Search(ctx, domain.UserSearchQuery{Query: "anna", Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if result.Total != 3 || len(result.Hits) != 1 {
+		t.Errorf("total = %d, hits = %d; want 3 and 1", result.Total, len(result.Hits))
+	}
+
+	result, err = service.Search(ctx, domain.UserSearchQuery{Query: "anna", Limit: 1000})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if result.Limit != MaxSearchLimit {
+		t.Errorf("limit = %d, want %d", result.Limit, MaxSearchLimit)
+	}
+}
+
+func TestSearchRejectsShortQueries(t *testing.T) {
+	service, _ := newTestUserService(HoldDeletedIdentity)
+
+	_, err := service.Search(context.Background(), domain.UserSearchQuery{Query: "  ab  "})
+	if !errors.Is(err, domain.ErrSearchQueryTooShort) {
+		t.Errorf("err = %v, want ErrSearchQueryTooShort", err)
+	}
+}
+
+func TestHighlightEscapesHTML(t *testing.T) {
+	got, ok := highlight("<b>Ana</b>", []string{"ana"})
+	if !ok || got != "&lt;b&gt;<mark>Ana</mark>&lt;/b&gt;" {
+		t.Errorf("highlight = %q, %v", got, ok)
+	}
+}
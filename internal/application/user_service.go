@@ -1,6 +1,10 @@
 package application
 
-import "user-api-restful/internal/domain"
+import (
+	"context"
+	"time"
+	"user-api-restful/internal/domain"
+)
 
 // Package application define las interfaces y estructuras de los servicios
 // de la aplicación que contienen la lógica de negocio principal.
@@ -8,19 +12,46 @@ import "user-api-restful/internal/domain"
 // UserService define el contract para las operaciones de negocio relacionadas
 // con la gestión de usuarios. Actúa como orquestador entre el puerto de entrada
 // (e.g., HTTP handler) y la capa de dominio/persistencia.
+//
+// Todas las operaciones reciben el context.Context de la petición, del que se
+// obtiene el principal autenticado (actor) para la atribución de cambios.
 type UserService interface {
 	// Create valida los datos de entrada y persiste un nuevo usuario.
 	// Retorna la entidad User creada y puede retornar errores como
 	// ErrUsernameInUse o ErrEmailInUse.
-	Create(user *domain.UserCreateRequest) (*domain.User, error)
-	// FindAll recupera la lista completa de todos los usuarios.
-	FindAll() (*[]domain.User, error)
+	Create(ctx context.Context, user *domain.UserCreateRequest) (*domain.User, error)
+	// FindAll recupera la lista de usuarios que cumplen el filtro.
+	FindAll(ctx context.Context, filter domain.UserFilter) (*[]domain.User, error)
+	// Export invoca fn por cada usuario que cumple el filtro, en orden de ID,
+	// sin cargarlos todos en memoria.
+	Export(ctx context.Context, filter domain.UserFilter, fn func(user *domain.User) error) error
 	// FindById recupera un usuario específico utilizando su ID.
-	// Retorna ErrUserNotFound si el usuario no existe.
-	FindById(id string) (*domain.User, error)
+	// Retorna ErrUserNotFound si el usuario no existe. Si se indican fields,
+	// solo se leen esos campos.
+	FindById(ctx context.Context, id string, fields ...string) (*domain.User, error)
 	// Update aplica los cambios al usuario proporcionado.
 	// Retorna ErrUserNotFound si el usuario a actualizar no existe.
-	Update(user *domain.User) (*domain.User, error)
-	// Delete elimina un usuario del sistema por su ID.
-	Delete(id string) error
+	Update(ctx context.Context, user *domain.User) (*domain.User, error)
+	// Delete elimina lógicamente un usuario del sistema por su ID.
+	Delete(ctx context.Context, id string) error
+	// Restore revierte la eliminación lógica de un usuario.
+	// Retorna ErrUserNotFound si no existe o ErrUserNotDeleted si no está eliminado.
+	Restore(ctx context.Context, id string) (*domain.User, error)
+	// ChangeStatus aplica una acción del ciclo de vida (e.g., suspend) a la
+	// cuenta del usuario, con el motivo indicado. Retorna
+	// ErrInvalidStatusTransition si la acción no se admite desde el estado
+	// actual o ErrStatusReasonRequired si requiere un motivo.
+	ChangeStatus(ctx context.Context, id string, action domain.UserStatusAction, reason string) (*domain.User, error)
+	// PurgeDeleted elimina permanentemente los usuarios eliminados antes del
+	// instante indicado y retorna cuántos fueron purgados.
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
+	// Batch aplica un lote de operaciones de creación, actualización y
+	// eliminación. Con atomic, todas se ejecutan en una única transacción: si
+	// una falla, ninguna se aplica y las demás retornan ErrBatchNotApplied. Sin
+	// atomic, cada operación se aplica en su propia transacción. Retorna un
+	// resultado por operación, en el mismo orden.
+	Batch(ctx context.Context, operations []domain.UserBatchOperation, atomic bool) []domain.UserBatchResult
+	// Search busca usuarios por coincidencia parcial o aproximada en name,
+	// username y email. Retorna ErrSearchQueryTooShort si la consulta es muy corta.
+	Search(ctx context.Context, query domain.UserSearchQuery) (*domain.UserSearchResult, error)
 }
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -18,48 +19,46 @@ type UserServiceImpl struct {
 	Repo domain.UserRepository
 	// txPort es el contract para manejar los límites transaccionales.
 	txPort domain.UserTransactionPort
+	// normalizer canonicaliza username y email antes de persistirlos.
+	normalizer *Normalizer
+	// identityPolicy decide si el username/email de un usuario eliminado se retienen o liberan.
+	identityPolicy DeletedIdentityPolicy
 }
 
+// DeletedIdentityPolicy define qué ocurre con el username y el email de un
+// usuario eliminado lógicamente mientras no sea purgado.
+type DeletedIdentityPolicy string
+
+const (
+	// HoldDeletedIdentity retiene username y email: nadie más puede usarlos
+	// y el usuario siempre puede ser restaurado.
+	HoldDeletedIdentity DeletedIdentityPolicy = "hold"
+	// ReleaseDeletedIdentity libera username y email al eliminar; la
+	// restauración falla con conflicto si otro usuario los tomó.
+	ReleaseDeletedIdentity DeletedIdentityPolicy = "release"
+)
+
 // NewUserServiceImpl crea e inicializa un nuevo UserServiceImpl.
 // Recibe los contratos (interfaces) de Repositorio y Transacción, siguiendo el
 // patrón de Inyección de Dependencias.
-func NewUserServiceImpl(repo domain.UserRepository, tx domain.UserTransactionPort) *UserServiceImpl {
-	return &UserServiceImpl{Repo: repo, txPort: tx}
+func NewUserServiceImpl(repo domain.UserRepository, tx domain.UserTransactionPort, normalizer *Normalizer, identityPolicy DeletedIdentityPolicy) *UserServiceImpl {
+	return &UserServiceImpl{Repo: repo, txPort: tx, normalizer: normalizer, identityPolicy: identityPolicy}
 }
 
 // Asegura que UserServiceImpl implemente la interfaz UserService en tiempo de compilación.
 var _ UserService = (*UserServiceImpl)(nil)
 
 // Create valida los datos de entrada, genera un ID único (ULID) y persiste
-// el nuevo usuario dentro de una transacción.
-func (u *UserServiceImpl) Create(user *domain.UserCreateRequest) (*domain.User, error) {
+// el nuevo usuario dentro de una transacción, junto con su registro de
+// auditoría y el evento UserCreated.
+func (u *UserServiceImpl) Create(ctx context.Context, user *domain.UserCreateRequest) (*domain.User, error) {
 	var createdUser *domain.User
 
 	// Ejecuta la lógica de creación de usuario dentro de una transacción.
-	err := u.txPort.Execute(func(repo domain.UserRepository) error {
-
-		// Mapeo del DTO de entrada a la entidad de dominio.
-		newUser := domain.User{
-			Name:     user.Name,
-			Username: user.Username,
-			Email:    user.Email,
-		}
-
-		// Generación de un ULID (ID único, ordenable por tiempo).
-		t := time.Now()
-		entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
-		newUser.ID = ulid.MustNew(ulid.Timestamp(t), entropy).String()
-
-		// Persistencia del nuevo usuario.
-		result := repo.Create(&newUser)
-
-		if result != nil {
-			log.Printf("Estamos en create, error: %v", result)
-			return result
-		}
-
-		createdUser = &newUser
-		return nil
+	err := u.txPort.Execute(func(uow domain.UnitOfWork) error {
+		var err error
+		createdUser, err = u.create(ctx, uow, user)
+		return err
 	})
 
 	if err != nil {
@@ -70,9 +69,49 @@ func (u *UserServiceImpl) Create(user *domain.UserCreateRequest) (*domain.User,
 	return createdUser, nil
 }
 
-// FindAll recupera todos los usuarios del repositorio.
-func (u *UserServiceImpl) FindAll() (*[]domain.User, error) {
-	users, err := u.Repo.FindAll()
+// create persiste un nuevo usuario dentro de la UnitOfWork indicada.
+func (u *UserServiceImpl) create(ctx context.Context, uow domain.UnitOfWork, user *domain.UserCreateRequest) (*domain.User, error) {
+	// Mapeo del DTO de entrada a la entidad de dominio.
+	newUser := domain.User{
+		Name:       user.Name,
+		Username:   user.Username,
+		Email:      user.Email,
+		ExternalID: user.ExternalID,
+		Status:     user.Status,
+	}
+	if newUser.Status == "" {
+		newUser.Status = domain.UserActive
+	}
+
+	// Canonicalización de username/email (se conserva la forma de presentación).
+	u.normalizer.Apply(&newUser)
+
+	// Generación de un ULID (ID único, ordenable por tiempo).
+	t := time.Now()
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
+	newUser.ID = ulid.MustNew(ulid.Timestamp(t), entropy).String()
+
+	// Atribución: el actor es el principal autenticado de la petición.
+	actor := domain.ActorFromContext(ctx)
+	newUser.CreatedAt, newUser.UpdatedAt = t.UTC(), t.UTC()
+	newUser.CreatedBy, newUser.UpdatedBy = actor, actor
+	statusChangedAt := t.UTC()
+	newUser.StatusChangedAt = &statusChangedAt
+
+	// Persistencia del nuevo usuario.
+	result := usersOf(ctx, uow).Create(&newUser)
+
+	if result != nil {
+		log.Printf("Estamos en create, error: %v", result)
+		return nil, result
+	}
+
+	return &newUser, recordChange(ctx, uow, domain.AuditActionCreate, newUser.ID, nil, &newUser)
+}
+
+// FindAll recupera los usuarios del repositorio que cumplen el filtro.
+func (u *UserServiceImpl) FindAll(ctx context.Context, filter domain.UserFilter) (*[]domain.User, error) {
+	users, err := u.users(ctx).FindAll(filter)
 
 	if err != nil {
 		// Mapea el error antes de retornarlo.
@@ -82,9 +121,14 @@ func (u *UserServiceImpl) FindAll() (*[]domain.User, error) {
 	return users, nil
 }
 
+// Export recorre los usuarios que cumplen el filtro directamente desde el repositorio.
+func (u *UserServiceImpl) Export(ctx context.Context, filter domain.UserFilter, fn func(user *domain.User) error) error {
+	return u.users(ctx).Each(filter, fn)
+}
+
 // FindById recupera un usuario por su ID.
-func (u *UserServiceImpl) FindById(id string) (*domain.User, error) {
-	user, err := u.Repo.FindById(id)
+func (u *UserServiceImpl) FindById(ctx context.Context, id string, fields ...string) (*domain.User, error) {
+	user, err := u.users(ctx).FindById(id, fields...)
 
 	if err != nil {
 		// Mapea el error antes de retornarlo.
@@ -94,63 +138,284 @@ func (u *UserServiceImpl) FindById(id string) (*domain.User, error) {
 	return user, nil
 }
 
-// Update aplica los cambios a un usuario existente dentro de una transacción.
-func (u *UserServiceImpl) Update(user *domain.User) (*domain.User, error) {
+// Update aplica los cambios a un usuario existente dentro de una transacción,
+// registrando en la auditoría la diferencia entre el estado previo y el nuevo.
+func (u *UserServiceImpl) Update(ctx context.Context, user *domain.User) (*domain.User, error) {
+	var updatedUser *domain.User
 
-	err := u.txPort.Execute(func(repo domain.UserRepository) error {
-		// El repositorio se encarga de la lógica de actualización.
-		err := repo.Update(user)
-		if err != nil {
+	err := u.txPort.Execute(func(uow domain.UnitOfWork) error {
+		var err error
+		updatedUser, err = u.update(ctx, uow, user)
+		return err
+	})
+
+	if err != nil {
+		return nil, u.mapRepositoryError(err)
+	}
+
+	// Retorna el usuario actualizado.
+	return updatedUser, nil
+}
+
+// update aplica los cambios a un usuario existente dentro de la UnitOfWork indicada.
+func (u *UserServiceImpl) update(ctx context.Context, uow domain.UnitOfWork, user *domain.User) (*domain.User, error) {
+	// Recalcula las formas canónicas de los campos presentes en el request.
+	u.normalizer.Apply(user)
+
+	// Atribución: los datos de creación nunca se toman del cliente. El estado
+	// de la cuenta solo cambia con ChangeStatus y la verificación del email
+	// con EmailVerificationService (valores vacíos = sin cambios).
+	touch(ctx, user)
+	user.CreatedAt, user.CreatedBy = time.Time{}, ""
+	user.Status, user.StatusReason, user.StatusChangedAt = "", "", nil
+	user.EmailVerified, user.EmailVerifiedAt = false, nil
+
+	before, err := usersOf(ctx, uow).FindById(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// El repositorio se encarga de la lógica de actualización.
+	err = usersOf(ctx, uow).Update(user)
+	if err != nil {
+		return nil, err
+	}
+
+	// Relee el usuario para retornar el estado completo persistido.
+	updatedUser, err := usersOf(ctx, uow).FindById(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Una nueva dirección debe verificarse de nuevo (el cambio de mayúsculas
+	// u otras variantes de la misma forma canónica conserva la verificación).
+	if updatedUser.EmailVerified && updatedUser.EmailNormalized != before.EmailNormalized {
+		updatedUser.EmailVerified, updatedUser.EmailVerifiedAt = false, nil
+		if err := usersOf(ctx, uow).UpdateEmailVerification(updatedUser); err != nil {
+			return nil, err
+		}
+	}
+
+	return updatedUser, recordChange(ctx, uow, domain.AuditActionUpdate, user.ID, before, updatedUser)
+}
+
+// Delete elimina lógicamente un usuario por su ID, ejecutándose dentro de una
+// transacción. Según la política configurada, libera también su username y email.
+func (u *UserServiceImpl) Delete(ctx context.Context, id string) error {
+	err := u.txPort.Execute(func(uow domain.UnitOfWork) error {
+		return u.delete(ctx, uow, id)
+	})
+
+	if err != nil {
+		return u.mapRepositoryError(err)
+	}
+
+	return nil
+}
+
+// delete elimina lógicamente un usuario dentro de la UnitOfWork indicada.
+func (u *UserServiceImpl) delete(ctx context.Context, uow domain.UnitOfWork, id string) error {
+	before, err := usersOf(ctx, uow).FindById(id)
+	if err != nil {
+		return err
+	}
+
+	// El repositorio se encarga de la lógica de eliminación.
+	err = usersOf(ctx, uow).Delete(id)
+	if err != nil {
+		return err
+	}
+	if u.identityPolicy == ReleaseDeletedIdentity {
+		if err := usersOf(ctx, uow).ReleaseIdentity(id); err != nil {
 			return err
 		}
+	}
+
+	after := *before
+	deletedAt := time.Now().UTC()
+	after.DeletedAt = &deletedAt
+
+	return recordChange(ctx, uow, domain.AuditActionDelete, id, before, &after)
+}
+
+// Batch aplica un lote de operaciones, de forma atómica o individualmente.
+func (u *UserServiceImpl) Batch(ctx context.Context, operations []domain.UserBatchOperation, atomic bool) []domain.UserBatchResult {
+	results := make([]domain.UserBatchResult, len(operations))
+	for i, operation := range operations {
+		results[i] = domain.UserBatchResult{Index: operation.Index, Op: operation.Op}
+	}
+
+	if !atomic {
+		// Cada operación en su propia transacción: un fallo no afecta a las demás.
+		for i := range operations {
+			err := u.txPort.Execute(func(uow domain.UnitOfWork) error {
+				var err error
+				results[i].User, err = u.apply(ctx, uow, &operations[i])
+				return err
+			})
+			if err != nil {
+				results[i].User, results[i].Err = nil, u.mapRepositoryError(err)
+			}
+		}
+		return results
+	}
+
+	// Todas las operaciones en una única transacción.
+	failed := -1
+	err := u.txPort.Execute(func(uow domain.UnitOfWork) error {
+		for i := range operations {
+			var err error
+			if results[i].User, err = u.apply(ctx, uow, &operations[i]); err != nil {
+				failed = i
+				return err
+			}
+		}
 		return nil
 	})
 
+	if err != nil {
+		mapped := u.mapRepositoryError(err)
+		for i := range results {
+			results[i].User, results[i].Err = nil, domain.ErrBatchNotApplied
+			// Si la transacción falló sin una operación responsable (e.g., al
+			// confirmar), todas reportan el error.
+			if i == failed || failed < 0 {
+				results[i].Err = mapped
+			}
+		}
+	}
+
+	return results
+}
+
+// apply ejecuta una operación de un lote dentro de la UnitOfWork indicada.
+func (u *UserServiceImpl) apply(ctx context.Context, uow domain.UnitOfWork, operation *domain.UserBatchOperation) (*domain.User, error) {
+	switch operation.Op {
+	case domain.BatchCreate:
+		return u.create(ctx, uow, operation.Create)
+	case domain.BatchUpdate:
+		return u.update(ctx, uow, operation.Update)
+	case domain.BatchDelete:
+		return nil, u.delete(ctx, uow, operation.ID)
+	default:
+		return nil, fmt.Errorf("unknown batch operation %q", operation.Op)
+	}
+}
+
+// Restore revierte la eliminación lógica de un usuario. Si la identidad había
+// sido liberada, la vuelve a reclamar; falla con ErrUsernameInUse o
+// ErrEmailInUse si otro usuario la tomó mientras tanto.
+func (u *UserServiceImpl) Restore(ctx context.Context, id string) (*domain.User, error) {
+	var restoredUser *domain.User
+
+	err := u.txPort.Execute(func(uow domain.UnitOfWork) error {
+		before, err := usersOf(ctx, uow).Restore(id)
+		if err != nil {
+			return err
+		}
+
+		user, err := usersOf(ctx, uow).FindById(id)
+		if err != nil {
+			return err
+		}
+
+		if u.identityPolicy == ReleaseDeletedIdentity {
+			u.normalizer.Apply(user)
+		}
+
+		// Registra la restauración como una modificación del actor actual.
+		touch(ctx, user)
+		if err := usersOf(ctx, uow).Update(user); err != nil {
+			return err
+		}
+
+		restoredUser = user
+		return recordChange(ctx, uow, domain.AuditActionRestore, id, before, restoredUser)
+	})
+
 	if err != nil {
 		return nil, u.mapRepositoryError(err)
 	}
 
-	// Retorna el usuario actualizado.
-	return user, nil
+	return restoredUser, nil
 }
 
-// Delete elimina un usuario del sistema por su ID, ejecutándose dentro de una transacción.
-func (u *UserServiceImpl) Delete(id string) error {
-	err := u.txPort.Execute(func(repo domain.UserRepository) error {
-		// El repositorio se encarga de la lógica de eliminación.
-		err := repo.Delete(id)
+// PurgeDeleted elimina permanentemente los usuarios eliminados lógicamente
+// antes de deletedBefore, en todas las organizaciones, y retorna cuántos
+// fueron purgados. Cada usuario purgado queda registrado en la auditoría de
+// su organización dentro de la misma transacción.
+func (u *UserServiceImpl) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged []domain.User
+
+	err := u.txPort.Execute(func(uow domain.UnitOfWork) error {
+		var err error
+		purged, err = uow.Users().Purge(deletedBefore)
 		if err != nil {
 			return err
 		}
+
+		for _, user := range purged {
+			tenantCtx := domain.WithTenant(ctx, user.TenantID)
+			if err := recordChange(tenantCtx, uow, domain.AuditActionPurge, user.ID, nil, nil); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 
 	if err != nil {
-		return u.mapRepositoryError(err)
+		return 0, u.mapRepositoryError(err)
 	}
 
-	return nil
+	return int64(len(purged)), nil
+}
+
+// users retorna el repositorio restringido a la organización del contexto.
+func (u *UserServiceImpl) users(ctx context.Context) domain.UserRepository {
+	return u.Repo.ForTenant(domain.TenantFromContext(ctx))
+}
+
+// usersOf retorna el repositorio de la UnitOfWork restringido a la
+// organización del contexto.
+func usersOf(ctx context.Context, uow domain.UnitOfWork) domain.UserRepository {
+	return uow.Users().ForTenant(domain.TenantFromContext(ctx))
+}
+
+// touch marca al usuario como modificado ahora por el actor del contexto.
+func touch(ctx context.Context, user *domain.User) {
+	user.UpdatedAt = time.Now().UTC()
+	user.UpdatedBy = domain.ActorFromContext(ctx)
 }
 
 // mapRepositoryError traduce los errores específicos del repositorio (como los de la BD)
 // a errores estándar de la capa de aplicación/dominio, asegurando que la capa de
 // presentación (e.g., HTTP handlers) no dependa de detalles de persistencia.
 func (u *UserServiceImpl) mapRepositoryError(err error) error {
-	// Errores de "Sentinel" (comparación con errors.Is)
-	if errors.Is(err, domain.ErrUserNotFound) {
-		return fmt.Errorf("user not found")
+	// Los errores del ciclo de vida de la cuenta conservan el detalle de la
+	// transición rechazada (e.g., "cannot unlock a user that is active").
+	if errors.Is(err, domain.ErrInvalidStatusTransition) || errors.Is(err, domain.ErrStatusReasonRequired) {
+		return err
 	}
-	if errors.Is(err, domain.ErrUsernameInUse) {
-		return fmt.Errorf("username already in use")
-	}
-	if errors.Is(err, domain.ErrEmailInUse) {
-		return fmt.Errorf("email already in use")
+
+	// Errores de "Sentinel" (comparación con errors.Is). Se retornan tal cual
+	// para que la capa de presentación pueda seguir identificándolos.
+	for _, sentinel := range []error{
+		domain.ErrUserNotFound,
+		domain.ErrUsernameInUse,
+		domain.ErrEmailInUse,
+		domain.ErrIdInUse,
+		domain.ErrUserNotDeleted,
+		domain.ErrTenantNotFound,
+	} {
+		if errors.Is(err, sentinel) {
+			return sentinel
+		}
 	}
 
 	// Errores dinámicos (comparación con errors.As)
 	var errValue domain.ErrValueNotNullable
 	if errors.As(err, &errValue) {
-		return fmt.Errorf(errValue.Error())
+		return errValue
 	}
 
 	// Error interno genérico (Wrapping)
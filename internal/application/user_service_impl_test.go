@@ -0,0 +1,195 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// newTestUserService crea un UserServiceImpl sobre un memoryStore.
+func newTestUserService(policy DeletedIdentityPolicy) (*UserServiceImpl, *memoryStore) {
+	store := newMemoryStore()
+	return NewUserServiceImpl(store.Users(), store, NewNormalizer(true), policy), store
+}
+
+// mustCreate crea un usuario y falla la prueba si no es posible.
+func mustCreate(t *testing.T, ctx context.Context, service *UserServiceImpl, username, email string) *domain.User {
+	t.Helper()
+	user, err := service.Create(ctx, &domain.UserCreateRequest{Name: "Test User", Username: username, Email: email})
+	if err != nil {
+		t.Fatalf("Create(%q, %q): %v", username, email, err)
+	}
+	return user
+}
+
+func TestCreateRejectsCanonicalDuplicates(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestUserService(HoldDeletedIdentity)
+	created := mustCreate(t, ctx, service, "JaneDoe", "Jane.Doe@gmail.com")
+
+	if created.Username != "JaneDoe" || created.UsernameNormalized != "janedoe" {
+		t.Errorf("created username = %q (%q)", created.Username, created.UsernameNormalized)
+	}
+
+	_, err := service.Create(ctx, &domain.UserCreateRequest{Name: "Other", Username: "janedoe", Email: "other@example.com"})
+	if !errors.Is(err, domain.ErrUsernameInUse) {
+		t.Errorf("duplicate username: err = %v, want ErrUsernameInUse", err)
+	}
+
+	_, err = service.Create(ctx, &domain.UserCreateRequest{Name: "Other", Username: "other", Email: "janedoe+spam@googlemail.com"})
+	if !errors.Is(err, domain.ErrEmailInUse) {
+		t.Errorf("duplicate gmail address: err = %v, want ErrEmailInUse", err)
+	}
+}
+
+func TestUpdateRejectsCanonicalDuplicates(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestUserService(HoldDeletedIdentity)
+	mustCreate(t, ctx, service, "jane", "jane@example.com")
+	john := mustCreate(t, ctx, service, "john", "john@example.com")
+
+	_, err := service.Update(ctx, &domain.User{ID: john.ID, Email: " JANE@example.com"})
+	if !errors.Is(err, domain.ErrEmailInUse) {
+		t.Fatalf("err = %v, want ErrEmailInUse", err)
+	}
+
+	updated, err := service.Update(ctx, &domain.User{ID: john.ID, Username: "John"})
+	if err != nil {
+		t.Fatalf("changing only the case of the own username: %v", err)
+	}
+	if updated.Username != "John" || updated.UsernameNormalized != "john" {
+		t.Errorf("updated username = %q (%q)", updated.Username, updated.UsernameNormalized)
+	}
+}
+
+func TestDeleteAndRestore(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestUserService(HoldDeletedIdentity)
+	user := mustCreate(t, ctx, service, "jane", "jane@example.com")
+
+	if err := service.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := service.FindById(ctx, user.ID); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("FindById after delete: err = %v, want ErrUserNotFound", err)
+	}
+	if err := service.Delete(ctx, user.ID); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("second Delete: err = %v, want ErrUserNotFound", err)
+	}
+
+	all, _ := service.FindAll(ctx, domain.UserFilter{IncludeDeleted: true})
+	if len(*all) != 1 || (*all)[0].DeletedAt == nil {
+		t.Errorf("include_deleted must list the deleted user: %+v", *all)
+	}
+
+	// Con la política hold, la identidad sigue reservada.
+	if _, err := service.Create(ctx, &domain.UserCreateRequest{Name: "Other", Username: "JANE", Email: "other@example.com"}); !errors.Is(err, domain.ErrUsernameInUse) {
+		t.Errorf("reusing a held username: err = %v, want ErrUsernameInUse", err)
+	}
+
+	restored, err := service.Restore(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("restored user still deleted: %v", restored.DeletedAt)
+	}
+	if _, err := service.Restore(ctx, user.ID); !errors.Is(err, domain.ErrUserNotDeleted) {
+		t.Errorf("restoring an active user: err = %v, want ErrUserNotDeleted", err)
+	}
+}
+
+func TestReleasePolicyFreesIdentityAndBlocksConflictingRestore(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestUserService(ReleaseDeletedIdentity)
+	user := mustCreate(t, ctx, service, "jane", "jane@example.com")
+
+	if err := service.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	mustCreate(t, ctx, service, "Jane", "new-jane@example.com")
+
+	if _, err := service.Restore(ctx, user.ID); !errors.Is(err, domain.ErrUsernameInUse) {
+		t.Errorf("restore after the username was taken: err = %v, want ErrUsernameInUse", err)
+	}
+	if _, err := service.FindById(ctx, user.ID); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("a failed restore must be rolled back: err = %v", err)
+	}
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	ctx := context.Background()
+	service, store := newTestUserService(HoldDeletedIdentity)
+	deleted := mustCreate(t, ctx, service, "old", "old@example.com")
+	kept := mustCreate(t, ctx, service, "kept", "kept@example.com")
+	if err := service.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	purged, err := service.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+	if err != nil || purged != 1 {
+		t.Fatalf("PurgeDeleted = %d, %v; want 1", purged, err)
+	}
+	if _, ok := store.users[deleted.ID]; ok {
+		t.Error("the deleted user must be removed permanently")
+	}
+	if _, ok := store.users[kept.ID]; !ok {
+		t.Error("active users must never be purged")
+	}
+
+	last := store.audit[len(store.audit)-1]
+	if last.Action != domain.AuditActionPurge || last.UserID != deleted.ID {
+		t.Errorf("last audit record = %s %s, want the purge", last.Action, last.UserID)
+	}
+}
+
+// asActor retorna un contexto autenticado como el principal indicado.
+func asActor(subject string, roles ...string) context.Context {
+	return domain.WithPrincipal(context.Background(), domain.Principal{Subject: subject, Roles: roles})
+}
+
+func TestCreateAndUpdateAttribution(t *testing.T) {
+	service, _ := newTestUserService(HoldDeletedIdentity)
+
+	start := time.Now().UTC()
+	created := mustCreate(t, asActor("alice"), service, "jane", "jane@example.com")
+	if created.CreatedBy != "alice" || created.UpdatedBy != "alice" {
+		t.Errorf("created by %q / updated by %q, want alice", created.CreatedBy, created.UpdatedBy)
+	}
+	if created.CreatedAt.Before(start) || !created.CreatedAt.Equal(created.UpdatedAt) {
+		t.Errorf("created_at = %v, updated_at = %v", created.CreatedAt, created.UpdatedAt)
+	}
+
+	// Los datos de creación enviados por el cliente se ignoran.
+	forged := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated, err := service.Update(asActor("bob"), &domain.User{
+		ID: created.ID, Name: "Jane Doe", CreatedAt: forged, CreatedBy: "mallory", UpdatedBy: "mallory",
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.CreatedBy != "alice" || !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("creation data changed: %q at %v", updated.CreatedBy, updated.CreatedAt)
+	}
+	if updated.UpdatedBy != "bob" || updated.UpdatedAt.Before(created.UpdatedAt) {
+		t.Errorf("updated by %q at %v, want bob after %v", updated.UpdatedBy, updated.UpdatedAt, created.UpdatedAt)
+	}
+}
+
+func TestRestoreIsAttributedToTheRestoringActor(t *testing.T) {
+	service, _ := newTestUserService(HoldDeletedIdentity)
+	created := mustCreate(t, asActor("alice"), service, "jane", "jane@example.com")
+	if err := service.Delete(asActor("alice"), created.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	restored, err := service.Restore(asActor("carol", domain.RoleAdmin), created.ID)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if restored.UpdatedBy != "carol" || restored.CreatedBy != "alice" {
+		t.Errorf("restored: created by %q, updated by %q", restored.CreatedBy, restored.UpdatedBy)
+	}
+}
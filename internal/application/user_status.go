@@ -0,0 +1,106 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// statusTransition es una arista de la máquina de estados de la cuenta: los
+// estados desde los que se admite la acción y el estado resultante.
+type statusTransition struct {
+	from []domain.UserStatus
+	to   domain.UserStatus
+	// reasonRequired indica que la acción debe indicar un motivo.
+	reasonRequired bool
+}
+
+// statusTransitions es la máquina de estados del ciclo de vida de la cuenta:
+//
+//	pending ──activate──▶ active ◀──unlock── locked
+//	active ──suspend──▶ suspended ──activate──▶ active
+//	active ──lock──▶ locked ──suspend──▶ suspended
+//	(cualquiera) ──deactivate──▶ deactivated ──activate──▶ active
+var statusTransitions = map[domain.UserStatusAction]statusTransition{
+	domain.StatusActivate: {
+		from: []domain.UserStatus{domain.UserPending, domain.UserSuspended, domain.UserDeactivated},
+		to:   domain.UserActive,
+	},
+	domain.StatusSuspend: {
+		from:           []domain.UserStatus{domain.UserActive, domain.UserLocked},
+		to:             domain.UserSuspended,
+		reasonRequired: true,
+	},
+	domain.StatusLock: {
+		from:           []domain.UserStatus{domain.UserActive},
+		to:             domain.UserLocked,
+		reasonRequired: true,
+	},
+	domain.StatusUnlock: {
+		from: []domain.UserStatus{domain.UserLocked},
+		to:   domain.UserActive,
+	},
+	domain.StatusDeactivate: {
+		from:           []domain.UserStatus{domain.UserPending, domain.UserActive, domain.UserSuspended, domain.UserLocked},
+		to:             domain.UserDeactivated,
+		reasonRequired: true,
+	},
+}
+
+// nextStatus retorna el estado al que lleva la acción desde current.
+// Retorna ErrInvalidStatusTransition si la acción no existe o no se admite
+// desde current, y ErrStatusReasonRequired si requiere un motivo y no se indicó.
+func nextStatus(current domain.UserStatus, action domain.UserStatusAction, reason string) (domain.UserStatus, error) {
+	transition, ok := statusTransitions[action]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown action %q", domain.ErrInvalidStatusTransition, action)
+	}
+	if !slices.Contains(transition.from, current) {
+		return "", fmt.Errorf("%w: cannot %s a user that is %s", domain.ErrInvalidStatusTransition, action, current)
+	}
+	if transition.reasonRequired && strings.TrimSpace(reason) == "" {
+		return "", fmt.Errorf("%w (%s)", domain.ErrStatusReasonRequired, action)
+	}
+	return transition.to, nil
+}
+
+// ChangeStatus aplica una acción del ciclo de vida a la cuenta del usuario
+// dentro de una transacción, registrando la auditoría y el evento
+// UserUpdated. Retorna ErrUserNotFound, ErrInvalidStatusTransition o
+// ErrStatusReasonRequired.
+func (u *UserServiceImpl) ChangeStatus(ctx context.Context, id string, action domain.UserStatusAction, reason string) (*domain.User, error) {
+	var changedUser *domain.User
+
+	err := u.txPort.Execute(func(uow domain.UnitOfWork) error {
+		before, err := usersOf(ctx, uow).FindById(id)
+		if err != nil {
+			return err
+		}
+
+		status, err := nextStatus(before.Status, action, reason)
+		if err != nil {
+			return err
+		}
+
+		after := *before
+		now := time.Now().UTC()
+		after.Status, after.StatusReason, after.StatusChangedAt = status, strings.TrimSpace(reason), &now
+		touch(ctx, &after)
+
+		if err := usersOf(ctx, uow).UpdateStatus(&after); err != nil {
+			return err
+		}
+
+		changedUser = &after
+		return recordChange(ctx, uow, domain.AuditActionStatusChange, id, before, &after)
+	})
+
+	if err != nil {
+		return nil, u.mapRepositoryError(err)
+	}
+
+	return changedUser, nil
+}
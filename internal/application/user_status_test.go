@@ -0,0 +1,168 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"user-api-restful/internal/domain"
+)
+
+func TestNextStatusFollowsTheStateMachine(t *testing.T) {
+	// allowed es el estado resultante de cada acción desde cada estado; las
+	// combinaciones ausentes no se admiten.
+	allowed := map[domain.UserStatusAction]map[domain.UserStatus]domain.UserStatus{
+		domain.StatusActivate: {
+			domain.UserPending: domain.UserActive, domain.UserSuspended: domain.UserActive, domain.UserDeactivated: domain.UserActive,
+		},
+		domain.StatusSuspend: {domain.UserActive: domain.UserSuspended, domain.UserLocked: domain.UserSuspended},
+		domain.StatusLock:    {domain.UserActive: domain.UserLocked},
+		domain.StatusUnlock:  {domain.UserLocked: domain.UserActive},
+		domain.StatusDeactivate: {
+			domain.UserPending: domain.UserDeactivated, domain.UserActive: domain.UserDeactivated,
+			domain.UserSuspended: domain.UserDeactivated, domain.UserLocked: domain.UserDeactivated,
+		},
+	}
+
+	for _, action := range domain.UserStatusActions {
+		for _, current := range domain.UserStatuses {
+			t.Run(string(action)+" from "+string(current), func(t *testing.T) {
+				got, err := nextStatus(current, action, "reason")
+				want, ok := allowed[action][current]
+				switch {
+				case ok && (err != nil || got != want):
+					t.Errorf("nextStatus = %q, %v; want %q", got, err, want)
+				case !ok && !errors.Is(err, domain.ErrInvalidStatusTransition):
+					t.Errorf("nextStatus = %q, %v; want ErrInvalidStatusTransition", got, err)
+				}
+			})
+		}
+	}
+
+	if _, err := nextStatus(domain.UserActive, "ban", "reason"); !errors.Is(err, domain.ErrInvalidStatusTransition) {
+		t.Errorf("unknown action: err = %v, want ErrInvalidStatusTransition", err)
+	}
+}
+
+func TestNextStatusRequiresAReason(t *testing.T) {
+	tests := []struct {
+		action  domain.UserStatusAction
+		from    domain.UserStatus
+		reasons bool
+	}{
+		{domain.StatusActivate, domain.UserSuspended, false},
+		{domain.StatusSuspend, domain.UserActive, true},
+		{domain.StatusLock, domain.UserActive, true},
+		{domain.StatusUnlock, domain.UserLocked, false},
+		{domain.StatusDeactivate, domain.UserActive, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.action), func(t *testing.T) {
+			_, err := nextStatus(tt.from, tt.action, "  ")
+			if tt.reasons && !errors.Is(err, domain.ErrStatusReasonRequired) {
+				t.Errorf("blank reason: err = %v, want ErrStatusReasonRequired", err)
+			}
+			if !tt.reasons && err != nil {
+				t.Errorf("blank reason: err = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestChangeStatusRecordsTheChange(t *testing.T) {
+	ctx := domain.WithPrincipal(context.Background(), domain.Principal{Subject: "admin"})
+	service, store := newTestUserService(HoldDeletedIdentity)
+	user := mustCreate(t, ctx, service, "jane", "jane@example.com")
+	createdAt := *user.StatusChangedAt
+
+	changed, err := service.ChangeStatus(ctx, user.ID, domain.StatusSuspend, "  chargeback ")
+	if err != nil {
+		t.Fatalf("ChangeStatus: %v", err)
+	}
+	if changed.Status != domain.UserSuspended || changed.StatusReason != "chargeback" || changed.StatusChangedAt.Before(createdAt) {
+		t.Errorf("changed = %s %q %v, want suspended with the trimmed reason", changed.Status, changed.StatusReason, changed.StatusChangedAt)
+	}
+	if stored := store.users[user.ID]; stored.Status != domain.UserSuspended || stored.UpdatedBy != "admin" {
+		t.Errorf("stored = %s by %q, want suspended by admin", stored.Status, stored.UpdatedBy)
+	}
+
+	records, _ := store.Audit().Find(domain.AuditFilter{UserID: user.ID})
+	if len(records) == 0 || records[0].Action != domain.AuditActionStatusChange {
+		t.Fatalf("audit = %+v, want a status_change record first", records)
+	}
+	fields := map[string]domain.FieldChange{}
+	for _, change := range records[0].Changes {
+		fields[change.Field] = change
+	}
+	if change := fields["status"]; fmt.Sprint(change.Before) != "active" || fmt.Sprint(change.After) != "suspended" {
+		t.Errorf("status change = %+v, want active → suspended", change)
+	}
+
+	events := store.events()
+	last := events[len(events)-1]
+	if last.Type != domain.EventUserUpdated || last.User == nil || last.User.Status != domain.UserSuspended {
+		t.Errorf("last event = %+v, want UserUpdated with the suspended user", last)
+	}
+}
+
+func TestChangeStatusRejectsWithoutChanges(t *testing.T) {
+	ctx := context.Background()
+	service, store := newTestUserService(HoldDeletedIdentity)
+	user := mustCreate(t, ctx, service, "jane", "jane@example.com")
+	events := len(store.events())
+
+	tests := []struct {
+		name    string
+		id      string
+		action  domain.UserStatusAction
+		reason  string
+		wantErr error
+	}{
+		{"transition not allowed", user.ID, domain.StatusUnlock, "", domain.ErrInvalidStatusTransition},
+		{"missing reason", user.ID, domain.StatusLock, "", domain.ErrStatusReasonRequired},
+		{"unknown user", "missing", domain.StatusActivate, "", domain.ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.ChangeStatus(ctx, tt.id, tt.action, tt.reason); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if stored := store.users[user.ID]; stored.Status != domain.UserActive {
+		t.Errorf("status = %s, want it unchanged", stored.Status)
+	}
+	if len(store.events()) != events {
+		t.Errorf("events = %d, want %d (no event for rejected changes)", len(store.events()), events)
+	}
+}
+
+func TestCreatePendingUserAndActivate(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestUserService(HoldDeletedIdentity)
+
+	user, err := service.Create(ctx, &domain.UserCreateRequest{Name: "Jane", Username: "jane", Email: "jane@example.com", Status: domain.UserPending})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if user.Status != domain.UserPending || user.Status.CanAuthenticate() {
+		t.Errorf("status = %s, want pending (cannot authenticate)", user.Status)
+	}
+
+	activated, err := service.ChangeStatus(ctx, user.ID, domain.StatusActivate, "")
+	if err != nil || activated.Status != domain.UserActive || !activated.Status.CanAuthenticate() {
+		t.Errorf("activate = %+v, %v; want active", activated, err)
+	}
+
+	// PUT /users ignora los campos de estado
+	update := *activated
+	update.Status, update.Name = domain.UserLocked, "Jane Doe"
+	updated, err := service.Update(ctx, &update)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if stored, _ := service.FindById(ctx, user.ID); stored.Status != domain.UserActive || updated.Name != "Jane Doe" {
+		t.Errorf("after Update status = %s, name = %q; want active and the new name", stored.Status, updated.Name)
+	}
+}
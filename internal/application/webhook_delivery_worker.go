@@ -0,0 +1,171 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// Cabeceras de las peticiones de webhook.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookIDHeader        = "X-Webhook-Id"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// WebhookDeliveryWorker envía las entregas pendientes de webhooks, las
+// reintenta con backoff exponencial y desactiva los webhooks que acumulan
+// demasiados fallos consecutivos.
+type WebhookDeliveryWorker struct {
+	repo         domain.WebhookRepository
+	sender       domain.WebhookSender
+	pollInterval time.Duration
+	maxAttempts  int
+	disableAfter int
+	maxBackoff   time.Duration
+}
+
+// NewWebhookDeliveryWorker crea un worker que consulta las entregas cada
+// pollInterval, reintenta cada una hasta maxAttempts veces y desactiva un
+// webhook tras disableAfter fallos consecutivos.
+func NewWebhookDeliveryWorker(repo domain.WebhookRepository, sender domain.WebhookSender, pollInterval time.Duration, maxAttempts, disableAfter int, maxBackoff time.Duration) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		repo:         repo,
+		sender:       sender,
+		pollInterval: pollInterval,
+		maxAttempts:  maxAttempts,
+		disableAfter: disableAfter,
+		maxBackoff:   maxBackoff,
+	}
+}
+
+// Run procesa entregas hasta que el contexto sea cancelado.
+func (w *WebhookDeliveryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverDue procesa un lote de entregas cuyo próximo intento ya venció.
+func (w *WebhookDeliveryWorker) deliverDue(ctx context.Context) {
+	deliveries, err := w.repo.FindDueDeliveries(time.Now().UTC(), 100)
+	if err != nil {
+		log.Printf("[Webhooks] fetch failed: %v", err)
+		return
+	}
+
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := w.attempt(ctx, &deliveries[i]); err != nil {
+			log.Printf("[Webhooks] delivery %s: %v", deliveries[i].ID, err)
+		}
+	}
+}
+
+// attempt realiza un intento de entrega y actualiza la entrega y el webhook.
+func (w *WebhookDeliveryWorker) attempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	webhook, err := w.repo.FindById(delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	if !webhook.Active {
+		delivery.State = domain.DeliveryFailed
+		delivery.LastError = "webhook is disabled"
+		return w.repo.UpdateDelivery(delivery)
+	}
+
+	now := time.Now().UTC()
+	statusCode, sendErr := w.sender.Send(ctx, signRequest(webhook, delivery, now))
+
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+
+	if sendErr == nil && statusCode >= 200 && statusCode < 300 {
+		delivery.State = domain.DeliverySucceeded
+		delivery.DeliveredAt = &now
+		webhook.ConsecutiveFailures = 0
+	} else {
+		if sendErr != nil {
+			delivery.LastError = sendErr.Error()
+		} else {
+			delivery.LastError = fmt.Sprintf("unexpected status code %d", statusCode)
+		}
+
+		delivery.NextAttemptAt = now.Add(w.backoff(delivery.Attempts))
+		if delivery.Attempts >= w.maxAttempts {
+			delivery.State = domain.DeliveryFailed
+		}
+
+		// Fallo sostenido: desactiva el webhook hasta que se reactive manualmente.
+		webhook.ConsecutiveFailures++
+		if webhook.ConsecutiveFailures >= w.disableAfter && webhook.Active {
+			webhook.Active = false
+			webhook.DisabledAt = &now
+			log.Printf("[Webhooks] webhook %s disabled after %d consecutive failures", webhook.ID, webhook.ConsecutiveFailures)
+		}
+	}
+
+	webhook.UpdatedAt = now
+	if err := w.repo.UpdateDelivery(delivery); err != nil {
+		return err
+	}
+
+	return w.repo.Update(webhook)
+}
+
+// backoff calcula la espera exponencial (30s, 1m, 2m, ...) acotada por maxBackoff.
+func (w *WebhookDeliveryWorker) backoff(attempts int) time.Duration {
+	delay := 30 * time.Second << min(attempts-1, 20)
+	return min(delay, w.maxBackoff)
+}
+
+// signRequest construye la petición de la entrega firmada con HMAC-SHA256.
+// La firma cubre "<timestamp>.<body>" para impedir ataques de repetición: los
+// receptores deben rechazar timestamps demasiado antiguos.
+func signRequest(webhook *domain.Webhook, delivery *domain.WebhookDelivery, now time.Time) domain.WebhookRequest {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	body := []byte(delivery.Payload)
+
+	return domain.WebhookRequest{
+		URL: webhook.URL,
+		Headers: map[string]string{
+			"Content-Type":         "application/json",
+			WebhookSignatureHeader: "sha256=" + SignWebhookPayload(webhook.Secret, timestamp, body),
+			WebhookTimestampHeader: timestamp,
+			WebhookEventHeader:     string(delivery.EventType),
+			WebhookIDHeader:        delivery.EventID,
+			WebhookDeliveryHeader:  delivery.ID,
+		},
+		Body: body,
+	}
+}
+
+// SignWebhookPayload calcula la firma hexadecimal HMAC-SHA256 de
+// "<timestamp>.<body>" con el secreto del webhook.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
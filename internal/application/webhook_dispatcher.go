@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// WebhookDispatcher es un domain.EventPublisher que, por cada evento
+// publicado por el relay del outbox, encola una entrega para cada webhook
+// activo de su organización suscripto a su tipo. El envío HTTP lo realiza
+// WebhookDeliveryWorker, de modo que un destino lento no demora la
+// publicación de otros eventos.
+type WebhookDispatcher struct {
+	repo domain.WebhookRepository
+}
+
+// NewWebhookDispatcher crea un WebhookDispatcher sobre el repositorio indicado.
+func NewWebhookDispatcher(repo domain.WebhookRepository) *WebhookDispatcher {
+	return &WebhookDispatcher{repo: repo}
+}
+
+// Asegura que WebhookDispatcher implemente domain.EventPublisher.
+var _ domain.EventPublisher = (*WebhookDispatcher)(nil)
+
+// Publish encola una entrega del evento para cada webhook interesado. Si el
+// relay reintenta el evento, las entregas pueden duplicarse: los receptores
+// deben deduplicar por X-Webhook-Id.
+func (d *WebhookDispatcher) Publish(ctx context.Context, event domain.UserEvent) error {
+	webhooks, err := d.repo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Accepts(event) {
+			continue
+		}
+
+		now := time.Now().UTC()
+		delivery := &domain.WebhookDelivery{
+			ID:            newULID(now),
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       string(payload),
+			State:         domain.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		if err := d.repo.CreateDelivery(delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,303 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// DefaultDeliveryLimit es la cantidad de entregas retornadas por defecto en el registro.
+const DefaultDeliveryLimit = 50
+
+// knownEventTypes son los tipos de evento a los que un webhook puede suscribirse.
+var knownEventTypes = map[domain.EventType]bool{
+	domain.EventUserCreated: true,
+	domain.EventUserUpdated: true,
+	domain.EventUserDeleted: true,
+}
+
+// WebhookService define el contract para administrar las suscripciones de
+// webhooks y consultar o reenviar sus entregas.
+type WebhookService interface {
+	// Create registra un webhook. Si no se indica un secreto, se genera uno;
+	// el webhook retornado es el único que expone el secreto.
+	Create(ctx context.Context, request *domain.WebhookCreateRequest) (*domain.Webhook, error)
+	FindAll(ctx context.Context) ([]domain.Webhook, error)
+	// FindById retorna ErrWebhookNotFound si no existe.
+	FindById(ctx context.Context, id string) (*domain.Webhook, error)
+	// Update aplica los campos presentes en el request.
+	Update(ctx context.Context, id string, request *domain.WebhookUpdateRequest) (*domain.Webhook, error)
+	Delete(ctx context.Context, id string) error
+	// Deliveries retorna el registro de entregas del webhook.
+	Deliveries(ctx context.Context, webhookID string, limit int) ([]domain.WebhookDelivery, error)
+	// Redeliver programa un nuevo envío inmediato del evento de una entrega previa.
+	Redeliver(ctx context.Context, webhookID, deliveryID string) (*domain.WebhookDelivery, error)
+}
+
+// WebhookServiceImpl es la implementación concreta de WebhookService.
+type WebhookServiceImpl struct {
+	repo domain.WebhookRepository
+	// allowPrivateTargets desactiva la verificación de las direcciones de
+	// destino (solo para desarrollo, con receptores locales).
+	allowPrivateTargets bool
+	// lookup resuelve el host de las URLs registradas.
+	lookup func(ctx context.Context, host string) ([]netip.Addr, error)
+}
+
+// NewWebhookServiceImpl crea un WebhookServiceImpl sobre el repositorio
+// indicado. Salvo con allowPrivateTargets, rechaza las URLs cuyo host
+// resuelve a una dirección interna o reservada.
+func NewWebhookServiceImpl(repo domain.WebhookRepository, allowPrivateTargets bool) *WebhookServiceImpl {
+	return &WebhookServiceImpl{
+		repo:                repo,
+		allowPrivateTargets: allowPrivateTargets,
+		lookup: func(ctx context.Context, host string) ([]netip.Addr, error) {
+			return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		},
+	}
+}
+
+// Asegura que WebhookServiceImpl implemente la interfaz WebhookService en tiempo de compilación.
+var _ WebhookService = (*WebhookServiceImpl)(nil)
+
+// Create valida el filtro de eventos y el destino, genera ID y secreto, y
+// persiste el webhook.
+func (s *WebhookServiceImpl) Create(ctx context.Context, request *domain.WebhookCreateRequest) (*domain.Webhook, error) {
+	if err := validateEventTypes(request.Events); err != nil {
+		return nil, err
+	}
+	if err := s.checkTarget(ctx, request.URL); err != nil {
+		return nil, err
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateSecret(); err != nil {
+			return nil, domain.ErrInternalServer{Value: err.Error()}
+		}
+	}
+
+	now := time.Now().UTC()
+	webhook := &domain.Webhook{
+		ID:        newULID(now),
+		TenantID:  domain.TenantFromContext(ctx),
+		URL:       request.URL,
+		Events:    normalizeEventTypes(request.Events),
+		Secret:    secret,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.repo.Create(webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// FindAll retorna los webhooks de la organización, sin sus secretos.
+func (s *WebhookServiceImpl) FindAll(ctx context.Context) ([]domain.Webhook, error) {
+	all, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID := domain.TenantFromContext(ctx)
+	webhooks := make([]domain.Webhook, 0, len(all))
+	for _, webhook := range all {
+		if webhook.TenantID == tenantID {
+			webhook.Secret = ""
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	return webhooks, nil
+}
+
+// FindById retorna un webhook, sin su secreto.
+func (s *WebhookServiceImpl) FindById(ctx context.Context, id string) (*domain.Webhook, error) {
+	webhook, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Secret = ""
+
+	return webhook, nil
+}
+
+// Update aplica los campos presentes. Al reactivar un webhook se reinician
+// su contador de fallos y la marca de desactivación automática.
+func (s *WebhookServiceImpl) Update(ctx context.Context, id string, request *domain.WebhookUpdateRequest) (*domain.Webhook, error) {
+	webhook, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.URL != nil {
+		if err := s.checkTarget(ctx, *request.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *request.URL
+	}
+	if request.Events != nil {
+		if err := validateEventTypes(*request.Events); err != nil {
+			return nil, err
+		}
+		webhook.Events = normalizeEventTypes(*request.Events)
+	}
+	if request.Secret != nil {
+		webhook.Secret = *request.Secret
+	}
+	if request.Active != nil {
+		if *request.Active && !webhook.Active {
+			webhook.ConsecutiveFailures = 0
+			webhook.DisabledAt = nil
+		}
+		webhook.Active = *request.Active
+	}
+	webhook.UpdatedAt = time.Now().UTC()
+
+	if err := s.repo.Update(webhook); err != nil {
+		return nil, err
+	}
+
+	webhook.Secret = ""
+
+	return webhook, nil
+}
+
+// Delete elimina el webhook y su registro de entregas.
+func (s *WebhookServiceImpl) Delete(ctx context.Context, id string) error {
+	if _, err := s.find(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+// Deliveries retorna el registro de entregas de un webhook existente.
+func (s *WebhookServiceImpl) Deliveries(ctx context.Context, webhookID string, limit int) ([]domain.WebhookDelivery, error) {
+	if _, err := s.find(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultDeliveryLimit
+	}
+
+	return s.repo.FindDeliveries(webhookID, limit)
+}
+
+// Redeliver crea una nueva entrega pendiente con el mismo evento, que el
+// worker enviará en su próxima pasada.
+func (s *WebhookServiceImpl) Redeliver(ctx context.Context, webhookID, deliveryID string) (*domain.WebhookDelivery, error) {
+	if _, err := s.find(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	original, err := s.repo.FindDelivery(webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	delivery := &domain.WebhookDelivery{
+		ID:            newULID(now),
+		WebhookID:     original.WebhookID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		State:         domain.DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+
+	if err := s.repo.CreateDelivery(delivery); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// find retorna el webhook si pertenece a la organización del contexto; los
+// de otras organizaciones se reportan como inexistentes (ErrWebhookNotFound).
+func (s *WebhookServiceImpl) find(ctx context.Context, id string) (*domain.Webhook, error) {
+	webhook, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.TenantID != domain.TenantFromContext(ctx) {
+		return nil, domain.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// checkTarget retorna ErrWebhookTargetNotAllowed si el host de rawURL es, o
+// resuelve a, alguna dirección no admitida por domain.WebhookAddressAllowed.
+// El sender vuelve a verificar la dirección en cada conexión, ya que el DNS
+// puede cambiar después del registro.
+func (s *WebhookServiceImpl) checkTarget(ctx context.Context, rawURL string) error {
+	if s.allowPrivateTargets {
+		return nil
+	}
+
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrWebhookTargetNotAllowed, err)
+	}
+	host := target.Hostname()
+
+	addrs := []netip.Addr{}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, addr)
+	} else if addrs, err = s.lookup(ctx, host); err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: %s cannot be resolved", domain.ErrWebhookTargetNotAllowed, host)
+	}
+
+	for _, addr := range addrs {
+		if !domain.WebhookAddressAllowed(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", domain.ErrWebhookTargetNotAllowed, host, addr)
+		}
+	}
+
+	return nil
+}
+
+// validateEventTypes retorna ErrInvalidEventType si algún tipo es desconocido.
+func validateEventTypes(events []domain.EventType) error {
+	for _, event := range events {
+		if !knownEventTypes[event] {
+			return fmt.Errorf("%w: %s", domain.ErrInvalidEventType, event)
+		}
+	}
+	return nil
+}
+
+// normalizeEventTypes elimina duplicados conservando el orden.
+func normalizeEventTypes(events []domain.EventType) []domain.EventType {
+	seen := map[domain.EventType]bool{}
+	unique := []domain.EventType{}
+	for _, event := range events {
+		if !seen[event] {
+			seen[event] = true
+			unique = append(unique, event)
+		}
+	}
+	return unique
+}
+
+// generateSecret genera un secreto aleatorio de 32 bytes codificado en hexadecimal.
+func generateSecret() (string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buffer), nil
+}
@@ -0,0 +1,278 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// scriptedSender responde con los códigos de status en orden y registra las peticiones.
+type scriptedSender struct {
+	statuses []int
+	requests []domain.WebhookRequest
+}
+
+func (s *scriptedSender) Send(_ context.Context, request domain.WebhookRequest) (int, error) {
+	s.requests = append(s.requests, request)
+	if len(s.statuses) == 0 {
+		return 0, errors.New("connection refused")
+	}
+	status := s.statuses[0]
+	s.statuses = s.statuses[1:]
+	return status, nil
+}
+
+// testResolver resuelve los hosts de las pruebas sin consultar el DNS: los
+// hosts ausentes no resuelven.
+var testResolver = map[string][]netip.Addr{
+	"example.com":          {netip.MustParseAddr("93.184.215.14")},
+	"partner.example.com":  {netip.MustParseAddr("93.184.215.14"), netip.MustParseAddr("2606:2800:21f:cb07:6820:80da:af6b:8b2c")},
+	"internal.example.com": {netip.MustParseAddr("93.184.215.14"), netip.MustParseAddr("10.0.0.7")},
+}
+
+// newTestWebhookService crea un WebhookServiceImpl que resuelve con testResolver.
+func newTestWebhookService(repo domain.WebhookRepository) *WebhookServiceImpl {
+	service := NewWebhookServiceImpl(repo, false)
+	service.lookup = func(_ context.Context, host string) ([]netip.Addr, error) {
+		if addrs, ok := testResolver[host]; ok {
+			return addrs, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	return service
+}
+
+// newTestWebhook registra un webhook activo de la organización tenantID.
+func newTestWebhook(t *testing.T, repo *memoryWebhooks, tenantID string, events ...domain.EventType) *domain.Webhook {
+	t.Helper()
+	service := newTestWebhookService(repo)
+	webhook, err := service.Create(domain.WithTenant(context.Background(), tenantID), &domain.WebhookCreateRequest{
+		URL: "https://partner.example.com/hooks", Events: events, Secret: "0123456789abcdef",
+	})
+	if err != nil {
+		t.Fatalf("Create webhook: %v", err)
+	}
+	return webhook
+}
+
+func TestWebhookServiceValidatesAndScopesByTenant(t *testing.T) {
+	repo := newMemoryWebhooks()
+	service := newTestWebhookService(repo)
+	acme := domain.WithTenant(context.Background(), "acme")
+
+	_, err := service.Create(acme, &domain.WebhookCreateRequest{URL: "https://example.com", Events: []domain.EventType{"user.exploded"}})
+	if !errors.Is(err, domain.ErrInvalidEventType) {
+		t.Errorf("unknown event type: err = %v, want ErrInvalidEventType", err)
+	}
+
+	created, err := service.Create(acme, &domain.WebhookCreateRequest{
+		URL: "https://example.com", Events: []domain.EventType{domain.EventUserCreated, domain.EventUserCreated},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(created.Secret) == 0 || len(created.Events) != 1 {
+		t.Errorf("created = %+v, want a generated secret and deduplicated events", created)
+	}
+
+	found, err := service.FindById(acme, created.ID)
+	if err != nil || found.Secret != "" {
+		t.Errorf("FindById = %+v, %v; want the webhook without its secret", found, err)
+	}
+
+	globex := domain.WithTenant(context.Background(), "globex")
+	if _, err := service.FindById(globex, created.ID); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("other tenant: err = %v, want ErrWebhookNotFound", err)
+	}
+	if webhooks, _ := service.FindAll(globex); len(webhooks) != 0 {
+		t.Errorf("other tenant lists %d webhooks", len(webhooks))
+	}
+}
+
+func TestWebhookDispatcherQueuesDeliveriesForSubscribedWebhooks(t *testing.T) {
+	repo := newMemoryWebhooks()
+	all := newTestWebhook(t, repo, "acme")
+	deletions := newTestWebhook(t, repo, "acme", domain.EventUserDeleted)
+	newTestWebhook(t, repo, "globex")
+
+	event := domain.UserEvent{ID: "evt-1", Type: domain.EventUserCreated, TenantID: "acme", UserID: "u1"}
+	if err := NewWebhookDispatcher(repo).Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if len(repo.deliveries) != 1 {
+		t.Fatalf("%d deliveries, want 1", len(repo.deliveries))
+	}
+	delivery := repo.deliveries[0]
+	if delivery.WebhookID != all.ID || delivery.WebhookID == deletions.ID || delivery.EventID != "evt-1" || delivery.State != domain.DeliveryPending {
+		t.Errorf("delivery = %+v", delivery)
+	}
+}
+
+func TestWebhookDeliveryIsSigned(t *testing.T) {
+	repo := newMemoryWebhooks()
+	webhook := newTestWebhook(t, repo, "acme")
+	_ = NewWebhookDispatcher(repo).Publish(context.Background(), domain.UserEvent{ID: "evt-1", Type: domain.EventUserCreated, TenantID: "acme"})
+
+	sender := &scriptedSender{statuses: []int{204}}
+	NewWebhookDeliveryWorker(repo, sender, time.Hour, 3, 5, time.Hour).deliverDue(context.Background())
+
+	if len(sender.requests) != 1 {
+		t.Fatalf("%d requests, want 1", len(sender.requests))
+	}
+	request := sender.requests[0]
+	timestamp := request.Headers[WebhookTimestampHeader]
+	if _, err := strconv.ParseInt(timestamp, 10, 64); err != nil {
+		t.Errorf("timestamp header %q: %v", timestamp, err)
+	}
+	want := "sha256=" + SignWebhookPayload("0123456789abcdef", timestamp, request.Body)
+	if got := request.Headers[WebhookSignatureHeader]; got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	if request.Headers[WebhookIDHeader] != "evt-1" || request.URL != webhook.URL {
+		t.Errorf("request = %+v", request)
+	}
+
+	delivery := repo.deliveries[0]
+	if delivery.State != domain.DeliverySucceeded || delivery.LastStatusCode != 204 || delivery.DeliveredAt == nil {
+		t.Errorf("delivery = %+v", delivery)
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	// Vector calculado con: printf '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	got := SignWebhookPayload("secret", "1700000000", []byte("{}"))
+	if want := "b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"; got != want {
+		t.Errorf("signature = %q", got)
+	}
+	if SignWebhookPayload("other", "1700000000", []byte("{}")) == got {
+		t.Error("signature does not depend on the secret")
+	}
+	if SignWebhookPayload("secret", "1700000001", []byte("{}")) == got {
+		t.Error("signature does not depend on the timestamp")
+	}
+}
+
+func TestWebhookDeliveryRetriesAndDisablesAfterSustainedFailure(t *testing.T) {
+	repo := newMemoryWebhooks()
+	webhook := newTestWebhook(t, repo, "acme")
+	_ = NewWebhookDispatcher(repo).Publish(context.Background(), domain.UserEvent{ID: "evt-1", Type: domain.EventUserCreated, TenantID: "acme"})
+
+	sender := &scriptedSender{statuses: []int{500}}
+	worker := NewWebhookDeliveryWorker(repo, sender, time.Hour, 5, 2, time.Hour)
+
+	worker.deliverDue(context.Background())
+	delivery := repo.deliveries[0]
+	if delivery.State != domain.DeliveryPending || delivery.Attempts != 1 || delivery.LastStatusCode != 500 {
+		t.Fatalf("after a 500: delivery = %+v", delivery)
+	}
+	if wait := time.Until(delivery.NextAttemptAt); wait < 29*time.Second || wait > 31*time.Second {
+		t.Errorf("next attempt in %s, want 30s", wait)
+	}
+
+	// El reintento aún no venció: el worker no vuelve a enviar.
+	worker.deliverDue(context.Background())
+	if len(sender.requests) != 1 {
+		t.Fatalf("%d requests before the retry time, want 1", len(sender.requests))
+	}
+
+	repo.deliveries[0].NextAttemptAt = time.Now().Add(-time.Second)
+	worker.deliverDue(context.Background())
+
+	stored, _ := repo.FindById(webhook.ID)
+	if stored.Active || stored.DisabledAt == nil || stored.ConsecutiveFailures != 2 {
+		t.Errorf("webhook = %+v, want it disabled after 2 consecutive failures", stored)
+	}
+	if repo.deliveries[0].LastError != "connection refused" {
+		t.Errorf("last error = %q", repo.deliveries[0].LastError)
+	}
+
+	// Una entrega pendiente de un webhook desactivado se da por fallida.
+	repo.deliveries[0].NextAttemptAt = time.Now().Add(-time.Second)
+	worker.deliverDue(context.Background())
+	if repo.deliveries[0].State != domain.DeliveryFailed || len(sender.requests) != 2 {
+		t.Errorf("delivery = %+v after disabling, %d requests", repo.deliveries[0], len(sender.requests))
+	}
+}
+
+func TestWebhookServiceRejectsInternalTargets(t *testing.T) {
+	ctx := domain.WithTenant(context.Background(), "acme")
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://partner.example.com/hooks", true},
+		{"https://93.184.215.14:8443/hooks", true},
+		{"http://127.0.0.1:8080/hooks", false},
+		{"http://localhost/hooks", false},
+		{"http://169.254.169.254/latest/meta-data/", false},
+		{"http://[::1]/hooks", false},
+		{"http://[::ffff:10.0.0.1]/hooks", false},
+		{"http://0.0.0.0/hooks", false},
+		{"https://internal.example.com/hooks", false},
+		{"https://unknown.example.com/hooks", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			repo := newMemoryWebhooks()
+			service := newTestWebhookService(repo)
+
+			_, err := service.Create(ctx, &domain.WebhookCreateRequest{URL: tt.url})
+			if tt.allowed && err != nil {
+				t.Errorf("Create: %v", err)
+			}
+			if !tt.allowed && !errors.Is(err, domain.ErrWebhookTargetNotAllowed) {
+				t.Errorf("Create: err = %v, want ErrWebhookTargetNotAllowed", err)
+			}
+
+			// Update verifica la nueva URL igual que Create.
+			webhook := newTestWebhook(t, repo, "acme")
+			_, err = service.Update(ctx, webhook.ID, &domain.WebhookUpdateRequest{URL: &tt.url})
+			if tt.allowed != (err == nil) || (err != nil && !errors.Is(err, domain.ErrWebhookTargetNotAllowed)) {
+				t.Errorf("Update: err = %v, want allowed = %t", err, tt.allowed)
+			}
+		})
+	}
+
+	// Con allowPrivateTargets se admiten receptores locales.
+	if _, err := NewWebhookServiceImpl(newMemoryWebhooks(), true).Create(ctx, &domain.WebhookCreateRequest{URL: "http://127.0.0.1:8080/hooks"}); err != nil {
+		t.Errorf("Create with allowPrivateTargets: %v", err)
+	}
+}
+
+func TestWebhookReactivationAndRedelivery(t *testing.T) {
+	repo := newMemoryWebhooks()
+	webhook := newTestWebhook(t, repo, "acme")
+	_ = NewWebhookDispatcher(repo).Publish(context.Background(), domain.UserEvent{ID: "evt-1", Type: domain.EventUserCreated, TenantID: "acme"})
+	NewWebhookDeliveryWorker(repo, &scriptedSender{}, time.Hour, 1, 1, time.Hour).deliverDue(context.Background())
+
+	ctx := domain.WithTenant(context.Background(), "acme")
+	service := newTestWebhookService(repo)
+
+	active := true
+	reactivated, err := service.Update(ctx, webhook.ID, &domain.WebhookUpdateRequest{Active: &active})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if !reactivated.Active || reactivated.ConsecutiveFailures != 0 || reactivated.DisabledAt != nil {
+		t.Errorf("reactivated = %+v", reactivated)
+	}
+
+	failed := repo.deliveries[0]
+	redelivery, err := service.Redeliver(ctx, webhook.ID, failed.ID)
+	if err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+	if redelivery.ID == failed.ID || redelivery.EventID != "evt-1" || redelivery.Payload != failed.Payload || redelivery.State != domain.DeliveryPending {
+		t.Errorf("redelivery = %+v", redelivery)
+	}
+
+	log, err := service.Deliveries(ctx, webhook.ID, 0)
+	if err != nil || len(log) != 2 || log[0].ID != redelivery.ID {
+		t.Errorf("Deliveries = %+v, %v; want the redelivery first", log, err)
+	}
+}
@@ -0,0 +1,253 @@
+// Package config centraliza la lectura de la configuración de la aplicación
+// desde variables de entorno, para que el servidor y las herramientas de línea
+// de comandos compartan exactamente los mismos valores.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config agrupa todos los parámetros configurables de la aplicación.
+type Config struct {
+	// Port es el puerto HTTP en el que escucha el servidor.
+	Port string
+	// GRPCPort es el puerto del servidor gRPC; vacío lo deshabilita.
+	GRPCPort string
+
+	// DBHost, DBPort, DBUser, DBPassword y DBName definen la conexión a PostgreSQL.
+	DBHost     string
+	DBPort     string
+	DBUser     string
+	DBPassword string
+	DBName     string
+
+	// BasicAuthUser y BasicAuthPass son las credenciales de Basic Auth.
+	BasicAuthUser string
+	BasicAuthPass string
+	// AuthDisabled concede acceso anónimo de administrador cuando no hay
+	// credenciales de Basic Auth. Solo para desarrollo local.
+	AuthDisabled bool
+	// SCIMBearerToken es el token con el que se autentica el proveedor de
+	// identidad en /scim/v2; vacío deshabilita los endpoints SCIM.
+	SCIMBearerToken string
+	// SCIMTenantID es la organización a la que pertenece SCIMBearerToken: el
+	// proveedor de identidad solo puede aprovisionar cuentas en ella.
+	SCIMTenantID string
+
+	// TenantBaseDomain es el dominio bajo el cual cada organización se
+	// resuelve por subdominio (<id>.<dominio>); vacío = solo por cabecera.
+	TenantBaseDomain string
+	// TenantRowLevelSecurity activa las políticas de row-level security de
+	// PostgreSQL sobre user_entities, además del filtro de cada consulta.
+	TenantRowLevelSecurity bool
+
+	// NormalizeGmailAddresses activa la eliminación de puntos y sufijos "+tag"
+	// en la parte local de direcciones de Gmail al canonicalizar emails.
+	NormalizeGmailAddresses bool
+
+	// DeletedUserRetention es el tiempo que un usuario eliminado lógicamente
+	// se conserva antes de ser purgado; PurgeInterval es la frecuencia de la purga.
+	DeletedUserRetention time.Duration
+	PurgeInterval        time.Duration
+	// DeletedIdentityPolicy es "hold" (retiene username/email de usuarios
+	// eliminados) o "release" (los libera para su reutilización).
+	DeletedIdentityPolicy string
+
+	// EventPublisher selecciona el destino de los eventos del outbox:
+	// "stdout", "file" (EventFilePath) o "none".
+	EventPublisher string
+	EventFilePath  string
+	// OutboxPollInterval, OutboxBatchSize y OutboxMaxBackoff configuran el relay del outbox.
+	OutboxPollInterval time.Duration
+	OutboxBatchSize    int
+	OutboxMaxBackoff   time.Duration
+	// EventLogRetention es el tiempo que los eventos publicados se conservan
+	// para reanudar el feed SSE con Last-Event-ID.
+	EventLogRetention time.Duration
+
+	// BatchMaxOperations es la cantidad máxima de operaciones de POST /users:batch.
+	BatchMaxOperations int
+
+	// GraphQLMaxDepth y GraphQLMaxComplexity acotan las consultas de /graphql.
+	GraphQLMaxDepth      int
+	GraphQLMaxComplexity int
+
+	// ImportMaxBytes es el tamaño máximo de un archivo de importación;
+	// ImportChunkSize las filas confirmadas por transacción y
+	// ImportPollInterval la frecuencia con la que se buscan importaciones pendientes.
+	ImportMaxBytes     int
+	ImportChunkSize    int
+	ImportPollInterval time.Duration
+
+	// IdempotencyKeyTTL es el tiempo durante el cual una clave Idempotency-Key
+	// reproduce la respuesta original.
+	IdempotencyKeyTTL time.Duration
+
+	// ValidateRequests y ValidateResponses activan la validación de las
+	// peticiones y de las respuestas contra la especificación OpenAPI.
+	ValidateRequests  bool
+	ValidateResponses bool
+
+	// APIV1DeprecatedAt y APIV1Sunset son los instantes anunciados en las
+	// cabeceras Deprecation y Sunset de la v1 de la API; cero = sin anunciar.
+	APIV1DeprecatedAt time.Time
+	APIV1Sunset       time.Time
+
+	// Configuración de las entregas de webhooks: frecuencia del worker,
+	// intentos por entrega, fallos consecutivos antes de desactivar el
+	// webhook, espera máxima entre reintentos y timeout de cada petición.
+	WebhookPollInterval time.Duration
+	WebhookMaxAttempts  int
+	WebhookDisableAfter int
+	WebhookMaxBackoff   time.Duration
+	WebhookTimeout      time.Duration
+
+	// WebhookAllowPrivateTargets admite webhooks con destino en direcciones
+	// internas o reservadas (solo para desarrollo, con receptores locales).
+	WebhookAllowPrivateTargets bool
+
+	// Mailer selecciona cómo se envían los mensajes de correo: "smtp",
+	// "file" (MailFilePath) o "log". MailFrom es el remitente.
+	Mailer       string
+	MailFrom     string
+	MailFilePath string
+	// SMTPHost, SMTPPort, SMTPUsername y SMTPPassword definen el servidor
+	// SMTP (usuario vacío = sin autenticación); SMTPTimeout acota cada envío.
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPTimeout  time.Duration
+	// MailTemplateDir reemplaza las plantillas de los mensajes incluidas en
+	// el binario; vacío = las incluidas.
+	MailTemplateDir string
+
+	// EmailVerificationURL es la página a la que lleva el enlace de
+	// verificación (vacío = el mensaje incluye solo el token) y
+	// EmailVerificationTokenTTL la validez de cada token.
+	EmailVerificationURL      string
+	EmailVerificationTokenTTL time.Duration
+}
+
+// Load construye la configuración a partir de las variables de entorno,
+// aplicando valores por defecto donde corresponda.
+func Load() Config {
+	return Config{
+		Port:                    getEnv("PORT", "8080"),
+		GRPCPort:                getEnv("GRPC_PORT", "9090"),
+		DBHost:                  os.Getenv("DB_HOST"),
+		DBPort:                  getEnv("DB_PORT", "5432"),
+		DBUser:                  os.Getenv("POSTGRES_USER"),
+		DBPassword:              os.Getenv("PASSWORD_"),
+		DBName:                  getEnv("DB_NAME", "users_db"),
+		BasicAuthUser:           os.Getenv("BASIC_AUTH_USER"),
+		BasicAuthPass:           os.Getenv("BASIC_AUTH_PASS"),
+		AuthDisabled:            getEnvBool("AUTH_DISABLED", false),
+		SCIMBearerToken:         os.Getenv("SCIM_BEARER_TOKEN"),
+		SCIMTenantID:            getEnv("SCIM_TENANT_ID", "default"),
+		TenantBaseDomain:        os.Getenv("TENANT_BASE_DOMAIN"),
+		TenantRowLevelSecurity:  getEnvBool("TENANT_ROW_LEVEL_SECURITY", false),
+		NormalizeGmailAddresses: getEnvBool("NORMALIZE_GMAIL_ADDRESSES", false),
+		DeletedUserRetention:    getEnvDuration("DELETED_USER_RETENTION", 30*24*time.Hour),
+		PurgeInterval:           getEnvDuration("PURGE_INTERVAL", time.Hour),
+		DeletedIdentityPolicy:   getEnv("DELETED_IDENTITY_POLICY", "hold"),
+		EventPublisher:          getEnv("EVENT_PUBLISHER", "stdout"),
+		EventFilePath:           getEnv("EVENT_FILE_PATH", "user-events.ndjson"),
+		OutboxPollInterval:      getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatchSize:         getEnvInt("OUTBOX_BATCH_SIZE", 100),
+		OutboxMaxBackoff:        getEnvDuration("OUTBOX_MAX_BACKOFF", 5*time.Minute),
+		EventLogRetention:       getEnvDuration("EVENT_LOG_RETENTION", 7*24*time.Hour),
+		BatchMaxOperations:      getEnvInt("BATCH_MAX_OPERATIONS", 1000),
+		GraphQLMaxDepth:         getEnvInt("GRAPHQL_MAX_DEPTH", 10),
+		GraphQLMaxComplexity:    getEnvInt("GRAPHQL_MAX_COMPLEXITY", 1000),
+		ImportMaxBytes:          getEnvInt("IMPORT_MAX_BYTES", 10<<20),
+		ImportChunkSize:         getEnvInt("IMPORT_CHUNK_SIZE", 500),
+		ImportPollInterval:      getEnvDuration("IMPORT_POLL_INTERVAL", 5*time.Second),
+		IdempotencyKeyTTL:       getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		ValidateRequests:        getEnvBool("OPENAPI_VALIDATE_REQUESTS", true),
+		ValidateResponses:       getEnvBool("OPENAPI_VALIDATE_RESPONSES", false),
+		APIV1DeprecatedAt:       getEnvTime("API_V1_DEPRECATED_AT", time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)),
+		APIV1Sunset:             getEnvTime("API_V1_SUNSET", time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)),
+		WebhookPollInterval:     getEnvDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+		WebhookMaxAttempts:      getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookDisableAfter:     getEnvInt("WEBHOOK_DISABLE_AFTER_FAILURES", 20),
+		WebhookMaxBackoff:       getEnvDuration("WEBHOOK_MAX_BACKOFF", time.Hour),
+		WebhookTimeout:          getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+
+		WebhookAllowPrivateTargets: getEnvBool("WEBHOOK_ALLOW_PRIVATE_TARGETS", false),
+
+		Mailer:                    getEnv("MAILER", "log"),
+		MailFrom:                  getEnv("MAIL_FROM", "no-reply@localhost"),
+		MailFilePath:              getEnv("MAIL_FILE_PATH", "outgoing-mail.eml"),
+		SMTPHost:                  getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:                  getEnv("SMTP_PORT", "1025"),
+		SMTPUsername:              os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:              os.Getenv("SMTP_PASSWORD"),
+		SMTPTimeout:               getEnvDuration("SMTP_TIMEOUT", 10*time.Second),
+		MailTemplateDir:           os.Getenv("MAIL_TEMPLATE_DIR"),
+		EmailVerificationURL:      os.Getenv("EMAIL_VERIFICATION_URL"),
+		EmailVerificationTokenTTL: getEnvDuration("EMAIL_VERIFICATION_TOKEN_TTL", 48*time.Hour),
+	}
+}
+
+// DSN retorna la cadena de conexión de PostgreSQL para GORM.
+func (c Config) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=America/New_York",
+		c.DBHost, c.DBUser, c.DBPassword, c.DBName, c.DBPort)
+}
+
+// getEnv retorna el valor de la variable de entorno o el valor por defecto si está vacía.
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvBool interpreta la variable de entorno como booleano (true/false/1/0).
+// Retorna el valor por defecto si está vacía o no es válida.
+func getEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvInt interpreta la variable de entorno como entero positivo.
+// Retorna el valor por defecto si está vacía, no es válida o no es positiva.
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// getEnvDuration interpreta la variable de entorno como time.Duration (e.g., "720h").
+// Retorna el valor por defecto si está vacía o no es válida.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvTime interpreta la variable de entorno como instante RFC 3339
+// (e.g., "2027-04-30T00:00:00Z"). Retorna el valor por defecto si está vacía
+// o no es válida; "none" retorna el instante cero.
+func getEnvTime(key string, fallback time.Time) time.Time {
+	raw := os.Getenv(key)
+	if raw == "none" {
+		return time.Time{}
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,66 @@
+// Package domain contiene las estructuras de datos fundamentales (models/entities)
+// y define los contracts (interfaces) para la lógica de negocio.
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrAPIKeyNotFound indica que la clave de API solicitada no existe.
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrInvalidRole indica que se pidió asignar un rol desconocido.
+	ErrInvalidRole = errors.New("invalid role")
+)
+
+// APIKey es una credencial de la API para integraciones, enviada como
+// Authorization: Bearer <secreto>. Del secreto solo se conservan su hash y
+// su prefijo (para reconocerlo en los listados).
+type APIKey struct {
+	ID string `json:"id"`
+	// Name identifica a la integración; las rotaciones conservan el nombre.
+	Name   string   `json:"name"`
+	Prefix string   `json:"prefix"`
+	Hash   string   `json:"-"`
+	Roles  []string `json:"roles"`
+	// TenantID restringe la clave a una organización; vacío = clave de
+	// operador, que elige la organización por cabecera o subdominio.
+	TenantID string `json:"tenant_id,omitempty"`
+	// UserID vincula la clave a un usuario de la organización TenantID: solo
+	// es válida mientras la cuenta del usuario permita autenticarse (ver
+	// UserStatus.CanAuthenticate). Vacío = clave de integración.
+	UserID string `json:"user_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by"`
+	// ExpiresAt es el fin de la validez (e.g., el período de gracia de una
+	// clave rotada); nil = sin vencimiento.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// RevokedAt es el instante en que la clave se revocó.
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// ValidAt indica si la clave no está revocada ni vencida en el instante indicado.
+func (k *APIKey) ValidAt(t time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || t.Before(*k.ExpiresAt))
+}
+
+// APIKeyRepository define el contract para la persistencia de claves de API.
+type APIKeyRepository interface {
+	Create(key *APIKey) error
+	// FindById retorna la clave con el ID indicado o ErrAPIKeyNotFound.
+	FindById(id string) (*APIKey, error)
+	// FindByHash retorna la clave con el hash indicado o ErrAPIKeyNotFound.
+	FindByHash(hash string) (*APIKey, error)
+	// FindAll retorna las claves en orden de creación; con includeRevoked
+	// incluye las revocadas.
+	FindAll(includeRevoked bool) ([]APIKey, error)
+	// Rotate inserta key y, en la misma transacción, fija el vencimiento de
+	// las claves no revocadas con su nombre, organización y usuario que
+	// venzan después de expiresAt (o nunca). Retorna cuántas se modificaron.
+	Rotate(key *APIKey, expiresAt time.Time) (int64, error)
+	// Revoke revoca la clave. Retorna ErrAPIKeyNotFound si no existe o ya
+	// estaba revocada.
+	Revoke(id string, revokedAt time.Time) error
+}
@@ -0,0 +1,64 @@
+// Package domain contiene las estructuras de datos fundamentales (models/entities)
+// y define los contracts (interfaces) para la lógica de negocio.
+package domain
+
+import "time"
+
+// AuditAction identifica el tipo de mutación registrada en la auditoría.
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+	AuditActionPurge   AuditAction = "purge"
+	// AuditActionStatusChange es una acción del ciclo de vida de la cuenta
+	// (e.g., suspender o activar).
+	AuditActionStatusChange AuditAction = "status_change"
+	// AuditActionEmailVerify es la confirmación del email con un token de verificación.
+	AuditActionEmailVerify AuditAction = "email_verify"
+)
+
+// FieldChange describe el cambio de un campo: su valor antes y después de la
+// mutación. Los valores sensibles se guardan enmascarados.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// AuditRecord es una entrada inmutable del registro de auditoría.
+type AuditRecord struct {
+	ID        string        `json:"id"`
+	TenantID  string        `json:"-"`
+	UserID    string        `json:"user_id"`
+	Action    AuditAction   `json:"action"`
+	Actor     string        `json:"actor"`
+	RequestID string        `json:"request_id,omitempty"`
+	Timestamp time.Time     `json:"timestamp"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// AuditFilter define los criterios para consultar el registro de auditoría.
+type AuditFilter struct {
+	// TenantID restringe a los registros de una organización; vacío = todas.
+	TenantID string
+	// UserID restringe a los registros de un usuario.
+	UserID string
+	// Actor restringe a los registros de un actor.
+	Actor string
+	// Since restringe a los registros posteriores (inclusive) al instante indicado.
+	Since *time.Time
+	// Limit es la cantidad máxima de registros a retornar.
+	Limit int
+}
+
+// AuditRepository define el contract para persistir y consultar el registro
+// de auditoría. Solo permite agregar registros: nunca modificarlos ni borrarlos.
+type AuditRepository interface {
+	// Append agrega un registro de auditoría.
+	Append(record *AuditRecord) error
+	// Find retorna los registros que cumplen el filtro, del más reciente al más antiguo.
+	Find(filter AuditFilter) ([]AuditRecord, error)
+}
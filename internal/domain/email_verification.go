@@ -0,0 +1,83 @@
+// Package domain contiene las estructuras de datos fundamentales (models/entities)
+// y define los contracts (interfaces) para la lógica de negocio.
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidVerificationToken indica que el token de verificación no
+	// existe, no pertenece al usuario, ya fue usado, venció o corresponde a
+	// una dirección que el usuario ya no tiene.
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	// ErrEmailAlreadyVerified indica que el email del usuario ya fue verificado.
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+	// ErrEmailDeliveryFailed indica que el mensaje no pudo entregarse al
+	// servidor de correo.
+	ErrEmailDeliveryFailed = errors.New("email delivery failed")
+)
+
+// EmailVerificationToken es un token de un solo uso que confirma que el
+// usuario controla la dirección Email. Del token solo se conserva su hash:
+// el valor se envía por correo y no puede recuperarse.
+type EmailVerificationToken struct {
+	ID       string
+	TenantID string
+	UserID   string
+	// Email es la dirección a verificar; si el usuario la cambia, el token
+	// deja de ser válido.
+	Email string
+	Hash  string
+	// EventID es el evento que originó el token (vacío si se reenvió a
+	// pedido); evita emitir dos tokens para el mismo evento.
+	EventID   string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// UsedAt es el instante en que el token se usó; nil si no se usó.
+	UsedAt *time.Time
+}
+
+// ValidAt indica si el token no fue usado y no está vencido en el instante indicado.
+func (t *EmailVerificationToken) ValidAt(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// EmailVerificationRepository define el contract para la persistencia de
+// los tokens de verificación de email.
+type EmailVerificationRepository interface {
+	Create(token *EmailVerificationToken) error
+	// FindByHash retorna el token con el hash indicado o ErrInvalidVerificationToken.
+	FindByHash(hash string) (*EmailVerificationToken, error)
+	// ExistsForEvent indica si ya se emitió un token para el evento indicado.
+	ExistsForEvent(eventID string) (bool, error)
+	// Consume marca el token como usado si sigue vigente en usedAt. Retorna
+	// ErrInvalidVerificationToken si ya fue usado o venció, de modo que dos
+	// usos concurrentes no pueden tener éxito.
+	Consume(id string, usedAt time.Time) error
+	// ExpirePending hace vencer en el instante indicado los tokens vigentes
+	// del usuario, para que solo el último emitido sea válido.
+	ExpirePending(userID string, at time.Time) error
+}
+
+// EmailVerificationRequest es el cuerpo de POST /users/{id}/verify-email.
+type EmailVerificationRequest struct {
+	// Token es el valor recibido por correo.
+	Token string `json:"token" validate:"required,max=128"`
+}
+
+// EmailMessage es un mensaje de correo con sus versiones de texto y HTML.
+type EmailMessage struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer envía mensajes de correo. Retorna un error si el mensaje no pudo
+// entregarse al destino (e.g., el servidor SMTP lo rechazó).
+type Mailer interface {
+	Send(ctx context.Context, message EmailMessage) error
+}
@@ -16,6 +16,12 @@ var (
 	ErrEmailInUse = errors.New("email already in use")
 	// ErrIdInUse indica que un identificador proporcionado ya está en uso.
 	ErrIdInUse = errors.New("id already in use")
+
+	// ErrUserNotDeleted indica que se intentó restaurar un usuario que no está eliminado.
+	ErrUserNotDeleted = errors.New("user is not deleted")
+
+	// ErrSearchQueryTooShort indica que el texto de búsqueda no alcanza la longitud mínima.
+	ErrSearchQueryTooShort = errors.New("search query is too short")
 )
 
 // ErrValueNotNullable representa un error cuando se intenta dejar nulo
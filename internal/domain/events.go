@@ -0,0 +1,105 @@
+// Package domain contiene las estructuras de datos fundamentales (models/entities)
+// y define los contracts (interfaces) para la lógica de negocio.
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// EventType identifica el tipo de un evento de dominio.
+type EventType string
+
+const (
+	// EventUserCreated se emite al crear un usuario.
+	EventUserCreated EventType = "user.created"
+	// EventUserUpdated se emite al modificar (o restaurar) un usuario.
+	EventUserUpdated EventType = "user.updated"
+	// EventUserDeleted se emite al eliminar un usuario.
+	EventUserDeleted EventType = "user.deleted"
+)
+
+// UserEvent es un evento de dominio sobre el ciclo de vida de un usuario.
+type UserEvent struct {
+	// ID es el identificador único (ULID) del evento.
+	ID string `json:"id"`
+	// Sequence es la posición del evento en el outbox. La asigna la
+	// persistencia al agregarlo, por lo que una transacción que confirma más
+	// tarde puede tener una Sequence menor que eventos ya publicados.
+	Sequence int64 `json:"sequence"`
+	// PublishedSequence es la posición del evento en el orden de entrega. La
+	// asigna el relay justo antes de publicarlo, de un contador monótono, y
+	// no cambia en los reintentos (0 = aún no asignada).
+	PublishedSequence int64     `json:"published_sequence,omitempty"`
+	Type              EventType `json:"type"`
+	// TenantID es la organización del usuario. Los eventos solo se entregan
+	// a los suscriptores y webhooks de esa organización.
+	TenantID   string    `json:"tenant_id"`
+	UserID     string    `json:"user_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Actor      string    `json:"actor"`
+	RequestID  string    `json:"request_id,omitempty"`
+	// ChangedFields lista los campos modificados (solo en EventUserUpdated).
+	ChangedFields []string `json:"changed_fields,omitempty"`
+	// User es el estado del usuario tras el cambio (antes, en EventUserDeleted).
+	User *User `json:"user"`
+}
+
+// Tenant retorna la organización del evento. Los eventos registrados antes
+// de habilitar multi-tenancy pertenecen a DefaultTenantID.
+func (e UserEvent) Tenant() string {
+	if e.TenantID == "" {
+		return DefaultTenantID
+	}
+	return e.TenantID
+}
+
+// OutboxRepository define el contract del outbox transaccional: los eventos
+// se agregan en la misma transacción que la mutación que los origina y luego
+// un relay los publica en el orden en que quedan visibles.
+type OutboxRepository interface {
+	// Append agrega un evento al outbox y le asigna su Sequence.
+	Append(event *UserEvent) error
+	// FetchPending retorna hasta limit eventos no publicados: primero los que
+	// ya tienen PublishedSequence (reintentos), en ese orden, y luego los
+	// demás en orden de Sequence.
+	FetchPending(limit int) ([]PendingEvent, error)
+	// AssignPublishedSequence asigna al evento su PublishedSequence, si aún no
+	// la tiene, y la retorna.
+	AssignPublishedSequence(sequence int64) (int64, error)
+	// MarkPublished marca el evento como publicado.
+	MarkPublished(sequence int64) error
+	// MarkFailed registra un intento fallido y cuándo reintentar.
+	MarkFailed(sequence int64, cause string, nextAttemptAt time.Time) error
+	// FindPublished retorna hasta limit eventos ya publicados de la
+	// organización tenantID con PublishedSequence mayor a afterPublished, en
+	// ese orden, opcionalmente filtrados por tipo. Los eventos publicados se
+	// conservan como registro para reanudar suscripciones.
+	FindPublished(tenantID string, afterPublished int64, types []EventType, limit int) ([]UserEvent, error)
+	// PurgePublished elimina los eventos publicados antes del instante indicado
+	// y retorna cuántos se eliminaron.
+	PurgePublished(publishedBefore time.Time) (int64, error)
+}
+
+// PendingEvent es un evento del outbox aún no publicado, con su estado de reintentos.
+type PendingEvent struct {
+	Event         UserEvent
+	Attempts      int
+	NextAttemptAt time.Time
+}
+
+// EventPublisher publica eventos de dominio hacia sistemas externos. Las
+// implementaciones deben ser idempotentes respecto de UserEvent.ID, ya que la
+// entrega es "at-least-once".
+type EventPublisher interface {
+	Publish(ctx context.Context, event UserEvent) error
+}
+
+// EventStream permite suscribirse en vivo a los eventos a medida que son publicados.
+type EventStream interface {
+	// Subscribe retorna un canal con los eventos de la organización tenantID
+	// publicados desde ahora y una función para cancelar la suscripción. Si el
+	// suscriptor no consume a tiempo, el canal se cierra y el suscriptor debe
+	// reanudar desde el registro.
+	Subscribe(tenantID string) (events <-chan UserEvent, cancel func())
+}
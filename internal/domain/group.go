@@ -0,0 +1,117 @@
+// Package domain contiene las estructuras de datos fundamentales (models/entities)
+// y define los contracts (interfaces) para la lógica de negocio.
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrGroupNotFound indica que el grupo solicitado no existe en la organización.
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrGroupNameInUse indica que la organización ya tiene un grupo con ese nombre.
+	ErrGroupNameInUse = errors.New("group name already in use")
+	// ErrGroupCycle indica que agregar el grupo como miembro crearía un ciclo
+	// (un grupo contenido, directa o indirectamente, en sí mismo).
+	ErrGroupCycle = errors.New("membership would create a group cycle")
+	// ErrInvalidMemberType indica un tipo de miembro distinto de "user" y "group".
+	ErrInvalidMemberType = errors.New("member type must be user or group")
+	// ErrMemberNotFound indica que un miembro a agregar (usuario o grupo) no existe.
+	ErrMemberNotFound = errors.New("member not found")
+)
+
+// MemberType es el tipo de un miembro de grupo.
+type MemberType string
+
+const (
+	// MemberUser identifica a un usuario miembro.
+	MemberUser MemberType = "user"
+	// MemberGroup identifica a un grupo anidado.
+	MemberGroup MemberType = "group"
+)
+
+// Group es un conjunto de usuarios y de otros grupos (anidados) de una
+// organización, usado para administrar accesos.
+type Group struct {
+	ID          string    `json:"id"`
+	TenantID    string    `json:"-"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	CreatedBy   string    `json:"created_by"`
+	UpdatedBy   string    `json:"updated_by"`
+}
+
+// GroupCreateRequest es la estructura utilizada para crear un grupo.
+type GroupCreateRequest struct {
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=1024"`
+}
+
+// GroupUpdateRequest es la estructura utilizada para modificar un grupo;
+// los campos ausentes no se modifican.
+type GroupUpdateRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1024"`
+}
+
+// GroupMember es un miembro directo o efectivo de un grupo.
+type GroupMember struct {
+	Type MemberType `json:"type"`
+	ID   string     `json:"id"`
+	// Display es el username del usuario o el nombre del grupo.
+	Display string `json:"display"`
+}
+
+// MemberRef identifica a un miembro en un cambio de membresía.
+type MemberRef struct {
+	Type MemberType `json:"type" validate:"required,oneof=user group"`
+	ID   string     `json:"id" validate:"required"`
+}
+
+// GroupMembershipChange agrega y quita miembros de un grupo en una sola
+// transacción: si algún cambio falla, no se aplica ninguno. Agregar un
+// miembro existente o quitar uno inexistente no es un error.
+type GroupMembershipChange struct {
+	Add    []MemberRef `json:"add" validate:"dive"`
+	Remove []MemberRef `json:"remove" validate:"dive"`
+}
+
+// GroupRepository define el contract para la persistencia de grupos y sus
+// membresías.
+type GroupRepository interface {
+	// Create inserta el grupo. Retorna ErrGroupNameInUse si la organización
+	// ya tiene un grupo con ese nombre.
+	Create(group *Group) error
+	// FindAll retorna los grupos en orden de nombre.
+	FindAll() ([]Group, error)
+	// FindByIds retorna los grupos existentes de entre los IDs indicados.
+	FindByIds(ids []string) ([]Group, error)
+	// FindById retorna el grupo o ErrGroupNotFound.
+	FindById(id string) (*Group, error)
+	// Update persiste nombre, descripción y marcas de modificación. Retorna
+	// ErrGroupNotFound si no existe o ErrGroupNameInUse.
+	Update(group *Group) error
+	// Delete elimina el grupo y sus membresías (como grupo y como miembro de
+	// otros grupos). Retorna ErrGroupNotFound si no existe.
+	Delete(id string) error
+	// Members retorna los miembros directos del grupo (Display solo se
+	// completa para los grupos).
+	Members(groupID string) ([]GroupMember, error)
+	// MemberUserIDs retorna los IDs de los usuarios miembros del grupo; con
+	// transitive incluye los de sus grupos anidados, a cualquier profundidad.
+	MemberUserIDs(groupID string, transitive bool) ([]string, error)
+	// GroupsOf retorna los grupos de los que el miembro es miembro directo o,
+	// con transitive, también indirecto (a través de grupos anidados).
+	GroupsOf(member MemberRef, transitive bool) ([]Group, error)
+	// ChangeMembers aplica el cambio en una sola transacción. Retorna
+	// ErrGroupNotFound si el grupo no existe, ErrMemberNotFound si no existe
+	// un miembro a agregar o ErrGroupCycle si un grupo agregado contiene,
+	// directa o indirectamente, al grupo.
+	ChangeMembers(groupID string, change GroupMembershipChange, actor string) error
+	// ForTenant retorna un GroupRepository restringido a la organización
+	// indicada; los grupos creados le pertenecen.
+	ForTenant(tenantID string) GroupRepository
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrIdempotencyKeyExists indica que ya existe un registro para la clave de idempotencia.
+	ErrIdempotencyKeyExists = errors.New("idempotency key already exists")
+	// ErrIdempotencyKeyNotFound indica que no existe un registro vigente para la clave.
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+	// ErrIdempotencyKeyReused indica que la clave ya se usó con una petición distinta.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different request")
+	// ErrIdempotencyRequestInProgress indica que la petición original con la
+	// misma clave todavía se está procesando.
+	ErrIdempotencyRequestInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// IdempotencyRecord guarda el resultado de una petición identificada por una
+// clave de idempotencia, para reproducir la respuesta original en reintentos.
+type IdempotencyRecord struct {
+	// Scope aísla las claves de cada actor y organización (dos clientes pueden usar la misma clave).
+	Scope string
+	Key   string
+	// Fingerprint es el hash del método, la ruta y el cuerpo de la petición original.
+	Fingerprint string
+	// StatusCode es 0 mientras la petición original está en curso.
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Completed indica si la petición original ya tiene una respuesta registrada.
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// IdempotencyRepository define el contract de persistencia de las claves de idempotencia.
+type IdempotencyRepository interface {
+	// Create reserva la clave; retorna ErrIdempotencyKeyExists si ya existe un
+	// registro vigente para el mismo scope y clave.
+	Create(record *IdempotencyRecord) error
+	// Find retorna el registro vigente o ErrIdempotencyKeyNotFound.
+	Find(scope, key string) (*IdempotencyRecord, error)
+	// Complete registra la respuesta de la petición original.
+	Complete(scope, key string, statusCode int, contentType string, body []byte) error
+	// Delete libera la clave (e.g., si la petición original falló).
+	Delete(scope, key string) error
+	// PurgeExpired elimina los registros expirados antes del instante indicado.
+	PurgeExpired(before time.Time) (int64, error)
+}
@@ -0,0 +1,79 @@
+// Package domain contiene las estructuras de datos fundamentales (models/entities)
+// y define los contracts (interfaces) para la lógica de negocio.
+package domain
+
+import "context"
+
+const (
+	// RoleAdmin es el rol que habilita operaciones administrativas
+	// (e.g., listar o restaurar usuarios eliminados).
+	RoleAdmin = "admin"
+	// RoleAuditor es el rol que habilita la lectura del registro de auditoría.
+	RoleAuditor = "auditor"
+)
+
+// Principal representa la identidad autenticada que realiza una petición.
+type Principal struct {
+	// Subject identifica al actor (e.g., el usuario de Basic Auth).
+	Subject string
+	// Roles son los roles otorgados al actor.
+	Roles []string
+	// TenantID restringe al principal a una organización (e.g., una clave de
+	// API emitida para un cliente). Vacío = puede operar sobre cualquiera,
+	// eligiéndola por cabecera o subdominio (operadores del despliegue).
+	TenantID string
+}
+
+// HasRole indica si el principal tiene asignado el rol indicado.
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin indica si el principal tiene el rol de administrador.
+func (p Principal) IsAdmin() bool {
+	return p.HasRole(RoleAdmin)
+}
+
+// IsOperator indica si el principal administra el despliegue: es
+// administrador y no está restringido a una organización. Solo los
+// operadores pueden administrar las organizaciones.
+func (p Principal) IsOperator() bool {
+	return p.IsAdmin() && p.TenantID == ""
+}
+
+// CanReadAudit indica si el principal puede consultar el registro de auditoría.
+func (p Principal) CanReadAudit() bool {
+	return p.IsAdmin() || p.HasRole(RoleAuditor)
+}
+
+// SystemActor identifica los cambios realizados sin un principal autenticado
+// (e.g., tareas en segundo plano o herramientas de línea de comandos).
+const SystemActor = "system"
+
+// ActorFromContext retorna el Subject del principal del contexto, o
+// SystemActor si no hay un principal autenticado.
+func ActorFromContext(ctx context.Context) string {
+	if principal, ok := PrincipalFromContext(ctx); ok && principal.Subject != "" {
+		return principal.Subject
+	}
+	return SystemActor
+}
+
+// principalKey es la clave privada usada para guardar el Principal en un context.Context.
+type principalKey struct{}
+
+// WithPrincipal retorna una copia de ctx que transporta el principal autenticado.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext retorna el principal autenticado del contexto, si existe.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,19 @@
+// Package domain contiene las estructuras de datos fundamentales (models/entities)
+// y define los contracts (interfaces) para la lógica de negocio.
+package domain
+
+import "context"
+
+// requestIDKey es la clave privada usada para guardar el ID de la petición en un context.Context.
+type requestIDKey struct{}
+
+// WithRequestID retorna una copia de ctx que transporta el ID de la petición.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext retorna el ID de la petición del contexto, o "" si no existe.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
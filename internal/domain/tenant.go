@@ -0,0 +1,94 @@
+// Package domain contiene las estructuras de datos fundamentales (models/entities)
+// y define los contracts (interfaces) para la lógica de negocio.
+package domain
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+)
+
+// DefaultTenantID es la organización a la que pertenecen los datos creados
+// sin una organización explícita (e.g., los existentes antes de habilitar
+// multi-tenancy, o los de un despliegue de un solo cliente).
+const DefaultTenantID = "default"
+
+var (
+	// ErrTenantNotFound indica que la organización solicitada no existe.
+	ErrTenantNotFound = errors.New("tenant not found")
+	// ErrTenantIDInUse indica que ya existe una organización con ese ID.
+	ErrTenantIDInUse = errors.New("tenant id already in use")
+	// ErrInvalidTenantID indica que el ID no es un slug válido.
+	ErrInvalidTenantID = errors.New("tenant id must be 2-63 lowercase letters, digits or hyphens, starting with a letter or digit")
+	// ErrTenantNotEmpty indica que se intentó eliminar una organización que aún tiene usuarios.
+	ErrTenantNotEmpty = errors.New("tenant still has users")
+	// ErrDefaultTenant indica que se intentó eliminar la organización por defecto.
+	ErrDefaultTenant = errors.New("the default tenant cannot be deleted")
+	// ErrTenantForbidden indica que el principal no puede operar sobre la organización solicitada.
+	ErrTenantForbidden = errors.New("principal is not allowed to access this tenant")
+)
+
+// tenantIDPattern define los IDs válidos: slugs aptos para un subdominio.
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+// ValidTenantID indica si id es un ID de organización válido.
+func ValidTenantID(id string) bool {
+	return tenantIDPattern.MatchString(id)
+}
+
+// Tenant es una organización (cliente) del despliegue. Sus usuarios, auditoría,
+// eventos, webhooks e importaciones están aislados de los de las demás.
+type Tenant struct {
+	// ID es un slug inmutable; se usa en la cabecera X-Tenant-ID y como subdominio.
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by"`
+	UpdatedBy string    `json:"updated_by"`
+}
+
+// TenantCreateRequest es la estructura utilizada para crear una organización.
+type TenantCreateRequest struct {
+	ID   string `json:"id" validate:"required"`
+	Name string `json:"name" validate:"required"`
+}
+
+// TenantUpdateRequest es la estructura utilizada para modificar una organización.
+type TenantUpdateRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// TenantRepository define el contract para la persistencia de organizaciones.
+type TenantRepository interface {
+	// Create inserta la organización. Retorna ErrTenantIDInUse si el ID ya existe.
+	Create(tenant *Tenant) error
+	// FindAll retorna las organizaciones en orden de ID.
+	FindAll() ([]Tenant, error)
+	// FindById retorna la organización o ErrTenantNotFound.
+	FindById(id string) (*Tenant, error)
+	// Update persiste el nombre y las marcas de modificación. Retorna ErrTenantNotFound si no existe.
+	Update(tenant *Tenant) error
+	// Delete elimina la organización. Retorna ErrTenantNotFound si no existe
+	// o ErrTenantNotEmpty si aún tiene usuarios (incluidos los eliminados lógicamente).
+	Delete(id string) error
+}
+
+// tenantKey es la clave privada usada para guardar la organización en un context.Context.
+type tenantKey struct{}
+
+// WithTenant retorna una copia de ctx que opera sobre la organización indicada.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext retorna la organización del contexto, o DefaultTenantID
+// si no se resolvió ninguna (tareas en segundo plano, herramientas de línea
+// de comandos sin -tenant).
+func TenantFromContext(ctx context.Context) string {
+	if tenantID, _ := ctx.Value(tenantKey{}).(string); tenantID != "" {
+		return tenantID
+	}
+	return DefaultTenantID
+}
@@ -2,14 +2,27 @@
 // y define los contracts (interfaces) para la lógica de negocio.
 package domain
 
+// UnitOfWork agrupa los repositorios enlazados a una misma transacción.
+// Todo lo que se escriba a través de ellos se confirma o revierte en conjunto.
+type UnitOfWork interface {
+	// Users retorna el UserRepository de la transacción.
+	Users() UserRepository
+	// Audit retorna el AuditRepository de la transacción.
+	Audit() AuditRepository
+	// Outbox retorna el OutboxRepository de la transacción.
+	Outbox() OutboxRepository
+	// EmailVerifications retorna el EmailVerificationRepository de la transacción.
+	EmailVerifications() EmailVerificationRepository
+}
+
 // UserTransactionPort define el contract para manejar transacciones
 // a través de la capa de persistencia.
 // Su propósito principal es asegurar que un conjunto de operaciones de repositorio
 // se ejecuten de forma atómica (commit o rollback).
 type UserTransactionPort interface {
 	// Execute ejecuta la función 'fn' dentro de una única transacción.
-	// La función 'fn' recibe una instancia de UserRepository que está
-	// enlazada a la transacción actual. Si 'fn' retorna un error, la transacción
+	// La función 'fn' recibe una UnitOfWork cuyos repositorios están
+	// enlazados a la transacción actual. Si 'fn' retorna un error, la transacción
 	// debe ser revertida (rollback); de lo contrario, se confirma (commit).
-	Execute(fn func(repo UserRepository) error) error
+	Execute(fn func(uow UnitOfWork) error) error
 }
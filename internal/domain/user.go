@@ -2,12 +2,80 @@
 // para la aplicación.
 package domain
 
+import "time"
+
 // User representa la entidad principal de un usuario en el sistema.
 type User struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
 	Username string `json:"username"`
 	Email    string `json:"email"`
+
+	// UsernameNormalized y EmailNormalized son las formas canónicas usadas para
+	// garantizar la unicidad sin distinguir mayúsculas ni variantes Unicode.
+	// Las calcula la capa de aplicación y nunca se exponen al cliente.
+	UsernameNormalized string `json:"-"`
+	EmailNormalized    string `json:"-"`
+
+	// ExternalID es el identificador asignado por el proveedor de identidad
+	// que aprovisiona al usuario vía SCIM; solo se expone por SCIM.
+	ExternalID string `json:"-"`
+
+	// TenantID es la organización a la que pertenece el usuario. Lo fija el
+	// repositorio según la organización de la operación; no se expone.
+	TenantID string `json:"-"`
+
+	// CreatedAt/UpdatedAt registran cuándo se creó y se modificó por última vez
+	// el usuario; CreatedBy/UpdatedBy identifican al actor que lo hizo.
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by"`
+	UpdatedBy string    `json:"updated_by"`
+
+	// DeletedAt es el instante de la eliminación lógica (soft delete); nil
+	// si el usuario está activo.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	// Status es el estado de la cuenta (ver UserStatus). Solo lo modifican
+	// las acciones del ciclo de vida; StatusReason y StatusChangedAt
+	// registran el motivo y el instante del último cambio.
+	Status          UserStatus `json:"status"`
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+
+	// EmailVerified indica que el usuario confirmó que controla Email (ver
+	// EmailVerificationToken); EmailVerifiedAt es el instante de la
+	// confirmación. Cambiar el email los restablece.
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+}
+
+// UserFilter define los criterios para listar usuarios.
+type UserFilter struct {
+	// IncludeDeleted incluye en el resultado los usuarios eliminados lógicamente.
+	IncludeDeleted bool
+
+	// CreatedAfter/CreatedBefore y UpdatedAfter/UpdatedBefore acotan (de forma
+	// inclusiva) los rangos de creación y última modificación. nil = sin límite.
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+
+	// Fields limita los campos leídos y retornados (ver ParseUserFields); nil = todos.
+	Fields []string
+
+	// IDs limita el resultado a los usuarios indicados; nil = sin límite.
+	IDs []string
+
+	// Statuses limita el resultado a los usuarios en alguno de los estados
+	// indicados; nil = todos.
+	Statuses []UserStatus
+
+	// AfterID y Limit paginan por ID (keyset): solo se retornan los usuarios
+	// con ID mayor a AfterID, en orden de ID y hasta Limit. 0 = sin límite.
+	AfterID string
+	Limit   int
 }
 
 // UserCreateRequest es la estructura utilizada para recibir datos
@@ -16,13 +84,23 @@ type UserCreateRequest struct {
 	Name     string `json:"name" validate:"required,excludesall= "`
 	Username string `json:"username" validate:"required,excludesall= "`
 	Email    string `json:"email" validate:"required,excludesall= ,email"`
+
+	// Status es el estado inicial de la cuenta: pending o active (por defecto).
+	Status UserStatus `json:"status,omitempty" validate:"omitempty,oneof=pending active"`
+
+	// ExternalID es el identificador del proveedor de identidad (solo SCIM).
+	ExternalID string `json:"-"`
 }
 
 // UserResponse es la estructura utilizada para enviar de vuelta los datos
 // de un usuario al cliente.
 type UserResponse struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by"`
+	UpdatedBy string    `json:"updated_by"`
 }
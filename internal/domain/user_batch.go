@@ -0,0 +1,36 @@
+package domain
+
+import "errors"
+
+// ErrBatchNotApplied indica que una operación de un lote atómico no se
+// aplicó porque otra operación del mismo lote falló.
+var ErrBatchNotApplied = errors.New("operation not applied: the batch was rolled back")
+
+// BatchOperationType identifica el tipo de una operación de un lote.
+type BatchOperationType string
+
+const (
+	BatchCreate BatchOperationType = "create"
+	BatchUpdate BatchOperationType = "update"
+	BatchDelete BatchOperationType = "delete"
+)
+
+// UserBatchOperation es una operación de un lote de cambios sobre usuarios.
+// Según Op se usa Create (create), Update (update, con el ID incluido) o ID (delete).
+type UserBatchOperation struct {
+	// Index es la posición de la operación en el lote recibido.
+	Index  int
+	Op     BatchOperationType
+	ID     string
+	Create *UserCreateRequest
+	Update *User
+}
+
+// UserBatchResult es el resultado de una operación de un lote: el usuario
+// creado o actualizado, o el error que impidió aplicarla.
+type UserBatchResult struct {
+	Index int
+	Op    BatchOperationType
+	User  *User
+	Err   error
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrUnknownField indica que se solicitó un campo que User no expone.
+var ErrUnknownField = errors.New("unknown field")
+
+// userFieldNames son los campos públicos de User (sus nombres JSON), en
+// orden de declaración. Se derivan de los tags para que la lista de campos
+// permitidos no diverja de la representación del usuario.
+var userFieldNames = func() []string {
+	names := make([]string, 0)
+
+	userType := reflect.TypeOf(User{})
+	for i := 0; i < userType.NumField(); i++ {
+		name, _, _ := strings.Cut(userType.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}()
+
+// UserFieldNames retorna los campos que pueden solicitarse de un usuario,
+// que coinciden con los nombres de sus columnas.
+func UserFieldNames() []string {
+	return append([]string(nil), userFieldNames...)
+}
+
+// ParseUserFields valida una lista de campos separados por comas (e.g.,
+// "id,username") contra UserFieldNames y la retorna sin duplicados. Una
+// lista vacía retorna nil (todos los campos).
+func ParseUserFields(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	known := map[string]bool{}
+	for _, name := range userFieldNames {
+		known[name] = true
+	}
+
+	fields, seen := make([]string, 0), map[string]bool{}
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if !known[field] {
+			return nil, fmt.Errorf("%w %q; available fields: %s", ErrUnknownField, field, strings.Join(userFieldNames, ","))
+		}
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUserFieldNamesFollowTheJSONRepresentation(t *testing.T) {
+	want := []string{
+		"id", "name", "username", "email", "created_at", "updated_at", "created_by", "updated_by", "deleted_at",
+		"status", "status_reason", "status_changed_at", "email_verified", "email_verified_at",
+	}
+	if got := UserFieldNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("UserFieldNames() = %v, want %v (update the readme field list too)", got, want)
+	}
+}
+
+func TestParseUserFields(t *testing.T) {
+	fields, err := ParseUserFields(" id, username ,id,status")
+	if err != nil || !reflect.DeepEqual(fields, []string{"id", "username", "status"}) {
+		t.Errorf("ParseUserFields = %v, %v", fields, err)
+	}
+
+	if fields, err := ParseUserFields("  "); fields != nil || err != nil {
+		t.Errorf("empty list = %v, %v; want nil (all fields)", fields, err)
+	}
+
+	for _, raw := range []string{"password", "id,tenant_id", "username_normalized", "id,"} {
+		if _, err := ParseUserFields(raw); !errors.Is(err, ErrUnknownField) {
+			t.Errorf("ParseUserFields(%q): err = %v, want ErrUnknownField", raw, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrImportNotFound indica que la importación solicitada no existe.
+	ErrImportNotFound = errors.New("import not found")
+	// ErrInvalidImportFile indica que el archivo no puede leerse en el formato indicado
+	// (e.g., CSV mal formado o sin las columnas requeridas).
+	ErrInvalidImportFile = errors.New("invalid import file")
+	// ErrImportNotResumable indica que la importación ya terminó y no puede reanudarse.
+	ErrImportNotResumable = errors.New("import is not resumable")
+)
+
+// ImportFormat es el formato del archivo de una importación.
+type ImportFormat string
+
+const (
+	ImportCSV    ImportFormat = "csv"
+	ImportNDJSON ImportFormat = "ndjson"
+)
+
+// ImportStatus es el estado de una importación.
+type ImportStatus string
+
+const (
+	// ImportPending: aceptada, aún no comenzó.
+	ImportPending ImportStatus = "pending"
+	// ImportRunning: en curso; se reanuda desde ProcessedRows si el proceso se reinicia.
+	ImportRunning ImportStatus = "running"
+	// ImportCompleted: todas las filas fueron procesadas (las inválidas quedan en el reporte de errores).
+	ImportCompleted ImportStatus = "completed"
+	// ImportFailed: se interrumpió por un error inesperado; puede reanudarse.
+	ImportFailed ImportStatus = "failed"
+)
+
+// ImportColumnMapping indica qué columna del CSV corresponde a cada campo del
+// usuario. Un valor vacío usa el nombre del campo ("name", "username", "email").
+type ImportColumnMapping struct {
+	Name     string `json:"name,omitempty"`
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+// UserImport es una importación de usuarios procesada en segundo plano, por
+// bloques, con su progreso persistido.
+type UserImport struct {
+	ID string `json:"id"`
+	// TenantID es la organización en la que se crean los usuarios.
+	TenantID string              `json:"-"`
+	Format   ImportFormat        `json:"format"`
+	Mapping  ImportColumnMapping `json:"mapping"`
+	Status   ImportStatus        `json:"status"`
+	// TotalRows es la cantidad de filas de datos del archivo; ProcessedRows
+	// cuántas ya fueron procesadas (creadas o rechazadas).
+	TotalRows     int        `json:"total_rows"`
+	ProcessedRows int        `json:"processed_rows"`
+	CreatedRows   int        `json:"created_rows"`
+	FailedRows    int        `json:"failed_rows"`
+	Error         string     `json:"error,omitempty"`
+	CreatedBy     string     `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// ImportRowError describe por qué una fila no fue (o no sería) importada.
+type ImportRowError struct {
+	// Row es el número de línea en el archivo (en CSV, el encabezado es la línea 1).
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportReport es el resultado de validar un archivo de importación sin
+// aplicarlo (dry run).
+type ImportReport struct {
+	TotalRows   int              `json:"total_rows"`
+	ValidRows   int              `json:"valid_rows"`
+	InvalidRows int              `json:"invalid_rows"`
+	Errors      []ImportRowError `json:"errors"`
+}
+
+// UserImportRepository define el contract de persistencia de las importaciones.
+type UserImportRepository interface {
+	// Create persiste la importación junto con el contenido del archivo.
+	Create(userImport *UserImport, payload []byte) error
+	// FindById retorna ErrImportNotFound si no existe.
+	FindById(id string) (*UserImport, error)
+	// FindRunnable retorna las importaciones pendientes o en curso, en orden de creación.
+	FindRunnable(limit int) ([]UserImport, error)
+	// Claim toma (o renueva) hasta leaseUntil el derecho exclusivo de procesar
+	// una importación pendiente o en curso. Retorna false si otro proceso la
+	// tiene tomada o si ya no es procesable.
+	Claim(id, owner string, leaseUntil time.Time) (bool, error)
+	// Payload retorna el contenido del archivo de la importación.
+	Payload(id string) ([]byte, error)
+	// Update persiste el estado y el progreso de la importación.
+	Update(userImport *UserImport) error
+	// AppendErrors agrega errores de filas al reporte de la importación.
+	AppendErrors(importID string, rowErrors []ImportRowError) error
+	// Errors retorna el reporte de errores de la importación, ordenado por fila.
+	Errors(importID string) ([]ImportRowError, error)
+}
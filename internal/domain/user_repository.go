@@ -2,6 +2,8 @@
 // y define los contracts (interfaces) para la lógica de negocio.
 package domain
 
+import "time"
+
 // UserRepository define el contract para la persistencia de datos de usuario.
 // Esta interface desacopla la lógica de negocio del almacenamiento de datos
 // (como una base de datos o un servicio externo).
@@ -9,14 +11,47 @@ type UserRepository interface {
 	// Create inserta un nuevo User en el almacenamiento.
 	// Retorna un error si la operación falla (e.g., conflicto de ID o conexión).
 	Create(user *User) error
-	// FindAll recupera todos los usuarios del almacenamiento.
-	FindAll() (*[]User, error)
-	// FindById recupera un User por su identificador único (ID).
-	// Retorna nil si no se encuentra el usuario.
-	FindById(id string) (*User, error)
+	// FindAll recupera los usuarios del almacenamiento que cumplen el filtro.
+	// Por defecto excluye los usuarios eliminados lógicamente.
+	FindAll(filter UserFilter) (*[]User, error)
+	// Each invoca fn por cada usuario que cumple el filtro, en orden de ID, sin
+	// cargarlos todos en memoria. Si fn retorna un error, el recorrido se detiene
+	// y Each lo retorna.
+	Each(filter UserFilter, fn func(user *User) error) error
+	// FindById recupera un User activo por su identificador único (ID).
+	// Retorna ErrUserNotFound si no existe o está eliminado. Si se indican
+	// fields, solo se leen esos campos.
+	FindById(id string, fields ...string) (*User, error)
 	// Update aplica los cambios a un User existente en el almacenamiento.
 	// Retorna un error si la operación falla (e.g., el usuario no existe).
 	Update(user *User) error
-	// Delete elimina un User permanentemente del almacenamiento usando su ID.
+	// UpdateStatus persiste el estado de la cuenta del usuario (Status,
+	// StatusReason y StatusChangedAt) y sus marcas de modificación.
+	// Retorna ErrUserNotFound si no existe o está eliminado.
+	UpdateStatus(user *User) error
+	// UpdateEmailVerification persiste EmailVerified y EmailVerifiedAt del
+	// usuario y sus marcas de modificación.
+	// Retorna ErrUserNotFound si no existe o está eliminado.
+	UpdateEmailVerification(user *User) error
+	// Delete elimina lógicamente (soft delete) un User usando su ID.
+	// Retorna ErrUserNotFound si no existe o ya estaba eliminado.
 	Delete(id string) error
+	// ReleaseIdentity libera el username y email de un usuario eliminado para
+	// que puedan ser reutilizados, vaciando sus formas normalizadas.
+	ReleaseIdentity(id string) error
+	// Restore revierte la eliminación lógica de un User y retorna su estado
+	// previo (eliminado). Retorna ErrUserNotFound si no existe o
+	// ErrUserNotDeleted si no está eliminado.
+	Restore(id string) (*User, error)
+	// Purge elimina permanentemente los usuarios eliminados lógicamente antes
+	// del instante indicado y retorna los usuarios eliminados (solo ID y TenantID).
+	Purge(deletedBefore time.Time) ([]User, error)
+	// TakenIdentities retorna, de las formas normalizadas indicadas, las que ya
+	// están reservadas por algún usuario (incluidos los eliminados que las retienen).
+	TakenIdentities(usernamesNormalized, emailsNormalized []string) (usernames, emails []string, err error)
+	// ForTenant retorna un UserRepository restringido a la organización
+	// indicada: todas sus consultas filtran por ella y los usuarios creados
+	// le pertenecen. El repositorio sin restringir opera sobre todas las
+	// organizaciones y solo debe usarse en tareas de mantenimiento (e.g., Purge).
+	ForTenant(tenantID string) UserRepository
 }
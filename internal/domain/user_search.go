@@ -0,0 +1,40 @@
+// Package domain contiene las estructuras de datos fundamentales (models/entities)
+// y define los contracts (interfaces) para la lógica de negocio.
+package domain
+
+// UserSearchQuery describe una búsqueda de texto libre sobre los usuarios.
+type UserSearchQuery struct {
+	// Query es el texto buscado (parcial o aproximado) en name, username y email.
+	Query string
+	// Limit y Offset definen la página de resultados solicitada.
+	Limit  int
+	Offset int
+}
+
+// UserSearchHit es un usuario encontrado junto con su relevancia.
+type UserSearchHit struct {
+	User User `json:"user"`
+	// Rank es la relevancia del resultado; mayor es mejor.
+	Rank float64 `json:"rank"`
+	// Highlights contiene, por campo, el valor con los fragmentos coincidentes
+	// marcados con <mark></mark>. Solo incluye los campos que coincidieron.
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// UserSearchResult es una página de resultados de búsqueda.
+type UserSearchResult struct {
+	Hits   []UserSearchHit `json:"results"`
+	Total  int64           `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+// UserSearcher es una capacidad opcional de un UserRepository que sabe
+// resolver búsquedas de forma nativa (e.g., índices de texto completo).
+// Los repositorios que no la implementan siguen siendo válidos: la capa de
+// aplicación usa una búsqueda genérica sobre FindAll.
+type UserSearcher interface {
+	// Search retorna los usuarios que coinciden con la consulta, ordenados por
+	// relevancia descendente, junto con el total de coincidencias.
+	Search(query UserSearchQuery) (*UserSearchResult, error)
+}
@@ -0,0 +1,97 @@
+// Package domain contiene las estructuras de datos fundamentales (models/entities)
+// y define los contracts (interfaces) para la lógica de negocio.
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidUserStatus indica que se indicó un estado de cuenta desconocido.
+	ErrInvalidUserStatus = errors.New("invalid user status")
+	// ErrInvalidStatusTransition indica que la acción no está permitida desde
+	// el estado actual de la cuenta (e.g., desbloquear una cuenta no bloqueada).
+	ErrInvalidStatusTransition = errors.New("status transition not allowed")
+	// ErrStatusReasonRequired indica que la acción requiere un motivo.
+	ErrStatusReasonRequired = errors.New("a reason is required for this status change")
+)
+
+// UserStatus es el estado de la cuenta de un usuario. Es independiente de la
+// eliminación lógica: un usuario eliminado conserva su último estado.
+type UserStatus string
+
+const (
+	// UserPending es una cuenta creada que aún no fue activada.
+	UserPending UserStatus = "pending"
+	// UserActive es una cuenta habilitada; es el único estado que permite autenticarse.
+	UserActive UserStatus = "active"
+	// UserSuspended es una cuenta deshabilitada temporalmente por un administrador.
+	UserSuspended UserStatus = "suspended"
+	// UserLocked es una cuenta bloqueada por motivos de seguridad.
+	UserLocked UserStatus = "locked"
+	// UserDeactivated es una cuenta dada de baja, que puede reactivarse.
+	UserDeactivated UserStatus = "deactivated"
+)
+
+// UserStatuses son los estados de cuenta, en el orden de su ciclo de vida.
+var UserStatuses = []UserStatus{UserPending, UserActive, UserSuspended, UserLocked, UserDeactivated}
+
+// CanAuthenticate indica si las credenciales del usuario (e.g., sus claves
+// de API) son válidas en este estado.
+func (s UserStatus) CanAuthenticate() bool {
+	return s == UserActive
+}
+
+// ParseUserStatuses valida una lista de estados separados por comas (e.g.,
+// "suspended,locked"). Una lista vacía retorna nil (todos los estados).
+func ParseUserStatuses(raw string) ([]UserStatus, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	known := map[UserStatus]bool{}
+	for _, status := range UserStatuses {
+		known[status] = true
+	}
+
+	statuses := make([]UserStatus, 0)
+	for _, value := range strings.Split(raw, ",") {
+		status := UserStatus(strings.TrimSpace(value))
+		if !known[status] {
+			return nil, fmt.Errorf("%w %q; available statuses: pending, active, suspended, locked, deactivated", ErrInvalidUserStatus, status)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// UserStatusAction es una transición del ciclo de vida de la cuenta
+// (POST /users/{id}:<acción>).
+type UserStatusAction string
+
+const (
+	// StatusActivate activa una cuenta pendiente o reactiva una suspendida o dada de baja.
+	StatusActivate UserStatusAction = "activate"
+	// StatusSuspend suspende una cuenta activa o bloqueada.
+	StatusSuspend UserStatusAction = "suspend"
+	// StatusLock bloquea una cuenta activa.
+	StatusLock UserStatusAction = "lock"
+	// StatusUnlock desbloquea una cuenta bloqueada.
+	StatusUnlock UserStatusAction = "unlock"
+	// StatusDeactivate da de baja una cuenta en cualquier estado salvo deactivated.
+	StatusDeactivate UserStatusAction = "deactivate"
+)
+
+// UserStatusActions son las acciones del ciclo de vida de la cuenta.
+var UserStatusActions = []UserStatusAction{StatusActivate, StatusSuspend, StatusLock, StatusUnlock, StatusDeactivate}
+
+// UserStatusChangeRequest es el cuerpo (opcional) de las acciones del ciclo
+// de vida de la cuenta.
+type UserStatusChangeRequest struct {
+	// Reason se registra en el usuario y en la auditoría; es obligatorio para
+	// suspender, bloquear y dar de baja.
+	Reason string `json:"reason,omitempty" validate:"max=500"`
+}
@@ -0,0 +1,166 @@
+// Package domain contiene las estructuras de datos fundamentales (models/entities)
+// y define los contracts (interfaces) para la lógica de negocio.
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"time"
+)
+
+var (
+	// ErrWebhookNotFound indica que la suscripción de webhook solicitada no existe.
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrWebhookDeliveryNotFound indica que la entrega de webhook solicitada no existe.
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrInvalidEventType indica que un filtro de webhook contiene un tipo de evento desconocido.
+	ErrInvalidEventType = errors.New("invalid event type")
+	// ErrWebhookTargetNotAllowed indica que la URL de un webhook apunta (o su
+	// host resuelve) a una dirección interna o reservada.
+	ErrWebhookTargetNotAllowed = errors.New("webhook url must resolve to a public address")
+)
+
+// Webhook es una suscripción de un tercero a los eventos de usuario.
+type Webhook struct {
+	ID string `json:"id"`
+	// TenantID es la organización cuyos eventos recibe el webhook.
+	TenantID string `json:"-"`
+	URL      string `json:"url"`
+	// Events filtra los tipos de evento entregados; vacío significa todos.
+	Events []EventType `json:"events"`
+	// Secret es la clave de la firma HMAC-SHA256. Solo se expone al crear el webhook.
+	Secret string `json:"secret,omitempty"`
+	Active bool   `json:"active"`
+	// ConsecutiveFailures cuenta los intentos fallidos desde la última entrega exitosa.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+	// DisabledAt es el instante en que el webhook se desactivó automáticamente.
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Accepts indica si el webhook está activo y suscripto al tipo de evento
+// de su organización.
+func (w *Webhook) Accepts(event UserEvent) bool {
+	if !w.Active || w.TenantID != event.Tenant() {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, accepted := range w.Events {
+		if accepted == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryState es el estado de una entrega de webhook.
+type WebhookDeliveryState string
+
+const (
+	// DeliveryPending indica que la entrega espera su (re)intento.
+	DeliveryPending WebhookDeliveryState = "pending"
+	// DeliverySucceeded indica que el destino respondió con un código 2xx.
+	DeliverySucceeded WebhookDeliveryState = "succeeded"
+	// DeliveryFailed indica que se agotaron los reintentos o el webhook fue desactivado.
+	DeliveryFailed WebhookDeliveryState = "failed"
+)
+
+// WebhookDelivery es la entrega de un evento a un webhook, con su historial de intentos.
+type WebhookDelivery struct {
+	ID        string               `json:"id"`
+	WebhookID string               `json:"webhook_id"`
+	EventID   string               `json:"event_id"`
+	EventType EventType            `json:"event_type"`
+	Payload   string               `json:"-"`
+	State     WebhookDeliveryState `json:"state"`
+	Attempts  int                  `json:"attempts"`
+	// LastStatusCode es el código HTTP de la última respuesta (0 si no hubo respuesta).
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// WebhookRepository define el contract de persistencia de webhooks y de su registro de entregas.
+type WebhookRepository interface {
+	Create(webhook *Webhook) error
+	FindAll() ([]Webhook, error)
+	// FindById retorna ErrWebhookNotFound si no existe.
+	FindById(id string) (*Webhook, error)
+	// Update persiste todos los campos del webhook. Retorna ErrWebhookNotFound si no existe.
+	Update(webhook *Webhook) error
+	// Delete elimina el webhook y su registro de entregas.
+	Delete(id string) error
+
+	CreateDelivery(delivery *WebhookDelivery) error
+	UpdateDelivery(delivery *WebhookDelivery) error
+	// FindDelivery retorna ErrWebhookDeliveryNotFound si no existe.
+	FindDelivery(webhookID, deliveryID string) (*WebhookDelivery, error)
+	// FindDeliveries retorna las entregas de un webhook, de la más reciente a la más antigua.
+	FindDeliveries(webhookID string, limit int) ([]WebhookDelivery, error)
+	// FindDueDeliveries retorna hasta limit entregas pendientes cuyo próximo intento ya venció.
+	FindDueDeliveries(now time.Time, limit int) ([]WebhookDelivery, error)
+}
+
+// WebhookRequest es la petición HTTP firmada que se envía a un webhook.
+type WebhookRequest struct {
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// WebhookSender envía peticiones de webhook y retorna el código de estado de la respuesta.
+type WebhookSender interface {
+	Send(ctx context.Context, request WebhookRequest) (statusCode int, err error)
+}
+
+// WebhookCreateRequest es la estructura utilizada para registrar un webhook.
+// Si Secret se omite, se genera uno aleatorio.
+type WebhookCreateRequest struct {
+	URL    string      `json:"url" validate:"required,http_url"`
+	Events []EventType `json:"events"`
+	Secret string      `json:"secret" validate:"omitempty,min=16"`
+}
+
+// WebhookUpdateRequest es la estructura utilizada para modificar un webhook.
+// Los campos nil no se modifican. Reactivar un webhook reinicia su contador de fallos.
+type WebhookUpdateRequest struct {
+	URL    *string      `json:"url" validate:"omitempty,http_url"`
+	Events *[]EventType `json:"events"`
+	Secret *string      `json:"secret" validate:"omitempty,min=16"`
+	Active *bool        `json:"active"`
+}
+
+// reservedWebhookPrefixes son los rangos reservados que no cubren los
+// predicados de netip.Addr: "this network", el espacio compartido de CGNAT,
+// los rangos de benchmarking y los reservados para uso futuro (incluido el
+// broadcast).
+var reservedWebhookPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// WebhookAddressAllowed indica si un webhook puede enviar peticiones a addr.
+// Se rechazan las direcciones de loopback, privadas, link-local (incluida
+// 169.254.169.254, el servicio de metadatos de los proveedores de nube),
+// multicast, no especificadas y reservadas, también como IPv4 mapeada en IPv6.
+func WebhookAddressAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range reservedWebhookPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
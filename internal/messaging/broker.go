@@ -0,0 +1,71 @@
+package messaging
+
+import (
+	"context"
+	"sync"
+	"user-api-restful/internal/domain"
+)
+
+// Broker distribuye en memoria los eventos publicados a los suscriptores en
+// vivo (e.g., streams SSE). Implementa domain.EventPublisher, para recibir
+// los eventos del relay del outbox, y domain.EventStream.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[chan domain.UserEvent]string
+	buffer      int
+}
+
+// NewBroker crea un Broker cuyos suscriptores tienen un buffer de buffer eventos.
+func NewBroker(buffer int) *Broker {
+	return &Broker{subscribers: map[chan domain.UserEvent]string{}, buffer: buffer}
+}
+
+// Asegura que Broker implemente domain.EventPublisher y domain.EventStream.
+var (
+	_ domain.EventPublisher = (*Broker)(nil)
+	_ domain.EventStream    = (*Broker)(nil)
+)
+
+// Publish entrega el evento a los suscriptores de su organización sin bloquear. Un
+// suscriptor con el buffer lleno es desconectado (su canal se cierra) para
+// que reanude desde el registro en lugar de perder eventos en silencio.
+func (b *Broker) Publish(ctx context.Context, event domain.UserEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tenantID := event.Tenant()
+	for subscriber, subscribedTenant := range b.subscribers {
+		if subscribedTenant != tenantID {
+			continue
+		}
+
+		select {
+		case subscriber <- event:
+		default:
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe registra un nuevo suscriptor a los eventos de la organización tenantID.
+func (b *Broker) Subscribe(tenantID string) (<-chan domain.UserEvent, func()) {
+	subscriber := make(chan domain.UserEvent, b.buffer)
+
+	b.mu.Lock()
+	b.subscribers[subscriber] = tenantID
+	b.mu.Unlock()
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[subscriber]; ok {
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+
+	return subscriber, cancel
+}
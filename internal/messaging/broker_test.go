@@ -0,0 +1,44 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+	"user-api-restful/internal/domain"
+)
+
+func TestBrokerDeliversOnlyTheSubscribedTenant(t *testing.T) {
+	broker := NewBroker(4)
+	acme, cancelAcme := broker.Subscribe("acme")
+	defer cancelAcme()
+	legacy, cancelLegacy := broker.Subscribe(domain.DefaultTenantID)
+	defer cancelLegacy()
+
+	_ = broker.Publish(context.Background(), domain.UserEvent{ID: "1", TenantID: "globex"})
+	_ = broker.Publish(context.Background(), domain.UserEvent{ID: "2", TenantID: "acme"})
+	_ = broker.Publish(context.Background(), domain.UserEvent{ID: "3"})
+
+	if event := <-acme; event.ID != "2" || len(acme) != 0 {
+		t.Errorf("acme received %q (+%d), want only 2", event.ID, len(acme))
+	}
+	if event := <-legacy; event.ID != "3" || len(legacy) != 0 {
+		t.Errorf("default tenant received %q (+%d), want only 3", event.ID, len(legacy))
+	}
+}
+
+func TestBrokerDisconnectsSlowSubscribers(t *testing.T) {
+	broker := NewBroker(1)
+	events, cancel := broker.Subscribe("acme")
+
+	_ = broker.Publish(context.Background(), domain.UserEvent{ID: "1", TenantID: "acme"})
+	_ = broker.Publish(context.Background(), domain.UserEvent{ID: "2", TenantID: "acme"})
+
+	if event := <-events; event.ID != "1" {
+		t.Errorf("first event = %q, want 1", event.ID)
+	}
+	if _, ok := <-events; ok {
+		t.Error("slow subscriber channel is still open")
+	}
+
+	// Cancelar una suscripción ya cerrada no debe fallar.
+	cancel()
+}
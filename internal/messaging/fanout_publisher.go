@@ -0,0 +1,33 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"user-api-restful/internal/domain"
+)
+
+// FanoutPublisher publica cada evento en varios publishers. Si alguno falla,
+// el evento se reintenta en todos, por lo que cada destino debe tolerar
+// duplicados (entrega "at-least-once").
+type FanoutPublisher struct {
+	publishers []domain.EventPublisher
+}
+
+// NewFanoutPublisher crea un FanoutPublisher sobre los publishers indicados.
+func NewFanoutPublisher(publishers ...domain.EventPublisher) *FanoutPublisher {
+	return &FanoutPublisher{publishers: publishers}
+}
+
+// Asegura que FanoutPublisher implemente domain.EventPublisher.
+var _ domain.EventPublisher = (*FanoutPublisher)(nil)
+
+// Publish publica el evento en todos los publishers y combina sus errores.
+func (f *FanoutPublisher) Publish(ctx context.Context, event domain.UserEvent) error {
+	var errs []error
+	for _, publisher := range f.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,76 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// HTTPWebhookSender implementa domain.WebhookSender con un http.Client.
+type HTTPWebhookSender struct {
+	client *http.Client
+}
+
+// NewHTTPWebhookSender crea un sender cuyas peticiones expiran tras timeout.
+// Salvo con allowPrivateTargets, el sender no se conecta a direcciones
+// internas o reservadas (ver domain.WebhookAddressAllowed). Las peticiones
+// no usan el proxy del entorno, que impediría verificar el destino real.
+func NewHTTPWebhookSender(timeout time.Duration, allowPrivateTargets bool) *HTTPWebhookSender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivateTargets {
+		dialer.Control = rejectReservedAddresses
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &HTTPWebhookSender{client: &http.Client{Timeout: timeout, Transport: transport}}
+}
+
+// rejectReservedAddresses es el hook Control del dialer. Se ejecuta con la
+// dirección ya resuelta de cada conexión, incluidas las de las redirecciones,
+// por lo que un host que pasa a resolver a la red interna después del
+// registro (DNS rebinding) no puede alcanzarla.
+func rejectReservedAddresses(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !domain.WebhookAddressAllowed(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", domain.ErrWebhookTargetNotAllowed, addrPort.Addr())
+	}
+	return nil
+}
+
+// Asegura que HTTPWebhookSender implemente domain.WebhookSender.
+var _ domain.WebhookSender = (*HTTPWebhookSender)(nil)
+
+// Send envía la petición POST y retorna el código de estado de la respuesta.
+func (s *HTTPWebhookSender) Send(ctx context.Context, request domain.WebhookRequest) (int, error) {
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Body))
+	if err != nil {
+		return 0, err
+	}
+	for name, value := range request.Headers {
+		httpRequest.Header.Set(name, value)
+	}
+
+	response, err := s.client.Do(httpRequest)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	// Descarta (acotado) el cuerpo para permitir la reutilización de la conexión.
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	return response.StatusCode, nil
+}
@@ -0,0 +1,64 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+func TestHTTPWebhookSenderPostsHeadersAndBody(t *testing.T) {
+	var gotMethod, gotSignature, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethod, gotSignature, gotBody = r.Method, r.Header.Get("X-Webhook-Signature"), string(body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := NewHTTPWebhookSender(time.Second, true)
+	status, err := sender.Send(context.Background(), domain.WebhookRequest{
+		URL:     server.URL,
+		Headers: map[string]string{"X-Webhook-Signature": "sha256=abc"},
+		Body:    []byte(`{"id":"evt-1"}`),
+	})
+	if err != nil || status != http.StatusAccepted {
+		t.Fatalf("Send = %d, %v; want 202", status, err)
+	}
+	if gotMethod != http.MethodPost || gotSignature != "sha256=abc" || gotBody != `{"id":"evt-1"}` {
+		t.Errorf("received %s signature=%q body=%q", gotMethod, gotSignature, gotBody)
+	}
+}
+
+func TestHTTPWebhookSenderReportsUnreachableEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	status, err := NewHTTPWebhookSender(time.Second, true).Send(context.Background(), domain.WebhookRequest{URL: url})
+	if err == nil || status != 0 {
+		t.Errorf("Send = %d, %v; want a connection error", status, err)
+	}
+}
+
+func TestHTTPWebhookSenderRefusesInternalAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }))
+	defer server.Close()
+
+	// El host se resuelve al conectar: "localhost" pasa la validación de la
+	// URL pero su dirección (loopback) se rechaza en el dialer.
+	url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	status, err := NewHTTPWebhookSender(time.Second, false).Send(context.Background(), domain.WebhookRequest{URL: url})
+	if !errors.Is(err, domain.ErrWebhookTargetNotAllowed) || status != 0 {
+		t.Errorf("Send = %d, %v; want ErrWebhookTargetNotAllowed", status, err)
+	}
+	if called {
+		t.Error("the internal endpoint received the request")
+	}
+}
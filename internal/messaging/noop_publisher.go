@@ -0,0 +1,18 @@
+package messaging
+
+import (
+	"context"
+	"user-api-restful/internal/domain"
+)
+
+// NoopPublisher descarta los eventos. Permite desactivar la publicación sin
+// detener el relay (los eventos se marcan como publicados y quedan en el outbox).
+type NoopPublisher struct{}
+
+// Asegura que NoopPublisher implemente domain.EventPublisher.
+var _ domain.EventPublisher = NoopPublisher{}
+
+// Publish no hace nada.
+func (NoopPublisher) Publish(ctx context.Context, event domain.UserEvent) error {
+	return nil
+}
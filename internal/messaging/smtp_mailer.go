@@ -0,0 +1,91 @@
+package messaging
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// SMTPMailer implementa domain.Mailer enviando los mensajes a un servidor
+// SMTP. Usa STARTTLS si el servidor lo ofrece y se autentica (PLAIN) si se
+// indicó un usuario, lo que permite usar tanto un proveedor de correo como
+// un servidor local de pruebas (e.g., Mailpit o MailHog) sin TLS.
+type SMTPMailer struct {
+	addr    string
+	host    string
+	from    string
+	auth    smtp.Auth
+	timeout time.Duration
+}
+
+// NewSMTPMailer crea un SMTPMailer para el servidor host:port. username
+// vacío = sin autenticación. Cada envío expira tras timeout.
+func NewSMTPMailer(host, port, username, password, from string, timeout time.Duration) *SMTPMailer {
+	mailer := &SMTPMailer{addr: net.JoinHostPort(host, port), host: host, from: from, timeout: timeout}
+	if username != "" {
+		mailer.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return mailer
+}
+
+// Asegura que SMTPMailer implemente domain.Mailer.
+var _ domain.Mailer = (*SMTPMailer)(nil)
+
+// Send entrega el mensaje al servidor SMTP.
+func (m *SMTPMailer) Send(ctx context.Context, message domain.EmailMessage) error {
+	body, err := composeMessage(m.from, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: m.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(m.timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if err := client.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(m.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(body); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,76 @@
+package messaging
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// WriterMailer implementa domain.Mailer escribiendo cada mensaje completo
+// (RFC 5322) en un io.Writer en lugar de enviarlo. Útil en desarrollo y en
+// pruebas: los enlaces de verificación quedan en el log o en un archivo.
+type WriterMailer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+	file *os.File
+}
+
+// NewLogMailer crea un WriterMailer que escribe en la salida del log estándar.
+func NewLogMailer(from string) *WriterMailer {
+	return NewWriterMailer(log.Writer(), from)
+}
+
+// NewWriterMailer crea un WriterMailer que escribe en w.
+func NewWriterMailer(w io.Writer, from string) *WriterMailer {
+	return &WriterMailer{w: w, from: from}
+}
+
+// NewFileMailer crea un WriterMailer que agrega los mensajes al archivo
+// indicado, creándolo si no existe.
+func NewFileMailer(path, from string) (*WriterMailer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	mailer := NewWriterMailer(file, from)
+	mailer.file = file
+
+	return mailer, nil
+}
+
+// Asegura que WriterMailer implemente domain.Mailer.
+var _ domain.Mailer = (*WriterMailer)(nil)
+
+// Send escribe el mensaje seguido de una línea en blanco.
+func (m *WriterMailer) Send(ctx context.Context, message domain.EmailMessage) error {
+	body, err := composeMessage(m.from, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.w.Write(append(body, "\r\n"...)); err != nil {
+		return err
+	}
+	if m.file != nil {
+		return m.file.Sync()
+	}
+
+	return nil
+}
+
+// Close cierra el archivo subyacente, si lo hay.
+func (m *WriterMailer) Close() error {
+	if m.file != nil {
+		return m.file.Close()
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Package messaging contiene las implementaciones (adapters) de
+// domain.EventPublisher que entregan los eventos de dominio fuera del
+// servicio, y de domain.Mailer, que envían los mensajes de correo.
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"user-api-restful/internal/domain"
+)
+
+// WriterPublisher publica cada evento como una línea JSON (NDJSON) en un
+// io.Writer. Útil para uso offline, depuración o para ser recolectado por
+// un agente de logs.
+type WriterPublisher struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	file    *os.File
+}
+
+// NewStdoutPublisher crea un WriterPublisher que escribe en la salida estándar.
+func NewStdoutPublisher() *WriterPublisher {
+	return NewWriterPublisher(os.Stdout)
+}
+
+// NewWriterPublisher crea un WriterPublisher que escribe en w.
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{encoder: json.NewEncoder(w)}
+}
+
+// NewFilePublisher crea un WriterPublisher que agrega los eventos al archivo
+// indicado, creándolo si no existe. Cada evento se sincroniza a disco antes
+// de confirmarse.
+func NewFilePublisher(path string) (*WriterPublisher, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	publisher := NewWriterPublisher(file)
+	publisher.file = file
+
+	return publisher, nil
+}
+
+// Asegura que WriterPublisher implemente domain.EventPublisher.
+var _ domain.EventPublisher = (*WriterPublisher)(nil)
+
+// Publish escribe el evento como una línea JSON.
+func (p *WriterPublisher) Publish(ctx context.Context, event domain.UserEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.encoder.Encode(event); err != nil {
+		return err
+	}
+	if p.file != nil {
+		return p.file.Sync()
+	}
+
+	return nil
+}
+
+// Close cierra el archivo subyacente, si lo hay.
+func (p *WriterPublisher) Close() error {
+	if p.file != nil {
+		return p.file.Close()
+	}
+	return nil
+}
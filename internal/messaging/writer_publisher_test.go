@@ -0,0 +1,70 @@
+package messaging
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"user-api-restful/internal/domain"
+)
+
+// failingPublisher falla siempre con err.
+type failingPublisher struct{ err error }
+
+func (p failingPublisher) Publish(context.Context, domain.UserEvent) error { return p.err }
+
+func TestFilePublisherAppendsNDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+
+	for _, id := range []string{"e1", "e2"} {
+		publisher, err := NewFilePublisher(path)
+		if err != nil {
+			t.Fatalf("NewFilePublisher: %v", err)
+		}
+		if err := publisher.Publish(context.Background(), domain.UserEvent{ID: id, Type: domain.EventUserCreated}); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+		_ = publisher.Close()
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event domain.UserEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		ids = append(ids, event.ID)
+	}
+	if len(ids) != 2 || ids[0] != "e1" || ids[1] != "e2" {
+		t.Errorf("ids = %v, want [e1 e2]", ids)
+	}
+}
+
+func TestFanoutPublisherJoinsErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	file, err := NewFilePublisher(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	cause := errors.New("down")
+	fanout := NewFanoutPublisher(file, failingPublisher{err: cause})
+
+	if err := fanout.Publish(context.Background(), domain.UserEvent{ID: "e1"}); !errors.Is(err, cause) {
+		t.Errorf("err = %v, want %v", err, cause)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		t.Errorf("healthy publisher did not receive the event (%v)", err)
+	}
+}
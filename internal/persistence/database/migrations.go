@@ -0,0 +1,272 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+	"user-api-restful/internal/persistence/entity"
+
+	"gorm.io/gorm"
+)
+
+// migration representa un cambio de esquema versionado que no puede
+// expresarse con los tags de GORM (índices parciales, backfills, etc.).
+type migration struct {
+	ID string
+	Up func(tx *gorm.DB) error
+}
+
+// schemaMigration registra qué migraciones ya fueron aplicadas.
+type schemaMigration struct {
+	ID        string `gorm:"primaryKey"`
+	AppliedAt time.Time
+}
+
+// TableName fija el nombre de la tabla de control de migraciones.
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// migrations es la lista ordenada de migraciones. Nunca se debe modificar
+// una migración ya publicada; los cambios nuevos se agregan al final.
+var migrations = []migration{
+	{
+		// Unicidad sobre las formas canónicas de username y email. Antes de crear
+		// los índices se completan las formas canónicas de las filas existentes
+		// (NFKC + minúsculas, ver normalizedIdentityBackfill), salvo las que
+		// colisionan entre sí: esas quedan vacías, fuera de los índices parciales,
+		// hasta resolverlas con cmd/usernorm, que además aplica las reglas que
+		// PostgreSQL no puede replicar (case folding completo, Gmail).
+		ID: "0001_normalized_identity_indexes",
+		Up: func(tx *gorm.DB) error {
+			for _, column := range []string{"username", "email"} {
+				if err := tx.Exec(normalizedIdentityBackfill(column)).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_username_normalized
+				ON user_entities (username_normalized) WHERE username_normalized <> ''`).Error; err != nil {
+				return err
+			}
+			return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_email_normalized
+				ON user_entities (email_normalized) WHERE email_normalized <> ''`).Error
+		},
+	},
+	{
+		// Índices de texto completo y de trigramas que respaldan PostgresRepository.Search.
+		ID: "0002_user_search_indexes",
+		Up: func(tx *gorm.DB) error {
+			statements := []string{
+				`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+				`CREATE INDEX IF NOT EXISTS idx_users_search_document ON user_entities USING GIN (` + userSearchDocument + `)`,
+				`CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON user_entities USING GIN (name gin_trgm_ops)`,
+				`CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON user_entities USING GIN (username gin_trgm_ops)`,
+				`CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON user_entities USING GIN (email gin_trgm_ops)`,
+			}
+			for _, statement := range statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		// Los índices únicos sobre los valores originales son redundantes con los
+		// normalizados e impedirían reutilizar la identidad de un usuario eliminado.
+		ID: "0003_drop_raw_identity_indexes",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Exec(`DROP INDEX IF EXISTS idx_username`).Error; err != nil {
+				return err
+			}
+			return tx.Exec(`DROP INDEX IF EXISTS idx_email`).Error
+		},
+	},
+	{
+		// El registro de auditoría es inmutable: se rechaza cualquier UPDATE o DELETE.
+		ID: "0004_audit_immutable",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Exec(`CREATE OR REPLACE FUNCTION user_audit_log_immutable() RETURNS trigger AS $$
+				BEGIN
+					RAISE EXCEPTION 'user_audit_log is append-only';
+				END;
+				$$ LANGUAGE plpgsql`).Error; err != nil {
+				return err
+			}
+			return tx.Exec(`CREATE TRIGGER user_audit_log_immutable
+				BEFORE UPDATE OR DELETE ON user_audit_log
+				FOR EACH ROW EXECUTE FUNCTION user_audit_log_immutable()`).Error
+		},
+	},
+	{
+		// Multi-tenancy: los usuarios existentes quedan en la organización
+		// "default" (valor por defecto de tenant_id), la unicidad de username y
+		// email pasa a ser por organización y tenant_id referencia a tenants.
+		ID: "0005_tenants",
+		Up: func(tx *gorm.DB) error {
+			statements := []string{
+				`INSERT INTO tenants (id, name, created_at, updated_at, created_by, updated_by)
+					VALUES ('default', 'Default', now(), now(), 'system', 'system') ON CONFLICT (id) DO NOTHING`,
+				`DROP INDEX IF EXISTS idx_username_normalized`,
+				`DROP INDEX IF EXISTS idx_email_normalized`,
+				`CREATE UNIQUE INDEX idx_username_normalized
+					ON user_entities (tenant_id, username_normalized) WHERE username_normalized <> ''`,
+				`CREATE UNIQUE INDEX idx_email_normalized
+					ON user_entities (tenant_id, email_normalized) WHERE email_normalized <> ''`,
+				`ALTER TABLE user_entities ADD CONSTRAINT fk_user_entities_tenant
+					FOREIGN KEY (tenant_id) REFERENCES tenants (id)`,
+			}
+			for _, statement := range statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		// Política de row-level security de user_entities: solo son visibles
+		// (y escribibles) las filas de la organización fijada en app.tenant_id,
+		// o todas con "*". Solo tiene efecto si la row-level security está
+		// habilitada (ver ConfigureRowLevelSecurity).
+		ID: "0006_tenant_row_level_security",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec(`CREATE POLICY user_entities_tenant_isolation ON user_entities
+				USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'))
+				WITH CHECK (current_setting('app.tenant_id', true) IN (tenant_id, '*'))`).Error
+		},
+	},
+	{
+		// Grupos: pertenecen a una organización (y se eliminan con ella) y sus
+		// membresías referencian (id, tenant_id), de modo que un grupo solo
+		// contiene usuarios y grupos de su organización. Las membresías se
+		// eliminan con el grupo, el subgrupo o el usuario (al purgarlo).
+		ID: "0007_groups",
+		Up: func(tx *gorm.DB) error {
+			statements := []string{
+				`CREATE UNIQUE INDEX idx_user_entities_id_tenant ON user_entities (id, tenant_id)`,
+				`CREATE UNIQUE INDEX idx_user_groups_id_tenant ON user_groups (id, tenant_id)`,
+				`ALTER TABLE user_groups ADD CONSTRAINT fk_user_groups_tenant
+					FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE`,
+				`ALTER TABLE user_group_members ADD CONSTRAINT fk_user_group_members_group
+					FOREIGN KEY (group_id, tenant_id) REFERENCES user_groups (id, tenant_id) ON DELETE CASCADE`,
+				`ALTER TABLE user_group_members ADD CONSTRAINT fk_user_group_members_user
+					FOREIGN KEY (user_id, tenant_id) REFERENCES user_entities (id, tenant_id) ON DELETE CASCADE`,
+				`ALTER TABLE user_group_nesting ADD CONSTRAINT fk_user_group_nesting_parent
+					FOREIGN KEY (parent_id, tenant_id) REFERENCES user_groups (id, tenant_id) ON DELETE CASCADE`,
+				`ALTER TABLE user_group_nesting ADD CONSTRAINT fk_user_group_nesting_child
+					FOREIGN KEY (child_id, tenant_id) REFERENCES user_groups (id, tenant_id) ON DELETE CASCADE`,
+				`ALTER TABLE user_group_nesting ADD CONSTRAINT chk_user_group_nesting_self
+					CHECK (parent_id <> child_id)`,
+			}
+			for _, statement := range statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		// Estado de la cuenta: solo se admiten los estados del ciclo de vida
+		// (ver domain.UserStatus). Las filas existentes quedan activas por el
+		// valor por defecto de la columna.
+		ID: "0008_user_status",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec(`ALTER TABLE user_entities ADD CONSTRAINT chk_user_entities_status
+				CHECK (status IN ('pending', 'active', 'suspended', 'locked', 'deactivated'))`).Error
+		},
+	},
+	{
+		// Los tokens de verificación de email pertenecen a un usuario de la
+		// organización y se eliminan con él (al purgarlo).
+		ID: "0009_email_verification_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec(`ALTER TABLE email_verification_tokens ADD CONSTRAINT fk_email_verification_tokens_user
+				FOREIGN KEY (user_id, tenant_id) REFERENCES user_entities (id, tenant_id) ON DELETE CASCADE`).Error
+		},
+	},
+	{
+		// El registro de eventos se consulta por organización: los eventos
+		// existentes toman la organización de su payload (o "default", el valor
+		// por defecto de la columna, si se registraron antes de multi-tenancy).
+		ID: "0010_outbox_tenant",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec(`UPDATE user_outbox SET tenant_id = payload->>'tenant_id'
+				WHERE coalesce(payload->>'tenant_id', '') NOT IN ('', tenant_id)`).Error
+		},
+	},
+	{
+		// El relay asigna published_sequence al publicar cada evento. Los eventos
+		// ya publicados conservan su sequence como posición, para que los
+		// clientes que reanudan con un Last-Event-ID previo no pierdan eventos,
+		// y el contador continúa después del mayor.
+		ID: "0011_outbox_published_sequence",
+		Up: func(tx *gorm.DB) error {
+			statements := []string{
+				`CREATE SEQUENCE IF NOT EXISTS user_outbox_published_sequence`,
+				`UPDATE user_outbox SET published_sequence = sequence
+					WHERE published_at IS NOT NULL AND published_sequence IS NULL`,
+				`SELECT setval('user_outbox_published_sequence', coalesce(max(sequence), 0) + 1, false) FROM user_outbox`,
+			}
+			for _, statement := range statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+}
+
+// normalizedIdentityBackfill retorna la sentencia que completa
+// <column>_normalized en las filas que aún no la tienen, con la forma
+// canónica aproximada normalize(lower(normalize(btrim(valor), NFKC)), NFKC).
+// Se omiten las filas cuya forma canónica comparte otra fila (incluidas las
+// eliminadas, que retienen su identidad), para que los índices únicos puedan
+// crearse.
+func normalizedIdentityBackfill(column string) string {
+	canonical := "normalize(lower(normalize(btrim(" + column + "), NFKC)), NFKC)"
+
+	return `UPDATE user_entities AS u SET ` + column + `_normalized = c.value
+		FROM (SELECT id, ` + canonical + ` AS value,
+				count(*) OVER (PARTITION BY ` + canonical + `) AS holders
+			FROM user_entities) AS c
+		WHERE u.id = c.id AND c.holders = 1 AND c.value <> '' AND u.` + column + `_normalized = ''`
+}
+
+// Migrate sincroniza el esquema con las entidades (AutoMigrate) y aplica,
+// en orden y dentro de una transacción cada una, las migraciones pendientes.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&entity.UserEntity{}, &entity.AuditEntity{}, &entity.OutboxEntity{},
+		&entity.WebhookEntity{}, &entity.WebhookDeliveryEntity{}, &entity.IdempotencyEntity{},
+		&entity.UserImportEntity{}, &entity.UserImportErrorEntity{}, &entity.APIKeyEntity{},
+		&entity.TenantEntity{}, &entity.GroupEntity{}, &entity.GroupUserMemberEntity{},
+		&entity.GroupNestingEntity{}, &entity.EmailVerificationEntity{}, &schemaMigration{}); err != nil {
+		return fmt.Errorf("auto migrate: %w", err)
+	}
+
+	for _, m := range migrations {
+		var applied int64
+		if err := db.Model(&schemaMigration{}).Where("id = ?", m.ID).Count(&applied).Error; err != nil {
+			return fmt.Errorf("migration %s: %w", m.ID, err)
+		}
+		if applied > 0 {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{ID: m.ID, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s: %w", m.ID, err)
+		}
+
+		log.Printf("[Migration] applied %s", m.ID)
+	}
+
+	return nil
+}
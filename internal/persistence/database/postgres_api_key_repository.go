@@ -0,0 +1,128 @@
+package database
+
+import (
+	"errors"
+	"time"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/entity"
+
+	"gorm.io/gorm"
+)
+
+// PostgresAPIKeyRepository implementa domain.APIKeyRepository sobre PostgreSQL.
+type PostgresAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresAPIKeyRepository crea una nueva instancia del repositorio de claves de API.
+func NewPostgresAPIKeyRepository(db *gorm.DB) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{db: db}
+}
+
+// Asegura que PostgresAPIKeyRepository implemente domain.APIKeyRepository.
+var _ domain.APIKeyRepository = (*PostgresAPIKeyRepository)(nil)
+
+// Create inserta una nueva clave de API.
+func (p *PostgresAPIKeyRepository) Create(key *domain.APIKey) error {
+	keyEntity := entity.ToAPIKeyEntity(key)
+
+	if err := p.db.Create(&keyEntity).Error; err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return nil
+}
+
+// FindById recupera la clave con el ID indicado.
+func (p *PostgresAPIKeyRepository) FindById(id string) (*domain.APIKey, error) {
+	var keyEntity entity.APIKeyEntity
+
+	err := p.db.Where("id = ?", id).First(&keyEntity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	key := entity.FromAPIKeyEntity(&keyEntity)
+	return &key, nil
+}
+
+// FindByHash recupera la clave con el hash indicado.
+func (p *PostgresAPIKeyRepository) FindByHash(hash string) (*domain.APIKey, error) {
+	var keyEntity entity.APIKeyEntity
+
+	err := p.db.Where("hash = ?", hash).First(&keyEntity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	key := entity.FromAPIKeyEntity(&keyEntity)
+	return &key, nil
+}
+
+// FindAll recupera las claves ordenadas por fecha de creación.
+func (p *PostgresAPIKeyRepository) FindAll(includeRevoked bool) ([]domain.APIKey, error) {
+	var keyEntities []entity.APIKeyEntity
+
+	query := p.db.Order("created_at")
+	if !includeRevoked {
+		query = query.Where("revoked_at IS NULL")
+	}
+	if err := query.Find(&keyEntities).Error; err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	keys := make([]domain.APIKey, len(keyEntities))
+	for i := range keyEntities {
+		keys[i] = entity.FromAPIKeyEntity(&keyEntities[i])
+	}
+
+	return keys, nil
+}
+
+// Rotate inserta la nueva clave y adelanta, en la misma transacción, el
+// vencimiento de las claves vigentes con su nombre, organización y usuario.
+func (p *PostgresAPIKeyRepository) Rotate(key *domain.APIKey, expiresAt time.Time) (int64, error) {
+	keyEntity := entity.ToAPIKeyEntity(key)
+
+	var expired int64
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&keyEntity).Error; err != nil {
+			return domain.ErrInternalServer{Value: err.Error()}
+		}
+
+		result := tx.Model(&entity.APIKeyEntity{}).
+			Where("name = ? AND tenant_id = ? AND user_id = ? AND id <> ?", key.Name, key.TenantID, key.UserID, key.ID).
+			Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", expiresAt).
+			Update("expires_at", expiresAt)
+		if result.Error != nil {
+			return domain.ErrInternalServer{Value: result.Error.Error()}
+		}
+
+		expired = result.RowsAffected
+		return nil
+	})
+
+	return expired, err
+}
+
+// Revoke marca la clave como revocada.
+func (p *PostgresAPIKeyRepository) Revoke(id string, revokedAt time.Time) error {
+	result := p.db.Model(&entity.APIKeyEntity{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+
+	if result.Error != nil {
+		return domain.ErrInternalServer{Value: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
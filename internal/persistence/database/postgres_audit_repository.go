@@ -0,0 +1,73 @@
+package database
+
+import (
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/entity"
+
+	"gorm.io/gorm"
+)
+
+// PostgresAuditRepository implementa domain.AuditRepository sobre PostgreSQL.
+// Dentro de Execute comparte la transacción del PostgresRepository.
+type PostgresAuditRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresAuditRepository crea una nueva instancia del repositorio de auditoría.
+func NewPostgresAuditRepository(db *gorm.DB) *PostgresAuditRepository {
+	return &PostgresAuditRepository{db: db}
+}
+
+// Asegura que PostgresAuditRepository implemente domain.AuditRepository.
+var _ domain.AuditRepository = (*PostgresAuditRepository)(nil)
+
+// Append inserta un registro de auditoría.
+func (p *PostgresAuditRepository) Append(record *domain.AuditRecord) error {
+	auditEntity, err := entity.ToAuditEntity(record)
+	if err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	if err := p.db.Create(&auditEntity).Error; err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return nil
+}
+
+// Find retorna los registros que cumplen el filtro, del más reciente al más antiguo.
+func (p *PostgresAuditRepository) Find(filter domain.AuditFilter) ([]domain.AuditRecord, error) {
+	var auditEntities []entity.AuditEntity
+
+	query := p.db.Order("timestamp DESC, id DESC")
+	if filter.TenantID != "" {
+		query = query.Where("tenant_id = ?", filter.TenantID)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Since != nil {
+		query = query.Where("timestamp >= ?", *filter.Since)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	if err := query.Find(&auditEntities).Error; err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	records := make([]domain.AuditRecord, len(auditEntities))
+	for i := range auditEntities {
+		record, err := entity.FromAuditEntity(&auditEntities[i])
+		if err != nil {
+			return nil, domain.ErrInternalServer{Value: err.Error()}
+		}
+		records[i] = record
+	}
+
+	return records, nil
+}
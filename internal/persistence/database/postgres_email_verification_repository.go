@@ -0,0 +1,92 @@
+package database
+
+import (
+	"errors"
+	"time"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/entity"
+
+	"gorm.io/gorm"
+)
+
+// PostgresEmailVerificationRepository implementa domain.EmailVerificationRepository sobre PostgreSQL.
+type PostgresEmailVerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresEmailVerificationRepository crea una nueva instancia del
+// repositorio de tokens de verificación de email.
+func NewPostgresEmailVerificationRepository(db *gorm.DB) *PostgresEmailVerificationRepository {
+	return &PostgresEmailVerificationRepository{db: db}
+}
+
+// Asegura que PostgresEmailVerificationRepository implemente domain.EmailVerificationRepository.
+var _ domain.EmailVerificationRepository = (*PostgresEmailVerificationRepository)(nil)
+
+// Create inserta un nuevo token.
+func (p *PostgresEmailVerificationRepository) Create(token *domain.EmailVerificationToken) error {
+	tokenEntity := entity.ToEmailVerificationEntity(token)
+
+	if err := p.db.Create(&tokenEntity).Error; err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return nil
+}
+
+// FindByHash recupera el token con el hash indicado.
+func (p *PostgresEmailVerificationRepository) FindByHash(hash string) (*domain.EmailVerificationToken, error) {
+	var tokenEntity entity.EmailVerificationEntity
+
+	err := p.db.Where("hash = ?", hash).First(&tokenEntity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrInvalidVerificationToken
+		}
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	token := entity.FromEmailVerificationEntity(&tokenEntity)
+	return &token, nil
+}
+
+// ExistsForEvent indica si hay un token emitido por el evento indicado.
+func (p *PostgresEmailVerificationRepository) ExistsForEvent(eventID string) (bool, error) {
+	var count int64
+
+	err := p.db.Model(&entity.EmailVerificationEntity{}).Where("event_id = ?", eventID).Count(&count).Error
+	if err != nil {
+		return false, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return count > 0, nil
+}
+
+// Consume marca el token como usado si sigue vigente.
+func (p *PostgresEmailVerificationRepository) Consume(id string, usedAt time.Time) error {
+	result := p.db.Model(&entity.EmailVerificationEntity{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, usedAt).
+		Update("used_at", usedAt)
+
+	if result.Error != nil {
+		return domain.ErrInternalServer{Value: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidVerificationToken
+	}
+
+	return nil
+}
+
+// ExpirePending adelanta el vencimiento de los tokens vigentes del usuario.
+func (p *PostgresEmailVerificationRepository) ExpirePending(userID string, at time.Time) error {
+	err := p.db.Model(&entity.EmailVerificationEntity{}).
+		Where("user_id = ? AND used_at IS NULL AND expires_at > ?", userID, at).
+		Update("expires_at", at).Error
+
+	if err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return nil
+}
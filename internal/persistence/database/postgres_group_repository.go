@@ -0,0 +1,354 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// groupDescendantsQuery retorna los IDs de los grupos contenidos, directa o
+// indirectamente, en @group. UNION (y no UNION ALL) garantiza que la
+// recursión termine aunque existiera un ciclo.
+const groupDescendantsQuery = `
+WITH RECURSIVE descendants(id) AS (
+	SELECT child_id FROM user_group_nesting WHERE parent_id = @group
+	UNION
+	SELECT n.child_id FROM user_group_nesting n JOIN descendants d ON n.parent_id = d.id
+)
+SELECT id FROM descendants`
+
+// groupAncestorsQuery retorna los IDs de los grupos que contienen, directa o
+// indirectamente, a alguno de @groups.
+const groupAncestorsQuery = `
+WITH RECURSIVE ancestors(id) AS (
+	SELECT parent_id FROM user_group_nesting WHERE child_id IN @groups
+	UNION
+	SELECT n.parent_id FROM user_group_nesting n JOIN ancestors a ON n.child_id = a.id
+)
+SELECT id FROM ancestors`
+
+// PostgresGroupRepository implementa domain.GroupRepository sobre PostgreSQL.
+type PostgresGroupRepository struct {
+	db *gorm.DB
+	// tenantID restringe las operaciones a una organización; vacío = todas.
+	tenantID string
+}
+
+// NewPostgresGroupRepository crea una nueva instancia del repositorio de grupos.
+func NewPostgresGroupRepository(db *gorm.DB) *PostgresGroupRepository {
+	return &PostgresGroupRepository{db: db}
+}
+
+// Asegura que PostgresGroupRepository implemente domain.GroupRepository.
+var _ domain.GroupRepository = (*PostgresGroupRepository)(nil)
+
+// ForTenant retorna una copia del repositorio restringida a la organización.
+func (p *PostgresGroupRepository) ForTenant(tenantID string) domain.GroupRepository {
+	scoped := *p
+	scoped.tenantID = tenantID
+	return &scoped
+}
+
+// scoped restringe la consulta a la organización del repositorio, si la hay.
+func (p *PostgresGroupRepository) scoped(db *gorm.DB) *gorm.DB {
+	if p.tenantID == "" {
+		return db
+	}
+	return db.Where("tenant_id = ?", p.tenantID)
+}
+
+// Create inserta un nuevo grupo en la organización del repositorio.
+func (p *PostgresGroupRepository) Create(group *domain.Group) error {
+	if p.tenantID != "" {
+		group.TenantID = p.tenantID
+	}
+	if group.TenantID == "" {
+		group.TenantID = domain.DefaultTenantID
+	}
+	groupEntity := entity.ToGroupEntity(group)
+
+	if err := p.db.Create(&groupEntity).Error; err != nil {
+		return mapGroupWriteError(err)
+	}
+
+	return nil
+}
+
+// FindAll recupera los grupos ordenados por nombre.
+func (p *PostgresGroupRepository) FindAll() ([]domain.Group, error) {
+	var groupEntities []entity.GroupEntity
+
+	if err := p.scoped(p.db).Order("name").Find(&groupEntities).Error; err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return fromGroupEntities(groupEntities), nil
+}
+
+// FindByIds recupera los grupos existentes de entre los IDs indicados, ordenados por nombre.
+func (p *PostgresGroupRepository) FindByIds(ids []string) ([]domain.Group, error) {
+	if len(ids) == 0 {
+		return []domain.Group{}, nil
+	}
+
+	var groupEntities []entity.GroupEntity
+
+	if err := p.scoped(p.db).Where("id IN ?", ids).Order("name").Find(&groupEntities).Error; err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return fromGroupEntities(groupEntities), nil
+}
+
+// FindById recupera un grupo por su ID.
+func (p *PostgresGroupRepository) FindById(id string) (*domain.Group, error) {
+	return p.findById(p.db, id)
+}
+
+// findById es FindById sobre la conexión (o transacción) indicada.
+func (p *PostgresGroupRepository) findById(db *gorm.DB, id string) (*domain.Group, error) {
+	var groupEntity entity.GroupEntity
+
+	err := p.scoped(db).Where("id = ?", id).First(&groupEntity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrGroupNotFound
+		}
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	group := entity.FromGroupEntity(&groupEntity)
+	return &group, nil
+}
+
+// Update persiste nombre, descripción y marcas de modificación de un grupo.
+func (p *PostgresGroupRepository) Update(group *domain.Group) error {
+	result := p.scoped(p.db.Model(&entity.GroupEntity{})).
+		Where("id = ?", group.ID).
+		Updates(map[string]any{
+			"name":        group.Name,
+			"description": group.Description,
+			"updated_at":  group.UpdatedAt,
+			"updated_by":  group.UpdatedBy,
+		})
+
+	if result.Error != nil {
+		return mapGroupWriteError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrGroupNotFound
+	}
+
+	return nil
+}
+
+// Delete elimina un grupo; las claves foráneas eliminan sus membresías.
+func (p *PostgresGroupRepository) Delete(id string) error {
+	result := p.scoped(p.db).Where("id = ?", id).Delete(&entity.GroupEntity{})
+
+	if result.Error != nil {
+		return domain.ErrInternalServer{Value: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrGroupNotFound
+	}
+
+	return nil
+}
+
+// Members recupera los miembros directos del grupo: primero los grupos
+// (ordenados por nombre) y luego los usuarios (ordenados por ID).
+func (p *PostgresGroupRepository) Members(groupID string) ([]domain.GroupMember, error) {
+	if _, err := p.FindById(groupID); err != nil {
+		return nil, err
+	}
+
+	var groupEntities []entity.GroupEntity
+	err := p.db.Joins("JOIN user_group_nesting n ON n.child_id = user_groups.id").
+		Where("n.parent_id = ?", groupID).Order("user_groups.name").Find(&groupEntities).Error
+	if err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	var userIDs []string
+	err = p.db.Model(&entity.GroupUserMemberEntity{}).Where("group_id = ?", groupID).Order("user_id").Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	members := make([]domain.GroupMember, 0, len(groupEntities)+len(userIDs))
+	for _, groupEntity := range groupEntities {
+		members = append(members, domain.GroupMember{Type: domain.MemberGroup, ID: groupEntity.ID, Display: groupEntity.Name})
+	}
+	for _, userID := range userIDs {
+		members = append(members, domain.GroupMember{Type: domain.MemberUser, ID: userID})
+	}
+
+	return members, nil
+}
+
+// MemberUserIDs recupera los IDs de los usuarios miembros del grupo,
+// ordenados y sin repetir.
+func (p *PostgresGroupRepository) MemberUserIDs(groupID string, transitive bool) ([]string, error) {
+	if _, err := p.FindById(groupID); err != nil {
+		return nil, err
+	}
+
+	groupIDs := []string{groupID}
+	if transitive {
+		var descendants []string
+		if err := p.db.Raw(groupDescendantsQuery, map[string]any{"group": groupID}).Scan(&descendants).Error; err != nil {
+			return nil, domain.ErrInternalServer{Value: err.Error()}
+		}
+		groupIDs = append(groupIDs, descendants...)
+	}
+
+	var userIDs []string
+	err := p.db.Model(&entity.GroupUserMemberEntity{}).Distinct("user_id").
+		Where("group_id IN ?", groupIDs).Order("user_id").Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return userIDs, nil
+}
+
+// GroupsOf recupera los grupos del miembro, ordenados por nombre.
+func (p *PostgresGroupRepository) GroupsOf(member domain.MemberRef, transitive bool) ([]domain.Group, error) {
+	// 1. Grupos de los que es miembro directo
+	var groupIDs []string
+	var err error
+	switch member.Type {
+	case domain.MemberUser:
+		err = p.scoped(p.db.Model(&entity.GroupUserMemberEntity{})).Where("user_id = ?", member.ID).Pluck("group_id", &groupIDs).Error
+	case domain.MemberGroup:
+		err = p.scoped(p.db.Model(&entity.GroupNestingEntity{})).Where("child_id = ?", member.ID).Pluck("parent_id", &groupIDs).Error
+	default:
+		return nil, fmt.Errorf("%w: unknown member type %q", domain.ErrMemberNotFound, member.Type)
+	}
+	if err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	// 2. Grupos que los contienen, a cualquier profundidad
+	if transitive && len(groupIDs) > 0 {
+		var ancestors []string
+		if err := p.db.Raw(groupAncestorsQuery, map[string]any{"groups": groupIDs}).Scan(&ancestors).Error; err != nil {
+			return nil, domain.ErrInternalServer{Value: err.Error()}
+		}
+		groupIDs = append(groupIDs, ancestors...)
+	}
+
+	return p.FindByIds(groupIDs)
+}
+
+// ChangeMembers aplica el cambio de membresía en una transacción. Un lock
+// transaccional por organización serializa los cambios de anidamiento, de
+// modo que dos transacciones concurrentes no puedan crear un ciclo entre ambas.
+func (p *PostgresGroupRepository) ChangeMembers(groupID string, change domain.GroupMembershipChange, actor string) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		// 1. El grupo debe existir en la organización
+		group, err := p.findById(tx, groupID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "user_group_nesting:"+group.TenantID).Error; err != nil {
+			return domain.ErrInternalServer{Value: err.Error()}
+		}
+
+		// 2. Bajas
+		for _, member := range change.Remove {
+			var result *gorm.DB
+			switch member.Type {
+			case domain.MemberUser:
+				result = tx.Where("group_id = ? AND user_id = ?", groupID, member.ID).Delete(&entity.GroupUserMemberEntity{})
+			case domain.MemberGroup:
+				result = tx.Where("parent_id = ? AND child_id = ?", groupID, member.ID).Delete(&entity.GroupNestingEntity{})
+			}
+			if result != nil && result.Error != nil {
+				return domain.ErrInternalServer{Value: result.Error.Error()}
+			}
+		}
+
+		// 3. Altas (las existentes se ignoran)
+		now := time.Now().UTC()
+		for _, member := range change.Add {
+			switch member.Type {
+			case domain.MemberUser:
+				membership := entity.GroupUserMemberEntity{GroupID: groupID, UserID: member.ID, TenantID: group.TenantID, CreatedAt: now, CreatedBy: actor}
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&membership).Error; err != nil {
+					return mapMembershipError(err, member)
+				}
+			case domain.MemberGroup:
+				if err := p.checkCycle(tx, groupID, member.ID); err != nil {
+					return err
+				}
+				nesting := entity.GroupNestingEntity{ParentID: groupID, ChildID: member.ID, TenantID: group.TenantID, CreatedAt: now, CreatedBy: actor}
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&nesting).Error; err != nil {
+					return mapMembershipError(err, member)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// checkCycle retorna ErrGroupCycle si agregar childID como miembro de
+// parentID crearía un ciclo, es decir, si parentID es childID o está
+// contenido en él. Considera las altas previas de la misma transacción.
+func (p *PostgresGroupRepository) checkCycle(tx *gorm.DB, parentID, childID string) error {
+	if parentID == childID {
+		return fmt.Errorf("%w: group %s cannot contain itself", domain.ErrGroupCycle, parentID)
+	}
+
+	var descendants []string
+	if err := tx.Raw(groupDescendantsQuery, map[string]any{"group": childID}).Scan(&descendants).Error; err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+	for _, descendant := range descendants {
+		if descendant == parentID {
+			return fmt.Errorf("%w: group %s already contains group %s", domain.ErrGroupCycle, childID, parentID)
+		}
+	}
+
+	return nil
+}
+
+// mapGroupWriteError traduce los errores de PostgreSQL al crear o modificar
+// un grupo: nombre en uso (23505) y organización inexistente (23503).
+func mapGroupWriteError(err error) error {
+	if pgErr := extractPgError(err); pgErr != nil {
+		switch pgErr.Code {
+		case "23505":
+			return domain.ErrGroupNameInUse
+		case "23503":
+			return domain.ErrTenantNotFound
+		}
+	}
+	return domain.ErrInternalServer{Value: err.Error()}
+}
+
+// mapMembershipError traduce la violación de clave foránea (23503) de una
+// alta, que indica que el miembro no existe en la organización del grupo.
+func mapMembershipError(err error, member domain.MemberRef) error {
+	if pgErr := extractPgError(err); pgErr != nil && pgErr.Code == "23503" {
+		return fmt.Errorf("%w: %s %s", domain.ErrMemberNotFound, member.Type, member.ID)
+	}
+	return domain.ErrInternalServer{Value: err.Error()}
+}
+
+// fromGroupEntities convierte las entidades de grupo a su modelo de dominio.
+func fromGroupEntities(groupEntities []entity.GroupEntity) []domain.Group {
+	groups := make([]domain.Group, len(groupEntities))
+	for i := range groupEntities {
+		groups[i] = entity.FromGroupEntity(&groupEntities[i])
+	}
+	return groups
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"errors"
+	"time"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/entity"
+
+	"gorm.io/gorm"
+)
+
+// PostgresIdempotencyRepository implementa domain.IdempotencyRepository sobre PostgreSQL.
+type PostgresIdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresIdempotencyRepository crea una nueva instancia del repositorio de claves de idempotencia.
+func NewPostgresIdempotencyRepository(db *gorm.DB) *PostgresIdempotencyRepository {
+	return &PostgresIdempotencyRepository{db: db}
+}
+
+// Asegura que PostgresIdempotencyRepository implemente domain.IdempotencyRepository.
+var _ domain.IdempotencyRepository = (*PostgresIdempotencyRepository)(nil)
+
+// Create reserva la clave. Un registro expirado con la misma clave se
+// reemplaza dentro de la misma transacción.
+func (p *PostgresIdempotencyRepository) Create(record *domain.IdempotencyRecord) error {
+	idempotencyEntity := entity.ToIdempotencyEntity(record)
+
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("scope = ? AND key = ? AND expires_at <= ?", record.Scope, record.Key, time.Now()).
+			Delete(&entity.IdempotencyEntity{}).Error
+		if err != nil {
+			return domain.ErrInternalServer{Value: err.Error()}
+		}
+
+		if err := tx.Create(&idempotencyEntity).Error; err != nil {
+			if pgErr := extractPgError(err); pgErr != nil && pgErr.Code == "23505" {
+				return domain.ErrIdempotencyKeyExists
+			}
+			return domain.ErrInternalServer{Value: err.Error()}
+		}
+
+		return nil
+	})
+}
+
+// Find recupera el registro vigente de la clave.
+func (p *PostgresIdempotencyRepository) Find(scope, key string) (*domain.IdempotencyRecord, error) {
+	var idempotencyEntity entity.IdempotencyEntity
+
+	err := p.db.Where("scope = ? AND key = ? AND expires_at > ?", scope, key, time.Now()).First(&idempotencyEntity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrIdempotencyKeyNotFound
+		}
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	record := entity.FromIdempotencyEntity(&idempotencyEntity)
+
+	return &record, nil
+}
+
+// Complete registra la respuesta de la petición original.
+func (p *PostgresIdempotencyRepository) Complete(scope, key string, statusCode int, contentType string, body []byte) error {
+	result := p.db.Model(&entity.IdempotencyEntity{}).
+		Where("scope = ? AND key = ?", scope, key).
+		Updates(map[string]any{"status_code": statusCode, "content_type": contentType, "body": body})
+	if result.Error != nil {
+		return domain.ErrInternalServer{Value: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+// Delete libera la clave.
+func (p *PostgresIdempotencyRepository) Delete(scope, key string) error {
+	err := p.db.Where("scope = ? AND key = ?", scope, key).Delete(&entity.IdempotencyEntity{}).Error
+	if err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return nil
+}
+
+// PurgeExpired elimina los registros con expires_at anterior a before.
+func (p *PostgresIdempotencyRepository) PurgeExpired(before time.Time) (int64, error) {
+	result := p.db.Where("expires_at < ?", before).Delete(&entity.IdempotencyEntity{})
+	if result.Error != nil {
+		return 0, domain.ErrInternalServer{Value: result.Error.Error()}
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"time"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/entity"
+
+	"gorm.io/gorm"
+)
+
+// PostgresOutboxRepository implementa domain.OutboxRepository sobre PostgreSQL.
+// Dentro de Execute comparte la transacción del PostgresRepository.
+type PostgresOutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresOutboxRepository crea una nueva instancia del repositorio del outbox.
+func NewPostgresOutboxRepository(db *gorm.DB) *PostgresOutboxRepository {
+	return &PostgresOutboxRepository{db: db}
+}
+
+// Asegura que PostgresOutboxRepository implemente domain.OutboxRepository.
+var _ domain.OutboxRepository = (*PostgresOutboxRepository)(nil)
+
+// Append inserta el evento y le asigna la secuencia generada por la base de datos.
+func (p *PostgresOutboxRepository) Append(event *domain.UserEvent) error {
+	outboxEntity, err := entity.ToOutboxEntity(event)
+	if err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	if err := p.db.Create(&outboxEntity).Error; err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	event.Sequence = outboxEntity.Sequence
+
+	return nil
+}
+
+// FetchPending retorna los eventos no publicados: primero el que ya tiene
+// posición de publicación (un reintento) y luego los demás en orden de secuencia.
+func (p *PostgresOutboxRepository) FetchPending(limit int) ([]domain.PendingEvent, error) {
+	var outboxEntities []entity.OutboxEntity
+
+	err := p.db.Where("published_at IS NULL").Order("published_sequence NULLS LAST, sequence").Limit(limit).Find(&outboxEntities).Error
+	if err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	pending := make([]domain.PendingEvent, len(outboxEntities))
+	for i := range outboxEntities {
+		event, err := entity.FromOutboxEntity(&outboxEntities[i])
+		if err != nil {
+			return nil, domain.ErrInternalServer{Value: err.Error()}
+		}
+		pending[i] = domain.PendingEvent{
+			Event:         event,
+			Attempts:      outboxEntities[i].Attempts,
+			NextAttemptAt: outboxEntities[i].NextAttemptAt,
+		}
+	}
+
+	return pending, nil
+}
+
+// AssignPublishedSequence toma la siguiente posición de
+// user_outbox_published_sequence si el evento aún no tiene una.
+func (p *PostgresOutboxRepository) AssignPublishedSequence(sequence int64) (int64, error) {
+	var published int64
+	err := p.db.Raw(`UPDATE user_outbox
+		SET published_sequence = coalesce(published_sequence, nextval('user_outbox_published_sequence'))
+		WHERE sequence = ? RETURNING published_sequence`, sequence).Scan(&published).Error
+	if err != nil {
+		return 0, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return published, nil
+}
+
+// MarkPublished registra la publicación exitosa del evento.
+func (p *PostgresOutboxRepository) MarkPublished(sequence int64) error {
+	err := p.db.Model(&entity.OutboxEntity{}).
+		Where("sequence = ?", sequence).
+		Updates(map[string]any{"published_at": time.Now().UTC(), "attempts": gorm.Expr("attempts + 1"), "last_error": ""}).Error
+	if err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return nil
+}
+
+// MarkFailed incrementa los intentos del evento y programa el siguiente.
+func (p *PostgresOutboxRepository) MarkFailed(sequence int64, cause string, nextAttemptAt time.Time) error {
+	err := p.db.Model(&entity.OutboxEntity{}).
+		Where("sequence = ?", sequence).
+		Updates(map[string]any{"attempts": gorm.Expr("attempts + 1"), "last_error": cause, "next_attempt_at": nextAttemptAt}).Error
+	if err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return nil
+}
+
+// FindPublished retorna los eventos publicados de una organización
+// posteriores a afterPublished, en orden de published_sequence.
+func (p *PostgresOutboxRepository) FindPublished(tenantID string, afterPublished int64, types []domain.EventType, limit int) ([]domain.UserEvent, error) {
+	var outboxEntities []entity.OutboxEntity
+
+	query := p.db.Where("tenant_id = ? AND published_at IS NOT NULL AND published_sequence > ?", tenantID, afterPublished)
+	if len(types) > 0 {
+		names := make([]string, len(types))
+		for i, eventType := range types {
+			names[i] = string(eventType)
+		}
+		query = query.Where("type IN ?", names)
+	}
+
+	if err := query.Order("published_sequence").Limit(limit).Find(&outboxEntities).Error; err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	events := make([]domain.UserEvent, len(outboxEntities))
+	for i := range outboxEntities {
+		event, err := entity.FromOutboxEntity(&outboxEntities[i])
+		if err != nil {
+			return nil, domain.ErrInternalServer{Value: err.Error()}
+		}
+		events[i] = event
+	}
+
+	return events, nil
+}
+
+// PurgePublished elimina los eventos publicados antes de publishedBefore.
+func (p *PostgresOutboxRepository) PurgePublished(publishedBefore time.Time) (int64, error) {
+	result := p.db.Where("published_at IS NOT NULL AND published_at < ?", publishedBefore).Delete(&entity.OutboxEntity{})
+	if result.Error != nil {
+		return 0, domain.ErrInternalServer{Value: result.Error.Error()}
+	}
+
+	return result.RowsAffected, nil
+}
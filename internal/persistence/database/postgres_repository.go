@@ -3,12 +3,15 @@ package database
 import (
 	"errors"
 	"log"
+	"time"
 	"user-api-restful/internal/domain"
 	"user-api-restful/internal/persistence/entity"
 
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/lib/pq"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Package database contiene las implementaciones de los contratos de repositorio (UserRepository)
@@ -16,8 +19,19 @@ import (
 //
 // PostgresRepository implementa las interfaces domain.UserRepository y
 // domain.UserTransactionPort para la persistencia de usuarios en PostgreSQL.
+//
+// Un PostgresRepository puede restringirse a una organización (ver
+// ForTenant); con row-level security (ver WithRowLevelSecurity), además,
+// PostgreSQL solo expone las filas de esa organización.
 type PostgresRepository struct {
 	db *gorm.DB
+	// tenantID restringe las operaciones a una organización; vacío = todas.
+	tenantID string
+	// rls indica que user_entities tiene row-level security habilitada (ver
+	// ConfigureRowLevelSecurity) y que cada operación debe fijar app.tenant_id.
+	rls bool
+	// inTx indica que db es una transacción en curso (ver Execute).
+	inTx bool
 }
 
 // PgErrorData es una estructura auxiliar para manejar y tipificar errores de PostgreSQL.
@@ -63,39 +77,123 @@ func extractPgError(err error) *PgErrorData {
 	return nil
 }
 
+// Connect abre la conexión a PostgreSQL a partir de un DSN.
+func Connect(dsn string) (*gorm.DB, error) {
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{})
+}
+
 // NewPostgresRepository crea una nueva instancia del repositorio, inyectando la conexión a GORM.
 func NewPostgresRepository(db *gorm.DB) *PostgresRepository {
 	return &PostgresRepository{db: db}
 }
 
-// Create inserta un nuevo usuario. Mapea errores de unicidad (23505) y
-// not-null (23502) de PostgreSQL a los errores de dominio.
+// WithRowLevelSecurity retorna una copia del repositorio que, si enabled,
+// fija app.tenant_id en la transacción de cada operación, como requieren las
+// políticas de row-level security de user_entities (ver ConfigureRowLevelSecurity).
+func (p *PostgresRepository) WithRowLevelSecurity(enabled bool) *PostgresRepository {
+	scoped := *p
+	scoped.rls = enabled
+	return &scoped
+}
+
+// ForTenant implementa domain.UserRepository retornando una copia del
+// repositorio (y de su transacción, si la hay) restringida a la organización.
+func (p *PostgresRepository) ForTenant(tenantID string) domain.UserRepository {
+	return p.forTenant(tenantID)
+}
+
+// forTenant es ForTenant con el tipo concreto.
+func (p *PostgresRepository) forTenant(tenantID string) *PostgresRepository {
+	scoped := *p
+	scoped.tenantID = tenantID
+	return &scoped
+}
+
+// run ejecuta fn con la conexión de la operación. Con row-level security,
+// fn corre dentro de una transacción (la en curso, si la hay) que fija
+// app.tenant_id a la organización del repositorio, o a "*" (todas) si no
+// está restringido.
+func (p *PostgresRepository) run(fn func(db *gorm.DB) error) error {
+	if !p.rls {
+		return fn(p.db)
+	}
+
+	scope := p.tenantID
+	if scope == "" {
+		scope = allTenantsScope
+	}
+	withScope := func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT set_config('app.tenant_id', ?, true)", scope).Error; err != nil {
+			return domain.ErrInternalServer{Value: err.Error()}
+		}
+		return fn(tx)
+	}
+
+	if p.inTx {
+		return withScope(p.db)
+	}
+	return p.db.Transaction(withScope)
+}
+
+// scoped restringe la consulta a la organización del repositorio, si la hay.
+func (p *PostgresRepository) scoped(db *gorm.DB) *gorm.DB {
+	if p.tenantID == "" {
+		return db
+	}
+	return db.Where("tenant_id = ?", p.tenantID)
+}
+
+// mapWriteError traduce los errores de PostgreSQL de Create y Update a los
+// errores de dominio: unicidad (23505), not-null (23502) y organización
+// inexistente (23503). Retorna nil si no corresponde a ninguno.
+func mapWriteError(result error) error {
+	err := extractPgError(result)
+	if (err != nil) && (err.Code == "23505") { // Código de violación de Unique/Primary Key
+		switch err.Constraint {
+		case "users_pkey":
+			return domain.ErrIdInUse
+		case "idx_username", "idx_username_normalized":
+			return domain.ErrUsernameInUse
+		case "idx_email", "idx_email_normalized":
+			return domain.ErrEmailInUse
+		}
+	}
+	if (err != nil) && (err.Code == "23502") { // Código de violación Not Null
+		column := ""
+		switch err.Constraint {
+		case "id", "username", "email":
+			column = err.Constraint
+		default:
+			column = "a column"
+		}
+		return domain.ErrValueNotNullable{Value: column}
+	}
+	if (err != nil) && (err.Code == "23503") { // Código de violación de Foreign Key (tenant_id)
+		return domain.ErrTenantNotFound
+	}
+	return nil
+}
+
+// Create inserta un nuevo usuario en la organización del repositorio (o en
+// la del usuario, si el repositorio no está restringido). Mapea errores de
+// unicidad (23505), not-null (23502) y de organización inexistente (23503)
+// de PostgreSQL a los errores de dominio.
 func (p *PostgresRepository) Create(user *domain.User) error {
+	if p.tenantID != "" {
+		user.TenantID = p.tenantID
+	}
+	if user.TenantID == "" {
+		user.TenantID = domain.DefaultTenantID
+	}
 	userEntity := entity.ToEntity(user)
 
-	result := p.db.Create(&userEntity).Error
+	result := p.run(func(db *gorm.DB) error {
+		return db.Create(&userEntity).Error
+	})
 
 	if result != nil {
-		err := extractPgError(result)
-		if (err != nil) && (err.Code == "23505") { // Código de violación de Unique/Primary Key
-			switch err.Constraint {
-			case "users_pkey":
-				return domain.ErrIdInUse
-			case "idx_username":
-				return domain.ErrUsernameInUse
-			case "idx_email":
-				return domain.ErrEmailInUse
-			}
-		}
-		if (err != nil) && (err.Code == "23502") { // Código de violación Not Null
-			column := ""
-			switch err.Constraint {
-			case "id", "username", "email":
-				column = err.Constraint
-			default:
-				column = "a column"
-			}
-			return domain.ErrValueNotNullable{Value: column}
+		if err := mapWriteError(result); err != nil {
+			return err
 		}
 		// Cualquier otro error de persistencia se mapea como error interno.
 		return domain.ErrInternalServer{Value: result.Error()}
@@ -104,11 +202,17 @@ func (p *PostgresRepository) Create(user *domain.User) error {
 	return nil
 }
 
-// FindAll recupera todos los registros de usuario y los mapea a entidades de dominio.
-func (p *PostgresRepository) FindAll() (*[]domain.User, error) {
+// FindAll recupera los registros de usuario que cumplen el filtro y los mapea a entidades de dominio.
+func (p *PostgresRepository) FindAll(filter domain.UserFilter) (*[]domain.User, error) {
 	var userEntities []entity.UserEntity
 
-	err := p.db.Find(&userEntities).Error
+	err := p.run(func(db *gorm.DB) error {
+		query := p.filtered(db, filter)
+		if filter.Limit > 0 {
+			query = query.Order("id").Limit(filter.Limit)
+		}
+		return query.Find(&userEntities).Error
+	})
 
 	if err != nil {
 		return nil, domain.ErrInternalServer{Value: err.Error()}
@@ -124,11 +228,85 @@ func (p *PostgresRepository) FindAll() (*[]domain.User, error) {
 	return &users, nil
 }
 
+// Each recorre los usuarios que cumplen el filtro, en orden de ID, leyendo
+// de a una fila del cursor de la base de datos (memoria constante).
+func (p *PostgresRepository) Each(filter domain.UserFilter, fn func(user *domain.User) error) error {
+	return p.run(func(db *gorm.DB) error {
+		rows, err := p.filtered(db, filter).Model(&entity.UserEntity{}).Order("id").Rows()
+		if err != nil {
+			return domain.ErrInternalServer{Value: err.Error()}
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var userEntity entity.UserEntity
+			if err := db.ScanRows(rows, &userEntity); err != nil {
+				return domain.ErrInternalServer{Value: err.Error()}
+			}
+
+			user := entity.FromEntity(&userEntity)
+			if err := fn(&user); err != nil {
+				return err
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			return domain.ErrInternalServer{Value: err.Error()}
+		}
+
+		return nil
+	})
+}
+
+// filtered construye la consulta de los usuarios de la organización que cumplen el filtro.
+func (p *PostgresRepository) filtered(db *gorm.DB, filter domain.UserFilter) *gorm.DB {
+	query := p.scoped(db)
+	if filter.IncludeDeleted {
+		// Unscoped desactiva el filtro automático de soft delete de GORM.
+		query = query.Unscoped()
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at <= ?", *filter.CreatedBefore)
+	}
+	if filter.UpdatedAfter != nil {
+		query = query.Where("updated_at >= ?", *filter.UpdatedAfter)
+	}
+	if filter.UpdatedBefore != nil {
+		query = query.Where("updated_at <= ?", *filter.UpdatedBefore)
+	}
+	if filter.IDs != nil {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	if filter.Statuses != nil {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.AfterID != "" {
+		query = query.Where("id > ?", filter.AfterID)
+	}
+
+	return selectFields(query, filter.Fields)
+}
+
+// selectFields limita el SELECT a los campos indicados (nil = todos). Los
+// nombres de campo de domain.User coinciden con los de las columnas y ya
+// fueron validados con domain.ParseUserFields.
+func selectFields(query *gorm.DB, fields []string) *gorm.DB {
+	if len(fields) == 0 {
+		return query
+	}
+	return query.Select(fields)
+}
+
 // FindById recupera un usuario por su ID. Mapea gorm.ErrRecordNotFound a domain.ErrUserNotFound.
-func (p *PostgresRepository) FindById(id string) (*domain.User, error) {
+func (p *PostgresRepository) FindById(id string, fields ...string) (*domain.User, error) {
 	var userEntity entity.UserEntity
 
-	err := p.db.Where("id = ?", id).First(&userEntity).Error
+	err := p.run(func(db *gorm.DB) error {
+		return selectFields(p.scoped(db), fields).Where("id = ?", id).First(&userEntity).Error
+	})
 
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -146,76 +324,293 @@ func (p *PostgresRepository) FindById(id string) (*domain.User, error) {
 // para errores de unicidad y not-null.
 func (p *PostgresRepository) Update(user *domain.User) error {
 	userEntity := entity.ToEntity(user)
+	// Un usuario nunca cambia de organización: el valor vacío no se actualiza.
+	userEntity.TenantID = ""
+
+	var rowsAffected int64
+	resultErr := p.run(func(db *gorm.DB) error {
+		// Usa Model y Updates para actualizar solo los campos provistos y basándose en el ID.
+		result := p.scoped(db).Model(&entity.UserEntity{ID: userEntity.ID}).Updates(userEntity)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
 
-	// Usa Model y Updates para actualizar solo los campos provistos y basándose en el ID.
-	result := p.db.Model(&entity.UserEntity{ID: userEntity.ID}).Updates(userEntity)
-
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+	if resultErr != nil {
+		if errors.Is(resultErr, gorm.ErrRecordNotFound) {
 			return domain.ErrUserNotFound
 		}
 
 		// Mapeo de errores de PostgreSQL.
-		err := extractPgError(result.Error)
-		if (err != nil) && (err.Code == "23505") {
-			switch err.Constraint {
-			case "users_pkey":
-				return domain.ErrIdInUse
-			case "idx_username":
-				return domain.ErrUsernameInUse
-			case "idx_email":
-				return domain.ErrEmailInUse
-			}
+		if err := mapWriteError(resultErr); err != nil {
+			return err
 		}
-
-		if (err != nil) && (err.Code == "23502") {
-			column := ""
-			switch err.Constraint {
-			case "id", "username", "email":
-				column = err.Constraint
-			default:
-				column = "a column"
-			}
-			return domain.ErrValueNotNullable{Value: column}
-		}
-		return domain.ErrInternalServer{Value: result.Error.Error()}
+		return domain.ErrInternalServer{Value: resultErr.Error()}
 	}
 
 	// Si GORM no reportó error, pero ninguna fila fue afectada, significa que el usuario no existía.
-	if result.RowsAffected == 0 {
+	if rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateStatus escribe las columnas de estado (incluido un motivo vacío, que
+// Update omitiría) y las marcas de modificación de un usuario no eliminado.
+func (p *PostgresRepository) UpdateStatus(user *domain.User) error {
+	var rowsAffected int64
+	err := p.run(func(db *gorm.DB) error {
+		result := p.scoped(db).Model(&entity.UserEntity{}).
+			Where("id = ?", user.ID).
+			UpdateColumns(map[string]any{
+				"status":            string(user.Status),
+				"status_reason":     user.StatusReason,
+				"status_changed_at": user.StatusChangedAt,
+				"updated_at":        user.UpdatedAt,
+				"updated_by":        user.UpdatedBy,
+			})
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+
+	if err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	if rowsAffected == 0 {
 		return domain.ErrUserNotFound
 	}
 
 	return nil
 }
 
-// Delete elimina un usuario por su ID.
+// UpdateEmailVerification escribe explícitamente la verificación del email
+// (Updates omitiría el valor false).
+func (p *PostgresRepository) UpdateEmailVerification(user *domain.User) error {
+	var rowsAffected int64
+	err := p.run(func(db *gorm.DB) error {
+		result := p.scoped(db).Model(&entity.UserEntity{}).
+			Where("id = ?", user.ID).
+			UpdateColumns(map[string]any{
+				"email_verified":    user.EmailVerified,
+				"email_verified_at": user.EmailVerifiedAt,
+				"updated_at":        user.UpdatedAt,
+				"updated_by":        user.UpdatedBy,
+			})
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+
+	if err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Delete elimina lógicamente un usuario por su ID. Al tener UserEntity un
+// campo gorm.DeletedAt, GORM traduce el DELETE en un UPDATE de deleted_at.
 func (p *PostgresRepository) Delete(id string) error {
 	userToDelete := entity.UserEntity{ID: id}
 
-	result := p.db.Delete(&userToDelete)
+	var rowsAffected int64
+	err := p.run(func(db *gorm.DB) error {
+		result := p.scoped(db).Delete(&userToDelete)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
 
-	if result.Error != nil {
-		return domain.ErrInternalServer{Value: result.Error.Error()}
+	if err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
 	}
 
 	// Si RowsAffected es cero, el usuario no fue encontrado para eliminar.
-	if result.RowsAffected == 0 {
+	if rowsAffected == 0 {
 		return domain.ErrUserNotFound
 	}
 
 	return nil
 }
 
+// ReleaseIdentity vacía las formas normalizadas de un usuario eliminado, lo que
+// lo excluye de los índices únicos parciales y libera su username y email.
+func (p *PostgresRepository) ReleaseIdentity(id string) error {
+	var rowsAffected int64
+	err := p.run(func(db *gorm.DB) error {
+		result := p.scoped(db).Unscoped().Model(&entity.UserEntity{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			UpdateColumns(map[string]any{"username_normalized": "", "email_normalized": ""})
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+
+	if err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateNormalized escribe solo las formas normalizadas de un usuario activo,
+// sin hooks ni marcas de modificación. Solo actualiza la fila si su username y
+// email siguen siendo los de user, para no pisar una edición concurrente; en
+// otro caso retorna ErrUserNotFound. Lo usa el backfill de cmd/usernorm.
+func (p *PostgresRepository) UpdateNormalized(user *domain.User) error {
+	var rowsAffected int64
+	err := p.run(func(db *gorm.DB) error {
+		result := p.scoped(db).Model(&entity.UserEntity{}).
+			Where("id = ? AND username = ? AND email = ?", user.ID, user.Username, user.Email).
+			UpdateColumns(map[string]any{
+				"username_normalized": user.UsernameNormalized,
+				"email_normalized":    user.EmailNormalized,
+			})
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+
+	if err != nil {
+		if mapped := mapWriteError(err); mapped != nil {
+			return mapped
+		}
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Restore revierte el soft delete de un usuario y retorna su estado previo
+// (aún eliminado). Distingue entre un usuario inexistente (ErrUserNotFound)
+// y uno que no estaba eliminado (ErrUserNotDeleted).
+func (p *PostgresRepository) Restore(id string) (*domain.User, error) {
+	var userEntity entity.UserEntity
+
+	err := p.run(func(db *gorm.DB) error {
+		return p.scoped(db).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&userEntity).Error
+	})
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrInternalServer{Value: err.Error()}
+		}
+		if _, findErr := p.FindById(id); findErr == nil {
+			return nil, domain.ErrUserNotDeleted
+		}
+		return nil, domain.ErrUserNotFound
+	}
+
+	err = p.run(func(db *gorm.DB) error {
+		return p.scoped(db).Unscoped().Model(&entity.UserEntity{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			UpdateColumn("deleted_at", nil).Error
+	})
+
+	if err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	deletedUser := entity.FromEntity(&userEntity)
+
+	return &deletedUser, nil
+}
+
+// Purge elimina permanentemente los usuarios eliminados lógicamente antes de
+// deletedBefore y retorna su ID y organización (DELETE ... RETURNING id, tenant_id).
+func (p *PostgresRepository) Purge(deletedBefore time.Time) ([]domain.User, error) {
+	var purged []entity.UserEntity
+
+	err := p.run(func(db *gorm.DB) error {
+		return p.scoped(db).Unscoped().
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "tenant_id"}}}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Delete(&purged).Error
+	})
+
+	if err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	users := make([]domain.User, len(purged))
+	for i, purgedEntity := range purged {
+		users[i] = domain.User{ID: purgedEntity.ID, TenantID: purgedEntity.TenantID}
+	}
+
+	return users, nil
+}
+
+// TakenIdentities consulta, incluyendo los usuarios eliminados, qué formas
+// normalizadas de username y email ya están en uso en la organización.
+func (p *PostgresRepository) TakenIdentities(usernamesNormalized, emailsNormalized []string) ([]string, []string, error) {
+	usernames, emails := make([]string, 0), make([]string, 0)
+
+	err := p.run(func(db *gorm.DB) error {
+		if len(usernamesNormalized) > 0 {
+			err := p.scoped(db).Unscoped().Model(&entity.UserEntity{}).
+				Where("username_normalized IN ?", usernamesNormalized).
+				Pluck("username_normalized", &usernames).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(emailsNormalized) > 0 {
+			return p.scoped(db).Unscoped().Model(&entity.UserEntity{}).
+				Where("email_normalized IN ?", emailsNormalized).
+				Pluck("email_normalized", &emails).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return usernames, emails, nil
+}
+
+// Users implementa domain.UnitOfWork retornando el propio repositorio.
+func (p *PostgresRepository) Users() domain.UserRepository {
+	return p
+}
+
+// Audit implementa domain.UnitOfWork retornando un repositorio de auditoría
+// que comparte la conexión (o transacción) de este repositorio.
+func (p *PostgresRepository) Audit() domain.AuditRepository {
+	return NewPostgresAuditRepository(p.db)
+}
+
+// Outbox implementa domain.UnitOfWork retornando un repositorio del outbox
+// que comparte la conexión (o transacción) de este repositorio.
+func (p *PostgresRepository) Outbox() domain.OutboxRepository {
+	return NewPostgresOutboxRepository(p.db)
+}
+
+// EmailVerifications implementa domain.UnitOfWork retornando un repositorio
+// de tokens de verificación que comparte la conexión (o transacción) de este
+// repositorio.
+func (p *PostgresRepository) EmailVerifications() domain.EmailVerificationRepository {
+	return NewPostgresEmailVerificationRepository(p.db)
+}
+
 // Execute implementa el UserTransactionPort, ejecutando la función de dominio
 // dentro de una transacción de GORM.
-func (p *PostgresRepository) Execute(fn func(repo domain.UserRepository) error) error {
+func (p *PostgresRepository) Execute(fn func(uow domain.UnitOfWork) error) error {
 	var capturedDomainError error
 
 	// Inicia una transacción de GORM.
 	txErr := p.db.Transaction(func(tx *gorm.DB) error {
 		// Crea una nueva instancia de repositorio que usa la transacción (txRepo).
-		txRepo := &PostgresRepository{db: tx}
+		// Actúa como UnitOfWork: todos los repositorios que expone comparten tx.
+		txRepo := &PostgresRepository{db: tx, tenantID: p.tenantID, rls: p.rls, inTx: true}
 
 		// Ejecuta la lógica de negocio, pasando el repositorio transaccional.
 		txResultErr := fn(txRepo)
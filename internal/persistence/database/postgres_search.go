@@ -0,0 +1,87 @@
+package database
+
+import (
+	"strings"
+	"unicode"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/entity"
+
+	"gorm.io/gorm"
+)
+
+// userSearchDocument es la expresión tsvector indexada para la búsqueda de
+// texto completo. Debe coincidir exactamente con la del índice
+// idx_users_search_document para que PostgreSQL pueda utilizarlo.
+const userSearchDocument = `to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(username, '') || ' ' || translate(coalesce(email, ''), '@.', '  '))`
+
+// userSearchSQL combina coincidencias de prefijo (tsvector), similitud de
+// trigramas (pg_trgm) y subcadenas (ILIKE, también respaldado por trigramas).
+const userSearchSQL = `
+SELECT *, COUNT(*) OVER () AS total_count,
+	ts_rank(` + userSearchDocument + `, to_tsquery('simple', @tsquery))
+		+ greatest(similarity(name, @query), similarity(username, @query), similarity(email, @query)) AS rank
+FROM user_entities
+WHERE deleted_at IS NULL AND (@tenant = '' OR tenant_id = @tenant) AND (` + userSearchDocument + ` @@ to_tsquery('simple', @tsquery)
+	OR name % @query OR username % @query OR email % @query
+	OR name ILIKE @pattern OR username ILIKE @pattern OR email ILIKE @pattern)
+ORDER BY rank DESC, id
+LIMIT @limit OFFSET @offset`
+
+// searchRow es una fila del resultado de userSearchSQL.
+type searchRow struct {
+	entity.UserEntity `gorm:"embedded"`
+	Rank              float64
+	TotalCount        int64
+}
+
+// Asegura que PostgresRepository implemente la capacidad de búsqueda nativa.
+var _ domain.UserSearcher = (*PostgresRepository)(nil)
+
+// Search resuelve la búsqueda, dentro de la organización del repositorio,
+// con los índices tsvector y pg_trgm de PostgreSQL.
+func (p *PostgresRepository) Search(query domain.UserSearchQuery) (*domain.UserSearchResult, error) {
+	var rows []searchRow
+
+	err := p.run(func(db *gorm.DB) error {
+		return db.Raw(userSearchSQL, map[string]any{
+			"tenant":  p.tenantID,
+			"tsquery": prefixTsQuery(query.Query),
+			"query":   query.Query,
+			"pattern": "%" + escapeLike(query.Query) + "%",
+			"limit":   query.Limit,
+			"offset":  query.Offset,
+		}).Scan(&rows).Error
+	})
+
+	if err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	result := &domain.UserSearchResult{Hits: make([]domain.UserSearchHit, len(rows))}
+	for i, row := range rows {
+		result.Hits[i] = domain.UserSearchHit{User: entity.FromEntity(&row.UserEntity), Rank: row.Rank}
+		result.Total = row.TotalCount
+	}
+
+	return result, nil
+}
+
+// prefixTsQuery convierte la consulta en una tsquery de prefijos ("jan:* & do:*").
+// Solo conserva letras y dígitos para que la entrada no pueda alterar la sintaxis.
+func prefixTsQuery(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = word + ":*"
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// escapeLike escapa los comodines de LIKE/ILIKE presentes en la entrada.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"errors"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/entity"
+
+	"gorm.io/gorm"
+)
+
+// PostgresTenantRepository implementa domain.TenantRepository sobre PostgreSQL.
+type PostgresTenantRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresTenantRepository crea una nueva instancia del repositorio de organizaciones.
+func NewPostgresTenantRepository(db *gorm.DB) *PostgresTenantRepository {
+	return &PostgresTenantRepository{db: db}
+}
+
+// Asegura que PostgresTenantRepository implemente domain.TenantRepository.
+var _ domain.TenantRepository = (*PostgresTenantRepository)(nil)
+
+// Create inserta una nueva organización.
+func (p *PostgresTenantRepository) Create(tenant *domain.Tenant) error {
+	tenantEntity := entity.ToTenantEntity(tenant)
+
+	if err := p.db.Create(&tenantEntity).Error; err != nil {
+		if pgErr := extractPgError(err); pgErr != nil && pgErr.Code == "23505" {
+			return domain.ErrTenantIDInUse
+		}
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return nil
+}
+
+// FindAll recupera las organizaciones ordenadas por ID.
+func (p *PostgresTenantRepository) FindAll() ([]domain.Tenant, error) {
+	var tenantEntities []entity.TenantEntity
+
+	if err := p.db.Order("id").Find(&tenantEntities).Error; err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	tenants := make([]domain.Tenant, len(tenantEntities))
+	for i := range tenantEntities {
+		tenants[i] = entity.FromTenantEntity(&tenantEntities[i])
+	}
+
+	return tenants, nil
+}
+
+// FindById recupera una organización por su ID.
+func (p *PostgresTenantRepository) FindById(id string) (*domain.Tenant, error) {
+	var tenantEntity entity.TenantEntity
+
+	err := p.db.Where("id = ?", id).First(&tenantEntity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTenantNotFound
+		}
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	tenant := entity.FromTenantEntity(&tenantEntity)
+	return &tenant, nil
+}
+
+// Update persiste el nombre y las marcas de modificación de una organización.
+func (p *PostgresTenantRepository) Update(tenant *domain.Tenant) error {
+	result := p.db.Model(&entity.TenantEntity{}).
+		Where("id = ?", tenant.ID).
+		Updates(map[string]any{"name": tenant.Name, "updated_at": tenant.UpdatedAt, "updated_by": tenant.UpdatedBy})
+
+	if result.Error != nil {
+		return domain.ErrInternalServer{Value: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTenantNotFound
+	}
+
+	return nil
+}
+
+// Delete elimina una organización. La clave foránea de user_entities impide
+// eliminarla mientras tenga usuarios (23503).
+func (p *PostgresTenantRepository) Delete(id string) error {
+	result := p.db.Delete(&entity.TenantEntity{ID: id})
+
+	if result.Error != nil {
+		if pgErr := extractPgError(result.Error); pgErr != nil && pgErr.Code == "23503" {
+			return domain.ErrTenantNotEmpty
+		}
+		return domain.ErrInternalServer{Value: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTenantNotFound
+	}
+
+	return nil
+}
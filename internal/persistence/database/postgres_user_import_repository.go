@@ -0,0 +1,162 @@
+package database
+
+import (
+	"errors"
+	"time"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/entity"
+
+	"gorm.io/gorm"
+)
+
+// userImportColumns son las columnas de user_imports leídas sin el archivo.
+var userImportColumns = []string{
+	"id", "format", "mapping_name", "mapping_username", "mapping_email", "status", "total_rows",
+	"processed_rows", "created_rows", "failed_rows", "error", "created_by", "created_at", "updated_at", "completed_at",
+}
+
+// PostgresUserImportRepository implementa domain.UserImportRepository sobre PostgreSQL.
+type PostgresUserImportRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresUserImportRepository crea una nueva instancia del repositorio de importaciones.
+func NewPostgresUserImportRepository(db *gorm.DB) *PostgresUserImportRepository {
+	return &PostgresUserImportRepository{db: db}
+}
+
+// Asegura que PostgresUserImportRepository implemente domain.UserImportRepository.
+var _ domain.UserImportRepository = (*PostgresUserImportRepository)(nil)
+
+// Create inserta la importación junto con el contenido del archivo.
+func (p *PostgresUserImportRepository) Create(userImport *domain.UserImport, payload []byte) error {
+	importEntity := entity.ToUserImportEntity(userImport)
+	importEntity.Payload = payload
+
+	if err := p.db.Create(&importEntity).Error; err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return nil
+}
+
+// FindById recupera una importación por su ID (sin el archivo).
+func (p *PostgresUserImportRepository) FindById(id string) (*domain.UserImport, error) {
+	var importEntity entity.UserImportEntity
+
+	err := p.db.Select(userImportColumns).Where("id = ?", id).First(&importEntity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrImportNotFound
+		}
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	userImport := entity.FromUserImportEntity(&importEntity)
+
+	return &userImport, nil
+}
+
+// FindRunnable recupera las importaciones pendientes o en curso.
+func (p *PostgresUserImportRepository) FindRunnable(limit int) ([]domain.UserImport, error) {
+	var importEntities []entity.UserImportEntity
+
+	err := p.db.Select(userImportColumns).
+		Where("status IN ?", []string{string(domain.ImportPending), string(domain.ImportRunning)}).
+		Order("created_at").Limit(limit).Find(&importEntities).Error
+	if err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	imports := make([]domain.UserImport, len(importEntities))
+	for i := range importEntities {
+		imports[i] = entity.FromUserImportEntity(&importEntities[i])
+	}
+
+	return imports, nil
+}
+
+// Claim toma la importación si no tiene un lease vigente de otro proceso.
+func (p *PostgresUserImportRepository) Claim(id, owner string, leaseUntil time.Time) (bool, error) {
+	result := p.db.Model(&entity.UserImportEntity{}).
+		Where("id = ? AND status IN ?", id, []string{string(domain.ImportPending), string(domain.ImportRunning)}).
+		Where("lease_owner = ? OR lease_expires_at IS NULL OR lease_expires_at < ?", owner, time.Now()).
+		UpdateColumns(map[string]any{"lease_owner": owner, "lease_expires_at": leaseUntil})
+	if result.Error != nil {
+		return false, domain.ErrInternalServer{Value: result.Error.Error()}
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Payload recupera el contenido del archivo de la importación.
+func (p *PostgresUserImportRepository) Payload(id string) ([]byte, error) {
+	var importEntity entity.UserImportEntity
+
+	err := p.db.Select("payload").Where("id = ?", id).First(&importEntity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrImportNotFound
+		}
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return importEntity.Payload, nil
+}
+
+// Update persiste el estado y el progreso de la importación.
+func (p *PostgresUserImportRepository) Update(userImport *domain.UserImport) error {
+	importEntity := entity.ToUserImportEntity(userImport)
+
+	result := p.db.Model(&entity.UserImportEntity{ID: userImport.ID}).
+		Select("status", "total_rows", "processed_rows", "created_rows", "failed_rows", "error", "updated_at", "completed_at").
+		Updates(&importEntity)
+	if result.Error != nil {
+		return domain.ErrInternalServer{Value: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrImportNotFound
+	}
+
+	return nil
+}
+
+// AppendErrors inserta los errores de filas de la importación.
+func (p *PostgresUserImportRepository) AppendErrors(importID string, rowErrors []domain.ImportRowError) error {
+	if len(rowErrors) == 0 {
+		return nil
+	}
+
+	errorEntities := make([]entity.UserImportErrorEntity, len(rowErrors))
+	for i, rowError := range rowErrors {
+		errorEntities[i] = entity.UserImportErrorEntity{
+			ImportID: importID,
+			Row:      rowError.Row,
+			Field:    rowError.Field,
+			Message:  rowError.Message,
+		}
+	}
+
+	if err := p.db.CreateInBatches(&errorEntities, 500).Error; err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return nil
+}
+
+// Errors recupera el reporte de errores de la importación.
+func (p *PostgresUserImportRepository) Errors(importID string) ([]domain.ImportRowError, error) {
+	var errorEntities []entity.UserImportErrorEntity
+
+	err := p.db.Where("import_id = ?", importID).Order("row, id").Find(&errorEntities).Error
+	if err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	rowErrors := make([]domain.ImportRowError, len(errorEntities))
+	for i, errorEntity := range errorEntities {
+		rowErrors[i] = domain.ImportRowError{Row: errorEntity.Row, Field: errorEntity.Field, Message: errorEntity.Message}
+	}
+
+	return rowErrors, nil
+}
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"errors"
+	"time"
+	"user-api-restful/internal/domain"
+	"user-api-restful/internal/persistence/entity"
+
+	"gorm.io/gorm"
+)
+
+// PostgresWebhookRepository implementa domain.WebhookRepository sobre PostgreSQL.
+type PostgresWebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresWebhookRepository crea una nueva instancia del repositorio de webhooks.
+func NewPostgresWebhookRepository(db *gorm.DB) *PostgresWebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+// Asegura que PostgresWebhookRepository implemente domain.WebhookRepository.
+var _ domain.WebhookRepository = (*PostgresWebhookRepository)(nil)
+
+// Create inserta un nuevo webhook.
+func (p *PostgresWebhookRepository) Create(webhook *domain.Webhook) error {
+	webhookEntity := entity.ToWebhookEntity(webhook)
+
+	if err := p.db.Create(&webhookEntity).Error; err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return nil
+}
+
+// FindAll recupera todos los webhooks ordenados por fecha de creación.
+func (p *PostgresWebhookRepository) FindAll() ([]domain.Webhook, error) {
+	var webhookEntities []entity.WebhookEntity
+
+	if err := p.db.Order("created_at").Find(&webhookEntities).Error; err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	webhooks := make([]domain.Webhook, len(webhookEntities))
+	for i := range webhookEntities {
+		webhooks[i] = entity.FromWebhookEntity(&webhookEntities[i])
+	}
+
+	return webhooks, nil
+}
+
+// FindById recupera un webhook por su ID.
+func (p *PostgresWebhookRepository) FindById(id string) (*domain.Webhook, error) {
+	var webhookEntity entity.WebhookEntity
+
+	err := p.db.Where("id = ?", id).First(&webhookEntity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	webhook := entity.FromWebhookEntity(&webhookEntity)
+
+	return &webhook, nil
+}
+
+// Update persiste todos los campos del webhook (incluidos los valores cero).
+func (p *PostgresWebhookRepository) Update(webhook *domain.Webhook) error {
+	webhookEntity := entity.ToWebhookEntity(webhook)
+
+	result := p.db.Model(&entity.WebhookEntity{ID: webhook.ID}).Select("*").Omit("created_at").Updates(&webhookEntity)
+	if result.Error != nil {
+		return domain.ErrInternalServer{Value: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// Delete elimina el webhook y su registro de entregas en una transacción.
+func (p *PostgresWebhookRepository) Delete(id string) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&entity.WebhookDeliveryEntity{}).Error; err != nil {
+			return domain.ErrInternalServer{Value: err.Error()}
+		}
+
+		result := tx.Delete(&entity.WebhookEntity{ID: id})
+		if result.Error != nil {
+			return domain.ErrInternalServer{Value: result.Error.Error()}
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrWebhookNotFound
+		}
+
+		return nil
+	})
+}
+
+// CreateDelivery inserta una nueva entrega.
+func (p *PostgresWebhookRepository) CreateDelivery(delivery *domain.WebhookDelivery) error {
+	deliveryEntity := entity.ToWebhookDeliveryEntity(delivery)
+
+	if err := p.db.Create(&deliveryEntity).Error; err != nil {
+		return domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return nil
+}
+
+// UpdateDelivery persiste el estado de una entrega tras un intento.
+func (p *PostgresWebhookRepository) UpdateDelivery(delivery *domain.WebhookDelivery) error {
+	deliveryEntity := entity.ToWebhookDeliveryEntity(delivery)
+
+	result := p.db.Model(&entity.WebhookDeliveryEntity{ID: delivery.ID}).Select("*").Omit("created_at").Updates(&deliveryEntity)
+	if result.Error != nil {
+		return domain.ErrInternalServer{Value: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrWebhookDeliveryNotFound
+	}
+
+	return nil
+}
+
+// FindDelivery recupera una entrega de un webhook por su ID.
+func (p *PostgresWebhookRepository) FindDelivery(webhookID, deliveryID string) (*domain.WebhookDelivery, error) {
+	var deliveryEntity entity.WebhookDeliveryEntity
+
+	err := p.db.Where("id = ? AND webhook_id = ?", deliveryID, webhookID).First(&deliveryEntity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrWebhookDeliveryNotFound
+		}
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	delivery := entity.FromWebhookDeliveryEntity(&deliveryEntity)
+
+	return &delivery, nil
+}
+
+// FindDeliveries recupera el registro de entregas de un webhook.
+func (p *PostgresWebhookRepository) FindDeliveries(webhookID string, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveryEntities []entity.WebhookDeliveryEntity
+
+	err := p.db.Where("webhook_id = ?", webhookID).Order("created_at DESC, id DESC").Limit(limit).Find(&deliveryEntities).Error
+	if err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return fromDeliveryEntities(deliveryEntities), nil
+}
+
+// FindDueDeliveries recupera las entregas pendientes cuyo próximo intento ya venció.
+func (p *PostgresWebhookRepository) FindDueDeliveries(now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveryEntities []entity.WebhookDeliveryEntity
+
+	err := p.db.Where("state = ? AND next_attempt_at <= ?", string(domain.DeliveryPending), now).
+		Order("next_attempt_at, id").Limit(limit).Find(&deliveryEntities).Error
+	if err != nil {
+		return nil, domain.ErrInternalServer{Value: err.Error()}
+	}
+
+	return fromDeliveryEntities(deliveryEntities), nil
+}
+
+// fromDeliveryEntities mapea una lista de entidades de entrega a dominio.
+func fromDeliveryEntities(deliveryEntities []entity.WebhookDeliveryEntity) []domain.WebhookDelivery {
+	deliveries := make([]domain.WebhookDelivery, len(deliveryEntities))
+	for i := range deliveryEntities {
+		deliveries[i] = entity.FromWebhookDeliveryEntity(&deliveryEntities[i])
+	}
+	return deliveries
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// allTenantsScope es el valor de app.tenant_id con el que las políticas de
+// row-level security exponen las filas de todas las organizaciones. Lo usan
+// solo los repositorios no restringidos (tareas de mantenimiento).
+const allTenantsScope = "*"
+
+// ConfigureRowLevelSecurity habilita (o deshabilita) la row-level security
+// de user_entities. Habilitada, PostgreSQL aplica la política
+// user_entities_tenant_isolation (ver migración 0006_tenant_row_level_security)
+// incluso al dueño de la tabla: sin app.tenant_id, ninguna fila es visible.
+// Es una defensa en profundidad: los repositorios ya filtran por organización.
+func ConfigureRowLevelSecurity(db *gorm.DB, enabled bool) error {
+	statements := []string{
+		`ALTER TABLE user_entities DISABLE ROW LEVEL SECURITY`,
+		`ALTER TABLE user_entities NO FORCE ROW LEVEL SECURITY`,
+	}
+	if enabled {
+		statements = []string{
+			`ALTER TABLE user_entities ENABLE ROW LEVEL SECURITY`,
+			`ALTER TABLE user_entities FORCE ROW LEVEL SECURITY`,
+		}
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("row level security: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// APIKeyEntity representa una clave de API. Solo se guarda el hash SHA-256
+// del secreto; los roles se guardan separados por comas.
+type APIKeyEntity struct {
+	ID        string    `gorm:"primaryKey"`
+	Name      string    `gorm:"not null;index"`
+	Prefix    string    `gorm:"not null"`
+	Hash      string    `gorm:"not null;uniqueIndex"`
+	Roles     string    `gorm:"not null;default:''"`
+	TenantID  string    `gorm:"not null;default:''"`
+	UserID    string    `gorm:"not null;default:''"`
+	CreatedAt time.Time `gorm:"not null"`
+	CreatedBy string    `gorm:"not null;default:''"`
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+}
+
+// TableName fija el nombre de la tabla de claves de API.
+func (APIKeyEntity) TableName() string {
+	return "api_keys"
+}
+
+// ToAPIKeyEntity convierte una clave de API de dominio a su entidad de persistencia.
+func ToAPIKeyEntity(key *domain.APIKey) APIKeyEntity {
+	return APIKeyEntity{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Hash:      key.Hash,
+		Roles:     strings.Join(key.Roles, ","),
+		TenantID:  key.TenantID,
+		UserID:    key.UserID,
+		CreatedAt: key.CreatedAt,
+		CreatedBy: key.CreatedBy,
+		ExpiresAt: key.ExpiresAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+// FromAPIKeyEntity convierte una entidad de clave de API a su modelo de dominio.
+func FromAPIKeyEntity(keyEntity *APIKeyEntity) domain.APIKey {
+	roles := []string{}
+	if keyEntity.Roles != "" {
+		roles = strings.Split(keyEntity.Roles, ",")
+	}
+
+	return domain.APIKey{
+		ID:        keyEntity.ID,
+		Name:      keyEntity.Name,
+		Prefix:    keyEntity.Prefix,
+		Hash:      keyEntity.Hash,
+		Roles:     roles,
+		TenantID:  keyEntity.TenantID,
+		UserID:    keyEntity.UserID,
+		CreatedAt: keyEntity.CreatedAt,
+		CreatedBy: keyEntity.CreatedBy,
+		ExpiresAt: keyEntity.ExpiresAt,
+		RevokedAt: keyEntity.RevokedAt,
+	}
+}
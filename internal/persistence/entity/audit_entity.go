@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// AuditEntity representa una fila de la tabla de auditoría. Los cambios por
+// campo se guardan como JSONB. La tabla es de solo inserción (ver migración
+// 0004_audit_immutable).
+type AuditEntity struct {
+	ID        string    `gorm:"primaryKey"`
+	TenantID  string    `gorm:"not null;default:'default';index"`
+	UserID    string    `gorm:"not null;index"`
+	Action    string    `gorm:"not null"`
+	Actor     string    `gorm:"not null;index"`
+	RequestID string    `gorm:"not null;default:''"`
+	Timestamp time.Time `gorm:"not null;index"`
+	Changes   string    `gorm:"type:jsonb;not null"`
+}
+
+// TableName fija el nombre de la tabla de auditoría.
+func (AuditEntity) TableName() string {
+	return "user_audit_log"
+}
+
+// ToAuditEntity convierte un registro de auditoría de dominio a su entidad de persistencia.
+func ToAuditEntity(record *domain.AuditRecord) (AuditEntity, error) {
+	changes, err := json.Marshal(record.Changes)
+	if err != nil {
+		return AuditEntity{}, err
+	}
+
+	return AuditEntity{
+		ID:        record.ID,
+		TenantID:  record.TenantID,
+		UserID:    record.UserID,
+		Action:    string(record.Action),
+		Actor:     record.Actor,
+		RequestID: record.RequestID,
+		Timestamp: record.Timestamp,
+		Changes:   string(changes),
+	}, nil
+}
+
+// FromAuditEntity convierte una entidad de auditoría a un registro de dominio.
+func FromAuditEntity(entity *AuditEntity) (domain.AuditRecord, error) {
+	record := domain.AuditRecord{
+		ID:        entity.ID,
+		TenantID:  entity.TenantID,
+		UserID:    entity.UserID,
+		Action:    domain.AuditAction(entity.Action),
+		Actor:     entity.Actor,
+		RequestID: entity.RequestID,
+		Timestamp: entity.Timestamp,
+	}
+
+	err := json.Unmarshal([]byte(entity.Changes), &record.Changes)
+
+	return record, err
+}
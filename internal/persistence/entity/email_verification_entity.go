@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// EmailVerificationEntity representa un token de verificación de email.
+// Solo se guarda el hash SHA-256 del token.
+type EmailVerificationEntity struct {
+	ID        string    `gorm:"primaryKey"`
+	TenantID  string    `gorm:"not null"`
+	UserID    string    `gorm:"not null;index"`
+	Email     string    `gorm:"not null"`
+	Hash      string    `gorm:"not null;uniqueIndex"`
+	EventID   string    `gorm:"not null;default:'';index"`
+	CreatedAt time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+}
+
+// TableName fija el nombre de la tabla de tokens de verificación de email.
+func (EmailVerificationEntity) TableName() string {
+	return "email_verification_tokens"
+}
+
+// ToEmailVerificationEntity convierte un token de dominio a su entidad de persistencia.
+func ToEmailVerificationEntity(token *domain.EmailVerificationToken) EmailVerificationEntity {
+	return EmailVerificationEntity{
+		ID:        token.ID,
+		TenantID:  token.TenantID,
+		UserID:    token.UserID,
+		Email:     token.Email,
+		Hash:      token.Hash,
+		EventID:   token.EventID,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+		UsedAt:    token.UsedAt,
+	}
+}
+
+// FromEmailVerificationEntity convierte una entidad de token a su modelo de dominio.
+func FromEmailVerificationEntity(tokenEntity *EmailVerificationEntity) domain.EmailVerificationToken {
+	return domain.EmailVerificationToken{
+		ID:        tokenEntity.ID,
+		TenantID:  tokenEntity.TenantID,
+		UserID:    tokenEntity.UserID,
+		Email:     tokenEntity.Email,
+		Hash:      tokenEntity.Hash,
+		EventID:   tokenEntity.EventID,
+		CreatedAt: tokenEntity.CreatedAt,
+		ExpiresAt: tokenEntity.ExpiresAt,
+		UsedAt:    tokenEntity.UsedAt,
+	}
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// GroupEntity representa un grupo. El nombre es único por organización.
+type GroupEntity struct {
+	ID          string    `gorm:"primaryKey"`
+	TenantID    string    `gorm:"not null;default:'default';uniqueIndex:idx_user_groups_tenant_name,priority:1"`
+	Name        string    `gorm:"not null;uniqueIndex:idx_user_groups_tenant_name,priority:2"`
+	Description string    `gorm:"not null;default:''"`
+	CreatedAt   time.Time `gorm:"not null"`
+	UpdatedAt   time.Time `gorm:"not null"`
+	CreatedBy   string    `gorm:"not null;default:''"`
+	UpdatedBy   string    `gorm:"not null;default:''"`
+}
+
+// TableName fija el nombre de la tabla de grupos.
+func (GroupEntity) TableName() string {
+	return "user_groups"
+}
+
+// GroupUserMemberEntity representa la pertenencia directa de un usuario a un
+// grupo. Las claves foráneas (ver migración 0007_groups) incluyen tenant_id,
+// de modo que ambos deben pertenecer a la misma organización.
+type GroupUserMemberEntity struct {
+	GroupID   string    `gorm:"primaryKey"`
+	UserID    string    `gorm:"primaryKey;index"`
+	TenantID  string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	CreatedBy string    `gorm:"not null;default:''"`
+}
+
+// TableName fija el nombre de la tabla de usuarios miembros.
+func (GroupUserMemberEntity) TableName() string {
+	return "user_group_members"
+}
+
+// GroupNestingEntity representa la pertenencia de un grupo (ChildID) a otro
+// (ParentID).
+type GroupNestingEntity struct {
+	ParentID  string    `gorm:"primaryKey"`
+	ChildID   string    `gorm:"primaryKey;index"`
+	TenantID  string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	CreatedBy string    `gorm:"not null;default:''"`
+}
+
+// TableName fija el nombre de la tabla de grupos anidados.
+func (GroupNestingEntity) TableName() string {
+	return "user_group_nesting"
+}
+
+// ToGroupEntity convierte un grupo de dominio a su entidad de persistencia.
+func ToGroupEntity(group *domain.Group) GroupEntity {
+	return GroupEntity{
+		ID:          group.ID,
+		TenantID:    group.TenantID,
+		Name:        group.Name,
+		Description: group.Description,
+		CreatedAt:   group.CreatedAt,
+		UpdatedAt:   group.UpdatedAt,
+		CreatedBy:   group.CreatedBy,
+		UpdatedBy:   group.UpdatedBy,
+	}
+}
+
+// FromGroupEntity convierte una entidad de grupo a su modelo de dominio.
+func FromGroupEntity(entity *GroupEntity) domain.Group {
+	return domain.Group{
+		ID:          entity.ID,
+		TenantID:    entity.TenantID,
+		Name:        entity.Name,
+		Description: entity.Description,
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+		CreatedBy:   entity.CreatedBy,
+		UpdatedBy:   entity.UpdatedBy,
+	}
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// IdempotencyEntity representa una clave de idempotencia con la respuesta
+// registrada de la petición original.
+type IdempotencyEntity struct {
+	Scope       string    `gorm:"primaryKey"`
+	Key         string    `gorm:"primaryKey"`
+	Fingerprint string    `gorm:"not null"`
+	StatusCode  int       `gorm:"not null;default:0"`
+	ContentType string    `gorm:"not null;default:''"`
+	Body        []byte    `gorm:"type:bytea"`
+	CreatedAt   time.Time `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
+
+// TableName fija el nombre de la tabla de claves de idempotencia.
+func (IdempotencyEntity) TableName() string {
+	return "idempotency_keys"
+}
+
+// ToIdempotencyEntity mapea un domain.IdempotencyRecord a su entidad de persistencia.
+func ToIdempotencyEntity(record *domain.IdempotencyRecord) IdempotencyEntity {
+	return IdempotencyEntity{
+		Scope:       record.Scope,
+		Key:         record.Key,
+		Fingerprint: record.Fingerprint,
+		StatusCode:  record.StatusCode,
+		ContentType: record.ContentType,
+		Body:        record.Body,
+		CreatedAt:   record.CreatedAt,
+		ExpiresAt:   record.ExpiresAt,
+	}
+}
+
+// FromIdempotencyEntity mapea una IdempotencyEntity a domain.IdempotencyRecord.
+func FromIdempotencyEntity(e *IdempotencyEntity) domain.IdempotencyRecord {
+	return domain.IdempotencyRecord{
+		Scope:       e.Scope,
+		Key:         e.Key,
+		Fingerprint: e.Fingerprint,
+		StatusCode:  e.StatusCode,
+		ContentType: e.ContentType,
+		Body:        e.Body,
+		CreatedAt:   e.CreatedAt,
+		ExpiresAt:   e.ExpiresAt,
+	}
+}
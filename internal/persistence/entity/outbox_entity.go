@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// OutboxEntity representa una fila del outbox de eventos de usuario.
+// Sequence (bigserial) se asigna al insertar; PublishedSequence se asigna al
+// publicar, de la secuencia user_outbox_published_sequence, y define el orden
+// de entrega. Las filas publicadas se conservan como registro de eventos.
+type OutboxEntity struct {
+	Sequence          int64      `gorm:"primaryKey;autoIncrement;index:idx_user_outbox_tenant_sequence,priority:2"`
+	PublishedSequence *int64     `gorm:"uniqueIndex;index:idx_user_outbox_tenant_published_sequence,priority:2"`
+	EventID           string     `gorm:"not null;uniqueIndex"`
+	TenantID          string     `gorm:"not null;default:'default';index:idx_user_outbox_tenant_sequence,priority:1;index:idx_user_outbox_tenant_published_sequence,priority:1"`
+	Type              string     `gorm:"not null;index"`
+	UserID            string     `gorm:"not null;index"`
+	OccurredAt        time.Time  `gorm:"not null"`
+	Payload           string     `gorm:"type:jsonb;not null"`
+	PublishedAt       *time.Time `gorm:"index"`
+	Attempts          int        `gorm:"not null;default:0"`
+	LastError         string     `gorm:"not null;default:''"`
+	NextAttemptAt     time.Time  `gorm:"not null;default:now()"`
+}
+
+// TableName fija el nombre de la tabla del outbox.
+func (OutboxEntity) TableName() string {
+	return "user_outbox"
+}
+
+// ToOutboxEntity convierte un evento de dominio a una fila del outbox.
+func ToOutboxEntity(event *domain.UserEvent) (OutboxEntity, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return OutboxEntity{}, err
+	}
+
+	return OutboxEntity{
+		EventID:       event.ID,
+		TenantID:      event.Tenant(),
+		Type:          string(event.Type),
+		UserID:        event.UserID,
+		OccurredAt:    event.OccurredAt,
+		Payload:       string(payload),
+		NextAttemptAt: event.OccurredAt,
+	}, nil
+}
+
+// FromOutboxEntity reconstruye el evento de dominio de una fila del outbox.
+func FromOutboxEntity(entity *OutboxEntity) (domain.UserEvent, error) {
+	var event domain.UserEvent
+	if err := json.Unmarshal([]byte(entity.Payload), &event); err != nil {
+		return domain.UserEvent{}, err
+	}
+
+	event.Sequence = entity.Sequence
+	if entity.PublishedSequence != nil {
+		event.PublishedSequence = *entity.PublishedSequence
+	}
+
+	return event, nil
+}
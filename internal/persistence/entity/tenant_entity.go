@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// TenantEntity representa una organización. user_entities.tenant_id la
+// referencia (ver migración 0005_tenants), por lo que no puede eliminarse
+// mientras tenga usuarios.
+type TenantEntity struct {
+	ID        string    `gorm:"primaryKey"`
+	Name      string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+	CreatedBy string    `gorm:"not null;default:''"`
+	UpdatedBy string    `gorm:"not null;default:''"`
+}
+
+// TableName fija el nombre de la tabla de organizaciones.
+func (TenantEntity) TableName() string {
+	return "tenants"
+}
+
+// ToTenantEntity convierte una organización de dominio a su entidad de persistencia.
+func ToTenantEntity(tenant *domain.Tenant) TenantEntity {
+	return TenantEntity{
+		ID:        tenant.ID,
+		Name:      tenant.Name,
+		CreatedAt: tenant.CreatedAt,
+		UpdatedAt: tenant.UpdatedAt,
+		CreatedBy: tenant.CreatedBy,
+		UpdatedBy: tenant.UpdatedBy,
+	}
+}
+
+// FromTenantEntity convierte una entidad de organización a su modelo de dominio.
+func FromTenantEntity(entity *TenantEntity) domain.Tenant {
+	return domain.Tenant{
+		ID:        entity.ID,
+		Name:      entity.Name,
+		CreatedAt: entity.CreatedAt,
+		UpdatedAt: entity.UpdatedAt,
+		CreatedBy: entity.CreatedBy,
+		UpdatedBy: entity.UpdatedBy,
+	}
+}
@@ -1,6 +1,11 @@
 package entity
 
-import "user-api-restful/internal/domain"
+import (
+	"time"
+	"user-api-restful/internal/domain"
+
+	"gorm.io/gorm"
+)
 
 // Package database contiene las estructuras (Entities) específicas de la base de datos
 // y las utilidades de mapeo necesarias para la persistencia.
@@ -9,13 +14,48 @@ import "user-api-restful/internal/domain"
 // Utiliza tags de GORM para definir el esquema y las restricciones (primary key, unique index, not blank).
 type UserEntity struct {
 	ID       string `json:"id" gorm:"primary_key"`
+	TenantID string `json:"tenant_id" gorm:"column:tenant_id;not null;default:'default';index"`
 	Name     string `json:"name" gorm:"not blank"`
-	Username string `json:"username" gorm:"uniqueIndex:idx_username,not blank"`
-	Email    string `json:"email" gorm:"uniqueIndex:idx_email,not blank"`
+	Username string `json:"username" gorm:"not blank"`
+	Email    string `json:"email" gorm:"not blank"`
+
+	// Formas canónicas (case-insensitive, NFKC). Sus índices únicos
+	// (idx_username_normalized, idx_email_normalized), compuestos con
+	// tenant_id, se crean vía migración y son los únicos que garantizan la
+	// unicidad de username y email dentro de cada organización.
+	UsernameNormalized string `json:"-" gorm:"column:username_normalized;not null;default:''"`
+	EmailNormalized    string `json:"-" gorm:"column:email_normalized;not null;default:''"`
+
+	// ExternalID es el identificador del proveedor de identidad (SCIM); no es
+	// único porque distintos proveedores pueden usar los mismos valores.
+	ExternalID string `json:"-" gorm:"column:external_id;not null;default:'';index"`
+
+	// Marcas de auditoría. Los valores los fija la capa de aplicación; los
+	// defaults solo completan las filas existentes al migrar.
+	CreatedAt time.Time `json:"created_at" gorm:"not null;default:now();index"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"not null;default:now();index"`
+	CreatedBy string    `json:"created_by" gorm:"not null;default:''"`
+	UpdatedBy string    `json:"updated_by" gorm:"not null;default:''"`
+
+	// DeletedAt habilita el soft delete de GORM: las consultas normales
+	// excluyen automáticamente las filas con valor.
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+
+	// Estado de la cuenta. Los valores admitidos se restringen con
+	// chk_user_entities_status (ver migraciones); las filas existentes al
+	// migrar quedan activas.
+	Status          string `json:"status" gorm:"column:status;not null;default:'active';index"`
+	StatusReason    string `json:"status_reason" gorm:"column:status_reason;not null;default:''"`
+	StatusChangedAt *time.Time
+
+	// Verificación del email. Las filas existentes al migrar quedan sin verificar.
+	EmailVerified   bool `json:"email_verified" gorm:"column:email_verified;not null;default:false"`
+	EmailVerifiedAt *time.Time
 }
 
 // ToEntity convierte una entidad de dominio (*domain.User) a una entidad de persistencia (UserEntity).
-// Esto se utiliza antes de escribir datos en la base de datos.
+// Esto se utiliza antes de escribir datos en la base de datos. DeletedAt no se
+// mapea: solo lo gestionan Delete/Restore del repositorio.
 func ToEntity(user *domain.User) UserEntity {
 	if user == nil {
 		return UserEntity{}
@@ -23,9 +63,27 @@ func ToEntity(user *domain.User) UserEntity {
 
 	return UserEntity{
 		ID:       user.ID,
+		TenantID: user.TenantID,
 		Name:     user.Name,
 		Username: user.Username,
 		Email:    user.Email,
+
+		UsernameNormalized: user.UsernameNormalized,
+		EmailNormalized:    user.EmailNormalized,
+
+		ExternalID: user.ExternalID,
+
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		CreatedBy: user.CreatedBy,
+		UpdatedBy: user.UpdatedBy,
+
+		Status:          string(user.Status),
+		StatusReason:    user.StatusReason,
+		StatusChangedAt: user.StatusChangedAt,
+
+		EmailVerified:   user.EmailVerified,
+		EmailVerifiedAt: user.EmailVerifiedAt,
 	}
 }
 
@@ -34,8 +92,36 @@ func ToEntity(user *domain.User) UserEntity {
 func FromEntity(entity *UserEntity) domain.User {
 	return domain.User{
 		ID:       entity.ID,
+		TenantID: entity.TenantID,
 		Name:     entity.Name,
 		Username: entity.Username,
 		Email:    entity.Email,
+
+		UsernameNormalized: entity.UsernameNormalized,
+		EmailNormalized:    entity.EmailNormalized,
+
+		ExternalID: entity.ExternalID,
+
+		CreatedAt: entity.CreatedAt,
+		UpdatedAt: entity.UpdatedAt,
+		CreatedBy: entity.CreatedBy,
+		UpdatedBy: entity.UpdatedBy,
+
+		DeletedAt: fromDeletedAt(entity.DeletedAt),
+
+		Status:          domain.UserStatus(entity.Status),
+		StatusReason:    entity.StatusReason,
+		StatusChangedAt: entity.StatusChangedAt,
+
+		EmailVerified:   entity.EmailVerified,
+		EmailVerifiedAt: entity.EmailVerifiedAt,
+	}
+}
+
+// fromDeletedAt convierte el tipo de GORM al instante de eliminación de dominio.
+func fromDeletedAt(deletedAt gorm.DeletedAt) *time.Time {
+	if !deletedAt.Valid {
+		return nil
 	}
+	return &deletedAt.Time
 }
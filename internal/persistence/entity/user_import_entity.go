@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// UserImportEntity representa una importación de usuarios. El archivo se
+// conserva en Payload para poder reanudar la importación tras un reinicio.
+type UserImportEntity struct {
+	ID              string    `gorm:"primaryKey"`
+	TenantID        string    `gorm:"not null;default:'default'"`
+	Format          string    `gorm:"not null"`
+	MappingName     string    `gorm:"not null;default:''"`
+	MappingUsername string    `gorm:"not null;default:''"`
+	MappingEmail    string    `gorm:"not null;default:''"`
+	Status          string    `gorm:"not null;index"`
+	TotalRows       int       `gorm:"not null;default:0"`
+	ProcessedRows   int       `gorm:"not null;default:0"`
+	CreatedRows     int       `gorm:"not null;default:0"`
+	FailedRows      int       `gorm:"not null;default:0"`
+	Error           string    `gorm:"not null;default:''"`
+	Payload         []byte    `gorm:"type:bytea;not null"`
+	CreatedBy       string    `gorm:"not null"`
+	CreatedAt       time.Time `gorm:"not null"`
+	UpdatedAt       time.Time `gorm:"not null"`
+	CompletedAt     *time.Time
+	// LeaseOwner y LeaseExpiresAt identifican al proceso que la está procesando.
+	LeaseOwner     string `gorm:"not null;default:''"`
+	LeaseExpiresAt *time.Time
+}
+
+// TableName fija el nombre de la tabla de importaciones.
+func (UserImportEntity) TableName() string {
+	return "user_imports"
+}
+
+// UserImportErrorEntity representa un error de una fila de una importación.
+type UserImportErrorEntity struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement"`
+	ImportID string `gorm:"not null;index:idx_user_import_errors_row,priority:1"`
+	Row      int    `gorm:"not null;index:idx_user_import_errors_row,priority:2"`
+	Field    string `gorm:"not null;default:''"`
+	Message  string `gorm:"not null"`
+}
+
+// TableName fija el nombre de la tabla de errores de importación.
+func (UserImportErrorEntity) TableName() string {
+	return "user_import_errors"
+}
+
+// ToUserImportEntity mapea un domain.UserImport a su entidad de persistencia (sin el archivo).
+func ToUserImportEntity(userImport *domain.UserImport) UserImportEntity {
+	return UserImportEntity{
+		ID:              userImport.ID,
+		TenantID:        userImport.TenantID,
+		Format:          string(userImport.Format),
+		MappingName:     userImport.Mapping.Name,
+		MappingUsername: userImport.Mapping.Username,
+		MappingEmail:    userImport.Mapping.Email,
+		Status:          string(userImport.Status),
+		TotalRows:       userImport.TotalRows,
+		ProcessedRows:   userImport.ProcessedRows,
+		CreatedRows:     userImport.CreatedRows,
+		FailedRows:      userImport.FailedRows,
+		Error:           userImport.Error,
+		CreatedBy:       userImport.CreatedBy,
+		CreatedAt:       userImport.CreatedAt,
+		UpdatedAt:       userImport.UpdatedAt,
+		CompletedAt:     userImport.CompletedAt,
+	}
+}
+
+// FromUserImportEntity mapea una UserImportEntity a domain.UserImport.
+func FromUserImportEntity(e *UserImportEntity) domain.UserImport {
+	return domain.UserImport{
+		ID:       e.ID,
+		TenantID: e.TenantID,
+		Format:   domain.ImportFormat(e.Format),
+		Mapping: domain.ImportColumnMapping{
+			Name:     e.MappingName,
+			Username: e.MappingUsername,
+			Email:    e.MappingEmail,
+		},
+		Status:        domain.ImportStatus(e.Status),
+		TotalRows:     e.TotalRows,
+		ProcessedRows: e.ProcessedRows,
+		CreatedRows:   e.CreatedRows,
+		FailedRows:    e.FailedRows,
+		Error:         e.Error,
+		CreatedBy:     e.CreatedBy,
+		CreatedAt:     e.CreatedAt,
+		UpdatedAt:     e.UpdatedAt,
+		CompletedAt:   e.CompletedAt,
+	}
+}
@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"user-api-restful/internal/domain"
+)
+
+// WebhookEntity representa una suscripción de webhook. Los tipos de evento
+// del filtro se guardan separados por comas.
+type WebhookEntity struct {
+	ID                  string `gorm:"primaryKey"`
+	TenantID            string `gorm:"not null;default:'default';index"`
+	URL                 string `gorm:"not null"`
+	Events              string `gorm:"not null;default:''"`
+	Secret              string `gorm:"not null"`
+	Active              bool   `gorm:"not null"`
+	ConsecutiveFailures int    `gorm:"not null;default:0"`
+	DisabledAt          *time.Time
+	CreatedAt           time.Time `gorm:"not null"`
+	UpdatedAt           time.Time `gorm:"not null"`
+}
+
+// TableName fija el nombre de la tabla de webhooks.
+func (WebhookEntity) TableName() string {
+	return "webhooks"
+}
+
+// WebhookDeliveryEntity representa una entrega de un evento a un webhook.
+// Conserva el payload para poder reenviarlo manualmente.
+type WebhookDeliveryEntity struct {
+	ID             string    `gorm:"primaryKey"`
+	WebhookID      string    `gorm:"not null;index"`
+	EventID        string    `gorm:"not null"`
+	EventType      string    `gorm:"not null"`
+	Payload        string    `gorm:"type:jsonb;not null"`
+	State          string    `gorm:"not null;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts       int       `gorm:"not null;default:0"`
+	LastStatusCode int       `gorm:"not null;default:0"`
+	LastError      string    `gorm:"not null;default:''"`
+	NextAttemptAt  time.Time `gorm:"not null;index:idx_webhook_deliveries_due,priority:2"`
+	CreatedAt      time.Time `gorm:"not null;index"`
+	DeliveredAt    *time.Time
+}
+
+// TableName fija el nombre de la tabla de entregas de webhooks.
+func (WebhookDeliveryEntity) TableName() string {
+	return "webhook_deliveries"
+}
+
+// ToWebhookEntity convierte un webhook de dominio a su entidad de persistencia.
+func ToWebhookEntity(webhook *domain.Webhook) WebhookEntity {
+	events := make([]string, len(webhook.Events))
+	for i, event := range webhook.Events {
+		events[i] = string(event)
+	}
+
+	return WebhookEntity{
+		ID:                  webhook.ID,
+		TenantID:            webhook.TenantID,
+		URL:                 webhook.URL,
+		Events:              strings.Join(events, ","),
+		Secret:              webhook.Secret,
+		Active:              webhook.Active,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		DisabledAt:          webhook.DisabledAt,
+		CreatedAt:           webhook.CreatedAt,
+		UpdatedAt:           webhook.UpdatedAt,
+	}
+}
+
+// FromWebhookEntity convierte una entidad de webhook a su modelo de dominio.
+func FromWebhookEntity(entity *WebhookEntity) domain.Webhook {
+	events := []domain.EventType{}
+	if entity.Events != "" {
+		for _, event := range strings.Split(entity.Events, ",") {
+			events = append(events, domain.EventType(event))
+		}
+	}
+
+	return domain.Webhook{
+		ID:                  entity.ID,
+		TenantID:            entity.TenantID,
+		URL:                 entity.URL,
+		Events:              events,
+		Secret:              entity.Secret,
+		Active:              entity.Active,
+		ConsecutiveFailures: entity.ConsecutiveFailures,
+		DisabledAt:          entity.DisabledAt,
+		CreatedAt:           entity.CreatedAt,
+		UpdatedAt:           entity.UpdatedAt,
+	}
+}
+
+// ToWebhookDeliveryEntity convierte una entrega de dominio a su entidad de persistencia.
+func ToWebhookDeliveryEntity(delivery *domain.WebhookDelivery) WebhookDeliveryEntity {
+	return WebhookDeliveryEntity{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		EventType:      string(delivery.EventType),
+		Payload:        delivery.Payload,
+		State:          string(delivery.State),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+}
+
+// FromWebhookDeliveryEntity convierte una entidad de entrega a su modelo de dominio.
+func FromWebhookDeliveryEntity(entity *WebhookDeliveryEntity) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:             entity.ID,
+		WebhookID:      entity.WebhookID,
+		EventID:        entity.EventID,
+		EventType:      domain.EventType(entity.EventType),
+		Payload:        entity.Payload,
+		State:          domain.WebhookDeliveryState(entity.State),
+		Attempts:       entity.Attempts,
+		LastStatusCode: entity.LastStatusCode,
+		LastError:      entity.LastError,
+		NextAttemptAt:  entity.NextAttemptAt,
+		CreatedAt:      entity.CreatedAt,
+		DeliveredAt:    entity.DeliveredAt,
+	}
+}